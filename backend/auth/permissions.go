@@ -0,0 +1,164 @@
+// ABOUTME: Roles and the role to permission mapping used for coarse-grained checks
+// ABOUTME: Permissions are embedded in JWT claims and checked by route middleware
+
+package auth
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// Built-in roles; custom roles are added through the roles table
+const (
+	RoleAdmin   = "Admin"
+	RoleManager = "Manager"
+	RoleMember  = "Member"
+	RoleViewer  = "Viewer"
+)
+
+// builtinRoles lists the built-in roles from most to least privileged
+var builtinRoles = []string{RoleAdmin, RoleManager, RoleMember, RoleViewer}
+
+// builtinRolePermissions lists what each built-in role may do to each resource type, before
+// any per-resource checks (department, ownership, membership) are applied. It matches the
+// roles migrations' seed and is used until LoadRoles has read the roles table.
+var builtinRolePermissions = map[string][]string{
+	RoleAdmin: {
+		"tasks.create", "tasks.read", "tasks.update", "tasks.delete",
+		"users.create", "users.read", "users.update", "users.delete",
+		"projects.create", "projects.read", "projects.update", "projects.delete",
+		"departments.create", "departments.read", "departments.update", "departments.delete",
+		"settings.read", "settings.update",
+		"roles.create", "roles.update", "roles.delete",
+	},
+	RoleManager: {
+		"tasks.create", "tasks.read", "tasks.update", "tasks.delete",
+		"users.read",
+		"projects.create", "projects.read", "projects.update",
+		"departments.read",
+	},
+	RoleMember: {
+		"tasks.create", "tasks.read", "tasks.update", "tasks.delete",
+		"users.read",
+		"projects.read",
+		"departments.read",
+	},
+	RoleViewer: {
+		"tasks.read",
+		"users.read",
+		"projects.read",
+		"departments.read",
+	},
+}
+
+// roleCache holds the role mapping last read by LoadRoles
+var roleCache = struct {
+	sync.RWMutex
+	permissions map[string][]string
+	custom      []string
+}{permissions: builtinRolePermissions}
+
+// LoadRoles reads the roles table into the cache PermissionsForRole serves from. It runs at
+// startup, after every role change made through this instance, and every CACHE_REFRESH_SEC
+// from the cache refresh job, which is how other instances' changes arrive.
+func LoadRoles(db *gorm.DB) error {
+	var roles []models.Role
+	if err := db.Find(&roles).Error; err != nil {
+		return err
+	}
+	var grants []models.RolePermission
+	if err := db.Order("permission ASC").Find(&grants).Error; err != nil {
+		return err
+	}
+
+	permissions := make(map[string][]string, len(roles))
+	custom := []string{}
+	for _, role := range roles {
+		permissions[role.Name] = []string{}
+		if !IsBuiltinRole(role.Name) {
+			custom = append(custom, role.Name)
+		}
+	}
+	for _, grant := range grants {
+		permissions[grant.RoleName] = append(permissions[grant.RoleName], grant.Permission)
+	}
+	// Admin's permissions can't be edited, so it always keeps access to everything
+	permissions[RoleAdmin] = builtinRolePermissions[RoleAdmin]
+	sort.Strings(custom)
+
+	roleCache.Lock()
+	defer roleCache.Unlock()
+	roleCache.permissions = permissions
+	roleCache.custom = custom
+	return nil
+}
+
+// PermissionsForRole returns the role's permissions; unknown roles get Viewer permissions
+func PermissionsForRole(role string) []string {
+	roleCache.RLock()
+	defer roleCache.RUnlock()
+	if perms, ok := roleCache.permissions[role]; ok {
+		return perms
+	}
+	return roleCache.permissions[RoleViewer]
+}
+
+// HasPermission reports whether the role grants permission
+func HasPermission(role, permission string) bool {
+	for _, perm := range PermissionsForRole(role) {
+		if perm == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles returns every known role: the built-in ones, most privileged first, then custom
+// roles by name
+func Roles() []string {
+	roleCache.RLock()
+	defer roleCache.RUnlock()
+	return append(append([]string{}, builtinRoles...), roleCache.custom...)
+}
+
+// IsBuiltinRole reports whether role is one of the four built-in roles
+func IsBuiltinRole(role string) bool {
+	_, ok := builtinRolePermissions[role]
+	return ok
+}
+
+// IsKnownRole reports whether role is built in or has been created
+func IsKnownRole(role string) bool {
+	for _, known := range Roles() {
+		if known == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKnownPermission reports whether permission is one the API checks. Admins hold every
+// permission, so theirs is the full list.
+func IsKnownPermission(permission string) bool {
+	for _, perm := range builtinRolePermissions[RoleAdmin] {
+		if perm == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesWithPermission returns the known roles that grant permission. When Viewers have it,
+// unknownRoles is true too, since PermissionsForRole treats unknown roles as Viewers.
+func RolesWithPermission(permission string) (roles []string, unknownRoles bool) {
+	roles = []string{}
+	for _, role := range Roles() {
+		if HasPermission(role, permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles, HasPermission(RoleViewer, permission)
+}
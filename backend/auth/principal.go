@@ -0,0 +1,54 @@
+// ABOUTME: The authenticated caller that authorization decisions are made for
+// ABOUTME: Built from the user context RequireAuth stores on each request
+
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/models"
+)
+
+// Principal is the caller being authorized
+type Principal struct {
+	ID           string
+	Role         string
+	DepartmentID *string
+}
+
+// FromContext reads the caller set by the auth middleware. Missing values come back empty,
+// which authorizes nothing beyond what a Viewer without a department may do.
+func FromContext(c *gin.Context) Principal {
+	p := Principal{
+		ID:   c.GetString("user_id"),
+		Role: c.GetString("user_role"),
+	}
+	if departmentID, ok := c.Get("user_department_id"); ok {
+		p.DepartmentID, _ = departmentID.(*string)
+	}
+	return p
+}
+
+// PrincipalForUser authorizes on behalf of a stored user rather than the caller, e.g. to check
+// what a mentioned user or a calendar feed's owner may see
+func PrincipalForUser(user models.User) Principal {
+	return Principal{ID: user.ID, Role: user.Role, DepartmentID: user.DepartmentID}
+}
+
+func (p Principal) IsAdmin() bool   { return p.Role == RoleAdmin }
+func (p Principal) IsManager() bool { return p.Role == RoleManager }
+func (p Principal) IsViewer() bool  { return p.Role == RoleViewer }
+
+// InDepartment reports whether the caller belongs to departmentID. Callers and resources
+// without a department never match, so a missing department grants nothing.
+func (p Principal) InDepartment(departmentID *string) bool {
+	return p.DepartmentID != nil && departmentID != nil && *p.DepartmentID == *departmentID
+}
+
+// ownDepartment reports whether departmentID is the caller's department, treating "no
+// department" as matching a caller who has none. Used when placing new resources.
+func (p Principal) ownDepartment(departmentID *string) bool {
+	if p.DepartmentID == nil {
+		return departmentID == nil
+	}
+	return departmentID != nil && *departmentID == *p.DepartmentID
+}
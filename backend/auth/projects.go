@@ -0,0 +1,62 @@
+// ABOUTME: Authorization rules for projects and their member lists
+// ABOUTME: Non-Admins see their department's projects and those they're members of; Leads manage members
+
+package auth
+
+import (
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// ProjectRoleLead is the project member role allowed to manage the member list
+const ProjectRoleLead = "Lead"
+
+// CanCreateProject allows Admins anywhere and roles granting projects.create, such as
+// Managers, in their own department
+func CanCreateProject(p Principal, departmentID *string) bool {
+	return p.IsAdmin() || (HasPermission(p.Role, "projects.create") && p.ownDepartment(departmentID))
+}
+
+// CanAccessProject allows Admins, and other roles within the project's department or on its
+// member list, matching how task visibility follows department and involvement. memberRole
+// is p's project role, "" if not a member.
+func CanAccessProject(p Principal, project models.Project, memberRole string) bool {
+	return p.IsAdmin() || p.InDepartment(project.DepartmentID) || memberRole != ""
+}
+
+// CanModifyProject allows Admins and roles granting projects.update, such as Managers,
+// within the project's department
+func CanModifyProject(p Principal, project models.Project) bool {
+	return p.IsAdmin() || (HasPermission(p.Role, "projects.update") && p.InDepartment(project.DepartmentID))
+}
+
+// CanDeleteProject follows the same rule as CanModifyProject
+func CanDeleteProject(p Principal, project models.Project) bool {
+	return CanModifyProject(p, project)
+}
+
+// CanCloneProject allows whoever may both modify the project and create one in its
+// department: Admins, and Managers within their own department
+func CanCloneProject(p Principal, project models.Project) bool {
+	return CanModifyProject(p, project) && CanCreateProject(p, project.DepartmentID)
+}
+
+// CanTransferProject allows Admins to hand a project to anyone and Managers to users in
+// their own department
+func CanTransferProject(p Principal, newOwner models.User) bool {
+	return p.IsAdmin() || (p.IsManager() && p.InDepartment(newOwner.DepartmentID))
+}
+
+// CanManageProjectMembers allows whoever can modify the project, plus the project's Leads
+func CanManageProjectMembers(p Principal, project models.Project, memberRole string) bool {
+	return CanModifyProject(p, project) || memberRole == ProjectRoleLead
+}
+
+// ScopeProjects restricts a project query to the projects p may see in listings
+func ScopeProjects(query *gorm.DB, p Principal) *gorm.DB {
+	if p.IsAdmin() {
+		return query
+	}
+	return query.Where("department_id = ? OR id IN (SELECT project_id FROM project_members WHERE user_id = ?)",
+		p.DepartmentID, p.ID)
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Authorization rules for tasks, their checklists and time logs
+// ABOUTME: Single source for who may see, create, change and delete a task
+
+package auth
+
+import (
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// CanCreateTask allows roles granting tasks.create to create tasks. Non-admins may only
+// place them in their own department.
+func CanCreateTask(p Principal, departmentID *string) bool {
+	if !HasPermission(p.Role, "tasks.create") {
+		return false
+	}
+	return p.IsAdmin() || p.ownDepartment(departmentID)
+}
+
+// CanAccessTask allows Admins, the task's creator and assignees, and anyone in the task's
+// department. task.Assignees must be loaded for assignees to be recognized.
+func CanAccessTask(p Principal, task models.Task) bool {
+	if p.IsAdmin() || task.CreatorID == p.ID || p.InDepartment(task.DepartmentID) {
+		return true
+	}
+	return isAssignee(p, task)
+}
+
+// CanModifyTask allows Admins, Managers within the task's department and other roles
+// granting tasks.update, such as Members, on tasks they created. Viewers can never modify tasks.
+func CanModifyTask(p Principal, task models.Task) bool {
+	switch p.Role {
+	case RoleAdmin:
+		return true
+	case RoleManager:
+		return p.InDepartment(task.DepartmentID)
+	}
+	return HasPermission(p.Role, "tasks.update") && task.CreatorID == p.ID
+}
+
+// CanDeleteTask allows Admins and the task's creator when their role grants tasks.delete
+func CanDeleteTask(p Principal, task models.Task) bool {
+	return p.IsAdmin() || (HasPermission(p.Role, "tasks.delete") && task.CreatorID == p.ID)
+}
+
+// CanReassignTaskCreator allows only Admins to hand a task to a new creator
+func CanReassignTaskCreator(p Principal) bool {
+	return p.IsAdmin()
+}
+
+// CanLogTime allows the task's creator and assignees, Admins, and Managers who can access
+// the task. Roles without tasks.update, like Viewers, can never log time. task.Assignees
+// must be loaded.
+func CanLogTime(p Principal, task models.Task) bool {
+	if !HasPermission(p.Role, "tasks.update") {
+		return false
+	}
+	if p.IsAdmin() || task.CreatorID == p.ID || isAssignee(p, task) {
+		return true
+	}
+	return p.IsManager() && CanAccessTask(p, task)
+}
+
+// CanModifyTimeLog allows only the user who logged the entry to change or delete it
+func CanModifyTimeLog(p Principal, entry models.TimeLog) bool {
+	return entry.UserID == p.ID
+}
+
+// ScopeTasks restricts a task query to the tasks p may see in listings, matching CanAccessTask
+func ScopeTasks(query *gorm.DB, p Principal) *gorm.DB {
+	if p.IsAdmin() {
+		return query
+	}
+	return query.Where("creator_id = ? OR department_id = ? OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)",
+		p.ID, p.DepartmentID, p.ID)
+}
+
+// isAssignee reports whether p is among the task's loaded assignees
+func isAssignee(p Principal, task models.Task) bool {
+	for _, assigneeID := range task.Assignees {
+		if assigneeID == p.ID {
+			return true
+		}
+	}
+	return false
+}
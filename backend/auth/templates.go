@@ -0,0 +1,32 @@
+// ABOUTME: Authorization rules for task templates
+// ABOUTME: Global templates are shared; department templates stay within their department
+
+package auth
+
+import (
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// CanCreateTemplate allows Admins to create global or department templates and Managers to
+// create templates for their own department
+func CanCreateTemplate(p Principal, departmentID *string) bool {
+	return p.IsAdmin() || (p.IsManager() && p.InDepartment(departmentID))
+}
+
+// CanAccessTemplate allows Admins any template and everyone else global templates and
+// their department's
+func CanAccessTemplate(p Principal, template models.TaskTemplate) bool {
+	return p.IsAdmin() || template.DepartmentID == nil || p.InDepartment(template.DepartmentID)
+}
+
+// ScopeTemplates restricts a template query to the templates p may see, matching CanAccessTemplate
+func ScopeTemplates(query *gorm.DB, p Principal) *gorm.DB {
+	if p.IsAdmin() {
+		return query
+	}
+	if p.DepartmentID == nil {
+		return query.Where("department_id IS NULL")
+	}
+	return query.Where("department_id IS NULL OR department_id = ?", *p.DepartmentID)
+}
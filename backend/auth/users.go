@@ -0,0 +1,39 @@
+// ABOUTME: Authorization rules for user profiles and per-user data such as tasks and timesheets
+// ABOUTME: Everyone sees themselves; Admins see everyone; others stay within their department
+
+package auth
+
+import (
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// CanAccessUser allows viewing one's own profile, Admins any profile, and everyone else
+// profiles in their department
+func CanAccessUser(p Principal, target models.User) bool {
+	return p.ID == target.ID || p.IsAdmin() || p.InDepartment(target.DepartmentID)
+}
+
+// CanModifyUser allows users to edit their own profile and Admins to edit any
+func CanModifyUser(p Principal, target models.User) bool {
+	return p.ID == target.ID || p.IsAdmin()
+}
+
+// CanManageUserAccount allows only Admins to change a user's role, department or status
+func CanManageUserAccount(p Principal) bool {
+	return p.IsAdmin()
+}
+
+// CanViewUserWork allows viewing a user's tasks and timesheet: their own, any for Admins,
+// and their department's for Managers
+func CanViewUserWork(p Principal, target models.User) bool {
+	return p.ID == target.ID || p.IsAdmin() || (p.IsManager() && p.InDepartment(target.DepartmentID))
+}
+
+// ScopeUsers restricts a user query to the users p may see in listings, matching CanAccessUser
+func ScopeUsers(query *gorm.DB, p Principal) *gorm.DB {
+	if p.IsAdmin() {
+		return query
+	}
+	return query.Where("department_id = ? OR id = ?", p.DepartmentID, p.ID)
+}
@@ -3,20 +3,512 @@
 
 package config
 
-import "os"
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinJWTSecretLength is the shortest JWT_SECRET accepted at startup (HS256 wants 256 bits)
+const MinJWTSecretLength = 32
+
+// JWT signing algorithms accepted for JWT_ALG
+const (
+	JWTAlgHS256 = "HS256" // HMAC with JWT_SECRET; anything that can verify tokens can also mint them
+	JWTAlgRS256 = "RS256" // RSA key pair; verifiers only need the public key
+)
+
+// Connection pool defaults, used when the DB_* variables are unset
+const (
+	DefaultDBMaxOpenConns       = 25
+	DefaultDBMaxIdleConns       = 5
+	DefaultDBConnMaxLifetimeMin = 60
+	DefaultDBConnMaxIdleTimeMin = 10
+	DefaultDBQueryTimeoutSec    = 30
+)
+
+// HTTP server timeouts in seconds, used when the SERVER_*_TIMEOUT_SEC variables are unset.
+// Writes get longer than DB_QUERY_TIMEOUT_SEC so timed-out requests can still respond.
+const (
+	DefaultServerReadHeaderTimeoutSec = 5
+	DefaultServerReadTimeoutSec       = 15
+	DefaultServerWriteTimeoutSec      = 60
+	DefaultServerIdleTimeoutSec       = 120
+)
+
+// DefaultCompressionMinBytes is the smallest response gzipped when COMPRESSION_MIN_BYTES is unset
+const DefaultCompressionMinBytes = 1024
+
+// List endpoint page sizes, used when PAGINATION_DEFAULT and PAGINATION_MAX are unset
+const (
+	DefaultPaginationDefault = 20
+	DefaultPaginationMax     = 100
+)
+
+// Avatar styles for users without a stored avatar_url
+const (
+	AvatarStyleGravatar = "gravatar" // Gravatar for the email, falling back to an identicon
+	AvatarStyleInitials = "initials" // Generated image of the user's initials
+	AvatarStyleNone     = "none"     // Leave avatar_url empty
+)
+
+// Uploaded files live under DefaultUploadDir and avatars may be up to DefaultAvatarMaxBytes
+// when UPLOAD_DIR and AVATAR_MAX_BYTES are unset
+const (
+	DefaultUploadDir      = "uploads"
+	DefaultAvatarMaxBytes = 2 << 20
+)
+
+// DefaultTaskStatusTransitions is the task workflow used when TASK_STATUS_TRANSITIONS is
+// unset: work is reviewed before it's done, and done work can only be reopened
+var DefaultTaskStatusTransitions = map[string][]string{
+	"To Do":       {"In Progress", "Blocked"},
+	"In Progress": {"To Do", "In Review", "Blocked"},
+	"In Review":   {"In Progress", "Done", "Blocked"},
+	"Blocked":     {"To Do", "In Progress"},
+	"Done":        {"In Progress"},
+}
+
+// Task list views applied by GET /tasks when a request sends no filters
+const (
+	TaskViewAll            = "all"             // Every task in the caller's scope
+	TaskViewMine           = "mine"            // Tasks the caller created or is assigned to
+	TaskViewDepartmentOpen = "department_open" // Unfinished tasks in the caller's department
+)
+
+// DefaultTaskListViews is the unfiltered task list each role lands on. Roles that aren't
+// listed, including custom ones, see all tasks in their scope.
+var DefaultTaskListViews = map[string]string{
+	"Admin":   TaskViewAll,
+	"Manager": TaskViewDepartmentOpen,
+	"Member":  TaskViewMine,
+	"Viewer":  TaskViewAll,
+}
+
+// Project health thresholds, used when the PROJECT_*_PERCENT variables are unset: the share
+// of a project's tasks that are overdue before it is At Risk, and before it is Overdue
+const (
+	DefaultProjectAtRiskPercent  = 20
+	DefaultProjectOverduePercent = 50
+)
+
+// DefaultPasswordHistorySize is how many recent passwords can't be reused when
+// PASSWORD_HISTORY_SIZE is unset
+const DefaultPasswordHistorySize = 5
+
+// DefaultCacheRefreshSec is how often the settings cached in memory are re-read from the
+// database when CACHE_REFRESH_SEC is unset
+const DefaultCacheRefreshSec = 30
+
+// DefaultDigestTime is when the manager digest goes out when DIGEST_TIME is unset (UTC)
+const DefaultDigestTime = "08:00"
+
+// MaxTaskSourceLength matches tasks.source, so configured sources fit the column
+const MaxTaskSourceLength = 20
+
+// DefaultHiddenResourceStatus keeps out-of-scope resources answering 403 unless configured otherwise
+const DefaultHiddenResourceStatus = 403
 
 type Config struct {
 	DatabaseURL string
 	JWTSecret   string
 	Port        string
 	GinMode     string
+	BcryptCost  int // 0 when BCRYPT_COST is unset or not a number; utils falls back to its default
+
+	// JWTAlg signs access and refresh tokens, HS256 or RS256. RS256 reads its PEM keys from
+	// JWTPrivateKey and JWTPublicKey or from the files at the matching *Path settings; the
+	// public key may be left out and derived from the private one. JWT_SECRET is still
+	// required under RS256, since feed tokens and 2FA challenges are signed with it.
+	JWTAlg            string
+	JWTPrivateKey     string
+	JWTPublicKey      string
+	JWTPrivateKeyPath string
+	JWTPublicKeyPath  string
+
+	// JWTPreviousPublicKeys holds retired RS256 public keys, as concatenated PEM blocks, that
+	// still verify tokens and are still published in the JWKS while a rotation overlaps
+	JWTPreviousPublicKeys     string
+	JWTPreviousPublicKeysPath string
+
+	// JWTSecretPrevious lists retired HS256 secrets, comma separated, that still verify tokens
+	// while a JWT_SECRET rotation overlaps; new tokens are always signed with JWTSecret
+	JWTSecretPrevious string
+
+	// PasswordHistorySize is how many of a user's most recent passwords, counting the current
+	// one, a password change may not reuse; 1 or less only rejects the current password
+	PasswordHistorySize int
+
+	// TwoFactorEncryptionKey encrypts stored TOTP secrets; empty falls back to JWT_SECRET.
+	// Changing it invalidates every enrolled authenticator.
+	TwoFactorEncryptionKey string
+
+	// Connection pool tuning; SetupDatabase rejects values that aren't positive
+	DBMaxOpenConns       int
+	DBMaxIdleConns       int
+	DBConnMaxLifetimeMin int
+	DBConnMaxIdleTimeMin int
+
+	// DBQueryTimeoutSec bounds how long a request's queries may run before they are cancelled
+	DBQueryTimeoutSec int
+
+	// Server timeouts bound reading a request's headers, reading the whole request, writing
+	// the response and keeping an idle keep-alive connection; NewServer applies them
+	ServerReadHeaderTimeoutSec int
+	ServerReadTimeoutSec       int
+	ServerWriteTimeoutSec      int
+	ServerIdleTimeoutSec       int
+
+	// CompressionEnabled gzips responses of at least CompressionMinBytes for clients that
+	// accept it
+	CompressionEnabled  bool
+	CompressionMinBytes int
+
+	// HiddenResourceStatus is what callers get for tasks, projects and users outside their
+	// scope: 403 says the resource exists, 404 hides it from enumeration
+	HiddenResourceStatus int
+
+	// PaginationDefault is the per_page used when a list request doesn't ask for one;
+	// PaginationMax is the largest per_page a request may get
+	PaginationDefault int
+	PaginationMax     int
+
+	// TaskStatusTransitions is a JSON object mapping each task status to the statuses it may
+	// move to; empty uses DefaultTaskStatusTransitions. AdminStatusOverride lets Admins
+	// make any status change regardless.
+	TaskStatusTransitions string
+	AdminStatusOverride   bool
+
+	// RejectPastDueDates refuses new tasks whose due date has already passed
+	RejectPastDueDates bool
+
+	// TaskSources lists task sources accepted on top of the built-in ones, comma separated,
+	// so integrations can tag their own origin such as Jira or Zapier
+	TaskSources string
+
+	// TaskDefaultViews is a JSON object mapping roles to the task list view they land on,
+	// overriding DefaultTaskListViews for the roles it names
+	TaskDefaultViews string
+
+	// PublicBaseURL is the scheme and host clients reach the API on, e.g. https://api.example.com.
+	// Resource _links are prefixed with it; empty leaves them as root-relative paths.
+	PublicBaseURL string
+
+	// ProjectAtRiskPercent and ProjectOverduePercent are the percentages of a project's tasks
+	// that must be overdue for its health to be At Risk or Overdue
+	ProjectAtRiskPercent  int
+	ProjectOverduePercent int
+
+	// AvatarStyle picks the avatar_url derived for users who haven't set one
+	AvatarStyle string
+
+	// UploadDir is where uploaded files such as avatars are stored and served from;
+	// AvatarMaxBytes caps the size of an uploaded avatar image
+	UploadDir      string
+	AvatarMaxBytes int
+
+	// MetricsEnabled serves Prometheus metrics on /metrics; MetricsToken, when set, is the
+	// bearer token scrapers must send to read them
+	MetricsEnabled bool
+	MetricsToken   string
+
+	// CacheRefreshSec is how often each instance re-reads the roles, task workflows and custom
+	// fields it caches in memory, so changes made through another instance reach it
+	CacheRefreshSec int
+
+	// DigestEnabled sends each Manager a daily digest of their department's overdue and
+	// due-today tasks at DigestTime (HH:MM, UTC). Digests are posted to DigestWebhookURL as
+	// Slack-compatible messages, or sent as in-app notifications when no webhook is set.
+	DigestEnabled    bool
+	DigestTime       string
+	DigestWebhookURL string
 }
 
 func GetConfig() *Config {
 	return &Config{
-		DatabaseURL: os.Getenv("DATABASE_URL"),
-		JWTSecret:   os.Getenv("JWT_SECRET"),
-		Port:        os.Getenv("PORT"),
-		GinMode:     os.Getenv("GIN_MODE"),
+		DatabaseURL:                os.Getenv("DATABASE_URL"),
+		JWTSecret:                  os.Getenv("JWT_SECRET"),
+		Port:                       os.Getenv("PORT"),
+		GinMode:                    os.Getenv("GIN_MODE"),
+		BcryptCost:                 envInt("BCRYPT_COST"),
+		JWTAlg:                     envStringDefault("JWT_ALG", JWTAlgHS256),
+		JWTSecretPrevious:          os.Getenv("JWT_SECRET_PREVIOUS"),
+		JWTPrivateKey:              os.Getenv("JWT_PRIVATE_KEY"),
+		JWTPublicKey:               os.Getenv("JWT_PUBLIC_KEY"),
+		JWTPrivateKeyPath:          os.Getenv("JWT_PRIVATE_KEY_PATH"),
+		JWTPublicKeyPath:           os.Getenv("JWT_PUBLIC_KEY_PATH"),
+		JWTPreviousPublicKeys:      os.Getenv("JWT_PREVIOUS_PUBLIC_KEYS"),
+		JWTPreviousPublicKeysPath:  os.Getenv("JWT_PREVIOUS_PUBLIC_KEYS_PATH"),
+		PasswordHistorySize:        envIntDefault("PASSWORD_HISTORY_SIZE", DefaultPasswordHistorySize),
+		TwoFactorEncryptionKey:     os.Getenv("TWO_FACTOR_ENCRYPTION_KEY"),
+		DBMaxOpenConns:             envIntDefault("DB_MAX_OPEN_CONNS", DefaultDBMaxOpenConns),
+		DBMaxIdleConns:             envIntDefault("DB_MAX_IDLE_CONNS", DefaultDBMaxIdleConns),
+		DBConnMaxLifetimeMin:       envIntDefault("DB_CONN_MAX_LIFETIME_MIN", DefaultDBConnMaxLifetimeMin),
+		DBConnMaxIdleTimeMin:       envIntDefault("DB_CONN_MAX_IDLE_TIME_MIN", DefaultDBConnMaxIdleTimeMin),
+		DBQueryTimeoutSec:          envIntDefault("DB_QUERY_TIMEOUT_SEC", DefaultDBQueryTimeoutSec),
+		ServerReadHeaderTimeoutSec: envIntDefault("SERVER_READ_HEADER_TIMEOUT_SEC", DefaultServerReadHeaderTimeoutSec),
+		ServerReadTimeoutSec:       envIntDefault("SERVER_READ_TIMEOUT_SEC", DefaultServerReadTimeoutSec),
+		ServerWriteTimeoutSec:      envIntDefault("SERVER_WRITE_TIMEOUT_SEC", DefaultServerWriteTimeoutSec),
+		ServerIdleTimeoutSec:       envIntDefault("SERVER_IDLE_TIMEOUT_SEC", DefaultServerIdleTimeoutSec),
+		CompressionEnabled:         envBoolDefault("COMPRESSION_ENABLED", true),
+		CompressionMinBytes:        envIntDefault("COMPRESSION_MIN_BYTES", DefaultCompressionMinBytes),
+		HiddenResourceStatus:       envIntDefault("HIDDEN_RESOURCE_STATUS", DefaultHiddenResourceStatus),
+		PaginationDefault:          envIntDefault("PAGINATION_DEFAULT", DefaultPaginationDefault),
+		PaginationMax:              envIntDefault("PAGINATION_MAX", DefaultPaginationMax),
+		TaskStatusTransitions:      os.Getenv("TASK_STATUS_TRANSITIONS"),
+		AdminStatusOverride:        envBoolDefault("TASK_STATUS_ADMIN_OVERRIDE", true),
+		RejectPastDueDates:         envBool("REJECT_PAST_DUE_DATES"),
+		TaskSources:                os.Getenv("TASK_SOURCES"),
+		TaskDefaultViews:           os.Getenv("TASK_DEFAULT_VIEWS"),
+		PublicBaseURL:              os.Getenv("PUBLIC_BASE_URL"),
+		ProjectAtRiskPercent:       envIntDefault("PROJECT_AT_RISK_PERCENT", DefaultProjectAtRiskPercent),
+		ProjectOverduePercent:      envIntDefault("PROJECT_OVERDUE_PERCENT", DefaultProjectOverduePercent),
+		AvatarStyle:                envStringDefault("AVATAR_STYLE", AvatarStyleGravatar),
+		UploadDir:                  envStringDefault("UPLOAD_DIR", DefaultUploadDir),
+		AvatarMaxBytes:             envIntDefault("AVATAR_MAX_BYTES", DefaultAvatarMaxBytes),
+		MetricsEnabled:             envBool("METRICS_ENABLED"),
+		MetricsToken:               os.Getenv("METRICS_TOKEN"),
+		CacheRefreshSec:            envIntDefault("CACHE_REFRESH_SEC", DefaultCacheRefreshSec),
+		DigestEnabled:              envBool("DIGEST_ENABLED"),
+		DigestTime:                 envStringDefault("DIGEST_TIME", DefaultDigestTime),
+		DigestWebhookURL:           os.Getenv("DIGEST_WEBHOOK_URL"),
+	}
+}
+
+// Validate reports the first missing or unusable setting. PORT and GIN_MODE are optional.
+func (c *Config) Validate() error {
+	if c.DatabaseURL == "" {
+		return fmt.Errorf("DATABASE_URL is required")
+	}
+	if c.JWTSecret == "" {
+		return fmt.Errorf("JWT_SECRET is required")
+	}
+	if len(c.JWTSecret) < MinJWTSecretLength {
+		return fmt.Errorf("JWT_SECRET must be at least %d characters (got %d)", MinJWTSecretLength, len(c.JWTSecret))
+	}
+	for _, secret := range c.PreviousJWTSecrets() {
+		if len(secret) < MinJWTSecretLength {
+			return fmt.Errorf("each JWT_SECRET_PREVIOUS secret must be at least %d characters", MinJWTSecretLength)
+		}
+	}
+	switch c.JWTAlg {
+	case JWTAlgHS256:
+	case JWTAlgRS256:
+		if _, _, err := c.RSAKeys(); err != nil {
+			return err
+		}
+		if _, err := c.PreviousRSAPublicKeys(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("JWT_ALG must be HS256 or RS256")
+	}
+	if c.DBQueryTimeoutSec <= 0 {
+		return fmt.Errorf("DB_QUERY_TIMEOUT_SEC must be a positive integer")
+	}
+	if c.HiddenResourceStatus != 403 && c.HiddenResourceStatus != 404 {
+		return fmt.Errorf("HIDDEN_RESOURCE_STATUS must be 403 or 404")
+	}
+	if _, err := c.StatusTransitions(); err != nil {
+		return err
+	}
+	if _, err := c.TaskListViews(); err != nil {
+		return err
+	}
+	if _, err := c.CustomTaskSources(); err != nil {
+		return err
+	}
+	if c.PublicBaseURL != "" {
+		base, err := url.Parse(c.PublicBaseURL)
+		if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+			return fmt.Errorf("PUBLIC_BASE_URL must be an absolute http or https URL")
+		}
+	}
+	if c.ProjectAtRiskPercent < 1 || c.ProjectAtRiskPercent > 100 || c.ProjectOverduePercent < 1 || c.ProjectOverduePercent > 100 {
+		return fmt.Errorf("PROJECT_AT_RISK_PERCENT and PROJECT_OVERDUE_PERCENT must be between 1 and 100")
+	}
+	if c.ProjectAtRiskPercent > c.ProjectOverduePercent {
+		return fmt.Errorf("PROJECT_AT_RISK_PERCENT must not exceed PROJECT_OVERDUE_PERCENT")
+	}
+	if _, err := c.DigestClock(); err != nil {
+		return err
+	}
+	switch c.AvatarStyle {
+	case AvatarStyleGravatar, AvatarStyleInitials, AvatarStyleNone:
+	default:
+		return fmt.Errorf("AVATAR_STYLE must be gravatar, initials or none")
+	}
+	if c.AvatarMaxBytes <= 0 {
+		return fmt.Errorf("AVATAR_MAX_BYTES must be a positive integer")
+	}
+	if c.PaginationDefault <= 0 || c.PaginationMax <= 0 {
+		return fmt.Errorf("PAGINATION_DEFAULT and PAGINATION_MAX must be positive integers")
+	}
+	if c.PaginationDefault > c.PaginationMax {
+		return fmt.Errorf("PAGINATION_DEFAULT must not exceed PAGINATION_MAX")
+	}
+	if c.CacheRefreshSec <= 0 {
+		return fmt.Errorf("CACHE_REFRESH_SEC must be a positive integer")
+	}
+	if c.CompressionMinBytes < 0 {
+		return fmt.Errorf("COMPRESSION_MIN_BYTES must not be negative")
+	}
+	if c.PasswordHistorySize < 0 {
+		return fmt.Errorf("PASSWORD_HISTORY_SIZE must not be negative")
+	}
+	if c.TwoFactorEncryptionKey != "" && len(c.TwoFactorEncryptionKey) < MinJWTSecretLength {
+		return fmt.Errorf("TWO_FACTOR_ENCRYPTION_KEY must be at least %d characters", MinJWTSecretLength)
+	}
+	if err := c.ValidateServerTimeouts(); err != nil {
+		return err
+	}
+	return c.ValidatePool()
+}
+
+// PreviousJWTSecrets returns the retired secrets in JWTSecretPrevious, skipping blanks
+func (c *Config) PreviousJWTSecrets() []string {
+	var secrets []string
+	for _, secret := range strings.Split(c.JWTSecretPrevious, ",") {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// TOTPKey returns the key TOTP secrets are encrypted with
+func (c *Config) TOTPKey() string {
+	if c.TwoFactorEncryptionKey != "" {
+		return c.TwoFactorEncryptionKey
+	}
+	return c.JWTSecret
+}
+
+// PreviousTOTPKeys returns the keys TOTP secrets may have been encrypted with before a
+// rotation. Without TwoFactorEncryptionKey the secrets are keyed off JWT_SECRET, so its
+// previous values are tried.
+func (c *Config) PreviousTOTPKeys() []string {
+	if c.TwoFactorEncryptionKey != "" {
+		return nil
+	}
+	return c.PreviousJWTSecrets()
+}
+
+// StatusTransitions returns the allowed task status transitions, parsing
+// TaskStatusTransitions when it is set
+func (c *Config) StatusTransitions() (map[string][]string, error) {
+	if c.TaskStatusTransitions == "" {
+		return DefaultTaskStatusTransitions, nil
+	}
+	var transitions map[string][]string
+	if err := json.Unmarshal([]byte(c.TaskStatusTransitions), &transitions); err != nil || transitions == nil {
+		return nil, fmt.Errorf("TASK_STATUS_TRANSITIONS must be a JSON object mapping each status to a list of statuses")
+	}
+	return transitions, nil
+}
+
+// TaskListViews returns the default task list view for each role, applying TaskDefaultViews
+// over DefaultTaskListViews when it is set
+func (c *Config) TaskListViews() (map[string]string, error) {
+	views := make(map[string]string, len(DefaultTaskListViews))
+	for role, view := range DefaultTaskListViews {
+		views[role] = view
+	}
+	if c.TaskDefaultViews == "" {
+		return views, nil
+	}
+
+	var overrides map[string]string
+	if err := json.Unmarshal([]byte(c.TaskDefaultViews), &overrides); err != nil || overrides == nil {
+		return nil, fmt.Errorf("TASK_DEFAULT_VIEWS must be a JSON object mapping roles to task views")
+	}
+	for role, view := range overrides {
+		if !IsTaskView(view) {
+			return nil, fmt.Errorf("TASK_DEFAULT_VIEWS: %q is not a task view (use all, mine or department_open)", view)
+		}
+		views[role] = view
+	}
+	return views, nil
+}
+
+// CustomTaskSources returns the sources in TaskSources, skipping blanks
+func (c *Config) CustomTaskSources() ([]string, error) {
+	var sources []string
+	for _, source := range strings.Split(c.TaskSources, ",") {
+		if source = strings.TrimSpace(source); source == "" {
+			continue
+		}
+		if len(source) > MaxTaskSourceLength {
+			return nil, fmt.Errorf("TASK_SOURCES: %q is longer than %d characters", source, MaxTaskSourceLength)
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// DigestClock returns the time of day the manager digest is sent, as an offset from UTC
+// midnight. An empty DigestTime uses DefaultDigestTime.
+func (c *Config) DigestClock() (time.Duration, error) {
+	value := c.DigestTime
+	if value == "" {
+		value = DefaultDigestTime
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("DIGEST_TIME must be a time of day in HH:MM (24-hour, UTC)")
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// IsTaskView reports whether view names a task list view
+func IsTaskView(view string) bool {
+	switch view {
+	case TaskViewAll, TaskViewMine, TaskViewDepartmentOpen:
+		return true
+	}
+	return false
+}
+
+// envInt parses an integer environment variable, returning 0 when unset or invalid
+func envInt(key string) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// envBool parses a boolean environment variable, returning false when unset or invalid
+func envBool(key string) bool {
+	return envBoolDefault(key, false)
+}
+
+// envBoolDefault is envBool with a fallback for unset or invalid values
+func envBoolDefault(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// envStringDefault reads a string environment variable, with a fallback when unset or empty
+func envStringDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envIntDefault is envInt with a fallback for unset variables. Set but invalid values still
+// come back as 0 so validation reports them instead of silently using the default.
+func envIntDefault(key string, fallback int) int {
+	if _, ok := os.LookupEnv(key); !ok {
+		return fallback
 	}
+	return envInt(key)
 }
@@ -4,6 +4,7 @@
 package config
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -11,12 +12,16 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupDatabase(dsn string) (*gorm.DB, error) {
-	if dsn == "" {
+// SetupDatabase connects to cfg.DatabaseURL and applies the configured pool settings
+func SetupDatabase(cfg *Config) (*gorm.DB, error) {
+	if cfg.DatabaseURL == "" {
 		return nil, fmt.Errorf("DATABASE_URL environment variable not set")
 	}
+	if err := cfg.ValidatePool(); err != nil {
+		return nil, err
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -29,12 +34,34 @@ func SetupDatabase(dsn string) (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
 	}
-
-	// Connection pool configuration (matches PgBouncer settings)
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(1 * time.Hour)
-	sqlDB.SetConnMaxIdleTime(10 * time.Minute)
+	ApplyPoolSettings(sqlDB, cfg)
 
 	return db, nil
 }
+
+// ValidatePool checks that every connection pool setting is positive
+func (c *Config) ValidatePool() error {
+	settings := []struct {
+		name  string
+		value int
+	}{
+		{"DB_MAX_OPEN_CONNS", c.DBMaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", c.DBMaxIdleConns},
+		{"DB_CONN_MAX_LIFETIME_MIN", c.DBConnMaxLifetimeMin},
+		{"DB_CONN_MAX_IDLE_TIME_MIN", c.DBConnMaxIdleTimeMin},
+	}
+	for _, setting := range settings {
+		if setting.value <= 0 {
+			return fmt.Errorf("%s must be a positive integer", setting.name)
+		}
+	}
+	return nil
+}
+
+// ApplyPoolSettings sizes the connection pool (keep in line with PgBouncer's pool settings)
+func ApplyPoolSettings(sqlDB *sql.DB, cfg *Config) {
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetimeMin) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.DBConnMaxIdleTimeMin) * time.Minute)
+}
@@ -0,0 +1,95 @@
+// ABOUTME: Loading of the RSA key pair used when JWT_ALG is RS256
+// ABOUTME: Keys come from PEM environment variables or files; retired public keys may be kept for rotation
+
+package config
+
+import (
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing tokens
+const minRSAKeyBits = 2048
+
+// RSAKeys loads and parses the RS256 key pair. The private key is required; the public key
+// is derived from it when neither JWT_PUBLIC_KEY nor JWT_PUBLIC_KEY_PATH is set.
+func (c *Config) RSAKeys() (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	privatePEM, err := pemSetting("JWT_PRIVATE_KEY", c.JWTPrivateKey, c.JWTPrivateKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if privatePEM == nil {
+		return nil, nil, fmt.Errorf("JWT_ALG RS256 requires JWT_PRIVATE_KEY or JWT_PRIVATE_KEY_PATH")
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("JWT_PRIVATE_KEY is not a PEM encoded RSA private key: %w", err)
+	}
+	if privateKey.N.BitLen() < minRSAKeyBits {
+		return nil, nil, fmt.Errorf("JWT_PRIVATE_KEY must be at least %d bits", minRSAKeyBits)
+	}
+
+	publicPEM, err := pemSetting("JWT_PUBLIC_KEY", c.JWTPublicKey, c.JWTPublicKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if publicPEM == nil {
+		return privateKey, &privateKey.PublicKey, nil
+	}
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("JWT_PUBLIC_KEY is not a PEM encoded RSA public key: %w", err)
+	}
+	if !publicKey.Equal(&privateKey.PublicKey) {
+		return nil, nil, fmt.Errorf("JWT_PUBLIC_KEY does not match JWT_PRIVATE_KEY")
+	}
+	return privateKey, publicKey, nil
+}
+
+// PreviousRSAPublicKeys parses the retired public keys kept for rotation, in the order given
+func (c *Config) PreviousRSAPublicKeys() ([]*rsa.PublicKey, error) {
+	data, err := pemSetting("JWT_PREVIOUS_PUBLIC_KEYS", c.JWTPreviousPublicKeys, c.JWTPreviousPublicKeysPath)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var keys []*rsa.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem.EncodeToMemory(block))
+		if err != nil {
+			return nil, fmt.Errorf("JWT_PREVIOUS_PUBLIC_KEYS key %d is not a PEM encoded RSA public key: %w", len(keys)+1, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 || strings.TrimSpace(string(data)) != "" {
+		return nil, fmt.Errorf("JWT_PREVIOUS_PUBLIC_KEYS must be one or more PEM encoded RSA public keys")
+	}
+	return keys, nil
+}
+
+// pemSetting returns a PEM key given inline or as a file path, nil when neither is set.
+// Inline keys may use \n escapes in place of newlines, since env files can't hold them.
+func pemSetting(name, inline, path string) ([]byte, error) {
+	switch {
+	case inline != "" && path != "":
+		return nil, fmt.Errorf("set only one of %s and %s_PATH", name, name)
+	case inline != "":
+		return []byte(strings.ReplaceAll(inline, `\n`, "\n")), nil
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s_PATH: %w", name, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,49 @@
+// ABOUTME: HTTP server construction with configured connection timeouts
+// ABOUTME: Bounds how long clients may take to send requests and hold idle connections
+
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultPort is where the server listens when PORT is unset
+const DefaultPort = "8080"
+
+// NewServer returns an HTTP server for handler on cfg.Port with the configured timeouts.
+// Slow or stalled clients are cut off instead of holding connections open indefinitely.
+func NewServer(cfg *Config, handler http.Handler) *http.Server {
+	port := cfg.Port
+	if port == "" {
+		port = DefaultPort
+	}
+	return &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Duration(cfg.ServerReadHeaderTimeoutSec) * time.Second,
+		ReadTimeout:       time.Duration(cfg.ServerReadTimeoutSec) * time.Second,
+		WriteTimeout:      time.Duration(cfg.ServerWriteTimeoutSec) * time.Second,
+		IdleTimeout:       time.Duration(cfg.ServerIdleTimeoutSec) * time.Second,
+	}
+}
+
+// ValidateServerTimeouts checks that every server timeout is positive
+func (c *Config) ValidateServerTimeouts() error {
+	settings := []struct {
+		name  string
+		value int
+	}{
+		{"SERVER_READ_HEADER_TIMEOUT_SEC", c.ServerReadHeaderTimeoutSec},
+		{"SERVER_READ_TIMEOUT_SEC", c.ServerReadTimeoutSec},
+		{"SERVER_WRITE_TIMEOUT_SEC", c.ServerWriteTimeoutSec},
+		{"SERVER_IDLE_TIMEOUT_SEC", c.ServerIdleTimeoutSec},
+	}
+	for _, setting := range settings {
+		if setting.value <= 0 {
+			return fmt.Errorf("%s must be a positive integer", setting.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// ABOUTME: Helpers for writing activity log entries from handlers
+// ABOUTME: Entries are written in the caller's transaction so they commit with the change
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// Activity log entity types and actions
+const (
+	activityEntityTask    = "task"
+	activityEntityProject = "project"
+
+	activityOwnershipTransferred = "ownership_transferred"
+)
+
+// recordActivity writes an activity log entry for entityID using tx
+func recordActivity(tx *gorm.DB, actorID, entityType, entityID, action string, details map[string]interface{}) error {
+	entry := models.ActivityLog{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+	}
+	if actorID != "" {
+		entry.ActorID = &actorID
+	}
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		detailsJSON := string(encoded)
+		entry.Details = &detailsJSON
+	}
+	return tx.Create(&entry).Error
+}
+
+// sameOptionalID reports whether two optional IDs are both unset or equal
+func sameOptionalID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -4,10 +4,16 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
 	"github.com/synapse/backend/config"
 	"github.com/synapse/backend/models"
 	"github.com/synapse/backend/utils"
@@ -27,13 +33,28 @@ type RegisterRequest struct {
 	Email      string  `json:"email" binding:"required,email"`
 	Password   string  `json:"password" binding:"required,min=8,max=72"`
 	FullName   string  `json:"full_name" binding:"required"`
+	Username   *string `json:"username,omitempty" binding:"omitempty,min=3,max=50"`
 	Department *string `json:"department_id,omitempty"`
 }
 
-// LoginRequest represents the login request body
+// usernamePattern allows lowercase letters, digits, dots, underscores and hyphens, starting with a letter or digit
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// maxUsernameLength matches the users.username column
+const maxUsernameLength = 50
+
+// LoginRequest represents the login request body. Identifier matches either an email or a
+// username; Email is still accepted for older clients.
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Identifier string `json:"identifier"`
+	Email      string `json:"email" binding:"omitempty,email"`
+	Password   string `json:"password" binding:"required"`
+}
+
+// ChangePasswordRequest represents the change password request body
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
 }
 
 // RefreshRequest represents the token refresh request body
@@ -53,13 +74,13 @@ type AuthResponse struct {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
 		return
 	}
 
 	// Validate password requirements
 	if err := utils.IsValidPassword(req.Password); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", err.Error(), nil)
 		return
 	}
 
@@ -70,8 +91,33 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// Use the requested username, or derive a free one from the email prefix
+	var username string
+	if req.Username != nil {
+		username = strings.ToLower(*req.Username)
+		if !usernamePattern.MatchString(username) {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Username may only contain letters, digits, dots, underscores and hyphens, and must start with a letter or digit", nil)
+			return
+		}
+		var count int64
+		if err := h.db.Model(&models.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate username", nil)
+			return
+		}
+		if count > 0 {
+			utils.RespondError(c, http.StatusConflict, "USERNAME_EXISTS", "Username is already taken", nil)
+			return
+		}
+	} else {
+		var err error
+		if username, err = h.uniqueUsername(usernameFromEmail(req.Email)); err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate username", nil)
+			return
+		}
+	}
+
 	// Hash password
-	hashedPassword, err := utils.HashPassword(req.Password)
+	hashedPassword, err := utils.HashPasswordWithCost(req.Password, config.GetConfig().BcryptCost)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to process password", nil)
 		return
@@ -81,10 +127,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	hashedPasswordPtr := &hashedPassword
 	user := models.User{
 		Email:        strings.ToLower(req.Email),
-		Username:     strings.Split(strings.ToLower(req.Email), "@")[0], // Use email prefix as username
+		Username:     username,
 		PasswordHash: hashedPasswordPtr,
 		FullName:     req.FullName,
-		Role:         "Member", // Default role
+		Role:         auth.RoleMember, // Default role
 		DepartmentID: req.Department,
 		IsActive:     true,
 	}
@@ -94,42 +140,77 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Generate tokens
-	cfg := config.GetConfig()
-	accessToken, err := utils.GenerateJWT(&user, cfg.JWTSecret, 24) // 24 hours
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate access token", nil)
+	// Sign the new user in on this client
+	tokens, ok := h.issueTokens(c, &user)
+	if !ok {
 		return
 	}
+	utils.RespondSuccess(c, http.StatusCreated, tokens, "User registered successfully")
+}
 
-	refreshToken, err := utils.GenerateRefreshToken(&user, cfg.JWTSecret)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate refresh token", nil)
-		return
+// usernameFromEmail turns an email's local part into a username, dropping characters
+// usernamePattern doesn't allow
+func usernameFromEmail(email string) string {
+	local := strings.ToLower(strings.SplitN(email, "@", 2)[0])
+	var b strings.Builder
+	for _, r := range local {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
 	}
+	username := strings.TrimLeft(b.String(), "._-")
+	if len(username) < 3 {
+		username += "user"
+	}
+	return username
+}
 
-	// Clear password hash before returning
-	user.PasswordHash = nil
+// uniqueUsername returns base if it is free, otherwise base with the lowest numeric suffix
+// that is, e.g. john, john2, john3
+func (h *AuthHandler) uniqueUsername(base string) (string, error) {
+	// Leave room for a suffix within the column length
+	if len(base) > maxUsernameLength-4 {
+		base = base[:maxUsernameLength-4]
+	}
 
-	utils.RespondSuccess(c, http.StatusCreated, AuthResponse{
-		User:         &user,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    86400, // 24 hours in seconds
-	}, "User registered successfully")
+	var taken []string
+	if err := h.db.Model(&models.User{}).
+		Where("username LIKE ?", escapeLike(base)+"%").
+		Pluck("username", &taken).Error; err != nil {
+		return "", err
+	}
+	takenSet := make(map[string]bool, len(taken))
+	for _, name := range taken {
+		takenSet[name] = true
+	}
+
+	username := base
+	for suffix := 2; takenSet[username]; suffix++ {
+		username = base + strconv.Itoa(suffix)
+	}
+	return username, nil
 }
 
 // Login authenticates a user and returns JWT tokens
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	identifier := strings.ToLower(strings.TrimSpace(req.Identifier))
+	if identifier == "" {
+		identifier = strings.ToLower(req.Email)
+	}
+	if identifier == "" {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "identifier or email is required", nil)
 		return
 	}
 
-	// Find user by email and explicitly select password_hash
+	// Find user by email or username and explicitly select password_hash
 	var user models.User
-	if err := h.db.Select("*").Where("email = ?", strings.ToLower(req.Email)).First(&user).Error; err != nil {
+	if err := h.db.Select("*").Where("email = ? OR username = ?", identifier, identifier).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.RespondError(c, http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid email or password", nil)
 			return
@@ -154,43 +235,121 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Generate tokens
+	// Users with two-factor authentication finish logging in at /auth/2fa/login
 	cfg := config.GetConfig()
-	accessToken, err := utils.GenerateJWT(&user, cfg.JWTSecret, 24) // 24 hours
+	if user.TOTPEnabled {
+		if twoFactorLocked(user) {
+			respondTwoFactorLocked(c)
+			return
+		}
+		challenge, err := utils.GenerateTwoFactorChallenge(user.ID, user.TOTPFailedAttempts, cfg.JWTSecret, twoFactorChallengeTTL)
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate two-factor challenge", nil)
+			return
+		}
+		utils.RespondSuccess(c, http.StatusAccepted, TwoFactorChallengeResponse{
+			Code:           "2FA_REQUIRED",
+			ChallengeToken: challenge,
+			ExpiresIn:      int(twoFactorChallengeTTL.Seconds()),
+		}, "Two-factor code required")
+		return
+	}
+
+	// Record the login, upgrading hashes made at an older, lower cost in the same save
+	h.recordLogin(&user, req.Password, cfg.BcryptCost)
+	h.respondWithTokens(c, user, "Login successful")
+}
+
+// respondWithTokens answers a completed login with fresh access and refresh tokens for user
+func (h *AuthHandler) respondWithTokens(c *gin.Context, user models.User, message string) {
+	tokens, ok := h.issueTokens(c, &user)
+	if !ok {
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, tokens, message)
+}
+
+// issueTokens starts a session for user on the requesting client and returns its tokens,
+// responding with an error itself when that fails
+func (h *AuthHandler) issueTokens(c *gin.Context, user *models.User) (*AuthResponse, bool) {
+	session, err := startSession(h.db, c, user.ID)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to start session", nil)
+		return nil, false
+	}
+	return sessionTokens(c, user, session.ID)
+}
+
+// sessionTokens signs an access token and a refresh token for user tied to sessionID
+func sessionTokens(c *gin.Context, user *models.User, sessionID string) (*AuthResponse, bool) {
+	keys, err := utils.LoadJWTKeys(config.GetConfig())
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load signing keys", nil)
+		return nil, false
+	}
+	accessToken, err := utils.GenerateSessionJWT(user, keys, 24, sessionID) // 24 hours
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate access token", nil)
-		return
+		return nil, false
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(&user, cfg.JWTSecret)
+	refreshToken, err := utils.GenerateRefreshToken(user, keys, sessionID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate refresh token", nil)
-		return
+		return nil, false
 	}
 
 	// Clear password hash before returning
 	user.PasswordHash = nil
 
-	utils.RespondSuccess(c, http.StatusOK, AuthResponse{
-		User:         &user,
+	return &AuthResponse{
+		User:         user,
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiresIn:    86400, // 24 hours in seconds
-	}, "Login successful")
+	}, true
 }
 
-// Refresh generates a new access token using a valid refresh token
+// recordLogin stamps last_login and, while the plain text password is at hand, rehashes it
+// when the stored hash is below cost; an empty password skips the rehash. Failures are
+// logged rather than returned, since the login itself already succeeded and the next login
+// will retry.
+func (h *AuthHandler) recordLogin(user *models.User, password string, cost int) {
+	now := time.Now().UTC()
+	updates := map[string]interface{}{"last_login": now}
+
+	var hashedPassword string
+	if password != "" && user.PasswordHash != nil && utils.PasswordNeedsRehash(*user.PasswordHash, cost) {
+		var err error
+		if hashedPassword, err = utils.HashPasswordWithCost(password, cost); err != nil {
+			log.Printf("failed to upgrade password hash for user %s: %v", user.ID, err)
+		} else {
+			updates["password_hash"] = hashedPassword
+		}
+	}
+
+	if err := h.db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumns(updates).Error; err != nil {
+		log.Printf("failed to record login for user %s: %v", user.ID, err)
+		return
+	}
+	user.LastLogin = &now
+	if hashedPassword != "" {
+		user.PasswordHash = &hashedPassword
+	}
+}
+
+// Refresh generates a new access token using a valid refresh token. The token's session
+// must not have been revoked; each refresh extends the session and rotates the refresh token.
 func (h *AuthHandler) Refresh(c *gin.Context) {
 	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
 		return
 	}
 
-	// Validate refresh token
-	cfg := config.GetConfig()
-	claims, err := utils.ValidateJWT(req.RefreshToken, cfg.JWTSecret)
-	if err != nil {
+	// Validate refresh token; tokens issued before sessions existed carry no session
+	claims, err := validateRefreshToken(req.RefreshToken)
+	if err != nil || claims.SessionID == "" {
 		utils.RespondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or expired refresh token", nil)
 		return
 	}
@@ -212,38 +371,126 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	// Generate new access token
-	accessToken, err := utils.GenerateJWT(&user, cfg.JWTSecret, 24) // 24 hours
+	active, err := touchSession(h.db, user.ID, claims.SessionID)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate access token", nil)
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check session", nil)
 		return
 	}
+	if !active {
+		utils.RespondError(c, http.StatusUnauthorized, "SESSION_REVOKED", "Session has been revoked or has expired", nil)
+		return
+	}
+
+	tokens, ok := sessionTokens(c, &user, claims.SessionID)
+	if !ok {
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, tokens, "Token refreshed successfully")
+}
 
-	// Optionally generate new refresh token (rotate refresh tokens)
-	newRefreshToken, err := utils.GenerateRefreshToken(&user, cfg.JWTSecret)
+// validateRefreshToken checks a refresh token against the configured signing keys
+func validateRefreshToken(token string) (*utils.JWTClaims, error) {
+	keys, err := utils.LoadJWTKeys(config.GetConfig())
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate refresh token", nil)
+		return nil, err
+	}
+	return utils.ValidateJWT(token, keys)
+}
+
+// ChangePassword replaces the caller's password after verifying the current one and logs out
+// the caller's other sessions. The new password may not match the caller's last
+// PASSWORD_HISTORY_SIZE passwords.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
 		return
 	}
 
-	// Clear password hash before returning
-	user.PasswordHash = nil
+	// Validate password requirements
+	if err := utils.IsValidPassword(req.NewPassword); err != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+	if req.NewPassword == req.CurrentPassword {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "New password must be different from the current password", nil)
+		return
+	}
 
-	utils.RespondSuccess(c, http.StatusOK, AuthResponse{
-		User:         &user,
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
-		ExpiresIn:    86400, // 24 hours in seconds
-	}, "Token refreshed successfully")
+	userID, _ := c.Get("user_id")
+
+	// Explicitly select password_hash
+	var user models.User
+	if err := h.db.Select("*").First(&user, "id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to query user", nil)
+		return
+	}
+
+	// Verify current password
+	if user.PasswordHash == nil || utils.VerifyPassword(*user.PasswordHash, req.CurrentPassword) != nil {
+		utils.RespondError(c, http.StatusUnauthorized, "INVALID_CREDENTIALS", "Current password is incorrect", nil)
+		return
+	}
+
+	// Recent passwords can't be reused
+	cfg := config.GetConfig()
+	reused, err := passwordReused(h.db, user.ID, req.NewPassword, cfg.PasswordHistorySize)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check password history", nil)
+		return
+	}
+	if reused {
+		message := fmt.Sprintf("New password must not match any of your last %d passwords", cfg.PasswordHistorySize)
+		utils.RespondError(c, http.StatusUnprocessableEntity, "PASSWORD_REUSED", message, []utils.ErrorDetail{{Field: "new_password", Message: message}})
+		return
+	}
+
+	hashedPassword, err := utils.HashPasswordWithCost(req.NewPassword, cfg.BcryptCost)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to process password", nil)
+		return
+	}
+
+	// Other sessions may belong to whoever learned the old password, so they end with it
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := replacePassword(tx, user, hashedPassword, cfg.PasswordHistorySize); err != nil {
+			return err
+		}
+		_, err := revokeOtherSessions(tx, user.ID, c.GetString("session_id"))
+		return err
+	}); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update password", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Password changed successfully")
 }
 
-// Logout invalidates the current user session
+// LogoutRequest optionally names the refresh token whose session should end
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Logout ends the session of the given refresh token, so neither it nor the session's
+// access tokens work any longer.
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// For JWT-based auth, logout is typically handled client-side by removing tokens
-	// In a more sophisticated setup, you might:
-	// - Add token to a blacklist in Redis
-	// - Store active sessions in database and remove on logout
-	// For now, we'll just return success
+	var req LogoutRequest
+	// The body is optional; without a refresh token there is no session to end
+	_ = c.ShouldBindJSON(&req)
+
+	if req.RefreshToken != "" {
+		claims, err := validateRefreshToken(req.RefreshToken)
+		if err == nil && claims.SessionID != "" {
+			if _, err := revokeSessions(h.db.Where("id = ?", claims.SessionID), claims.UserID); err != nil {
+				utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to end session", nil)
+				return
+			}
+		}
+	}
 	utils.RespondSuccess(c, http.StatusOK, nil, "Logout successful")
 }
 
@@ -0,0 +1,158 @@
+// ABOUTME: Avatar upload handler storing profile images in the configured object store
+// ABOUTME: Accepts PNG, JPEG, GIF and WebP images and replaces the user's previous upload
+
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/storage"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// avatarFormField is the multipart field the image is sent in
+const avatarFormField = "avatar"
+
+// avatarExtensions maps the image types accepted as avatars to their file extensions
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type AvatarHandler struct {
+	db    *gorm.DB
+	store storage.Store
+}
+
+func NewAvatarHandler(db *gorm.DB, store storage.Store) *AvatarHandler {
+	return &AvatarHandler{db: db, store: store}
+}
+
+// UploadAvatar stores the image sent as multipart field "avatar" and sets it as the :id
+// user's avatar_url. Users may change their own avatar and Admins anyone's. The type is
+// sniffed from the content rather than trusted from the client, and the previous uploaded
+// avatar is deleted once the new one is saved.
+func (h *AvatarHandler) UploadAvatar(c *gin.Context) {
+	principal := auth.FromContext(c)
+
+	var user models.User
+	if err := h.db.First(&user, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch user", nil)
+		return
+	}
+	if !auth.CanModifyUser(principal, user) {
+		respondDenied(c, hiddenUser, "You don't have permission to update this user", func() (bool, error) {
+			return auth.CanAccessUser(principal, user), nil
+		})
+		return
+	}
+
+	// Leave room for the multipart framing around the image
+	maxBytes := int64(config.GetConfig().AvatarMaxBytes)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+64<<10)
+	header, err := c.FormFile(avatarFormField)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondAvatarTooLarge(c, maxBytes)
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ErrorDetail{{Field: avatarFormField, Message: "Send the image as multipart form field avatar"}})
+		return
+	}
+	if header.Size > maxBytes {
+		respondAvatarTooLarge(c, maxBytes)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to read upload", nil)
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err == io.EOF {
+		utils.RespondValidationError(c, []utils.ErrorDetail{{Field: avatarFormField, Message: "Avatar image is empty"}})
+		return
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to read upload", nil)
+		return
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	extension, ok := avatarExtensions[contentType]
+	if !ok {
+		utils.RespondError(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "Avatar must be a PNG, JPEG, GIF or WebP image", nil)
+		return
+	}
+
+	// A fresh key per upload keeps caches from serving the old image
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to store avatar", nil)
+		return
+	}
+	key := avatarKeyPrefix(user.ID) + hex.EncodeToString(suffix) + extension
+
+	ctx := c.Request.Context()
+	avatarURL, err := h.store.Put(ctx, key, contentType, io.MultiReader(bytes.NewReader(head), file))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to store avatar", nil)
+		return
+	}
+
+	previous := user.AvatarURL
+	if err := h.db.Model(&user).Update("avatar_url", avatarURL).Error; err != nil {
+		if err := h.store.Delete(ctx, key); err != nil {
+			log.Printf("failed to remove unused avatar upload %s: %v", key, err)
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update avatar", nil)
+		return
+	}
+	user.AvatarURL = &avatarURL
+
+	// External avatar URLs were never ours to delete, and only this user's own uploads are
+	if previous != nil {
+		if previousKey, ok := h.store.KeyFromURL(*previous); ok && strings.HasPrefix(previousKey, avatarKeyPrefix(user.ID)) {
+			if err := h.store.Delete(ctx, previousKey); err != nil {
+				log.Printf("failed to remove previous avatar upload %s: %v", previousKey, err)
+			}
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, user, "Avatar updated successfully")
+}
+
+// avatarKeyPrefix starts the storage key of every avatar uploaded for userID
+func avatarKeyPrefix(userID string) string {
+	return "avatars/" + userID + "-"
+}
+
+// respondAvatarTooLarge rejects an image over AVATAR_MAX_BYTES
+func respondAvatarTooLarge(c *gin.Context, maxBytes int64) {
+	utils.RespondError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE",
+		fmt.Sprintf("Avatar images may be at most %d bytes", maxBytes), nil)
+}
@@ -0,0 +1,55 @@
+// ABOUTME: Shared bookkeeping for bulk endpoints that act on a list of ids
+// ABOUTME: Dedupes the ids and splits them into acted on, forbidden and not found for the report
+
+package handlers
+
+// bulkAccess is what the caller may do with one loaded bulk target
+type bulkAccess int
+
+const (
+	bulkHidden  bulkAccess = iota // Can't see it; reported as not found
+	bulkVisible                   // Can see it but not act on it; reported as forbidden
+	bulkAllowed                   // Can act on it
+)
+
+// bulkTargets splits a bulk request's ids, in request order, by what the caller may do with them
+type bulkTargets struct {
+	Allowed   []string
+	Forbidden []string
+	NotFound  []string
+}
+
+// uniqueIDs returns ids with repeats dropped, keeping the order they were requested in
+func uniqueIDs(ids []string) []string {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// splitBulkTargets sorts ids by their access. Ids missing from access weren't found, and are
+// reported the same as hidden ones so bulk requests can't probe for ids.
+func splitBulkTargets(ids []string, access map[string]bulkAccess) bulkTargets {
+	targets := bulkTargets{Allowed: []string{}, Forbidden: []string{}, NotFound: []string{}}
+	for _, id := range ids {
+		switch access[id] {
+		case bulkAllowed:
+			targets.Allowed = append(targets.Allowed, id)
+		case bulkVisible:
+			targets.Forbidden = append(targets.Forbidden, id)
+		default:
+			targets.NotFound = append(targets.NotFound, id)
+		}
+	}
+	return targets
+}
+
+// skipped counts the targets the bulk action left alone
+func (t bulkTargets) skipped() int {
+	return len(t.Forbidden) + len(t.NotFound)
+}
@@ -0,0 +1,168 @@
+// ABOUTME: iCalendar feed of a user's task due dates for calendar app subscriptions
+// ABOUTME: Authenticated by a signed feed token in the query string instead of a Bearer header
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// icsTimeFormat is the UTC DATE-TIME form RFC 5545 uses
+const icsTimeFormat = "20060102T150405Z"
+
+type CalendarHandler struct {
+	db *gorm.DB
+}
+
+func NewCalendarHandler(db *gorm.DB) *CalendarHandler {
+	return &CalendarHandler{db: db}
+}
+
+// FeedTokenResponse is the caller's feed token for calendar subscriptions
+type FeedTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// GetFeedToken returns the caller's token for ?token= on calendar feeds. It doesn't expire;
+// whoever holds it sees the feeds the caller may see, checked against their current role.
+func (h *CalendarHandler) GetFeedToken(c *gin.Context) {
+	token, err := utils.GenerateFeedToken(c.GetString("user_id"), config.GetConfig().JWTSecret)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate feed token", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, FeedTokenResponse{Token: token}, "Feed token generated successfully")
+}
+
+// GetUserCalendar renders the :id user's dated tasks, created by or assigned to them, as an
+// iCalendar feed. The token's owner must be allowed to view that user's tasks.
+func (h *CalendarHandler) GetUserCalendar(c *gin.Context) {
+	cfg := config.GetConfig()
+	viewerID, err := utils.ValidateFeedToken(c.Query("token"), cfg.JWTSecret, cfg.PreviousJWTSecrets()...)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid feed token", nil)
+		return
+	}
+
+	db := h.db.WithContext(c.Request.Context())
+
+	// The token is long-lived, so the viewer's current account decides what it may read
+	var viewer models.User
+	if err := db.First(&viewer, "id = ?", viewerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid feed token", nil)
+			return
+		}
+		respondQueryError(c, err, "Failed to fetch user")
+		return
+	}
+	if !viewer.IsActive {
+		utils.RespondError(c, http.StatusForbidden, "ACCOUNT_DISABLED", "Account has been disabled", nil)
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil)
+			return
+		}
+		respondQueryError(c, err, "Failed to fetch user")
+		return
+	}
+
+	// Same rule as GetUserTasks
+	principal := auth.PrincipalForUser(viewer)
+	if !auth.CanViewUserWork(principal, user) {
+		respondDenied(c, hiddenUser, "You don't have permission to view this user's tasks", func() (bool, error) {
+			return auth.CanAccessUser(principal, user), nil
+		})
+		return
+	}
+
+	var tasks []models.Task
+	if err := db.
+		Where("creator_id = ? OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", user.ID, user.ID).
+		Where("due_date IS NOT NULL").
+		Order("due_date ASC, id ASC").
+		Find(&tasks).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch tasks")
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="tasks.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderTaskCalendar(user, tasks, viewer.ParsedPreferences().Timezone, time.Now())))
+}
+
+// renderTaskCalendar builds a VCALENDAR with one VEVENT per dated task, ending on each task's
+// due date. UIDs are derived from task ids so calendar apps update events in place. timezone,
+// when set, is the subscriber's preferred zone.
+func renderTaskCalendar(user models.User, tasks []models.Task, timezone string, now time.Time) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeICSLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Synapse//Task Due Dates//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICSText("Tasks for "+user.FullName))
+	// Times stay in UTC; the hint lets calendar apps show them on the subscriber's own days
+	if timezone != "" {
+		line("X-WR-TIMEZONE", timezone)
+	}
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", "task-"+task.ID+"@synapse")
+		line("DTSTAMP", now.UTC().Format(icsTimeFormat))
+		line("DTSTART", task.DueDate.UTC().Format(icsTimeFormat))
+		line("SUMMARY", escapeICSText(task.Title))
+		if task.Description != nil && *task.Description != "" {
+			line("DESCRIPTION", escapeICSText(*task.Description))
+		}
+		line("STATUS", "CONFIRMED")
+		line("CATEGORIES", escapeICSText(task.Status))
+		line("LAST-MODIFIED", task.UpdatedAt.UTC().Format(icsTimeFormat))
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// escapeICSText escapes a TEXT value per RFC 5545 section 3.3.11
+func escapeICSText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// writeICSLine writes a content line, folding it at 75 octets without splitting a UTF-8 character
+func writeICSLine(b *strings.Builder, line string) {
+	const limit = 75
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			// The leading space of a continuation line counts toward its length
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
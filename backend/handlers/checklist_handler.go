@@ -0,0 +1,346 @@
+// ABOUTME: Checklist item handlers for acceptance sub-items within a task
+// ABOUTME: Handles adding, editing, toggling, reordering and deleting items
+
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateChecklistItemRequest represents the checklist item creation request body
+type CreateChecklistItemRequest struct {
+	Text string `json:"text" binding:"required,max=500"`
+	Done bool   `json:"done"`
+}
+
+// UpdateChecklistItemRequest represents the checklist item update request body
+type UpdateChecklistItemRequest struct {
+	Text *string `json:"text" binding:"omitempty,min=1,max=500"`
+	Done *bool   `json:"done"`
+}
+
+// MoveChecklistItemRequest represents the checklist reorder request body
+type MoveChecklistItemRequest struct {
+	Position *int `json:"position" binding:"required,min=0"`
+}
+
+// GetChecklist returns a task's checklist items ordered by position
+func (h *TaskHandler) GetChecklist(c *gin.Context) {
+	task, err := h.tasks.FindByID(c.Param("id"))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task", nil)
+		return
+	}
+
+	tasks := []models.Task{task}
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return
+	}
+	task = tasks[0]
+
+	if !auth.CanAccessTask(auth.FromContext(c), task) {
+		respondHidden(c, hiddenTask, "You don't have permission to view this task")
+		return
+	}
+
+	items := []models.ChecklistItem{}
+	if err := h.db.Where("task_id = ?", task.ID).Order("position ASC").Find(&items).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch checklist", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, items, "Checklist retrieved successfully")
+}
+
+// AddChecklistItem appends an item to the end of a task's checklist
+func (h *TaskHandler) AddChecklistItem(c *gin.Context) {
+	var req CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	task, ok := h.loadModifiableTask(c)
+	if !ok {
+		return
+	}
+
+	item := models.ChecklistItem{TaskID: task.ID, Text: req.Text, Done: req.Done}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the task row so concurrent appends don't claim the same position
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Task{}, "id = ?", task.ID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.ChecklistItem{}).Where("task_id = ?", task.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		item.Position = int(count)
+
+		return tx.Create(&item).Error
+	})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to add checklist item", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, item, "Checklist item added successfully")
+}
+
+// UpdateChecklistItem edits an item's text and/or done state
+func (h *TaskHandler) UpdateChecklistItem(c *gin.Context) {
+	var req UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	item, ok := h.loadModifiableChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	if req.Text != nil {
+		item.Text = *req.Text
+	}
+	if req.Done != nil {
+		item.Done = *req.Done
+	}
+	item.UpdatedAt = time.Now()
+
+	if err := h.db.Save(&item).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update checklist item", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, item, "Checklist item updated successfully")
+}
+
+// ToggleChecklistItem flips an item's done state
+func (h *TaskHandler) ToggleChecklistItem(c *gin.Context) {
+	item, ok := h.loadModifiableChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	// Flip in SQL so two concurrent toggles don't both read the same state
+	if err := h.db.Model(&item).
+		Updates(map[string]interface{}{"done": gorm.Expr("NOT done"), "updated_at": time.Now()}).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to toggle checklist item", nil)
+		return
+	}
+	if err := h.db.First(&item, "id = ?", item.ID).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch checklist item", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, item, "Checklist item toggled successfully")
+}
+
+// MoveChecklistItem moves an item to a new position, shifting the items in between
+func (h *TaskHandler) MoveChecklistItem(c *gin.Context) {
+	var req MoveChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	item, ok := h.loadModifiableChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	items := []models.ChecklistItem{}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the task's items and re-read the item's current position inside the transaction
+		var locked []models.ChecklistItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("task_id = ?", item.TaskID).
+			Order("position ASC").
+			Find(&locked).Error; err != nil {
+			return err
+		}
+
+		from := -1
+		for _, other := range locked {
+			if other.ID == item.ID {
+				from = other.Position
+			}
+		}
+		if from < 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		to := *req.Position
+		if to > len(locked)-1 {
+			to = len(locked) - 1
+		}
+
+		if to < from {
+			if err := tx.Model(&models.ChecklistItem{}).
+				Where("task_id = ? AND position >= ? AND position < ?", item.TaskID, to, from).
+				UpdateColumn("position", gorm.Expr("position + 1")).Error; err != nil {
+				return err
+			}
+		} else if to > from {
+			if err := tx.Model(&models.ChecklistItem{}).
+				Where("task_id = ? AND position > ? AND position <= ?", item.TaskID, from, to).
+				UpdateColumn("position", gorm.Expr("position - 1")).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.ChecklistItem{}).
+			Where("id = ?", item.ID).
+			UpdateColumns(map[string]interface{}{"position": to, "updated_at": time.Now()}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("task_id = ?", item.TaskID).Order("position ASC").Find(&items).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		utils.RespondError(c, http.StatusNotFound, "CHECKLIST_ITEM_NOT_FOUND", "Checklist item not found", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to reorder checklist", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, items, "Checklist reordered successfully")
+}
+
+// DeleteChecklistItem removes an item and closes the gap in positions
+func (h *TaskHandler) DeleteChecklistItem(c *gin.Context) {
+	item, ok := h.loadModifiableChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.ChecklistItem{}, "id = ?", item.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.ChecklistItem{}).
+			Where("task_id = ? AND position > ?", item.TaskID, item.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		utils.RespondError(c, http.StatusNotFound, "CHECKLIST_ITEM_NOT_FOUND", "Checklist item not found", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete checklist item", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Checklist item deleted successfully")
+}
+
+// Helper functions
+
+// loadModifiableTask fetches the :id task and checks the caller may modify it,
+// writing the error response and returning false otherwise
+func (h *TaskHandler) loadModifiableTask(c *gin.Context) (models.Task, bool) {
+	task, err := h.tasks.FindByID(c.Param("id"))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
+			return task, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task", nil)
+		return task, false
+	}
+
+	principal := auth.FromContext(c)
+	if !auth.CanModifyTask(principal, task) {
+		respondDenied(c, hiddenTask, "You don't have permission to update this task", h.taskVisible(principal, task))
+		return task, false
+	}
+	return task, true
+}
+
+// loadModifiableChecklistItem fetches the :itemId item on a task the caller may modify
+func (h *TaskHandler) loadModifiableChecklistItem(c *gin.Context) (models.ChecklistItem, bool) {
+	var item models.ChecklistItem
+
+	task, ok := h.loadModifiableTask(c)
+	if !ok {
+		return item, false
+	}
+
+	if err := h.db.First(&item, "id = ? AND task_id = ?", c.Param("itemId"), task.ID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "CHECKLIST_ITEM_NOT_FOUND", "Checklist item not found", nil)
+			return item, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch checklist item", nil)
+		return item, false
+	}
+	return item, true
+}
+
+// checklistProgress counts done items out of the total
+func checklistProgress(items []models.ChecklistItem) *models.ChecklistProgress {
+	progress := &models.ChecklistProgress{Total: len(items)}
+	for _, item := range items {
+		if item.Done {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+// loadChecklistProgress fills checklist_progress for each task with a single aggregate query
+func loadChecklistProgress(db *gorm.DB, tasks *[]models.Task) error {
+	if len(*tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]string, len(*tasks))
+	taskMap := make(map[string]*models.Task)
+	for i := range *tasks {
+		taskIDs[i] = (*tasks)[i].ID
+		taskMap[(*tasks)[i].ID] = &(*tasks)[i]
+		(*tasks)[i].ChecklistProgress = &models.ChecklistProgress{}
+	}
+
+	var results []struct {
+		TaskID string `gorm:"column:task_id"`
+		Done   int    `gorm:"column:done"`
+		Total  int    `gorm:"column:total"`
+	}
+	if err := db.Raw(
+		"SELECT task_id, COUNT(*) FILTER (WHERE done) AS done, COUNT(*) AS total FROM checklist_items WHERE task_id IN ? GROUP BY task_id",
+		taskIDs,
+	).Scan(&results).Error; err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if task, ok := taskMap[result.TaskID]; ok {
+			task.ChecklistProgress.Done = result.Done
+			task.ChecklistProgress.Total = result.Total
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,348 @@
+// ABOUTME: Comment handlers for discussion on a task
+// ABOUTME: Resolves @username mentions and notifies mentioned users who can see the task
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// mentionPattern matches @username at the start of the text or after a character that can't
+// be part of an email address, so "alice@example.com" isn't read as a mention
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@-])@([A-Za-z0-9_.-]+)`)
+
+// CreateCommentRequest represents the comment creation request body
+type CreateCommentRequest struct {
+	Content string `json:"content" binding:"required,max=10000"`
+}
+
+// UpdateCommentRequest represents the comment edit request body
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,max=10000"`
+}
+
+// GetTaskComments returns a task's comments, oldest first
+func (h *TaskHandler) GetTaskComments(c *gin.Context) {
+	task, ok := h.loadVisibleTask(c)
+	if !ok {
+		return
+	}
+
+	comments := []models.Comment{}
+	if err := h.db.Preload("User").
+		Where("task_id = ?", task.ID).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch comments")
+		return
+	}
+	if err := loadCommentReactions(h.db, comments, auth.FromContext(c).ID); err != nil {
+		respondQueryError(c, err, "Failed to load reactions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, comments, "Comments retrieved successfully")
+}
+
+// CreateTaskComment adds a comment to a task the caller can see. Mentioned users who can
+// also see the task are recorded on the comment and notified; unknown usernames are ignored.
+func (h *TaskHandler) CreateTaskComment(c *gin.Context) {
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Comment content cannot be blank", nil)
+		return
+	}
+
+	task, ok := h.loadVisibleTask(c)
+	if !ok {
+		return
+	}
+
+	authorID := auth.FromContext(c).ID
+	mentioned, err := resolveMentions(h.db, task, parseMentions(content))
+	if err != nil {
+		respondQueryError(c, err, "Failed to resolve mentions")
+		return
+	}
+
+	comment := models.Comment{TaskID: task.ID, UserID: authorID, Content: content, MentionIDs: mentioned}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+
+		// Mentioning yourself links your name but doesn't notify you
+		var recipients []string
+		for _, userID := range mentioned {
+			if userID != authorID {
+				recipients = append(recipients, userID)
+			}
+		}
+		return notifyUsers(tx, recipients, models.Notification{
+			ActorID:   &authorID,
+			Type:      notificationMention,
+			TaskID:    &task.ID,
+			CommentID: &comment.ID,
+			Message:   fmt.Sprintf("You were mentioned in a comment on %q", task.Title),
+		})
+	})
+	if err != nil {
+		respondQueryError(c, err, "Failed to create comment")
+		return
+	}
+
+	h.db.Preload("User").First(&comment, "id = ?", comment.ID)
+	comment.Reactions = []models.ReactionSummary{}
+
+	utils.RespondSuccess(c, http.StatusCreated, comment, "Comment created successfully")
+}
+
+// UpdateComment replaces the text of one of the caller's comments, keeping the old text as a
+// revision. Users mentioned for the first time are notified.
+func (h *TaskHandler) UpdateComment(c *gin.Context) {
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Comment content cannot be blank", nil)
+		return
+	}
+
+	comment, task, ok := h.loadVisibleComment(c, false)
+	if !ok {
+		return
+	}
+	editorID := auth.FromContext(c).ID
+	if comment.UserID != editorID {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only the author can edit a comment", nil)
+		return
+	}
+	if content == comment.Content {
+		comments := []models.Comment{comment}
+		if err := loadCommentReactions(h.db, comments, editorID); err != nil {
+			respondQueryError(c, err, "Failed to load reactions")
+			return
+		}
+		utils.RespondSuccess(c, http.StatusOK, comments[0], "Comment unchanged")
+		return
+	}
+
+	mentioned, err := resolveMentions(h.db, task, parseMentions(content))
+	if err != nil {
+		respondQueryError(c, err, "Failed to resolve mentions")
+		return
+	}
+	alreadyMentioned := make(map[string]bool, len(comment.MentionIDs))
+	for _, userID := range comment.MentionIDs {
+		alreadyMentioned[userID] = true
+	}
+	var recipients []string
+	for _, userID := range mentioned {
+		if userID != editorID && !alreadyMentioned[userID] {
+			recipients = append(recipients, userID)
+		}
+	}
+
+	now := time.Now().UTC()
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		revision := models.CommentRevision{CommentID: comment.ID, Content: comment.Content, EditedBy: &editorID}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&comment).Updates(map[string]interface{}{
+			"content":     content,
+			"mention_ids": pq.StringArray(mentioned),
+			"edited_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+		return notifyUsers(tx, recipients, models.Notification{
+			ActorID:   &editorID,
+			Type:      notificationMention,
+			TaskID:    &task.ID,
+			CommentID: &comment.ID,
+			Message:   fmt.Sprintf("You were mentioned in a comment on %q", task.Title),
+		})
+	})
+	if err != nil {
+		respondQueryError(c, err, "Failed to update comment")
+		return
+	}
+
+	h.db.Preload("User").First(&comment, "id = ?", comment.ID)
+	comments := []models.Comment{comment}
+	if err := loadCommentReactions(h.db, comments, editorID); err != nil {
+		respondQueryError(c, err, "Failed to load reactions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, comments[0], "Comment updated successfully")
+}
+
+// DeleteComment hides a comment from its task. Authors can delete their own comments and
+// Admins any; the comment and its revisions are kept for auditing.
+func (h *TaskHandler) DeleteComment(c *gin.Context) {
+	comment, _, ok := h.loadVisibleComment(c, false)
+	if !ok {
+		return
+	}
+	principal := auth.FromContext(c)
+	if comment.UserID != principal.ID && !principal.IsAdmin() {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only the author or an admin can delete a comment", nil)
+		return
+	}
+
+	if err := h.db.Delete(&comment).Error; err != nil {
+		respondQueryError(c, err, "Failed to delete comment")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Comment deleted successfully")
+}
+
+// GetCommentRevisions returns the earlier versions of a comment, oldest first, to its author,
+// Admins and Managers. Deleted comments' revisions stay available.
+func (h *TaskHandler) GetCommentRevisions(c *gin.Context) {
+	comment, _, ok := h.loadVisibleComment(c, true)
+	if !ok {
+		return
+	}
+	principal := auth.FromContext(c)
+	if comment.UserID != principal.ID && !principal.IsAdmin() && !principal.IsManager() {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only the author, managers and admins can view comment history", nil)
+		return
+	}
+
+	revisions := []models.CommentRevision{}
+	if err := h.db.Where("comment_id = ?", comment.ID).
+		Order("created_at ASC, id ASC").
+		Find(&revisions).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch comment revisions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, revisions, "Comment revisions retrieved successfully")
+}
+
+// loadVisibleComment fetches the :id comment and its task when the caller can see the task.
+// Comments on tasks the caller can't see are reported as not found.
+func (h *TaskHandler) loadVisibleComment(c *gin.Context, includeDeleted bool) (models.Comment, models.Task, bool) {
+	var comment models.Comment
+	query := h.db
+	if includeDeleted {
+		query = query.Unscoped()
+	}
+	if err := query.First(&comment, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "COMMENT_NOT_FOUND", "Comment not found", nil)
+			return comment, models.Task{}, false
+		}
+		respondQueryError(c, err, "Failed to fetch comment")
+		return comment, models.Task{}, false
+	}
+
+	task, err := h.tasks.FindByID(comment.TaskID)
+	if err == nil {
+		tasks := []models.Task{task}
+		err = h.tasks.LoadAssignees(tasks)
+		task = tasks[0]
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task", nil)
+		return comment, task, false
+	}
+	if !auth.CanAccessTask(auth.FromContext(c), task) {
+		utils.RespondError(c, http.StatusNotFound, "COMMENT_NOT_FOUND", "Comment not found", nil)
+		return comment, task, false
+	}
+	return comment, task, true
+}
+
+// loadVisibleTask fetches the :id task with its assignees when the caller can see it
+func (h *TaskHandler) loadVisibleTask(c *gin.Context) (models.Task, bool) {
+	task, err := h.tasks.FindByID(c.Param("id"))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
+			return task, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task", nil)
+		return task, false
+	}
+
+	tasks := []models.Task{task}
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return task, false
+	}
+	task = tasks[0]
+
+	if !auth.CanAccessTask(auth.FromContext(c), task) {
+		respondHidden(c, hiddenTask, "You don't have permission to view this task")
+		return task, false
+	}
+	return task, true
+}
+
+// parseMentions returns the distinct lowercased usernames mentioned in content, in order of
+// first appearance. Trailing punctuation such as "@alice." is not part of the username.
+func parseMentions(content string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// resolveMentions returns the IDs of active users named in usernames who can see task
+// (whose assignees must be loaded), in mention order
+func resolveMentions(db *gorm.DB, task models.Task, usernames []string) ([]string, error) {
+	ids := []string{}
+	if len(usernames) == 0 {
+		return ids, nil
+	}
+
+	var users []models.User
+	if err := db.Where("LOWER(username) IN ? AND is_active = ?", usernames, true).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	byUsername := make(map[string]models.User, len(users))
+	for _, user := range users {
+		byUsername[strings.ToLower(user.Username)] = user
+	}
+
+	for _, username := range usernames {
+		user, ok := byUsername[username]
+		if !ok || !auth.CanAccessTask(auth.PrincipalForUser(user), task) {
+			continue
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,114 @@
+// ABOUTME: Emoji reactions on task comments
+// ABOUTME: Adds and removes the caller's reactions and summarizes reactions per comment
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// maxEmojiLength bounds a reaction in characters; multi-codepoint emoji and :shortcodes: fit
+const maxEmojiLength = 32
+
+// CommentReactionRequest names the emoji to add or remove
+type CommentReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// AddCommentReaction adds the caller's reaction to a comment on a task they can see. Reacting
+// twice with the same emoji is a no-op.
+func (h *TaskHandler) AddCommentReaction(c *gin.Context) {
+	h.changeCommentReaction(c, func(tx *gorm.DB, reaction models.CommentReaction) error {
+		return tx.Exec("INSERT INTO comment_reactions (comment_id, user_id, emoji) VALUES (?, ?, ?) ON CONFLICT DO NOTHING",
+			reaction.CommentID, reaction.UserID, reaction.Emoji).Error
+	}, "Reaction added")
+}
+
+// RemoveCommentReaction removes the caller's reaction from a comment. Removing a reaction
+// that isn't there is a no-op.
+func (h *TaskHandler) RemoveCommentReaction(c *gin.Context) {
+	h.changeCommentReaction(c, func(tx *gorm.DB, reaction models.CommentReaction) error {
+		return tx.Where("comment_id = ? AND user_id = ? AND emoji = ?", reaction.CommentID, reaction.UserID, reaction.Emoji).
+			Delete(&models.CommentReaction{}).Error
+	}, "Reaction removed")
+}
+
+// changeCommentReaction validates the request, applies change for the caller and responds
+// with the comment's updated reactions
+func (h *TaskHandler) changeCommentReaction(c *gin.Context, change func(*gorm.DB, models.CommentReaction) error, message string) {
+	var req CommentReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	emoji := strings.TrimSpace(req.Emoji)
+	if emoji == "" || len([]rune(emoji)) > maxEmojiLength || strings.IndexFunc(emoji, unicode.IsSpace) >= 0 {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "emoji must be a single emoji or shortcode", nil)
+		return
+	}
+
+	comment, _, ok := h.loadVisibleComment(c, false)
+	if !ok {
+		return
+	}
+
+	userID := auth.FromContext(c).ID
+	if err := change(h.db, models.CommentReaction{CommentID: comment.ID, UserID: userID, Emoji: emoji}); err != nil {
+		respondQueryError(c, err, "Failed to update reaction")
+		return
+	}
+
+	comments := []models.Comment{comment}
+	if err := loadCommentReactions(h.db, comments, userID); err != nil {
+		respondQueryError(c, err, "Failed to load reactions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, comments[0], message)
+}
+
+// loadCommentReactions fills Reactions on each comment with per-emoji counts, in order of
+// first use, marking the emoji userID reacted with
+func loadCommentReactions(db *gorm.DB, comments []models.Comment, userID string) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]string, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].ID
+		comments[i].Reactions = []models.ReactionSummary{}
+	}
+
+	var rows []struct {
+		CommentID string
+		Emoji     string
+		Count     int
+		Reacted   bool
+	}
+	if err := db.Model(&models.CommentReaction{}).
+		Select("comment_id, emoji, COUNT(*) AS count, BOOL_OR(user_id = ?) AS reacted", userID).
+		Where("comment_id IN ?", ids).
+		Group("comment_id, emoji").
+		Order("MIN(created_at) ASC, emoji ASC").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(comments))
+	for i := range comments {
+		index[comments[i].ID] = i
+	}
+	for _, row := range rows {
+		i := index[row.CommentID]
+		comments[i].Reactions = append(comments[i].Reactions, models.ReactionSummary{Emoji: row.Emoji, Count: row.Count, Reacted: row.Reacted})
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+// ABOUTME: Custom field definition handlers and the validation of task values against them
+// ABOUTME: Values are stored in the task's metadata under "custom", keyed by field name
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// customFieldsKey is where a task's custom field values live in its metadata
+const customFieldsKey = "custom"
+
+// maxCustomFieldNameLength matches custom_field_definitions.name
+const maxCustomFieldNameLength = 100
+
+// CustomFieldTypes are the kinds of value a custom field holds. Dates use YYYY-MM-DD and
+// select fields take one of the definition's options.
+var CustomFieldTypes = []string{"text", "number", "date", "boolean", "select"}
+
+var errCustomFieldExists = errors.New("custom field already exists")
+
+type CustomFieldHandler struct {
+	db *gorm.DB
+}
+
+func NewCustomFieldHandler(db *gorm.DB) *CustomFieldHandler {
+	return &CustomFieldHandler{db: db}
+}
+
+// CreateCustomFieldRequest defines a field on every task, or on one department's tasks when
+// department_id is set
+type CreateCustomFieldRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Type         string   `json:"type" binding:"required"`
+	Options      []string `json:"options"`
+	Required     bool     `json:"required"`
+	DepartmentID *string  `json:"department_id"`
+}
+
+// UpdateCustomFieldRequest changes a definition; omitted fields are left unchanged. The type
+// and department are fixed once created since stored values depend on them.
+type UpdateCustomFieldRequest struct {
+	Name     *string  `json:"name"`
+	Options  []string `json:"options"`
+	Required *bool    `json:"required"`
+}
+
+// GetCustomFields lists custom field definitions by name: all of them, or with ?department_id=
+// the ones that department's task form shows
+func (h *CustomFieldHandler) GetCustomFields(c *gin.Context) {
+	query := h.db.WithContext(c.Request.Context()).Order("name")
+	if value := c.Query("department_id"); value != "" {
+		if !checkDepartmentExists(c, h.db, value, http.StatusBadRequest) {
+			return
+		}
+		query = query.Where("department_id IS NULL OR department_id = ?", value)
+	}
+
+	definitions := []models.CustomFieldDefinition{}
+	if err := query.Find(&definitions).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch custom fields")
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, definitions, "Custom fields retrieved successfully")
+}
+
+// CreateCustomField defines a new custom field
+func (h *CustomFieldHandler) CreateCustomField(c *gin.Context) {
+	var req CreateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	definition := models.CustomFieldDefinition{
+		Name:         strings.TrimSpace(req.Name),
+		Type:         req.Type,
+		Options:      trimOptions(req.Options),
+		Required:     req.Required,
+		DepartmentID: req.DepartmentID,
+	}
+	if details := validateCustomFieldDefinition(definition); len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+	if req.DepartmentID != nil && !checkDepartmentExists(c, h.db, *req.DepartmentID, http.StatusUnprocessableEntity) {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCustomFieldNameFree(tx, definition.Name, ""); err != nil {
+			return err
+		}
+		return tx.Create(&definition).Error
+	})
+	if err == errCustomFieldExists {
+		utils.RespondError(c, http.StatusConflict, "FIELD_EXISTS", "A custom field with that name already exists", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create custom field", nil)
+		return
+	}
+
+	if !h.reloadCustomFields(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, definition, "Custom field created successfully")
+}
+
+// UpdateCustomField renames a field, changes its options or whether it is required. Renaming
+// moves the values tasks already hold to the new name.
+func (h *CustomFieldHandler) UpdateCustomField(c *gin.Context) {
+	var req UpdateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	var definition models.CustomFieldDefinition
+	if err := h.db.First(&definition, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "FIELD_NOT_FOUND", "Custom field not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch custom field", nil)
+		return
+	}
+
+	previousName := definition.Name
+	if req.Name != nil {
+		definition.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Options != nil {
+		definition.Options = trimOptions(req.Options)
+	}
+	if req.Required != nil {
+		definition.Required = *req.Required
+	}
+	if details := validateCustomFieldDefinition(definition); len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if definition.Name != previousName {
+			if err := checkCustomFieldNameFree(tx, definition.Name, definition.ID); err != nil {
+				return err
+			}
+			oldPath := pq.StringArray{customFieldsKey, previousName}
+			newPath := pq.StringArray{customFieldsKey, definition.Name}
+			if err := tx.Exec(`UPDATE tasks
+				SET metadata = jsonb_set(metadata #- CAST(? AS TEXT[]), CAST(? AS TEXT[]), metadata #> CAST(? AS TEXT[]))
+				WHERE metadata #> CAST(? AS TEXT[]) IS NOT NULL`,
+				oldPath, newPath, oldPath, oldPath).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&definition).Updates(map[string]interface{}{
+			"name":       definition.Name,
+			"options":    definition.Options,
+			"required":   definition.Required,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+	})
+	if err == errCustomFieldExists {
+		utils.RespondError(c, http.StatusConflict, "FIELD_EXISTS", "A custom field with that name already exists", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update custom field", nil)
+		return
+	}
+
+	if !h.reloadCustomFields(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, definition, "Custom field updated successfully")
+}
+
+// DeleteCustomField removes a definition. Values tasks already hold stay in their metadata
+// but are no longer validated or shown on the form.
+func (h *CustomFieldHandler) DeleteCustomField(c *gin.Context) {
+	result := h.db.Delete(&models.CustomFieldDefinition{}, "id = ?", c.Param("id"))
+	if result.Error != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete custom field", nil)
+		return
+	}
+	if result.RowsAffected == 0 {
+		utils.RespondError(c, http.StatusNotFound, "FIELD_NOT_FOUND", "Custom field not found", nil)
+		return
+	}
+
+	if !h.reloadCustomFields(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Custom field deleted successfully")
+}
+
+// reloadCustomFields refreshes the cached definitions after a change so task validation reflects it
+func (h *CustomFieldHandler) reloadCustomFields(c *gin.Context) bool {
+	if err := repository.LoadCustomFields(h.db); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Custom field saved but failed to reload definitions", nil)
+		return false
+	}
+	return true
+}
+
+// validateCustomFieldDefinition checks a definition's name, type and options. Only select
+// fields have options, and they need at least one.
+func validateCustomFieldDefinition(definition models.CustomFieldDefinition) []utils.ErrorDetail {
+	var details []utils.ErrorDetail
+	if definition.Name == "" || len(definition.Name) > maxCustomFieldNameLength {
+		details = append(details, utils.ErrorDetail{Field: "name", Message: "name must be between 1 and 100 characters"})
+	}
+	if !slices.Contains(CustomFieldTypes, definition.Type) {
+		details = append(details, utils.ErrorDetail{Field: "type", Message: "type must be one of " + strings.Join(CustomFieldTypes, ", ")})
+	}
+	switch {
+	case definition.Type == "select" && len(definition.Options) == 0:
+		details = append(details, utils.ErrorDetail{Field: "options", Message: "select fields need at least one option"})
+	case definition.Type != "select" && len(definition.Options) > 0:
+		details = append(details, utils.ErrorDetail{Field: "options", Message: "only select fields have options"})
+	case slices.Contains(definition.Options, ""):
+		details = append(details, utils.ErrorDetail{Field: "options", Message: "options cannot be empty"})
+	}
+	return details
+}
+
+// trimOptions trims each option and drops repeats, keeping the first occurrence's order
+func trimOptions(options []string) []string {
+	trimmed := []string{}
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if !slices.Contains(trimmed, option) {
+			trimmed = append(trimmed, option)
+		}
+	}
+	return trimmed
+}
+
+// checkCustomFieldNameFree returns errCustomFieldExists when another definition has name,
+// ignoring case and the definition exceptID
+func checkCustomFieldNameFree(db *gorm.DB, name, exceptID string) error {
+	query := db.Model(&models.CustomFieldDefinition{}).Where("LOWER(name) = LOWER(?)", name)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errCustomFieldExists
+	}
+	return nil
+}
+
+// applyCustomFields validates values against the fields defined for departmentID's tasks and
+// writes them into metadata, returning the updated metadata. A null or empty value clears a
+// field; every required field must have a value once values are applied.
+func applyCustomFields(metadata *string, departmentID *string, values map[string]interface{}) (*string, []utils.ErrorDetail) {
+	fields := make(map[string]json.RawMessage)
+	if metadata != nil && strings.TrimSpace(*metadata) != "" {
+		if err := json.Unmarshal([]byte(*metadata), &fields); err != nil {
+			return nil, []utils.ErrorDetail{{Field: "custom_fields", Message: "Task metadata is not a JSON object"}}
+		}
+	}
+	current := make(map[string]interface{})
+	if raw, ok := fields[customFieldsKey]; ok {
+		if err := json.Unmarshal(raw, &current); err != nil {
+			return nil, []utils.ErrorDetail{{Field: "custom_fields", Message: "Stored custom field values are not a JSON object"}}
+		}
+	}
+
+	definitions := repository.CustomFields(departmentID)
+	var details []utils.ErrorDetail
+	invalid := make(map[string]bool)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		index := slices.IndexFunc(definitions, func(d models.CustomFieldDefinition) bool { return d.Name == name })
+		if index < 0 {
+			details = append(details, utils.ErrorDetail{Field: "custom_fields." + name, Message: "Unknown custom field"})
+			continue
+		}
+		value := values[name]
+		if text, ok := value.(string); ok && strings.TrimSpace(text) == "" {
+			value = nil
+		}
+		if value == nil {
+			delete(current, name)
+			continue
+		}
+		if message := checkCustomFieldValue(definitions[index], value); message != "" {
+			details = append(details, utils.ErrorDetail{Field: "custom_fields." + name, Message: message})
+			invalid[name] = true
+			continue
+		}
+		current[name] = value
+	}
+	for _, definition := range definitions {
+		if _, ok := current[definition.Name]; definition.Required && !ok && !invalid[definition.Name] {
+			details = append(details, utils.ErrorDetail{Field: "custom_fields." + definition.Name, Message: "This field is required"})
+		}
+	}
+	if len(details) > 0 {
+		return nil, details
+	}
+
+	if len(current) == 0 {
+		delete(fields, customFieldsKey)
+	} else {
+		encoded, err := json.Marshal(current)
+		if err != nil {
+			return nil, []utils.ErrorDetail{{Field: "custom_fields", Message: "Custom field values could not be encoded"}}
+		}
+		fields[customFieldsKey] = encoded
+	}
+	if len(fields) == 0 && metadata == nil {
+		return nil, nil
+	}
+	updated, err := json.Marshal(fields)
+	if err != nil {
+		return nil, []utils.ErrorDetail{{Field: "custom_fields", Message: "Custom field values could not be encoded"}}
+	}
+	result := string(updated)
+	return &result, nil
+}
+
+// copyCustomFields carries source's custom field values over to a new task in departmentID,
+// dropping values the department's fields no longer define, and returns the new task's
+// metadata. The copy must still have every required field.
+func copyCustomFields(source *string, departmentID *string) (*string, []utils.ErrorDetail) {
+	fields := make(map[string]json.RawMessage)
+	if source != nil && strings.TrimSpace(*source) != "" {
+		if err := json.Unmarshal([]byte(*source), &fields); err != nil {
+			return nil, []utils.ErrorDetail{{Field: "custom_fields", Message: "Task metadata is not a JSON object"}}
+		}
+	}
+	values := make(map[string]interface{})
+	if raw, ok := fields[customFieldsKey]; ok {
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, []utils.ErrorDetail{{Field: "custom_fields", Message: "Stored custom field values are not a JSON object"}}
+		}
+	}
+
+	definitions := repository.CustomFields(departmentID)
+	for name := range values {
+		if !slices.ContainsFunc(definitions, func(d models.CustomFieldDefinition) bool { return d.Name == name }) {
+			delete(values, name)
+		}
+	}
+	return applyCustomFields(nil, departmentID, values)
+}
+
+// customFieldsError carries the custom field problems of a task created inside a transaction
+type customFieldsError struct {
+	details []utils.ErrorDetail
+}
+
+func (e *customFieldsError) Error() string {
+	return "invalid custom fields"
+}
+
+// checkCustomFieldValue returns why value doesn't fit definition, or "" when it does
+func checkCustomFieldValue(definition models.CustomFieldDefinition, value interface{}) string {
+	switch definition.Type {
+	case "text":
+		if _, ok := value.(string); !ok {
+			return "Must be text"
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return "Must be a number"
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return "Must be true or false"
+		}
+	case "date":
+		text, ok := value.(string)
+		if !ok {
+			return "Must be a date in YYYY-MM-DD format"
+		}
+		if _, err := time.Parse(dateLayout, text); err != nil {
+			return "Must be a date in YYYY-MM-DD format"
+		}
+	case "select":
+		text, ok := value.(string)
+		if !ok || !slices.Contains(definition.Options, text) {
+			return fmt.Sprintf("Must be one of %s", strings.Join(definition.Options, ", "))
+		}
+	}
+	return ""
+}
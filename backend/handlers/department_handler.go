@@ -4,13 +4,20 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
 	"github.com/synapse/backend/models"
 	"github.com/synapse/backend/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errDepartmentHasUsers = errors.New("department has users")
+	errDepartmentHasTasks = errors.New("department has tasks")
 )
 
 type DepartmentHandler struct {
@@ -26,26 +33,23 @@ type CreateDepartmentRequest struct {
 	Name        string  `json:"name" binding:"required,min=1,max=100"`
 	Description *string `json:"description"`
 	HeadID      *string `json:"head_id"`
+	MoveHead    bool    `json:"move_head"` // Move the head into the new department
 }
 
 // UpdateDepartmentRequest represents the department update request body
 type UpdateDepartmentRequest struct {
-	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
-	Description *string `json:"description"`
-	HeadID      *string `json:"head_id"`
+	Name            *string          `json:"name" binding:"omitempty,min=1,max=100"`
+	Description     *string          `json:"description"`
+	HeadID          *string          `json:"head_id"`
+	MoveHead        bool             `json:"move_head"`        // Move the head into this department
+	WorkingCalendar *WorkingCalendar `json:"working_calendar"` // Replaces the stored calendar
 }
 
-// GetDepartments returns a paginated list of departments
+// GetDepartments returns a paginated list of departments, with user_count and task_count
+// when ?with_counts=true
 func (h *DepartmentHandler) GetDepartments(c *gin.Context) {
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := utils.ParsePagination(c)
 
 	// Get filter parameters
 	search := c.Query("search")
@@ -70,13 +74,19 @@ func (h *DepartmentHandler) GetDepartments(c *gin.Context) {
 	var departments []models.Department
 	if err := query.
 		Preload("Head").
-		Order("name ASC").
+		Order("name ASC, id ASC").
 		Limit(perPage).
 		Offset(offset).
 		Find(&departments).Error; err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch departments", nil)
 		return
 	}
+	if wantsCounts(c) {
+		if err := loadDepartmentCounts(h.db, auth.FromContext(c), departments); err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to count department users and tasks", nil)
+			return
+		}
+	}
 
 	utils.RespondSuccessWithPagination(c, departments, page, perPage, total)
 }
@@ -104,7 +114,7 @@ func (h *DepartmentHandler) GetDepartment(c *gin.Context) {
 func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
 	var req CreateDepartmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
 		return
 	}
 
@@ -115,17 +125,12 @@ func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
 		return
 	}
 
-	// Validate head if provided
-	if req.HeadID != nil && *req.HeadID != "" {
-		var head models.User
-		if err := h.db.First(&head, "id = ?", *req.HeadID).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				utils.RespondError(c, http.StatusBadRequest, "INVALID_HEAD", "Department head user not found", nil)
-				return
-			}
-			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department head", nil)
-			return
-		}
+	// Validate head if provided; nobody is in a new department yet, so the head has to be moved
+	if req.HeadID != nil && *req.HeadID == "" {
+		req.HeadID = nil
+	}
+	if req.HeadID != nil && !h.checkDepartmentHead(c, *req.HeadID, "", req.MoveHead) {
+		return
 	}
 
 	// Create department
@@ -135,7 +140,13 @@ func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
 		HeadID:      req.HeadID,
 	}
 
-	if err := h.db.Create(&department).Error; err != nil {
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&department).Error; err != nil {
+			return err
+		}
+		return moveDepartmentHead(tx, department)
+	})
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create department", nil)
 		return
 	}
@@ -152,9 +163,15 @@ func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
 
 	var req UpdateDepartmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
 		return
 	}
+	if req.WorkingCalendar != nil {
+		if detail := req.WorkingCalendar.validate(); detail != nil {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, []utils.ErrorDetail{*detail})
+			return
+		}
+	}
 
 	// Fetch existing department
 	var department models.Department
@@ -184,22 +201,33 @@ func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
 		if *req.HeadID == "" {
 			department.HeadID = nil
 		} else {
-			// Validate head
-			var head models.User
-			if err := h.db.First(&head, "id = ?", *req.HeadID).Error; err != nil {
-				if err == gorm.ErrRecordNotFound {
-					utils.RespondError(c, http.StatusBadRequest, "INVALID_HEAD", "Department head user not found", nil)
-					return
-				}
-				utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department head", nil)
+			if !h.checkDepartmentHead(c, *req.HeadID, department.ID, req.MoveHead) {
 				return
 			}
 			department.HeadID = req.HeadID
 		}
 	}
 
-	// Save department
-	if err := h.db.Save(&department).Error; err != nil {
+	if req.WorkingCalendar != nil {
+		metadata, err := withWorkingCalendar(department.Metadata, *req.WorkingCalendar)
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update working calendar", nil)
+			return
+		}
+		department.Metadata = metadata
+	}
+
+	// Save department, moving a newly named head into it
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&department).Error; err != nil {
+			return err
+		}
+		if req.HeadID == nil || !req.MoveHead {
+			return nil
+		}
+		return moveDepartmentHead(tx, department)
+	})
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update department", nil)
 		return
 	}
@@ -210,6 +238,40 @@ func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, department, "Department updated successfully")
 }
 
+// checkDepartmentHead responds with INVALID_HEAD unless headID names a Manager or Admin who
+// is in departmentID ("" for a department being created). With moveHead, a head from
+// elsewhere is accepted and moved in when the department is saved.
+func (h *DepartmentHandler) checkDepartmentHead(c *gin.Context, headID, departmentID string, moveHead bool) bool {
+	invalid := func(message string) bool {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_HEAD", message, []utils.ErrorDetail{{Field: "head_id", Message: message}})
+		return false
+	}
+
+	var head models.User
+	if err := h.db.First(&head, "id = ?", headID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return invalid("Department head user not found")
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department head", nil)
+		return false
+	}
+	if head.Role != auth.RoleManager && head.Role != auth.RoleAdmin {
+		return invalid("Department head must be a Manager or Admin")
+	}
+	if !moveHead && (head.DepartmentID == nil || *head.DepartmentID != departmentID) {
+		return invalid("Department head must belong to the department; set move_head to move them into it")
+	}
+	return true
+}
+
+// moveDepartmentHead puts the department's head, if any, into the department within tx
+func moveDepartmentHead(tx *gorm.DB, department models.Department) error {
+	if department.HeadID == nil {
+		return nil
+	}
+	return tx.Model(&models.User{}).Where("id = ?", *department.HeadID).Update("department_id", department.ID).Error
+}
+
 // DeleteDepartment deletes a department (admin only)
 func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
 	departmentID := c.Param("id")
@@ -225,30 +287,39 @@ func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
 		return
 	}
 
-	// Check if department has users
-	var userCount int64
-	if err := h.db.Model(&models.User{}).Where("department_id = ?", departmentID).Count(&userCount).Error; err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check department users", nil)
-		return
-	}
-	if userCount > 0 {
+	// Check for users and tasks and delete under a lock on the department row, which inserts
+	// and moves into the department key-share lock through their foreign keys
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Department{}, "id = ?", departmentID).Error; err != nil {
+			return err
+		}
+		var userCount int64
+		if err := tx.Model(&models.User{}).Where("department_id = ?", departmentID).Count(&userCount).Error; err != nil {
+			return err
+		}
+		if userCount > 0 {
+			return errDepartmentHasUsers
+		}
+		var taskCount int64
+		if err := tx.Model(&models.Task{}).Where("department_id = ?", departmentID).Count(&taskCount).Error; err != nil {
+			return err
+		}
+		if taskCount > 0 {
+			return errDepartmentHasTasks
+		}
+		return tx.Delete(&department).Error
+	})
+	switch {
+	case err == errDepartmentHasUsers:
 		utils.RespondError(c, http.StatusConflict, "DEPARTMENT_HAS_USERS", "Cannot delete department with existing users", nil)
 		return
-	}
-
-	// Check if department has tasks
-	var taskCount int64
-	if err := h.db.Model(&models.Task{}).Where("department_id = ?", departmentID).Count(&taskCount).Error; err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check department tasks", nil)
-		return
-	}
-	if taskCount > 0 {
+	case err == errDepartmentHasTasks:
 		utils.RespondError(c, http.StatusConflict, "DEPARTMENT_HAS_TASKS", "Cannot delete department with existing tasks", nil)
 		return
-	}
-
-	// Delete department
-	if err := h.db.Delete(&department).Error; err != nil {
+	case err == gorm.ErrRecordNotFound:
+		utils.RespondError(c, http.StatusNotFound, "DEPARTMENT_NOT_FOUND", "Department not found", nil)
+		return
+	case err != nil:
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete department", nil)
 		return
 	}
@@ -272,17 +343,10 @@ func (h *DepartmentHandler) GetDepartmentUsers(c *gin.Context) {
 	}
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := utils.ParsePagination(c)
 
 	// Build query
-	query := h.db.Model(&models.User{}).Where("department_id = ?", departmentID)
+	query := auth.ScopeUsers(h.db.Model(&models.User{}), auth.FromContext(c)).Where("department_id = ?", departmentID)
 
 	// Count total
 	var total int64
@@ -295,7 +359,7 @@ func (h *DepartmentHandler) GetDepartmentUsers(c *gin.Context) {
 	offset := (page - 1) * perPage
 	var users []models.User
 	if err := query.
-		Order("full_name ASC").
+		Order("full_name ASC, id ASC").
 		Limit(perPage).
 		Offset(offset).
 		Find(&users).Error; err != nil {
@@ -311,7 +375,7 @@ func (h *DepartmentHandler) GetDepartmentUsers(c *gin.Context) {
 	utils.RespondSuccessWithPagination(c, users, page, perPage, total)
 }
 
-// GetDepartmentTasks returns tasks in a department
+// GetDepartmentTasks returns tasks in a department; ?expand=assignees adds assignee users
 func (h *DepartmentHandler) GetDepartmentTasks(c *gin.Context) {
 	departmentID := c.Param("id")
 
@@ -327,21 +391,14 @@ func (h *DepartmentHandler) GetDepartmentTasks(c *gin.Context) {
 	}
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := utils.ParsePagination(c)
 
 	// Get filter parameters
 	status := c.Query("status")
 	priority := c.Query("priority")
 
 	// Build query
-	query := h.db.Model(&models.Task{}).Where("department_id = ?", departmentID)
+	query := auth.ScopeTasks(h.db.Model(&models.Task{}), auth.FromContext(c)).Where("department_id = ?", departmentID)
 
 	// Apply filters
 	if status != "" {
@@ -363,9 +420,8 @@ func (h *DepartmentHandler) GetDepartmentTasks(c *gin.Context) {
 	var tasks []models.Task
 	if err := query.
 		Preload("Creator").
-		Preload("Assignees").
 		Preload("Project").
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(perPage).
 		Offset(offset).
 		Find(&tasks).Error; err != nil {
@@ -373,5 +429,10 @@ func (h *DepartmentHandler) GetDepartmentTasks(c *gin.Context) {
 		return
 	}
 
+	// Load assignees for all tasks
+	if !loadListAssignees(c, h.db, tasks) {
+		return
+	}
+
 	utils.RespondSuccessWithPagination(c, tasks, page, perPage, total)
 }
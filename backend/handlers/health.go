@@ -1,5 +1,5 @@
 // ABOUTME: Health check handler for service monitoring
-// ABOUTME: Returns server status and database connectivity information
+// ABOUTME: Returns server status, database connectivity and the running build's version
 
 package handlers
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/synapse/backend/utils"
+	"github.com/synapse/backend/version"
 	"gorm.io/gorm"
 )
 
@@ -20,6 +21,12 @@ func NewHealthHandler(db *gorm.DB) *HealthHandler {
 }
 
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
+	// A handler built without a database can't be healthy
+	if h.db == nil {
+		utils.RespondError(c, http.StatusServiceUnavailable, "UNHEALTHY", "database not configured", nil)
+		return
+	}
+
 	// Check database connection
 	sqlDB, err := h.db.DB()
 	if err != nil {
@@ -35,5 +42,11 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, gin.H{
 		"status":   "ok",
 		"database": "connected",
+		"version":  version.Version,
 	}, "")
 }
+
+// Version reports which build is running, so incidents can be matched to deploys
+func (h *HealthHandler) Version(c *gin.Context) {
+	utils.RespondSuccess(c, http.StatusOK, version.Get(), "")
+}
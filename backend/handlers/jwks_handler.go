@@ -0,0 +1,28 @@
+// ABOUTME: Publishes the public keys access tokens are verified with, as a JSON Web Key Set
+// ABOUTME: Lets other services verify RS256 tokens without sharing the signing secret
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/utils"
+)
+
+// jwksMaxAge is how long consumers may cache the key set, in seconds
+const jwksMaxAge = "300"
+
+// JWKS serves the current and previous RS256 public keys. The document is plain JWKS rather
+// than the usual response envelope, since JWT libraries fetch and parse it directly. Under
+// HS256 the set is empty.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	keys, err := utils.LoadJWTKeys(config.GetConfig())
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load signing keys", nil)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age="+jwksMaxAge)
+	c.JSON(http.StatusOK, keys.JWKS())
+}
@@ -0,0 +1,119 @@
+// ABOUTME: Opt-in child counts for project, department and task listings
+// ABOUTME: Each list's counts come from one grouped query per kind rather than one per row
+
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// wantsCounts reports whether the list was asked for ?with_counts=true
+func wantsCounts(c *gin.Context) bool {
+	return c.Query("with_counts") == "true"
+}
+
+// groupedCounts counts the rows of table whose column is in ids, by column value. Rows not
+// matching extra, a condition with its args, are left out.
+func groupedCounts(db *gorm.DB, table, column string, ids []string, extra string, args ...interface{}) (map[string]int, error) {
+	var rows []struct {
+		Value string
+		Count int
+	}
+	query := db.Table(table).
+		Select(column+" AS value, COUNT(*) AS count").
+		Where(column+" IN ?", ids)
+	if extra != "" {
+		query = query.Where(extra, args...)
+	}
+	if err := query.Group(column).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Value] = row.Count
+	}
+	return counts, nil
+}
+
+// countOf returns a pointer to id's count, zero when it has none
+func countOf(counts map[string]int, id string) *int {
+	count := counts[id]
+	return &count
+}
+
+// loadProjectCounts sets task_count on each project. Like GET /projects/:id/tasks, it counts
+// every task of a project the caller can see, so the count matches that list's total.
+func loadProjectCounts(db *gorm.DB, projects []models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	ids := make([]string, len(projects))
+	for i, project := range projects {
+		ids[i] = project.ID
+	}
+
+	tasks, err := groupedCounts(db, "tasks", "project_id", ids, "")
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		projects[i].TaskCount = countOf(tasks, projects[i].ID)
+	}
+	return nil
+}
+
+// loadDepartmentCounts sets user_count and task_count on each department. Deactivated users
+// aren't counted, and neither are tasks principal couldn't list with GET /tasks.
+func loadDepartmentCounts(db *gorm.DB, principal auth.Principal, departments []models.Department) error {
+	if len(departments) == 0 {
+		return nil
+	}
+	ids := make([]string, len(departments))
+	for i, department := range departments {
+		ids[i] = department.ID
+	}
+
+	users, err := groupedCounts(db, "users", "department_id", ids, "is_active")
+	if err != nil {
+		return err
+	}
+	tasks, err := groupedCounts(auth.ScopeTasks(db, principal), "tasks", "department_id", ids, "")
+	if err != nil {
+		return err
+	}
+	for i := range departments {
+		departments[i].UserCount = countOf(users, departments[i].ID)
+		departments[i].TaskCount = countOf(tasks, departments[i].ID)
+	}
+	return nil
+}
+
+// loadTaskCounts sets subtask_count, the task's checklist items, and comment_count, its
+// comments that haven't been deleted, on each task
+func loadTaskCounts(db *gorm.DB, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	subtasks, err := groupedCounts(db, "checklist_items", "task_id", ids, "")
+	if err != nil {
+		return err
+	}
+	comments, err := groupedCounts(db, "comments", "task_id", ids, "deleted_at IS NULL")
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].SubtaskCount = countOf(subtasks, tasks[i].ID)
+		tasks[i].CommentCount = countOf(comments, tasks[i].ID)
+	}
+	return nil
+}
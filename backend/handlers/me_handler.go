@@ -0,0 +1,76 @@
+// ABOUTME: Handlers for summaries of the authenticated user's own work
+// ABOUTME: Serves the badge counts shown in the navigation bar using count queries only
+
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+type MeHandler struct {
+	db *gorm.DB
+}
+
+func NewMeHandler(db *gorm.DB) *MeHandler {
+	return &MeHandler{db: db}
+}
+
+// MeCounts holds the navigation bar badge counts. Task counts cover open tasks (not Done)
+// assigned to the caller; a task due earlier today is both overdue and due today.
+type MeCounts struct {
+	AssignedOpen        int64 `json:"assigned_open"`
+	Overdue             int64 `json:"overdue"`
+	DueToday            int64 `json:"due_today"`
+	UnreadNotifications int64 `json:"unread_notifications"`
+}
+
+// GetCounts returns the caller's badge counts. "Today" is the current day in the caller's
+// timezone preference, or the UTC day without one.
+func (h *MeHandler) GetCounts(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+	userID := auth.FromContext(c).ID
+
+	loc, err := userLocation(db, userID)
+	if err != nil {
+		respondQueryError(c, err, "Failed to load timezone")
+		return
+	}
+	now := time.Now().UTC()
+	startOfDay, endOfDay := utils.DayBounds(now, loc)
+
+	// Each count starts from a fresh query so conditions don't accumulate
+	assignedOpen := func() *gorm.DB {
+		return db.Model(&models.Task{}).
+			Where("id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", userID).
+			Where("status <> ?", "Done")
+	}
+
+	var counts MeCounts
+	if err := assignedOpen().Count(&counts.AssignedOpen).Error; err != nil {
+		respondQueryError(c, err, "Failed to count assigned tasks")
+		return
+	}
+	if err := assignedOpen().Where("due_date < ?", now).Count(&counts.Overdue).Error; err != nil {
+		respondQueryError(c, err, "Failed to count overdue tasks")
+		return
+	}
+	if err := assignedOpen().Where("due_date >= ? AND due_date < ?", startOfDay, endOfDay).Count(&counts.DueToday).Error; err != nil {
+		respondQueryError(c, err, "Failed to count tasks due today")
+		return
+	}
+	if err := db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&counts.UnreadNotifications).Error; err != nil {
+		respondQueryError(c, err, "Failed to count unread notifications")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, counts, "Counts retrieved successfully")
+}
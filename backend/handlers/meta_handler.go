@@ -0,0 +1,91 @@
+// ABOUTME: Metadata handler listing the enumerated values the API accepts
+// ABOUTME: The lists here are the ones request validation checks against, so clients never drift
+
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+)
+
+// Canonical values in display order. Validation uses sets built from these lists; task
+// statuses come from the workflow_statuses table instead, and TASK_SOURCES may add sources.
+var (
+	TaskPriorities  = []string{"Low", "Medium", "High", "Urgent"}
+	TaskSources     = []string{"GUI", "Email", "API", "Document", "NLP"}
+	ProjectStatuses = []string{"Active", "On Hold", "Completed", "Archived"}
+)
+
+// enumSet turns a list of values into a membership set for validation
+func enumSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// taskSources returns the built-in TaskSources followed by those configured in TASK_SOURCES
+func taskSources() []string {
+	sources := append([]string{}, TaskSources...)
+	custom, err := config.GetConfig().CustomTaskSources()
+	if err != nil {
+		// Validate rejects a malformed setting at startup; accept the built-in sources regardless
+		return sources
+	}
+	for _, source := range custom {
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// EnumsResponse lists the accepted values for each enumerated field
+type EnumsResponse struct {
+	TaskStatuses      []string            `json:"task_statuses"`
+	TaskPriorities    []string            `json:"task_priorities"`
+	TaskSources       []string            `json:"task_sources"`
+	ProjectStatuses   []string            `json:"project_statuses"`
+	Roles             []string            `json:"roles"`
+	StatusTransitions map[string][]string `json:"status_transitions"`
+}
+
+type MetaHandler struct{}
+
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// GetEnums returns the canonical task and project enums, every role including custom ones,
+// and the task workflow with its allowed transitions, so clients can render authoritative
+// dropdowns. Task statuses are the default workflow's, or with ?department_id= the ones
+// that department's tasks use.
+func (h *MetaHandler) GetEnums(c *gin.Context) {
+	var departmentID *string
+	if value := c.Query("department_id"); value != "" {
+		departmentID = &value
+	}
+	names := workflowStatusNames(repository.Workflow(departmentID))
+
+	cfg := config.GetConfig()
+	transitions := make(map[string][]string, len(names))
+	for _, status := range names {
+		transitions[status] = allowedStatusTransitions(cfg, status, names)
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, EnumsResponse{
+		TaskStatuses:      names,
+		TaskPriorities:    TaskPriorities,
+		TaskSources:       taskSources(),
+		ProjectStatuses:   ProjectStatuses,
+		Roles:             auth.Roles(),
+		StatusTransitions: transitions,
+	}, "")
+}
@@ -0,0 +1,91 @@
+// ABOUTME: Notification handlers for the caller's in-app notifications
+// ABOUTME: Lists notifications newest first and marks them read one at a time or all at once
+
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+type NotificationHandler struct {
+	db *gorm.DB
+}
+
+func NewNotificationHandler(db *gorm.DB) *NotificationHandler {
+	return &NotificationHandler{db: db}
+}
+
+// GetNotifications returns the caller's notifications, newest first. ?unread=true limits
+// the list to notifications that haven't been read.
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	page, perPage := utils.ParsePagination(c)
+
+	query := h.db.Model(&models.Notification{}).Where("user_id = ?", auth.FromContext(c).ID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		respondQueryError(c, err, "Failed to count notifications")
+		return
+	}
+
+	offset := (page - 1) * perPage
+	notifications := []models.Notification{}
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Limit(perPage).
+		Offset(offset).
+		Find(&notifications).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch notifications")
+		return
+	}
+
+	utils.RespondSuccessWithPagination(c, notifications, page, perPage, total)
+}
+
+// MarkNotificationRead marks one of the caller's notifications read. Other users'
+// notifications are reported as not found.
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	var notification models.Notification
+	if err := h.db.First(&notification, "id = ? AND user_id = ?", c.Param("id"), auth.FromContext(c).ID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "NOTIFICATION_NOT_FOUND", "Notification not found", nil)
+			return
+		}
+		respondQueryError(c, err, "Failed to fetch notification")
+		return
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now().UTC()
+		if err := h.db.Model(&notification).Update("read_at", now).Error; err != nil {
+			respondQueryError(c, err, "Failed to update notification")
+			return
+		}
+		notification.ReadAt = &now
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, notification, "Notification marked as read")
+}
+
+// MarkAllNotificationsRead marks every unread notification of the caller read
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	result := h.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", auth.FromContext(c).ID).
+		Update("read_at", time.Now().UTC())
+	if result.Error != nil {
+		respondQueryError(c, result.Error, "Failed to update notifications")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, gin.H{"updated": result.RowsAffected}, "Notifications marked as read")
+}
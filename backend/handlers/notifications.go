@@ -0,0 +1,61 @@
+// ABOUTME: Helpers for creating in-app notifications from handlers
+// ABOUTME: Notifications are written in the caller's transaction so they commit with the change
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// Notification types
+const (
+	notificationMention    = "mention"
+	notificationAssigned   = "assigned"
+	notificationUnassigned = "unassigned"
+)
+
+// notifyUsers writes one copy of notification for each of userIDs using tx
+func notifyUsers(tx *gorm.DB, userIDs []string, notification models.Notification) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	notifications := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		n := notification
+		n.UserID = userID
+		notifications = append(notifications, n)
+	}
+	return tx.Create(&notifications).Error
+}
+
+// notifyAssigneeChanges tells users added to task that they have new work and users removed
+// from it that it's off their plate. The actor isn't notified about their own changes.
+func notifyAssigneeChanges(tx *gorm.DB, task models.Task, actorID string, added, removed []string) error {
+	others := func(userIDs []string) []string {
+		var recipients []string
+		for _, userID := range userIDs {
+			if userID != actorID {
+				recipients = append(recipients, userID)
+			}
+		}
+		return recipients
+	}
+
+	if err := notifyUsers(tx, others(added), models.Notification{
+		ActorID: &actorID,
+		Type:    notificationAssigned,
+		TaskID:  &task.ID,
+		Message: fmt.Sprintf("You were assigned to %q", task.Title),
+	}); err != nil {
+		return err
+	}
+	return notifyUsers(tx, others(removed), models.Notification{
+		ActorID: &actorID,
+		Type:    notificationUnassigned,
+		TaskID:  &task.ID,
+		Message: fmt.Sprintf("You were unassigned from %q", task.Title),
+	})
+}
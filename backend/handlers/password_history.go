@@ -0,0 +1,53 @@
+// ABOUTME: Password history checks that stop users from reusing recent passwords
+// ABOUTME: Replaced hashes are kept per user and pruned to the configured history size
+
+package handlers
+
+import (
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// passwordReused reports whether password matches one of the user's earlier passwords still
+// in their history. historySize counts the current password, which callers check themselves.
+func passwordReused(db *gorm.DB, userID, password string, historySize int) (bool, error) {
+	if historySize <= 1 {
+		return false, nil
+	}
+
+	var history []models.PasswordHistory
+	if err := db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(historySize - 1).
+		Find(&history).Error; err != nil {
+		return false, err
+	}
+	for _, entry := range history {
+		if utils.VerifyPassword(entry.PasswordHash, password) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// replacePassword sets the user's password hash to newHash, moves the hash it replaces into
+// their history and prunes the history to the historySize-1 most recent entries
+func replacePassword(tx *gorm.DB, user models.User, newHash string, historySize int) error {
+	if err := tx.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("password_hash", newHash).Error; err != nil {
+		return err
+	}
+	if user.PasswordHash != nil && historySize > 1 {
+		if err := tx.Create(&models.PasswordHistory{UserID: user.ID, PasswordHash: *user.PasswordHash}).Error; err != nil {
+			return err
+		}
+	}
+
+	keep := historySize - 1
+	if keep < 0 {
+		keep = 0
+	}
+	return tx.Exec(`DELETE FROM password_history WHERE user_id = ? AND id NOT IN (
+		SELECT id FROM password_history WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT ?)`,
+		user.ID, user.ID, keep).Error
+}
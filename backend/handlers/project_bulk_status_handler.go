@@ -0,0 +1,102 @@
+// ABOUTME: Bulk project status updates for closing out many projects at once
+// ABOUTME: Updates the projects the caller may modify and reports the ones it skipped
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxBulkProjectStatus caps how many ids one bulk status update may name
+const maxBulkProjectStatus = 100
+
+// BulkProjectStatusRequest lists the project ids to move to Status
+type BulkProjectStatusRequest struct {
+	IDs    []string `json:"ids" binding:"required,min=1,dive,uuid"`
+	Status string   `json:"status" binding:"required"`
+}
+
+// BulkProjectStatusResponse reports what happened to each requested id. Projects that don't
+// exist or that the caller can't see are reported as not found, like the single-project endpoints.
+type BulkProjectStatusResponse struct {
+	Status       string   `json:"status"`
+	Updated      []string `json:"updated"`
+	Forbidden    []string `json:"forbidden"`
+	NotFound     []string `json:"not_found"`
+	UpdatedCount int      `json:"updated_count"`
+	SkippedCount int      `json:"skipped_count"`
+}
+
+// BulkUpdateProjectStatus sets the status of the requested projects the caller may modify
+// (Admins, and Managers within their department) in one transaction, skipping the rest.
+// A failed update rolls back the whole batch.
+func (h *ProjectHandler) BulkUpdateProjectStatus(c *gin.Context) {
+	var req BulkProjectStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if !validProjectStatuses[req.Status] {
+		utils.RespondValidationError(c, []utils.ErrorDetail{{Field: "status", Message: "Invalid status: " + req.Status}})
+		return
+	}
+	if len(req.IDs) > maxBulkProjectStatus {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR",
+			fmt.Sprintf("At most %d projects can be updated at once", maxBulkProjectStatus), nil)
+		return
+	}
+
+	ids := uniqueIDs(req.IDs)
+	principal := auth.FromContext(c)
+	var targets bulkTargets
+	err := h.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// Lock the projects so permission checks and the update see the same rows
+		var found []models.Project
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return err
+		}
+
+		access := make(map[string]bulkAccess, len(found))
+		for _, project := range found {
+			if auth.CanModifyProject(principal, project) {
+				access[project.ID] = bulkAllowed
+				continue
+			}
+			canView, err := h.canViewProject(project, principal)
+			if err != nil {
+				return err
+			}
+			if canView {
+				access[project.ID] = bulkVisible
+			}
+		}
+
+		targets = splitBulkTargets(ids, access)
+		if len(targets.Allowed) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Project{}).Where("id IN ?", targets.Allowed).Update("status", req.Status).Error
+	})
+	if err != nil {
+		respondQueryError(c, err, "Failed to update project statuses")
+		return
+	}
+
+	result := BulkProjectStatusResponse{
+		Status:       req.Status,
+		Updated:      targets.Allowed,
+		Forbidden:    targets.Forbidden,
+		NotFound:     targets.NotFound,
+		UpdatedCount: len(targets.Allowed),
+		SkippedCount: targets.skipped(),
+	}
+	utils.RespondSuccess(c, http.StatusOK, result, fmt.Sprintf("Updated %d projects, skipped %d", result.UpdatedCount, result.SkippedCount))
+}
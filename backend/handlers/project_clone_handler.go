@@ -0,0 +1,154 @@
+// ABOUTME: Project cloning handler for reusing a project as a template
+// ABOUTME: Copies the project and optionally its tasks as a fresh To Do skeleton in one transaction
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// maxProjectCodeLength and maxProjectNameLength match the projects columns
+const (
+	maxProjectCodeLength = 50
+	maxProjectNameLength = 255
+)
+
+// CloneProject copies a project into a new Active project owned by the caller, with a new
+// code and a "Copy of" name. With ?with_tasks=true its tasks are copied too, keeping title,
+// description, tags, priority and custom fields but starting as To Do with no dates or assignees.
+func (h *ProjectHandler) CloneProject(c *gin.Context) {
+	withTasks := c.Query("with_tasks") == "true"
+	principal := auth.FromContext(c)
+
+	var source models.Project
+	if err := h.db.First(&source, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "PROJECT_NOT_FOUND", "Project not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch project", nil)
+		return
+	}
+
+	// Managers can only clone projects in their department
+	if !auth.CanCloneProject(principal, source) {
+		message := "Only managers and admins can clone projects"
+		if principal.IsManager() {
+			message = "You don't have permission to clone this project"
+		}
+		respondDenied(c, hiddenProject, message, func() (bool, error) {
+			return h.canViewProject(source, principal)
+		})
+		return
+	}
+
+	ownerID := principal.ID
+	project := models.Project{
+		Name:         truncateRunes("Copy of "+source.Name, maxProjectNameLength),
+		Description:  source.Description,
+		Status:       "Active",
+		OwnerID:      &ownerID,
+		DepartmentID: source.DepartmentID,
+		Metadata:     source.Metadata,
+	}
+	var clonedTasks int
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		code, err := cloneProjectCode(tx, source.ProjectID)
+		if err != nil {
+			return err
+		}
+		project.ProjectID = code
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		if !withTasks {
+			return nil
+		}
+
+		var tasks []models.Task
+		if err := tx.Where("project_id = ?", source.ID).Order("created_at ASC, id ASC").Find(&tasks).Error; err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			metadata, details := copyCustomFields(task.Metadata, task.DepartmentID)
+			if len(details) > 0 {
+				return &customFieldsError{details: details}
+			}
+			copied := models.Task{
+				Title:        task.Title,
+				Description:  task.Description,
+				Status:       initialWorkflowStatus(task.DepartmentID),
+				Priority:     task.Priority,
+				CreatorID:    principal.ID,
+				DepartmentID: task.DepartmentID,
+				ProjectID:    &project.ID,
+				Source:       "GUI",
+				Tags:         append([]string{}, task.Tags...),
+				Metadata:     metadata,
+			}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		}
+		clonedTasks = len(tasks)
+		return nil
+	})
+	if err != nil {
+		if fieldsErr, ok := err.(*customFieldsError); ok {
+			utils.RespondValidationError(c, fieldsErr.details)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to clone project", nil)
+		return
+	}
+
+	// Reload with associations
+	h.db.
+		Preload("Owner").
+		Preload("Department").
+		First(&project, "id = ?", project.ID)
+
+	utils.RespondSuccess(c, http.StatusCreated, gin.H{
+		"project":      project,
+		"tasks_cloned": clonedTasks,
+	}, "Project cloned successfully")
+}
+
+// cloneProjectCode picks an unused code for a copy of the project coded code: CODE-COPY,
+// then CODE-COPY-2 and so on. Copies of projects without a code get the next generated one.
+func cloneProjectCode(tx *gorm.DB, code string) (string, error) {
+	if code == "" {
+		return nextProjectCode(tx)
+	}
+	for n := 1; ; n++ {
+		suffix := "-COPY"
+		if n > 1 {
+			suffix = fmt.Sprintf("-COPY-%d", n)
+		}
+		candidate := truncateRunes(code, maxProjectCodeLength-len(suffix)) + suffix
+
+		var count int64
+		if err := tx.Model(&models.Project{}).Where("code = ?", candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+}
+
+// truncateRunes shortens s to at most limit characters
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return s
+}
@@ -4,16 +4,21 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
 	"github.com/synapse/backend/models"
 	"github.com/synapse/backend/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var errProjectHasTasks = errors.New("project has tasks")
+
 type ProjectHandler struct {
 	db *gorm.DB
 }
@@ -31,6 +36,9 @@ type CreateProjectRequest struct {
 	OwnerID      *string `json:"owner_id"`
 	StartDate    *string `json:"start_date"` // ISO 8601 format
 	EndDate      *string `json:"end_date"`   // ISO 8601 format
+
+	// AllowCrossDepartmentOwner lets an Admin caller keep an owner from another department
+	AllowCrossDepartmentOwner bool `json:"allow_cross_department_owner"`
 }
 
 // UpdateProjectRequest represents the project update request body
@@ -42,39 +50,61 @@ type UpdateProjectRequest struct {
 	OwnerID      *string `json:"owner_id"`
 	StartDate    *string `json:"start_date"`
 	EndDate      *string `json:"end_date"`
+
+	// AllowCrossDepartmentOwner lets an Admin caller keep an owner from another department
+	AllowCrossDepartmentOwner bool `json:"allow_cross_department_owner"`
 }
 
-// GetProjects returns a paginated list of projects
+// GetProjects returns a paginated list of projects. ?active_from= and ?active_to= keep the
+// projects whose start and end dates overlap that range; ?created_after= and friends filter
+// on when the project row was created or updated. ?with_counts=true adds each project's
+// task_count. Unchanged pages answer 304 to If-None-Match.
 func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := utils.ParsePagination(c)
 
 	// Get filter parameters
 	status := c.Query("status")
 	departmentID := c.Query("department_id")
 	ownerID := c.Query("owner_id")
 	search := c.Query("search")
+	code := c.Query("code")
+	member := c.Query("member")
+	activeFrom, err := parseTimeBound(c.Query("active_from"), false)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid active_from date, use YYYY-MM-DD or ISO 8601", nil)
+		return
+	}
+	activeTo, err := parseTimeBound(c.Query("active_to"), true)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid active_to date, use YYYY-MM-DD or ISO 8601", nil)
+		return
+	}
+	if activeFrom != nil && activeTo != nil && !activeFrom.Before(*activeTo) {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "active_from must be before active_to", nil)
+		return
+	}
+	bounds, ok := parseTimestampBounds(c)
+	if !ok {
+		return
+	}
 
 	// Get user context for access control
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	principal := auth.FromContext(c)
+
+	// Queries stop when the client goes away or the request deadline passes
+	db := h.db.WithContext(c.Request.Context())
 
 	// Build query
-	query := h.db.Model(&models.Project{})
+	query := db.Model(&models.Project{})
 
 	// Apply role-based filtering
-	if userRole == "Manager" {
-		// Managers can only see projects in their department
-		query = query.Where("department_id = ?", userDepartmentID)
+	query = auth.ScopeProjects(query, principal)
+
+	// Only projects where the caller is an explicit member
+	if member == "me" {
+		query = query.Where("id IN (SELECT project_id FROM project_members WHERE user_id = ?)", principal.ID)
 	}
-	// Admins can see all projects (no additional filter)
 
 	// Apply filters
 	if status != "" {
@@ -86,14 +116,25 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	if ownerID != "" {
 		query = query.Where("owner_id = ?", ownerID)
 	}
+	if code != "" {
+		query = query.Where("code = ?", code)
+	}
 	if search != "" {
-		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Where("name ILIKE ? OR description ILIKE ? OR code ILIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	}
+	// Projects running at any point in the range; a missing start or end date is open-ended
+	if activeFrom != nil {
+		query = query.Where("end_date IS NULL OR end_date >= ?", *activeFrom)
 	}
+	if activeTo != nil {
+		query = query.Where("start_date IS NULL OR start_date < ?", *activeTo)
+	}
+	query = bounds.apply(query)
 
 	// Count total
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to count projects", nil)
+		respondQueryError(c, err, "Failed to count projects")
 		return
 	}
 
@@ -103,18 +144,28 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	if err := query.
 		Preload("Owner").
 		Preload("Department").
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(perPage).
 		Offset(offset).
 		Find(&projects).Error; err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch projects", nil)
+		respondQueryError(c, err, "Failed to fetch projects")
+		return
+	}
+	if err := loadProjectHealth(db, projects); err != nil {
+		respondQueryError(c, err, "Failed to compute project health")
 		return
 	}
+	if wantsCounts(c) {
+		if err := loadProjectCounts(db, projects); err != nil {
+			respondQueryError(c, err, "Failed to count project tasks")
+			return
+		}
+	}
 
-	utils.RespondSuccessWithPagination(c, projects, page, perPage, total)
+	utils.RespondPaginatedWithETag(c, projects, page, perPage, total, nil)
 }
 
-// GetProject returns a single project by ID
+// GetProject returns a single project by ID with its derived health
 func (h *ProjectHandler) GetProject(c *gin.Context) {
 	projectID := c.Param("id")
 
@@ -131,37 +182,47 @@ func (h *ProjectHandler) GetProject(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
-
-	// Managers can only view projects in their department
-	if userRole == "Manager" {
-		if userDepartmentID == nil || project.DepartmentID == nil ||
-			*project.DepartmentID != userDepartmentID.(string) {
-			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to view this project", nil)
-			return
-		}
+	// Managers can only view projects in their department unless they are project members
+	allowed, err := h.canViewProject(project, auth.FromContext(c))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check project access", nil)
+		return
+	}
+	if !allowed {
+		respondHidden(c, hiddenProject, "You don't have permission to view this project")
+		return
 	}
 
-	utils.RespondSuccess(c, http.StatusOK, project, "Project retrieved successfully")
+	projects := []models.Project{project}
+	if err := loadProjectHealth(h.db, projects); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to compute project health", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, projects[0], "Project retrieved successfully")
 }
 
 // CreateProject creates a new project
 func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	var req CreateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
+		return
+	}
+	startDate, endDate, details := parseProjectDates(req.StartDate, req.EndDate)
+	if len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+	if !checkProjectDateRange(c, startDate, endDate) {
 		return
 	}
 
 	// Get user context
-	userID, _ := c.Get("user_id")
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	principal := auth.FromContext(c)
 
 	// Check permissions - only managers and admins can create projects
-	if userRole != "Manager" && userRole != "Admin" {
+	if !principal.IsManager() && !principal.IsAdmin() {
 		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only managers and admins can create projects", nil)
 		return
 	}
@@ -171,26 +232,21 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		var dept models.Department
 		if err := h.db.First(&dept, "id = ?", *req.DepartmentID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
-				utils.RespondError(c, http.StatusBadRequest, "INVALID_DEPARTMENT", "Department not found", nil)
+				utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_DEPARTMENT", "Department not found", nil)
 				return
 			}
 			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department", nil)
 			return
 		}
-
-		// Managers can only create projects in their department
-		if userRole == "Manager" {
-			if userDepartmentID == nil || *req.DepartmentID != userDepartmentID.(string) {
-				utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Managers can only create projects in their department", nil)
-				return
-			}
-		}
-	} else if userRole == "Manager" {
+	} else if principal.IsManager() {
 		// If no department specified, use manager's department
-		if userDepartmentID != nil {
-			depID := userDepartmentID.(string)
-			req.DepartmentID = &depID
-		}
+		req.DepartmentID = principal.DepartmentID
+	}
+
+	// Managers can only create projects in their department
+	if !auth.CanCreateProject(principal, req.DepartmentID) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Managers can only create projects in their department", nil)
+		return
 	}
 
 	// Validate owner if provided
@@ -198,7 +254,7 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		var owner models.User
 		if err := h.db.First(&owner, "id = ?", *req.OwnerID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
-				utils.RespondError(c, http.StatusBadRequest, "INVALID_OWNER", "Owner user not found", nil)
+				utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_OWNER", "Owner user not found", nil)
 				return
 			}
 			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate owner", nil)
@@ -206,32 +262,10 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		}
 	} else {
 		// Set current user as owner if not specified
-		ownerID := userID.(string)
+		ownerID := principal.ID
 		req.OwnerID = &ownerID
 	}
-
-	// Parse dates if provided
-	var startDate, endDate *time.Time
-	if req.StartDate != nil && *req.StartDate != "" {
-		parsed, err := time.Parse(time.RFC3339, *req.StartDate)
-		if err != nil {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid start_date format, use ISO 8601", nil)
-			return
-		}
-		startDate = &parsed
-	}
-	if req.EndDate != nil && *req.EndDate != "" {
-		parsed, err := time.Parse(time.RFC3339, *req.EndDate)
-		if err != nil {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid end_date format, use ISO 8601", nil)
-			return
-		}
-		endDate = &parsed
-	}
-
-	// Validate date range
-	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "End date cannot be before start date", nil)
+	if !h.checkOwnerDepartment(c, principal, *req.OwnerID, req.DepartmentID, req.AllowCrossDepartmentOwner) {
 		return
 	}
 
@@ -252,6 +286,13 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		EndDate:      endDate,
 	}
 
+	code, err := nextProjectCode(h.db)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate project code", nil)
+		return
+	}
+	project.ProjectID = code
+
 	if err := h.db.Create(&project).Error; err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create project", nil)
 		return
@@ -272,13 +313,17 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 
 	var req UpdateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
+		return
+	}
+	startDate, endDate, details := parseProjectDates(req.StartDate, req.EndDate)
+	if len(details) > 0 {
+		utils.RespondValidationError(c, details)
 		return
 	}
 
 	// Get user context
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	principal := auth.FromContext(c)
 
 	// Fetch existing project
 	var project models.Project
@@ -291,19 +336,22 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	if userRole == "Manager" {
-		// Managers can only update projects in their department
-		if userDepartmentID == nil || project.DepartmentID == nil ||
-			*project.DepartmentID != userDepartmentID.(string) {
-			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to update this project", nil)
-			return
+	// Check permissions - Managers can only update projects in their department
+	if !auth.CanModifyProject(principal, project) {
+		message := "Only managers and admins can update projects"
+		if principal.IsManager() {
+			message = "You don't have permission to update this project"
 		}
-	} else if userRole != "Admin" {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only managers and admins can update projects", nil)
+		respondDenied(c, hiddenProject, message, func() (bool, error) {
+			return h.canViewProject(project, principal)
+		})
 		return
 	}
 
+	// Keep the department and owner as they were so changes to either can be detected
+	previousDepartmentID := project.DepartmentID
+	previousOwnerID := project.OwnerID
+
 	// Update fields
 	if req.Name != nil {
 		project.Name = *req.Name
@@ -319,13 +367,7 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		if *req.DepartmentID == "" {
 			project.DepartmentID = nil
 		} else {
-			var dept models.Department
-			if err := h.db.First(&dept, "id = ?", *req.DepartmentID).Error; err != nil {
-				if err == gorm.ErrRecordNotFound {
-					utils.RespondError(c, http.StatusBadRequest, "INVALID_DEPARTMENT", "Department not found", nil)
-					return
-				}
-				utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department", nil)
+			if !h.checkProjectDepartment(c, *req.DepartmentID) {
 				return
 			}
 			project.DepartmentID = req.DepartmentID
@@ -336,51 +378,33 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		if *req.OwnerID == "" {
 			project.OwnerID = nil
 		} else {
-			var owner models.User
-			if err := h.db.First(&owner, "id = ?", *req.OwnerID).Error; err != nil {
-				if err == gorm.ErrRecordNotFound {
-					utils.RespondError(c, http.StatusBadRequest, "INVALID_OWNER", "Owner user not found", nil)
-					return
-				}
-				utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate owner", nil)
+			if !h.checkProjectOwner(c, principal, *req.OwnerID) {
 				return
 			}
 			project.OwnerID = req.OwnerID
 		}
 	}
+	// An empty date clears it
 	if req.StartDate != nil {
-		if *req.StartDate == "" {
-			project.StartDate = nil
-		} else {
-			parsed, err := time.Parse(time.RFC3339, *req.StartDate)
-			if err != nil {
-				utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid start_date format", nil)
-				return
-			}
-			project.StartDate = &parsed
-		}
+		project.StartDate = startDate
 	}
 	if req.EndDate != nil {
-		if *req.EndDate == "" {
-			project.EndDate = nil
-		} else {
-			parsed, err := time.Parse(time.RFC3339, *req.EndDate)
-			if err != nil {
-				utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid end_date format", nil)
-				return
-			}
-			project.EndDate = &parsed
-		}
+		project.EndDate = endDate
 	}
 
-	// Validate date range
-	if project.StartDate != nil && project.EndDate != nil && project.EndDate.Before(*project.StartDate) {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "End date cannot be before start date", nil)
+	// Validate the resulting date range, including a date the request didn't change
+	if !checkProjectDateRange(c, project.StartDate, project.EndDate) {
 		return
 	}
 
-	// Save project
-	if err := h.db.Save(&project).Error; err != nil {
+	// Moving the project or handing it over must leave the owner in its department
+	if project.OwnerID != nil &&
+		(!sameOptionalID(previousDepartmentID, project.DepartmentID) || !sameOptionalID(previousOwnerID, project.OwnerID)) &&
+		!h.checkOwnerDepartment(c, principal, *project.OwnerID, project.DepartmentID, req.AllowCrossDepartmentOwner) {
+		return
+	}
+
+	if err := h.saveProject(&project, principal, previousOwnerID); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update project", nil)
 		return
 	}
@@ -394,13 +418,130 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, project, "Project updated successfully")
 }
 
+// nextProjectCode generates the next code in the PROJ-0001 series
+func nextProjectCode(db *gorm.DB) (string, error) {
+	var n int64
+	if err := db.Raw("SELECT nextval('project_code_seq')").Scan(&n).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("PROJ-%04d", n), nil
+}
+
+// checkProjectDepartment responds with an error unless departmentID names a department
+func (h *ProjectHandler) checkProjectDepartment(c *gin.Context, departmentID string) bool {
+	var dept models.Department
+	if err := h.db.First(&dept, "id = ?", departmentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_DEPARTMENT", "Department not found", nil)
+			return false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department", nil)
+		return false
+	}
+	return true
+}
+
+// parseProjectDates parses a request's ISO 8601 start_date and end_date. Missing or empty
+// dates come back nil; each malformed date is reported against its own field.
+func parseProjectDates(start, end *string) (startDate, endDate *time.Time, details []utils.ErrorDetail) {
+	parse := func(field string, value *string) *time.Time {
+		if value == nil || *value == "" {
+			return nil
+		}
+		parsed, err := time.Parse(time.RFC3339, *value)
+		if err != nil {
+			details = append(details, utils.ErrorDetail{Field: field, Message: "Invalid " + field + " format, use ISO 8601"})
+			return nil
+		}
+		return &parsed
+	}
+	startDate = parse("start_date", start)
+	endDate = parse("end_date", end)
+	return startDate, endDate, details
+}
+
+// checkProjectDateRange responds with a validation error against end_date when a project
+// would end before it starts
+func checkProjectDateRange(c *gin.Context, startDate, endDate *time.Time) bool {
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "End date cannot be before start date", []utils.ErrorDetail{
+			{Field: "end_date", Message: "end_date cannot be before start_date"},
+		})
+		return false
+	}
+	return true
+}
+
+// checkProjectOwner responds with an error unless ownerID names a user the caller may hand
+// the project to
+func (h *ProjectHandler) checkProjectOwner(c *gin.Context, principal auth.Principal, ownerID string) bool {
+	var owner models.User
+	if err := h.db.First(&owner, "id = ?", ownerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_OWNER", "Owner user not found", nil)
+			return false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate owner", nil)
+		return false
+	}
+	// Managers can only hand projects to people in their own department
+	if !auth.CanTransferProject(principal, owner) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Managers can only transfer projects to users in their department", nil)
+		return false
+	}
+	return true
+}
+
+// checkOwnerDepartment responds with VALIDATION_ERROR unless the owner is an Admin or belongs
+// to the project's department. Projects without a department take any owner. Admin callers
+// may pass allowCrossDepartment to skip the check.
+func (h *ProjectHandler) checkOwnerDepartment(c *gin.Context, principal auth.Principal, ownerID string, departmentID *string, allowCrossDepartment bool) bool {
+	if departmentID == nil || (allowCrossDepartment && principal.IsAdmin()) {
+		return true
+	}
+
+	var owner models.User
+	if err := h.db.First(&owner, "id = ?", ownerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_OWNER", "Owner user not found", nil)
+			return false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate owner", nil)
+		return false
+	}
+	if owner.Role == auth.RoleAdmin || sameOptionalID(owner.DepartmentID, departmentID) {
+		return true
+	}
+
+	message := "Project owner must belong to the project's department"
+	utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", message, []utils.ErrorDetail{{Field: "owner_id", Message: message}})
+	return false
+}
+
+// saveProject saves project and records an ownership transfer when its owner differs from
+// previousOwnerID, in one transaction
+func (h *ProjectHandler) saveProject(project *models.Project, principal auth.Principal, previousOwnerID *string) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(project).Error; err != nil {
+			return err
+		}
+		if sameOptionalID(previousOwnerID, project.OwnerID) {
+			return nil
+		}
+		return recordActivity(tx, principal.ID, activityEntityProject, project.ID, activityOwnershipTransferred, map[string]interface{}{
+			"field": "owner_id",
+			"from":  previousOwnerID,
+			"to":    project.OwnerID,
+		})
+	})
+}
+
 // DeleteProject deletes a project
 func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	projectID := c.Param("id")
 
 	// Get user context
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	principal := auth.FromContext(c)
 
 	// Fetch existing project
 	var project models.Project
@@ -413,32 +554,42 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 		return
 	}
 
-	// Check permissions - only admins can delete projects
-	// Managers can only delete projects in their department
-	if userRole == "Manager" {
-		if userDepartmentID == nil || project.DepartmentID == nil ||
-			*project.DepartmentID != userDepartmentID.(string) {
-			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to delete this project", nil)
-			return
+	// Check permissions - Managers can only delete projects in their department
+	if !auth.CanDeleteProject(principal, project) {
+		message := "Only managers and admins can delete projects"
+		if principal.IsManager() {
+			message = "You don't have permission to delete this project"
 		}
-	} else if userRole != "Admin" {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only managers and admins can delete projects", nil)
+		respondDenied(c, hiddenProject, message, func() (bool, error) {
+			return h.canViewProject(project, principal)
+		})
 		return
 	}
 
-	// Check if project has tasks
-	var taskCount int64
-	if err := h.db.Model(&models.Task{}).Where("project_id = ?", projectID).Count(&taskCount).Error; err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check project tasks", nil)
-		return
-	}
-	if taskCount > 0 {
+	// Check for tasks and delete under a lock on the project row. Adding a task to the project
+	// key-share locks that row through the foreign key, so a concurrent insert either commits
+	// first and is counted here, or waits for the delete and then fails.
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Project{}, "id = ?", projectID).Error; err != nil {
+			return err
+		}
+		var taskCount int64
+		if err := tx.Model(&models.Task{}).Where("project_id = ?", projectID).Count(&taskCount).Error; err != nil {
+			return err
+		}
+		if taskCount > 0 {
+			return errProjectHasTasks
+		}
+		return tx.Delete(&project).Error
+	})
+	switch {
+	case err == errProjectHasTasks:
 		utils.RespondError(c, http.StatusConflict, "PROJECT_HAS_TASKS", "Cannot delete project with existing tasks", nil)
 		return
-	}
-
-	// Delete project
-	if err := h.db.Delete(&project).Error; err != nil {
+	case err == gorm.ErrRecordNotFound:
+		utils.RespondError(c, http.StatusNotFound, "PROJECT_NOT_FOUND", "Project not found", nil)
+		return
+	case err != nil:
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete project", nil)
 		return
 	}
@@ -446,7 +597,7 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, nil, "Project deleted successfully")
 }
 
-// GetProjectTasks returns tasks for a specific project
+// GetProjectTasks returns tasks for a specific project; ?expand=assignees adds assignee users
 func (h *ProjectHandler) GetProjectTasks(c *gin.Context) {
 	projectID := c.Param("id")
 
@@ -462,26 +613,18 @@ func (h *ProjectHandler) GetProjectTasks(c *gin.Context) {
 	}
 
 	// Check permissions
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
-
-	if userRole == "Manager" {
-		if userDepartmentID == nil || project.DepartmentID == nil ||
-			*project.DepartmentID != userDepartmentID.(string) {
-			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to view this project's tasks", nil)
-			return
-		}
+	allowed, err := h.canViewProject(project, auth.FromContext(c))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check project access", nil)
+		return
+	}
+	if !allowed {
+		respondHidden(c, hiddenProject, "You don't have permission to view this project's tasks")
+		return
 	}
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := utils.ParsePagination(c)
 
 	// Get filter parameters
 	status := c.Query("status")
@@ -510,9 +653,8 @@ func (h *ProjectHandler) GetProjectTasks(c *gin.Context) {
 	var tasks []models.Task
 	if err := query.
 		Preload("Creator").
-		Preload("Assignees").
 		Preload("Department").
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(perPage).
 		Offset(offset).
 		Find(&tasks).Error; err != nil {
@@ -520,5 +662,10 @@ func (h *ProjectHandler) GetProjectTasks(c *gin.Context) {
 		return
 	}
 
+	// Load assignees for all tasks
+	if !loadListAssignees(c, h.db, tasks) {
+		return
+	}
+
 	utils.RespondSuccessWithPagination(c, tasks, page, perPage, total)
 }
@@ -0,0 +1,84 @@
+// ABOUTME: Derived project health computed from task aggregates
+// ABOUTME: Classifies projects as On Track, At Risk or Overdue without loading their tasks
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// Project health values
+const (
+	projectHealthOnTrack = "On Track"
+	projectHealthAtRisk  = "At Risk"
+	projectHealthOverdue = "Overdue"
+)
+
+// projectTaskStats counts one project's tasks
+type projectTaskStats struct {
+	Total   int64
+	Open    int64 // Not Done
+	Overdue int64 // Not Done and past their due date
+}
+
+// loadProjectHealth sets Health on each project from one aggregate query over their tasks
+func loadProjectHealth(db *gorm.DB, projects []models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	ids := make([]string, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+
+	now := time.Now().UTC()
+	var rows []struct {
+		ProjectID string
+		Total     int64
+		Open      int64
+		Overdue   int64
+	}
+	if err := db.Model(&models.Task{}).
+		Select("project_id, COUNT(*) AS total, "+
+			"COUNT(*) FILTER (WHERE status <> 'Done') AS open, "+
+			"COUNT(*) FILTER (WHERE status <> 'Done' AND due_date < ?) AS overdue", now).
+		Where("project_id IN ?", ids).
+		Group("project_id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	stats := make(map[string]projectTaskStats, len(rows))
+	for _, row := range rows {
+		stats[row.ProjectID] = projectTaskStats{Total: row.Total, Open: row.Open, Overdue: row.Overdue}
+	}
+
+	cfg := config.GetConfig()
+	for i := range projects {
+		projects[i].Health = projectHealth(stats[projects[i].ID], projects[i].EndDate, now, cfg.ProjectAtRiskPercent, cfg.ProjectOverduePercent)
+	}
+	return nil
+}
+
+// projectHealth is Overdue when the project's end date has passed with tasks still open or
+// when at least overduePercent of its tasks are overdue, At Risk from atRiskPercent, and On
+// Track otherwise
+func projectHealth(stats projectTaskStats, endDate *time.Time, now time.Time, atRiskPercent, overduePercent int) string {
+	if endDate != nil && endDate.Before(now) && stats.Open > 0 {
+		return projectHealthOverdue
+	}
+	if stats.Total == 0 || stats.Overdue == 0 {
+		return projectHealthOnTrack
+	}
+	percent := stats.Overdue * 100 / stats.Total
+	switch {
+	case percent >= int64(overduePercent):
+		return projectHealthOverdue
+	case percent >= int64(atRiskPercent):
+		return projectHealthAtRisk
+	}
+	return projectHealthOnTrack
+}
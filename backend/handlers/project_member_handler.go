@@ -0,0 +1,210 @@
+// ABOUTME: Project membership handlers for listing, adding and removing members
+// ABOUTME: Members gain project visibility independent of their department
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddProjectMemberRequest represents the add/update member request body
+type AddProjectMemberRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"omitempty,oneof=Lead Contributor Viewer"`
+}
+
+// GetProjectMembers returns the explicit members of a project
+func (h *ProjectHandler) GetProjectMembers(c *gin.Context) {
+	project, ok := h.loadVisibleProject(c, "You don't have permission to view this project's members")
+	if !ok {
+		return
+	}
+
+	var members []models.ProjectMember
+	if err := h.db.
+		Preload("User").
+		Where("project_id = ?", project.ID).
+		Order("created_at ASC, user_id ASC").
+		Find(&members).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch project members", nil)
+		return
+	}
+
+	// Clear password hashes
+	for i := range members {
+		if members[i].User != nil {
+			members[i].User.PasswordHash = nil
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, members, "Project members retrieved successfully")
+}
+
+// AddProjectMember adds a user to a project, or updates their role if already a member
+func (h *ProjectHandler) AddProjectMember(c *gin.Context) {
+	var req AddProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if !utils.IsUUID(req.UserID) {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_USER", "user_id must be a UUID", nil)
+		return
+	}
+
+	project, ok := h.loadManageableProject(c)
+	if !ok {
+		return
+	}
+
+	// Validate user exists
+	var user models.User
+	if err := h.db.First(&user, "id = ?", req.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_USER", "User not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate user", nil)
+		return
+	}
+
+	role := "Contributor"
+	if req.Role != "" {
+		role = req.Role
+	}
+
+	member := models.ProjectMember{ProjectID: project.ID, UserID: user.ID, Role: role}
+	if err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(&member).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to add project member", nil)
+		return
+	}
+
+	user.PasswordHash = nil
+	member.User = &user
+
+	utils.RespondSuccess(c, http.StatusOK, member, "Project member saved successfully")
+}
+
+// RemoveProjectMember removes a user's explicit membership from a project
+func (h *ProjectHandler) RemoveProjectMember(c *gin.Context) {
+	project, ok := h.loadManageableProject(c)
+	if !ok {
+		return
+	}
+
+	result := h.db.Where("project_id = ? AND user_id = ?", project.ID, c.Param("userId")).Delete(&models.ProjectMember{})
+	if result.Error != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to remove project member", nil)
+		return
+	}
+	if result.RowsAffected == 0 {
+		utils.RespondError(c, http.StatusNotFound, "MEMBER_NOT_FOUND", "User is not a member of this project", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Project member removed successfully")
+}
+
+// loadVisibleProject fetches the :id project and checks the caller can view it,
+// writing the error response and returning false otherwise
+func (h *ProjectHandler) loadVisibleProject(c *gin.Context, forbiddenMessage string) (models.Project, bool) {
+	var project models.Project
+	if err := h.db.First(&project, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "PROJECT_NOT_FOUND", "Project not found", nil)
+			return project, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch project", nil)
+		return project, false
+	}
+
+	allowed, err := h.canViewProject(project, auth.FromContext(c))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check project access", nil)
+		return project, false
+	}
+	if !allowed {
+		respondHidden(c, hiddenProject, forbiddenMessage)
+		return project, false
+	}
+
+	return project, true
+}
+
+// loadManageableProject fetches the :id project and checks the caller may manage its members:
+// Admins, Managers of the project's department, and project Leads
+func (h *ProjectHandler) loadManageableProject(c *gin.Context) (models.Project, bool) {
+	var project models.Project
+	if err := h.db.First(&project, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "PROJECT_NOT_FOUND", "Project not found", nil)
+			return project, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch project", nil)
+		return project, false
+	}
+
+	principal := auth.FromContext(c)
+	allowed, err := h.canManageProjectMembers(project, principal)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check project access", nil)
+		return project, false
+	}
+	if !allowed {
+		respondDenied(c, hiddenProject, "You don't have permission to manage this project's members", func() (bool, error) {
+			return h.canViewProject(project, principal)
+		})
+		return project, false
+	}
+
+	return project, true
+}
+
+// canViewProject applies auth.CanAccessProject, looking up the caller's project
+// membership only when the decision depends on it
+func (h *ProjectHandler) canViewProject(project models.Project, principal auth.Principal) (bool, error) {
+	if auth.CanAccessProject(principal, project, "") {
+		return true, nil
+	}
+	memberRole, err := h.projectMemberRole(project.ID, principal.ID)
+	if err != nil {
+		return false, err
+	}
+	return auth.CanAccessProject(principal, project, memberRole), nil
+}
+
+// canManageProjectMembers applies auth.CanManageProjectMembers, looking up the caller's
+// project membership only when the decision depends on it
+func (h *ProjectHandler) canManageProjectMembers(project models.Project, principal auth.Principal) (bool, error) {
+	if auth.CanManageProjectMembers(principal, project, "") {
+		return true, nil
+	}
+	memberRole, err := h.projectMemberRole(project.ID, principal.ID)
+	if err != nil {
+		return false, err
+	}
+	return auth.CanManageProjectMembers(principal, project, memberRole), nil
+}
+
+// projectMemberRole returns the user's role on the project, or "" if they aren't a member
+func (h *ProjectHandler) projectMemberRole(projectID, userID string) (string, error) {
+	var member models.ProjectMember
+	err := h.db.Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
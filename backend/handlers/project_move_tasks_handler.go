@@ -0,0 +1,138 @@
+// ABOUTME: Bulk task moves between projects for restructuring work
+// ABOUTME: Moves some or all of a project's tasks to another project in one transaction
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MoveProjectTasksRequest names the target project and, optionally, which of the source
+// project's tasks to move; all of them when task_ids is omitted
+type MoveProjectTasksRequest struct {
+	ToProjectID string   `json:"to_project_id" binding:"required,uuid"`
+	TaskIDs     []string `json:"task_ids" binding:"omitempty,dive,uuid"`
+}
+
+// MoveProjectTasksResponse reports the moved tasks and the requested ids that weren't in the
+// source project, which are skipped
+type MoveProjectTasksResponse struct {
+	FromProjectID string   `json:"from_project_id"`
+	ToProjectID   string   `json:"to_project_id"`
+	Moved         []string `json:"moved"`
+	NotInProject  []string `json:"not_in_project"`
+	MovedCount    int      `json:"moved_count"`
+	SkippedCount  int      `json:"skipped_count"`
+}
+
+// MoveProjectTasks moves tasks from the :id project to another project. The caller must be
+// able to modify both projects: Admins, and Managers within the projects' department.
+func (h *ProjectHandler) MoveProjectTasks(c *gin.Context) {
+	var req MoveProjectTasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if req.TaskIDs != nil && len(req.TaskIDs) == 0 {
+		utils.RespondValidationError(c, []utils.ErrorDetail{{Field: "task_ids", Message: "task_ids must name at least one task, or be omitted to move all tasks"}})
+		return
+	}
+	if req.ToProjectID == c.Param("id") {
+		utils.RespondValidationError(c, []utils.ErrorDetail{{Field: "to_project_id", Message: "Tasks are already in this project"}})
+		return
+	}
+
+	principal := auth.FromContext(c)
+
+	var source models.Project
+	if err := h.db.First(&source, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "PROJECT_NOT_FOUND", "Project not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch project", nil)
+		return
+	}
+	if !auth.CanModifyProject(principal, source) {
+		message := "Only managers and admins can move project tasks"
+		if principal.IsManager() {
+			message = "You don't have permission to move this project's tasks"
+		}
+		respondDenied(c, hiddenProject, message, func() (bool, error) {
+			return h.canViewProject(source, principal)
+		})
+		return
+	}
+
+	// A target the caller can't see is reported as missing, like the single-project endpoints
+	var target models.Project
+	if err := h.db.First(&target, "id = ?", req.ToProjectID).Error; err != nil && err != gorm.ErrRecordNotFound {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch target project", nil)
+		return
+	}
+	if target.ID != "" && !auth.CanModifyProject(principal, target) {
+		canView, err := h.canViewProject(target, principal)
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check project access", nil)
+			return
+		}
+		if canView {
+			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to move tasks into the target project", nil)
+			return
+		}
+		target = models.Project{}
+	}
+	if target.ID == "" {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_PROJECT", "Target project not found", nil)
+		return
+	}
+
+	result := MoveProjectTasksResponse{FromProjectID: source.ID, ToProjectID: target.ID, Moved: []string{}, NotInProject: []string{}}
+	err := h.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// Lock the tasks so concurrent edits see the move and its version bump
+		query := tx.Model(&models.Task{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("project_id = ?", source.ID)
+		if req.TaskIDs != nil {
+			query = query.Where("id IN ?", req.TaskIDs)
+		}
+		if err := query.Order("created_at, id").Pluck("id", &result.Moved).Error; err != nil {
+			return err
+		}
+
+		if req.TaskIDs != nil {
+			moved := make(map[string]bool, len(result.Moved))
+			for _, id := range result.Moved {
+				moved[id] = true
+			}
+			for _, id := range req.TaskIDs {
+				if !moved[id] {
+					moved[id] = true
+					result.NotInProject = append(result.NotInProject, id)
+				}
+			}
+		}
+		if len(result.Moved) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Task{}).Where("id IN ?", result.Moved).Updates(map[string]interface{}{
+			"project_id": target.ID,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+	})
+	if err != nil {
+		respondQueryError(c, err, "Failed to move tasks")
+		return
+	}
+
+	result.MovedCount = len(result.Moved)
+	result.SkippedCount = len(result.NotInProject)
+	utils.RespondSuccess(c, http.StatusOK, result, fmt.Sprintf("Moved %d tasks, skipped %d", result.MovedCount, result.SkippedCount))
+}
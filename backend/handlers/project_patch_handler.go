@@ -0,0 +1,233 @@
+// ABOUTME: Partial project update handler with JSON merge semantics
+// ABOUTME: Distinguishes omitted keys (left unchanged) from explicit nulls (cleared)
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// validProjectStatuses matches the status values accepted by project create and update
+var validProjectStatuses = enumSet(ProjectStatuses)
+
+// PatchProjectResponse is the patched project plus which keys were applied.
+// Keys omitted from the request are left untouched; keys sent as null are cleared.
+type PatchProjectResponse struct {
+	models.Project
+	UpdatedFields []string `json:"updated_fields"`
+	ClearedFields []string `json:"cleared_fields"`
+}
+
+// projectPatch holds the outcome of applying a raw JSON patch to a project
+type projectPatch struct {
+	updated []string
+	cleared []string
+}
+
+// PatchProject partially updates a project, only touching keys present in the request body
+func (h *ProjectHandler) PatchProject(c *gin.Context) {
+	var fields map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&fields); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Request body must be a JSON object", nil)
+		return
+	}
+
+	// The override flag isn't a project field, so take it out before applying the patch
+	allowCrossDepartmentOwner := false
+	if raw, ok := fields["allow_cross_department_owner"]; ok {
+		if err := json.Unmarshal(raw, &allowCrossDepartmentOwner); err != nil {
+			utils.RespondValidationError(c, []utils.ErrorDetail{{Field: "allow_cross_department_owner", Message: "allow_cross_department_owner must be a boolean"}})
+			return
+		}
+		delete(fields, "allow_cross_department_owner")
+	}
+
+	// Get user context
+	principal := auth.FromContext(c)
+
+	// Fetch existing project
+	var project models.Project
+	if err := h.db.First(&project, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "PROJECT_NOT_FOUND", "Project not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch project", nil)
+		return
+	}
+
+	// Check permissions - Managers can only update projects in their department
+	if !auth.CanModifyProject(principal, project) {
+		message := "Only managers and admins can update projects"
+		if principal.IsManager() {
+			message = "You don't have permission to update this project"
+		}
+		respondDenied(c, hiddenProject, message, func() (bool, error) {
+			return h.canViewProject(project, principal)
+		})
+		return
+	}
+
+	previousDepartmentID := project.DepartmentID
+	previousOwnerID := project.OwnerID
+	patch, details := applyProjectPatch(&project, fields)
+	if len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+
+	// Validate the date range the patch leaves behind, including dates it didn't touch
+	if !checkProjectDateRange(c, project.StartDate, project.EndDate) {
+		return
+	}
+
+	if project.DepartmentID != nil && !sameOptionalID(previousDepartmentID, project.DepartmentID) &&
+		!h.checkProjectDepartment(c, *project.DepartmentID) {
+		return
+	}
+	if project.OwnerID != nil && !sameOptionalID(previousOwnerID, project.OwnerID) &&
+		!h.checkProjectOwner(c, principal, *project.OwnerID) {
+		return
+	}
+
+	// Moving the project or handing it over must leave the owner in its department
+	if project.OwnerID != nil &&
+		(!sameOptionalID(previousDepartmentID, project.DepartmentID) || !sameOptionalID(previousOwnerID, project.OwnerID)) &&
+		!h.checkOwnerDepartment(c, principal, *project.OwnerID, project.DepartmentID, allowCrossDepartmentOwner) {
+		return
+	}
+
+	if err := h.saveProject(&project, principal, previousOwnerID); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update project", nil)
+		return
+	}
+
+	// Reload with associations
+	h.db.
+		Preload("Owner").
+		Preload("Department").
+		First(&project, "id = ?", project.ID)
+
+	utils.RespondSuccess(c, http.StatusOK, PatchProjectResponse{
+		Project:       project,
+		UpdatedFields: patch.updated,
+		ClearedFields: patch.cleared,
+	}, "Project updated successfully; omitted fields were left unchanged and null fields were cleared")
+}
+
+// applyProjectPatch applies the keys present in fields to project, collecting per-field
+// validation errors. Referenced departments and owners are checked by the caller.
+func applyProjectPatch(project *models.Project, fields map[string]json.RawMessage) (projectPatch, []utils.ErrorDetail) {
+	patch := projectPatch{updated: []string{}, cleared: []string{}}
+	var details []utils.ErrorDetail
+
+	// Iterate in a stable order so responses are deterministic
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw := fields[key]
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		fail := func(message string) {
+			details = append(details, utils.ErrorDetail{Field: key, Message: message})
+		}
+
+		switch key {
+		case "name", "status":
+			if isNull {
+				fail(key + " cannot be null")
+				continue
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				fail(key + " must be a string")
+				continue
+			}
+			if key == "name" {
+				if value == "" || len([]rune(value)) > 200 {
+					fail("name must be between 1 and 200 characters")
+					continue
+				}
+				project.Name = value
+			} else {
+				if !validProjectStatuses[value] {
+					fail("Invalid status value")
+					continue
+				}
+				project.Status = value
+			}
+
+		case "description", "department_id", "owner_id":
+			var target **string
+			switch key {
+			case "description":
+				target = &project.Description
+			case "department_id":
+				target = &project.DepartmentID
+			case "owner_id":
+				target = &project.OwnerID
+			}
+			var value string
+			if !isNull {
+				if err := json.Unmarshal(raw, &value); err != nil {
+					fail(key + " must be a string or null")
+					continue
+				}
+			}
+			// An empty id clears the reference, as it does for PUT
+			if isNull || (value == "" && key != "description") {
+				*target = nil
+				patch.cleared = append(patch.cleared, key)
+				break
+			}
+			if key != "description" && !utils.IsUUID(value) {
+				fail(key + " must be a UUID")
+				continue
+			}
+			*target = &value
+
+		case "start_date", "end_date":
+			target := &project.StartDate
+			if key == "end_date" {
+				target = &project.EndDate
+			}
+			if isNull {
+				*target = nil
+				patch.cleared = append(patch.cleared, key)
+				break
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				fail(key + " must be an ISO 8601 string or null")
+				continue
+			}
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				fail("Invalid " + key + " format, use ISO 8601")
+				continue
+			}
+			*target = &parsed
+
+		default:
+			fail("Unknown or read-only field")
+			continue
+		}
+
+		patch.updated = append(patch.updated, key)
+	}
+
+	return patch, details
+}
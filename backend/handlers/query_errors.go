@@ -0,0 +1,36 @@
+// ABOUTME: Error responses for queries run with the request context
+// ABOUTME: Separates cancelled or timed-out requests from genuine database failures
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/synapse/backend/utils"
+)
+
+// pgQueryCanceled is the SQLSTATE postgres reports for statements cancelled mid-flight
+const pgQueryCanceled = "57014"
+
+// isQueryCancelled reports whether err came from the request's deadline or disconnect
+// rather than from the database itself
+func isQueryCancelled(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgQueryCanceled
+}
+
+// respondQueryError responds 504 for cancelled queries and 500 with message otherwise
+func respondQueryError(c *gin.Context, err error, message string) {
+	if isQueryCancelled(err) {
+		utils.RespondError(c, http.StatusGatewayTimeout, "REQUEST_TIMEOUT", "The request was cancelled before it completed", nil)
+		return
+	}
+	utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", message, nil)
+}
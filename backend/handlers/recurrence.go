@@ -0,0 +1,47 @@
+// ABOUTME: Validation of task recurrence patterns at the API boundary
+// ABOUTME: Rejects unknown frequencies, non-positive intervals and out-of-range days before they're stored
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+)
+
+// recurrenceFrequencies are the frequencies a RecurrencePattern may use
+var recurrenceFrequencies = map[string]bool{"daily": true, "weekly": true, "monthly": true, "yearly": true}
+
+// ValidateRecurrencePattern checks a recurrence pattern and returns one detail per invalid
+// field, or nil when the pattern is usable. No endpoint sets recurrence yet; anything that
+// starts accepting a pattern must run it through here before saving.
+func ValidateRecurrencePattern(pattern models.RecurrencePattern) []utils.ErrorDetail {
+	var details []utils.ErrorDetail
+	fail := func(field, message string) {
+		details = append(details, utils.ErrorDetail{Field: "recurrence_pattern." + field, Message: message})
+	}
+
+	if !recurrenceFrequencies[pattern.Frequency] {
+		fail("frequency", fmt.Sprintf("Unknown frequency %q, use daily, weekly, monthly or yearly", pattern.Frequency))
+	}
+	if pattern.Interval <= 0 {
+		fail("interval", "interval must be at least 1")
+	}
+	for _, day := range pattern.DaysOfWeek {
+		if day < 1 || day > 7 {
+			fail("daysOfWeek", fmt.Sprintf("Invalid weekday %d, use 1 (Monday) to 7 (Sunday)", day))
+			break
+		}
+	}
+	if pattern.DayOfMonth != nil && (*pattern.DayOfMonth == 0 || *pattern.DayOfMonth < -1 || *pattern.DayOfMonth > 31) {
+		fail("dayOfMonth", "dayOfMonth must be 1 to 31, or -1 for the last day")
+	}
+	if pattern.MonthOfYear != nil && (*pattern.MonthOfYear < 1 || *pattern.MonthOfYear > 12) {
+		fail("monthOfYear", "monthOfYear must be 1 to 12")
+	}
+	if pattern.Count != nil && *pattern.Count <= 0 {
+		fail("count", "count must be at least 1")
+	}
+	return details
+}
@@ -0,0 +1,243 @@
+// ABOUTME: Role handlers listing roles with their permissions and managing custom roles
+// ABOUTME: Admins create roles and edit permission sets; built-in roles cannot be deleted
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// roleNamePattern keeps role names to a single word that fits users.role
+var roleNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,19}$`)
+
+var errRoleExists = errors.New("role already exists")
+
+type RoleHandler struct {
+	db *gorm.DB
+}
+
+func NewRoleHandler(db *gorm.DB) *RoleHandler {
+	return &RoleHandler{db: db}
+}
+
+// RoleResponse is one role with its permissions
+type RoleResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	IsBuiltin   bool     `json:"is_builtin"`
+}
+
+// CreateRoleRequest represents the role creation request body
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// UpdateRoleRequest replaces a role's permission set
+type UpdateRoleRequest struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// GetRoles lists every role, built-in roles first, with its permissions
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	roles := []RoleResponse{}
+	for _, role := range auth.Roles() {
+		roles = append(roles, roleResponse(role))
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, roles, "Roles retrieved successfully")
+}
+
+// CreateRole adds a custom role with the given permissions. Permissions gate routes and, for
+// projects.create and projects.update, project changes in the caller's department; rules
+// written for a specific built-in role, like Managers' department-wide task access, don't apply.
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if !roleNamePattern.MatchString(req.Name) {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR",
+			"Role name must start with a letter and use at most 20 letters, digits, _ or -", nil)
+		return
+	}
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Role{}).Where("LOWER(name) = LOWER(?)", req.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errRoleExists
+		}
+		if err := tx.Create(&models.Role{Name: req.Name}).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, req.Name, permissions)
+	})
+	if err == errRoleExists {
+		utils.RespondError(c, http.StatusConflict, "ROLE_EXISTS", "Role with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create role", nil)
+		return
+	}
+	if !h.reloadRoles(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, roleResponse(req.Name), "Role created successfully")
+}
+
+// UpdateRole replaces a role's permission set. Admin always holds every permission.
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	name := c.Param("name")
+
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	role, ok := h.loadRole(c, name)
+	if !ok {
+		return
+	}
+	if role.Name == auth.RoleAdmin {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "The Admin role's permissions cannot be changed", nil)
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&role).UpdateColumn("updated_at", gorm.Expr("NOW()")).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role.Name, permissions)
+	}); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update role", nil)
+		return
+	}
+	if !h.reloadRoles(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, roleResponse(role.Name), "Role updated successfully")
+}
+
+// DeleteRole removes a custom role that no user holds
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	role, ok := h.loadRole(c, c.Param("name"))
+	if !ok {
+		return
+	}
+	if role.IsBuiltin {
+		utils.RespondError(c, http.StatusConflict, "ROLE_BUILTIN", "Built-in roles cannot be deleted", nil)
+		return
+	}
+
+	var userCount int64
+	if err := h.db.Model(&models.User{}).Where("role = ?", role.Name).Count(&userCount).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check role users", nil)
+		return
+	}
+	if userCount > 0 {
+		utils.RespondError(c, http.StatusConflict, "ROLE_HAS_USERS", "Cannot delete a role that users still hold", nil)
+		return
+	}
+
+	// role_permissions rows go with the role
+	if err := h.db.Delete(&role).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete role", nil)
+		return
+	}
+	if !h.reloadRoles(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Role deleted successfully")
+}
+
+// loadRole fetches a role by name, responding 404 when it doesn't exist
+func (h *RoleHandler) loadRole(c *gin.Context, name string) (models.Role, bool) {
+	var role models.Role
+	if err := h.db.First(&role, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "ROLE_NOT_FOUND", "Role not found", nil)
+			return role, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch role", nil)
+		return role, false
+	}
+	return role, true
+}
+
+// reloadRoles refreshes the cached role mapping after a change so new tokens reflect it
+func (h *RoleHandler) reloadRoles(c *gin.Context) bool {
+	if err := auth.LoadRoles(h.db); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Role saved but failed to reload roles", nil)
+		return false
+	}
+	return true
+}
+
+// replaceRolePermissions swaps the role's permission rows for permissions
+func replaceRolePermissions(tx *gorm.DB, role string, permissions []string) error {
+	if err := tx.Where("role_name = ?", role).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+	grants := make([]models.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		grants = append(grants, models.RolePermission{RoleName: role, Permission: permission})
+	}
+	return tx.Create(&grants).Error
+}
+
+// normalizePermissions rejects unknown permissions and returns the rest sorted without duplicates
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	normalized := []string{}
+	for _, permission := range permissions {
+		if !auth.IsKnownPermission(permission) {
+			return nil, fmt.Errorf("Unknown permission: %s", permission)
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			normalized = append(normalized, permission)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func roleResponse(role string) RoleResponse {
+	return RoleResponse{
+		Role:        role,
+		Permissions: auth.PermissionsForRole(role),
+		IsBuiltin:   auth.IsBuiltinRole(role),
+	}
+}
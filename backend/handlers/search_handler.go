@@ -0,0 +1,227 @@
+// ABOUTME: Global search handler across tasks, projects and users
+// ABOUTME: Applies each resource's visibility rules and returns one ranked result list
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+type SearchHandler struct {
+	db *gorm.DB
+}
+
+func NewSearchHandler(db *gorm.DB) *SearchHandler {
+	return &SearchHandler{db: db}
+}
+
+// SearchResult is one matched resource; Type says which resource Data holds
+type SearchResult struct {
+	Type     string      `json:"type"`
+	ID       string      `json:"id"`
+	Title    string      `json:"title"`
+	Subtitle string      `json:"subtitle,omitempty"`
+	Score    int         `json:"score"`
+	Data     interface{} `json:"data"`
+}
+
+// Searchable resource types, in the order ties are broken
+var searchTypes = []string{"tasks", "projects", "users"}
+
+// Search runs q against each requested type the caller's role can read and merges the
+// visible matches by rank
+func (h *SearchHandler) Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Query parameter q is required", nil)
+		return
+	}
+
+	types, err := parseSearchTypes(c.Query("types"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	// Cap results per type
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit < 1 || limit > 20 {
+		limit = 5
+	}
+
+	// Get user context
+	principal := auth.FromContext(c)
+
+	// Skip types the role can't read, as their list endpoints would refuse it
+	for t := range types {
+		if !auth.HasPermission(principal.Role, t+".read") {
+			delete(types, t)
+		}
+	}
+	if len(types) == 0 {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Insufficient permissions", nil)
+		return
+	}
+
+	pattern := "%" + escapeLike(q) + "%"
+	results := []SearchResult{}
+
+	if types["tasks"] {
+		var tasks []models.Task
+		query := auth.ScopeTasks(h.db.Model(&models.Task{}), principal)
+		if err := query.
+			Where("title ILIKE ? OR description ILIKE ?", pattern, pattern).
+			Order("updated_at DESC, id DESC").
+			Limit(limit).
+			Find(&tasks).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to search tasks", nil)
+			return
+		}
+		if err := repository.LoadTaskAssignees(h.db, tasks); err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+			return
+		}
+		for _, task := range tasks {
+			results = append(results, SearchResult{
+				Type:     "tasks",
+				ID:       task.ID,
+				Title:    task.Title,
+				Subtitle: task.Status,
+				Score:    searchScore(q, task.Title),
+				Data:     task,
+			})
+		}
+	}
+
+	if types["projects"] {
+		var projects []models.Project
+		query := auth.ScopeProjects(h.db.Model(&models.Project{}), principal)
+		if err := query.
+			Where("name ILIKE ? OR description ILIKE ? OR code ILIKE ?", pattern, pattern, pattern).
+			Order("updated_at DESC, id DESC").
+			Limit(limit).
+			Find(&projects).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to search projects", nil)
+			return
+		}
+		for _, project := range projects {
+			results = append(results, SearchResult{
+				Type:     "projects",
+				ID:       project.ID,
+				Title:    project.Name,
+				Subtitle: project.ProjectID,
+				Score:    searchScore(q, project.Name, project.ProjectID),
+				Data:     project,
+			})
+		}
+	}
+
+	if types["users"] {
+		var users []models.User
+		query := auth.ScopeUsers(h.db.Model(&models.User{}), principal)
+		if err := query.
+			Where("full_name ILIKE ? OR email ILIKE ? OR username ILIKE ?", pattern, pattern, pattern).
+			Order("full_name ASC, id ASC").
+			Limit(limit).
+			Find(&users).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to search users", nil)
+			return
+		}
+		for _, user := range users {
+			// Clear password hash
+			user.PasswordHash = nil
+			results = append(results, SearchResult{
+				Type:     "users",
+				ID:       user.ID,
+				Title:    user.FullName,
+				Subtitle: user.Email,
+				Score:    searchScore(q, user.FullName, user.Username, user.Email),
+				Data:     user,
+			})
+		}
+	}
+
+	rankSearchResults(results)
+
+	utils.RespondSuccess(c, http.StatusOK, results, "Search completed successfully")
+}
+
+// parseSearchTypes parses the comma-separated types filter; empty means all types
+func parseSearchTypes(value string) (map[string]bool, error) {
+	types := make(map[string]bool)
+	if strings.TrimSpace(value) == "" {
+		for _, t := range searchTypes {
+			types[t] = true
+		}
+		return types, nil
+	}
+
+	valid := make(map[string]bool)
+	for _, t := range searchTypes {
+		valid[t] = true
+	}
+	for _, t := range strings.Split(value, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if !valid[t] {
+			return nil, fmt.Errorf("Unsupported search type: %s (use %s)", t, strings.Join(searchTypes, ", "))
+		}
+		types[t] = true
+	}
+	return types, nil
+}
+
+// searchScore ranks how well q matches the best of the given fields:
+// exact match 3, prefix 2, word prefix 1, anything else 0
+func searchScore(q string, fields ...string) int {
+	q = strings.ToLower(q)
+	best := 0
+	for _, field := range fields {
+		field = strings.ToLower(field)
+		score := 0
+		switch {
+		case field == q:
+			score = 3
+		case strings.HasPrefix(field, q):
+			score = 2
+		case strings.Contains(field, " "+q):
+			score = 1
+		}
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// rankSearchResults orders results by score, keeping each type's own ordering for ties
+func rankSearchResults(results []SearchResult) {
+	typeOrder := make(map[string]int)
+	for i, t := range searchTypes {
+		typeOrder[t] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return typeOrder[results[i].Type] < typeOrder[results[j].Type]
+	})
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
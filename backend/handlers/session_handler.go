@@ -0,0 +1,163 @@
+// ABOUTME: Login session handlers for listing and revoking a user's refresh-token sessions
+// ABOUTME: Each login starts a session; its access and refresh tokens only work while it is active
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// sessionTTL matches the refresh token lifetime; a session lapses when it goes that long unused
+const sessionTTL = utils.RefreshTokenHours * time.Hour
+
+// maxUserAgentLength caps the stored User-Agent, which clients control
+const maxUserAgentLength = 512
+
+// SessionResponse describes one of the caller's active sessions
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// RevokeSessionsResponse reports how many sessions were revoked
+type RevokeSessionsResponse struct {
+	RevokedCount int64 `json:"revoked_count"`
+}
+
+// ListSessions returns the caller's active sessions, most recently used first. The session
+// the request's access token belongs to is marked current.
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	var sessions []models.Session
+	if err := h.db.WithContext(c.Request.Context()).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", c.GetString("user_id"), time.Now().UTC()).
+		Order("last_used_at DESC, created_at DESC").
+		Find(&sessions).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch sessions")
+		return
+	}
+
+	current := c.GetString("session_id")
+	result := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		result[i] = SessionResponse{
+			ID:         session.ID,
+			Device:     describeDevice(session.UserAgent),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == current,
+		}
+	}
+	utils.RespondSuccess(c, http.StatusOK, result, "Sessions retrieved successfully")
+}
+
+// RevokeSession ends one of the caller's sessions so its refresh token stops working
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	revoked, err := revokeSessions(h.db.Where("id = ?", c.Param("id")), c.GetString("user_id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to revoke session", nil)
+		return
+	}
+	if revoked == 0 {
+		utils.RespondError(c, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found", nil)
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, nil, "Session revoked successfully")
+}
+
+// RevokeOtherSessions ends every session of the caller's except the one making the request
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	revoked, err := revokeOtherSessions(h.db, c.GetString("user_id"), c.GetString("session_id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to revoke sessions", nil)
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, RevokeSessionsResponse{RevokedCount: revoked}, "Other sessions revoked successfully")
+}
+
+// startSession records a new login session for userID on the requesting client
+func startSession(db *gorm.DB, c *gin.Context, userID string) (models.Session, error) {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	now := time.Now().UTC()
+	session := models.Session{
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  c.ClientIP(),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(sessionTTL),
+	}
+	err := db.Create(&session).Error
+	return session, err
+}
+
+// touchSession marks an active session as used and extends it, reporting false when the
+// session has been revoked, has expired or belongs to someone else
+func touchSession(db *gorm.DB, userID, sessionID string) (bool, error) {
+	now := time.Now().UTC()
+	result := db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, userID, now).
+		UpdateColumns(map[string]interface{}{"last_used_at": now, "expires_at": now.Add(sessionTTL)})
+	return result.RowsAffected > 0, result.Error
+}
+
+// revokeSessions revokes userID's active sessions matched by scope and returns how many it revoked
+func revokeSessions(scope *gorm.DB, userID string) (int64, error) {
+	result := scope.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		UpdateColumn("revoked_at", time.Now().UTC())
+	return result.RowsAffected, result.Error
+}
+
+// revokeOtherSessions revokes userID's active sessions except currentID, or all of them when
+// currentID is empty
+func revokeOtherSessions(db *gorm.DB, userID, currentID string) (int64, error) {
+	scope := db
+	if currentID != "" {
+		scope = scope.Where("id <> ?", currentID)
+	}
+	return revokeSessions(scope, userID)
+}
+
+// describeDevice summarises a User-Agent as "<browser> on <platform>" for display
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+	browser := firstMatch(userAgent, "Unknown browser",
+		"Edg/", "Edge", "OPR/", "Opera", "Firefox/", "Firefox", "Chrome/", "Chrome",
+		"Safari/", "Safari", "curl/", "curl", "okhttp", "Android app", "CFNetwork", "iOS app")
+	platform := firstMatch(userAgent, "unknown platform",
+		"iPhone", "iOS", "iPad", "iPadOS", "Android", "Android", "Windows", "Windows",
+		"Mac OS X", "macOS", "Macintosh", "macOS", "CrOS", "ChromeOS", "Linux", "Linux")
+	return browser + " on " + platform
+}
+
+// firstMatch returns the name paired with the first marker found in s, checking the
+// marker/name pairs in order, or fallback when none match
+func firstMatch(s, fallback string, pairs ...string) string {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if strings.Contains(s, pairs[i]) {
+			return pairs[i+1]
+		}
+	}
+	return fallback
+}
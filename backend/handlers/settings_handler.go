@@ -0,0 +1,160 @@
+// ABOUTME: Application settings handlers for Admin-adjustable limits
+// ABOUTME: Lists known settings with their effective values and lets Admins override them
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const settingMaxTaskAssignees = "max_task_assignees"
+
+// settingDefinition describes an integer setting and the range an Admin may set it to
+type settingDefinition struct {
+	Description string
+	Default     int
+	Min         int
+	Max         int
+}
+
+// settingDefinitions lists every setting the API understands; unknown keys are rejected
+var settingDefinitions = map[string]settingDefinition{
+	settingMaxTaskAssignees: {Description: "Maximum number of assignees per task", Default: 25, Min: 1, Max: 1000},
+}
+
+type SettingsHandler struct {
+	db *gorm.DB
+}
+
+func NewSettingsHandler(db *gorm.DB) *SettingsHandler {
+	return &SettingsHandler{db: db}
+}
+
+// SettingResponse is a setting's effective value alongside its default and bounds
+type SettingResponse struct {
+	Key         string     `json:"key"`
+	Value       int        `json:"value"`
+	Default     int        `json:"default"`
+	Min         int        `json:"min"`
+	Max         int        `json:"max"`
+	Description string     `json:"description"`
+	UpdatedBy   *string    `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateSettingRequest represents the setting update request body
+type UpdateSettingRequest struct {
+	Value *int `json:"value" binding:"required"`
+}
+
+// GetSettings returns every known setting with its effective value
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	var stored []models.AppSetting
+	if err := h.db.Find(&stored).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch settings", nil)
+		return
+	}
+	storedByKey := make(map[string]models.AppSetting, len(stored))
+	for _, setting := range stored {
+		storedByKey[setting.Key] = setting
+	}
+
+	keys := make([]string, 0, len(settingDefinitions))
+	for key := range settingDefinitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	settings := make([]SettingResponse, 0, len(keys))
+	for _, key := range keys {
+		var override *models.AppSetting
+		if setting, ok := storedByKey[key]; ok {
+			override = &setting
+		}
+		settings = append(settings, settingResponse(key, override))
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, settings, "Settings retrieved successfully")
+}
+
+// UpdateSetting overrides a setting's value within its allowed range
+func (h *SettingsHandler) UpdateSetting(c *gin.Context) {
+	key := c.Param("key")
+	definition, ok := settingDefinitions[key]
+	if !ok {
+		utils.RespondError(c, http.StatusNotFound, "SETTING_NOT_FOUND", "Setting not found", nil)
+		return
+	}
+
+	var req UpdateSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if *req.Value < definition.Min || *req.Value > definition.Max {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR",
+			fmt.Sprintf("%s must be between %d and %d", key, definition.Min, definition.Max), nil)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	updatedBy := userID.(string)
+	setting := models.AppSetting{
+		Key:       key,
+		Value:     strconv.Itoa(*req.Value),
+		UpdatedBy: &updatedBy,
+		UpdatedAt: time.Now(),
+	}
+	if err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+	}).Create(&setting).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update setting", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, settingResponse(key, &setting), "Setting updated successfully")
+}
+
+// settingResponse renders a known setting, applying the stored override when there is one
+func settingResponse(key string, stored *models.AppSetting) SettingResponse {
+	definition := settingDefinitions[key]
+	response := SettingResponse{
+		Key:         key,
+		Value:       definition.Default,
+		Default:     definition.Default,
+		Min:         definition.Min,
+		Max:         definition.Max,
+		Description: definition.Description,
+	}
+	if stored != nil {
+		if value, err := strconv.Atoi(stored.Value); err == nil {
+			response.Value = value
+		}
+		response.UpdatedBy = stored.UpdatedBy
+		response.UpdatedAt = &stored.UpdatedAt
+	}
+	return response
+}
+
+// intSetting returns a setting's effective value, falling back to its default when unset
+func intSetting(db *gorm.DB, key string) (int, error) {
+	var setting models.AppSetting
+	if err := db.First(&setting, "key = ?", key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return settingDefinitions[key].Default, nil
+		}
+		return 0, err
+	}
+	return settingResponse(key, &setting).Value, nil
+}
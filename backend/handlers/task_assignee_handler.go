@@ -0,0 +1,215 @@
+// ABOUTME: Task assignee handlers for paginated listing and single-assignee add/remove
+// ABOUTME: Changes lock the task row and bump its version so concurrent edits don't overwrite each other
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errAssigneeLimit signals that an added assignee would exceed max_task_assignees
+var errAssigneeLimit = errors.New("assignee limit reached")
+
+// AddTaskAssigneeRequest represents the add assignee request body
+type AddTaskAssigneeRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// TaskAssigneesResponse is a task's assignee list after a change
+type TaskAssigneesResponse struct {
+	TaskID      string   `json:"task_id"`
+	AssigneeIDs []string `json:"assignee_ids"`
+}
+
+// GetTaskAssignees returns a page of the users assigned to a task the caller can see, in the
+// order they were assigned, so large assignee lists can be loaded lazily
+func (h *TaskHandler) GetTaskAssignees(c *gin.Context) {
+	page, perPage := utils.ParsePagination(c)
+
+	task, ok := h.loadVisibleTask(c)
+	if !ok {
+		return
+	}
+
+	query := h.db.WithContext(c.Request.Context()).Model(&models.User{}).
+		Joins("JOIN task_assignees ON task_assignees.user_id = users.id").
+		Where("task_assignees.task_id = ?", task.ID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		respondQueryError(c, err, "Failed to count task assignees")
+		return
+	}
+
+	users := []models.User{}
+	if err := query.
+		Order("task_assignees.assigned_at ASC, users.id ASC").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Find(&users).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch task assignees")
+		return
+	}
+	for i := range users {
+		users[i].PasswordHash = nil
+	}
+
+	utils.RespondSuccessWithPagination(c, users, page, perPage, total)
+}
+
+// AddTaskAssignee assigns one user to a task; assigning an existing assignee is a no-op
+func (h *TaskHandler) AddTaskAssignee(c *gin.Context) {
+	var req AddTaskAssigneeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	// A malformed id can't name a user, and would fail the uuid lookup below
+	if !utils.IsUUID(req.UserID) {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_ASSIGNEE", "Assignee not found: "+req.UserID, nil)
+		return
+	}
+
+	task, ok := h.loadModifiableTask(c)
+	if !ok {
+		return
+	}
+
+	// Validate user exists
+	var user models.User
+	if err := h.db.First(&user, "id = ?", req.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_ASSIGNEE", "Assignee not found: "+req.UserID, nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate assignee", nil)
+		return
+	}
+
+	// The task row lock serializes assignee changes, so concurrent adds can't together pass
+	// the limit and every change moves the task's version on
+	limit := 0
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockTaskVersion(tx, &task); err != nil {
+			return err
+		}
+
+		var current []string
+		if err := tx.Table("task_assignees").Where("task_id = ?", task.ID).Pluck("user_id", &current).Error; err != nil {
+			return err
+		}
+		var err error
+		if limit, err = intSetting(tx, settingMaxTaskAssignees); err != nil {
+			return err
+		}
+		if distinctCount(append(current, user.ID)) > limit {
+			return errAssigneeLimit
+		}
+
+		result := tx.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING", task.ID, user.ID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return bumpTaskVersion(tx, &task)
+	})
+	if err == errAssigneeLimit {
+		respondAssigneeLimit(c, limit)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to assign user", nil)
+		return
+	}
+
+	h.respondTaskAssignees(c, task, "Assignee added successfully")
+}
+
+// RemoveTaskAssignee unassigns one user from a task; removing a non-assignee is a no-op
+func (h *TaskHandler) RemoveTaskAssignee(c *gin.Context) {
+	task, ok := h.loadModifiableTask(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockTaskVersion(tx, &task); err != nil {
+			return err
+		}
+
+		result := tx.Exec("DELETE FROM task_assignees WHERE task_id = ? AND user_id = ?", task.ID, c.Param("userId"))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return bumpTaskVersion(tx, &task)
+	})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to unassign user", nil)
+		return
+	}
+
+	h.respondTaskAssignees(c, task, "Assignee removed successfully")
+}
+
+// lockTaskVersion locks the task row for the rest of tx and refreshes task.Version from it
+func lockTaskVersion(tx *gorm.DB, task *models.Task) error {
+	var locked models.Task
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "version").First(&locked, "id = ?", task.ID).Error; err != nil {
+		return err
+	}
+	task.Version = locked.Version
+	return nil
+}
+
+// respondTaskAssignees reloads and returns the task's current assignee IDs
+func (h *TaskHandler) respondTaskAssignees(c *gin.Context, task models.Task, message string) {
+	tasks := []models.Task{task}
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return
+	}
+
+	setTaskETag(c, task)
+	utils.RespondSuccess(c, http.StatusOK, TaskAssigneesResponse{
+		TaskID:      task.ID,
+		AssigneeIDs: tasks[0].Assignees,
+	}, message)
+}
+
+// checkAssigneeLimit rejects assignee lists longer than the max_task_assignees setting,
+// responding and returning false when the request should stop
+func (h *TaskHandler) checkAssigneeLimit(c *gin.Context, assigneeIDs []string) bool {
+	limit, err := intSetting(h.db, settingMaxTaskAssignees)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load assignee limit", nil)
+		return false
+	}
+
+	if distinctCount(assigneeIDs) > limit {
+		respondAssigneeLimit(c, limit)
+		return false
+	}
+	return true
+}
+
+// respondAssigneeLimit rejects an assignee list longer than limit with 422
+func respondAssigneeLimit(c *gin.Context, limit int) {
+	message := fmt.Sprintf("A task can have at most %d assignees", limit)
+	utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", message, []utils.ErrorDetail{{Field: "assignee_ids", Message: message}})
+}
+
+// distinctCount counts the unique IDs in ids
+func distinctCount(ids []string) int {
+	distinct := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		distinct[id] = true
+	}
+	return len(distinct)
+}
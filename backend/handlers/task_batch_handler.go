@@ -0,0 +1,74 @@
+// ABOUTME: Batch task lookup for clients refreshing a known set of tasks
+// ABOUTME: Returns the requested tasks the caller may see and silently drops the rest
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+)
+
+// maxBatchGetTasks caps how many ids one batch-get request may ask for
+const maxBatchGetTasks = 100
+
+// BatchGetTasksRequest lists the task ids to fetch
+type BatchGetTasksRequest struct {
+	IDs []string `json:"ids" binding:"required,dive,uuid"`
+}
+
+// BatchGetTasks returns the requested tasks in request order, with assignees. Missing tasks and
+// tasks the caller can't see are left out rather than reported, so ids can't be probed.
+func (h *TaskHandler) BatchGetTasks(c *gin.Context) {
+	var req BatchGetTasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if len(req.IDs) > maxBatchGetTasks {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR",
+			fmt.Sprintf("At most %d ids can be requested at once", maxBatchGetTasks), nil)
+		return
+	}
+
+	// Ask for each id once, remembering the order they were requested in
+	ids := uniqueIDs(req.IDs)
+	found, err := h.tasks.FindManyWithRelations(ids)
+	if err != nil {
+		respondQueryError(c, err, "Failed to fetch tasks")
+		return
+	}
+
+	// One query for every task's assignees, which the access check needs
+	if err := h.tasks.LoadAssignees(found); err != nil {
+		respondQueryError(c, err, "Failed to load task assignees")
+		return
+	}
+
+	principal := auth.FromContext(c)
+	byID := make(map[string]models.Task, len(found))
+	for _, task := range found {
+		if auth.CanAccessTask(principal, task) {
+			byID[task.ID] = task
+		}
+	}
+
+	tasks := make([]models.Task, 0, len(byID))
+	for _, id := range ids {
+		if task, ok := byID[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	if wantsExpand(c, "assignees") {
+		if err := h.tasks.LoadAssigneeDetails(tasks); err != nil {
+			respondQueryError(c, err, "Failed to load task assignees")
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, tasks, "Tasks retrieved successfully")
+}
@@ -0,0 +1,191 @@
+// ABOUTME: Board view handler returning tasks grouped into columns in one response
+// ABOUTME: Groups share the task list's filters and visibility and are each capped with a total
+
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// Tasks per board group, by default and at most
+const (
+	defaultBoardGroupLimit = 20
+	maxBoardGroupLimit     = 100
+)
+
+// boardGroupings are the ?group_by= values; status is the default
+var boardGroupings = []string{"status", "assignee", "priority", "project"}
+
+// BoardGroup is one board column: its key, how many tasks match in total and the first of them
+type BoardGroup struct {
+	Key   string        `json:"key"`
+	Total int64         `json:"total"`
+	Tasks []models.Task `json:"tasks"`
+}
+
+// BoardResponse lists the board's groups in column order
+type BoardResponse struct {
+	GroupBy string       `json:"group_by"`
+	Limit   int          `json:"limit"`
+	Groups  []BoardGroup `json:"groups"`
+}
+
+// GetTaskBoard returns the tasks GetTasks would list, grouped by ?group_by=status (default),
+// assignee, priority or project. Each group holds at most ?limit= tasks in board order, with
+// its total over the full filtered set. Status columns follow the workflow of ?department_id=
+// (or the default one), priority columns run Low to Urgent, and tasks without a value fall in
+// a "none" group. A task with several assignees appears under each of them.
+func (h *TaskHandler) GetTaskBoard(c *gin.Context) {
+	groupBy := c.DefaultQuery("group_by", "status")
+	if !slices.Contains(boardGroupings, groupBy) {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", []utils.ErrorDetail{{Field: "group_by", Message: "group_by must be status, assignee, priority or project"}})
+		return
+	}
+	limit := defaultBoardGroupLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxBoardGroupLimit {
+			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", []utils.ErrorDetail{{Field: "limit", Message: "limit must be between 1 and 100"}})
+			return
+		}
+		limit = parsed
+	}
+
+	query, ok := filteredTaskQuery(c, h.db)
+	if !ok {
+		return
+	}
+	db := h.db.WithContext(c.Request.Context())
+
+	totals, err := boardGroupTotals(db, query, groupBy)
+	if err != nil {
+		respondQueryError(c, err, "Failed to count tasks")
+		return
+	}
+
+	orderBy := taskListOrder(c, "rank")
+	groups := []BoardGroup{}
+	for _, key := range boardGroupKeys(c, groupBy, totals) {
+		group := BoardGroup{Key: key, Total: totals[key], Tasks: []models.Task{}}
+		if group.Total > 0 {
+			if err := boardGroupScope(query.Session(&gorm.Session{}), groupBy, key).
+				Preload("Creator").
+				Preload("Department").
+				Preload("Project").
+				Order(orderBy).
+				Limit(limit).
+				Find(&group.Tasks).Error; err != nil {
+				respondQueryError(c, err, "Failed to fetch tasks")
+				return
+			}
+			if !loadListAssignees(c, db, group.Tasks) {
+				return
+			}
+			if err := loadChecklistProgress(db, &group.Tasks); err != nil {
+				respondQueryError(c, err, "Failed to load checklist progress")
+				return
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, BoardResponse{GroupBy: groupBy, Limit: limit, Groups: groups}, "")
+}
+
+// boardGroupTotals counts the filtered tasks in each group, with tasks lacking a value
+// under filterNone
+func boardGroupTotals(db, query *gorm.DB, groupBy string) (map[string]int64, error) {
+	var rows []struct {
+		Value *string
+		Count int64
+	}
+	var err error
+	if groupBy == "assignee" {
+		matched := query.Session(&gorm.Session{}).Select("id")
+		err = db.Table("tasks").
+			Select("task_assignees.user_id AS value, COUNT(*) AS count").
+			Joins("LEFT JOIN task_assignees ON task_assignees.task_id = tasks.id").
+			Where("tasks.id IN (?)", matched).
+			Group("task_assignees.user_id").
+			Scan(&rows).Error
+	} else {
+		column := boardGroupColumn(groupBy)
+		err = query.Session(&gorm.Session{}).
+			Select(column + " AS value, COUNT(*) AS count").
+			Group(column).
+			Scan(&rows).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		key := filterNone
+		if row.Value != nil {
+			key = *row.Value
+		}
+		totals[key] += row.Count
+	}
+	return totals, nil
+}
+
+// boardGroupKeys lists the groups in column order. Status and priority boards always show
+// their full set of columns; any other value with tasks follows, then the "none" group.
+func boardGroupKeys(c *gin.Context, groupBy string, totals map[string]int64) []string {
+	var keys []string
+	switch groupBy {
+	case "status":
+		var departmentID *string
+		if value := c.Query("department_id"); value != "" && value != filterNone {
+			departmentID = &value
+		}
+		keys = workflowStatusNames(repository.Workflow(departmentID))
+	case "priority":
+		keys = slices.Clone(TaskPriorities)
+	}
+
+	var extra []string
+	for key := range totals {
+		if key != filterNone && !slices.Contains(keys, key) {
+			extra = append(extra, key)
+		}
+	}
+	slices.Sort(extra)
+	keys = append(keys, extra...)
+	if totals[filterNone] > 0 {
+		keys = append(keys, filterNone)
+	}
+	return keys
+}
+
+// boardGroupScope narrows the filtered query to one group's tasks
+func boardGroupScope(query *gorm.DB, groupBy, key string) *gorm.DB {
+	if groupBy == "assignee" {
+		if key == filterNone {
+			return query.Where("NOT EXISTS (SELECT 1 FROM task_assignees WHERE task_assignees.task_id = tasks.id)")
+		}
+		return query.Where("id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", key)
+	}
+	column := boardGroupColumn(groupBy)
+	if key == filterNone {
+		return query.Where(column + " IS NULL")
+	}
+	return query.Where(column+" = ?", key)
+}
+
+// boardGroupColumn is the tasks column a status, priority or project board groups on
+func boardGroupColumn(groupBy string) string {
+	if groupBy == "project" {
+		return "project_id"
+	}
+	return groupBy
+}
@@ -0,0 +1,80 @@
+// ABOUTME: Bulk task deletion for clearing out many tasks at once
+// ABOUTME: Deletes the tasks the caller may delete and reports the ones it skipped
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/utils"
+)
+
+// maxBulkDeleteTasks caps how many ids one bulk delete may name
+const maxBulkDeleteTasks = 100
+
+// BulkDeleteTasksRequest lists the task ids to delete
+type BulkDeleteTasksRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,dive,uuid"`
+}
+
+// BulkDeleteTasksResponse reports what happened to each requested id. Tasks that don't exist
+// or that the caller can't see are reported as not found, like the single-task endpoints.
+type BulkDeleteTasksResponse struct {
+	Deleted      []string `json:"deleted"`
+	Forbidden    []string `json:"forbidden"`
+	NotFound     []string `json:"not_found"`
+	DeletedCount int      `json:"deleted_count"`
+	SkippedCount int      `json:"skipped_count"`
+}
+
+// BulkDeleteTasks deletes the requested tasks the caller may delete (Admins, and creators
+// whose role grants tasks.delete) in one transaction, skipping the rest
+func (h *TaskHandler) BulkDeleteTasks(c *gin.Context) {
+	var req BulkDeleteTasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if len(req.IDs) > maxBulkDeleteTasks {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR",
+			fmt.Sprintf("At most %d tasks can be deleted at once", maxBulkDeleteTasks), nil)
+		return
+	}
+
+	ids := uniqueIDs(req.IDs)
+	found, err := h.tasks.FindManyWithRelations(ids)
+	if err != nil {
+		respondQueryError(c, err, "Failed to fetch tasks")
+		return
+	}
+	// Visibility depends on assignees
+	if err := h.tasks.LoadAssignees(found); err != nil {
+		respondQueryError(c, err, "Failed to load task assignees")
+		return
+	}
+
+	principal := auth.FromContext(c)
+	access := make(map[string]bulkAccess, len(found))
+	for _, task := range found {
+		switch {
+		case auth.CanDeleteTask(principal, task):
+			access[task.ID] = bulkAllowed
+		case auth.CanAccessTask(principal, task):
+			access[task.ID] = bulkVisible
+		}
+	}
+	targets := splitBulkTargets(ids, access)
+	result := BulkDeleteTasksResponse{Deleted: targets.Allowed, Forbidden: targets.Forbidden, NotFound: targets.NotFound}
+
+	if err := h.tasks.DeleteMany(result.Deleted); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete tasks", nil)
+		return
+	}
+
+	result.DeletedCount = len(result.Deleted)
+	result.SkippedCount = targets.skipped()
+	utils.RespondSuccess(c, http.StatusOK, result, fmt.Sprintf("Deleted %d tasks, skipped %d", result.DeletedCount, result.SkippedCount))
+}
@@ -0,0 +1,71 @@
+// ABOUTME: Due date suggestions counted in business days
+// ABOUTME: Skips the weekend days and holidays of the department's working calendar
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// maxSuggestedWorkingDays bounds how far ahead a suggestion may reach
+const maxSuggestedWorkingDays = 365
+
+// DueDateSuggestion is the date a given number of working days ahead
+type DueDateSuggestion struct {
+	DueDate      string  `json:"due_date"`
+	From         string  `json:"from"`
+	Days         int     `json:"days"`
+	DepartmentID *string `json:"department_id,omitempty"`
+}
+
+// SuggestDueDate returns the date ?days= working days after today (or ?from=YYYY-MM-DD) for
+// ?department_id=, defaulting to the caller's department
+func (h *TaskHandler) SuggestDueDate(c *gin.Context) {
+	days, err := strconv.Atoi(c.Query("days"))
+	if err != nil || days < 1 || days > maxSuggestedWorkingDays {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "days must be a whole number between 1 and 365", nil)
+		return
+	}
+
+	from := time.Now().UTC()
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(dateLayout, value); err != nil {
+			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must be a date in YYYY-MM-DD format", nil)
+			return
+		}
+	}
+
+	departmentID := auth.FromContext(c).DepartmentID
+	if value := c.Query("department_id"); value != "" {
+		departmentID = &value
+	}
+
+	calendar := defaultWorkingCalendar
+	if departmentID != nil {
+		var department models.Department
+		if err := h.db.First(&department, "id = ?", *departmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.RespondError(c, http.StatusBadRequest, "INVALID_DEPARTMENT", "Department not found", nil)
+				return
+			}
+			respondQueryError(c, err, "Failed to fetch department")
+			return
+		}
+		calendar = departmentWorkingCalendar(department.Metadata)
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, DueDateSuggestion{
+		DueDate:      calendar.addWorkingDays(from, days).Format(dateLayout),
+		From:         from.Format(dateLayout),
+		Days:         days,
+		DepartmentID: departmentID,
+	}, "Due date suggested successfully")
+}
@@ -0,0 +1,122 @@
+// ABOUTME: Task duplication handler for cloning a task as a starting point
+// ABOUTME: Copies the task's content into a fresh To Do task owned by the caller
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// maxTaskTitleLength matches CreateTaskRequest's title limit
+const maxTaskTitleLength = 255
+
+// DuplicateTask copies a task the caller can see into a new task they create. Title,
+// description, priority, tags, custom fields, department and project are copied; ?assignees=true and
+// ?checklist=true also copy the assignees and the checklist (with every item undone).
+func (h *TaskHandler) DuplicateTask(c *gin.Context) {
+	copyAssignees := c.Query("assignees") == "true"
+	copyChecklist := c.Query("checklist") == "true"
+
+	source, ok := h.loadVisibleTask(c)
+	if !ok {
+		return
+	}
+
+	// The route requires tasks.create; the copy stays in the source's department
+	principal := auth.FromContext(c)
+	if !auth.CanCreateTask(principal, source.DepartmentID) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You can only create tasks in your own department", nil)
+		return
+	}
+
+	var assigneeIDs []string
+	if copyAssignees {
+		assigneeIDs = source.Assignees
+		if !h.checkAssigneeLimit(c, assigneeIDs) {
+			return
+		}
+	}
+
+	// Custom field values are copied too, as long as they still satisfy the department's fields
+	metadata, details := copyCustomFields(source.Metadata, source.DepartmentID)
+	if len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+
+	// The copy starts over in the first status of its workflow
+	task := models.Task{
+		Title:        truncateRunes("Copy of "+source.Title, maxTaskTitleLength),
+		Description:  source.Description,
+		Status:       initialWorkflowStatus(source.DepartmentID),
+		Priority:     source.Priority,
+		CreatorID:    principal.ID,
+		DepartmentID: source.DepartmentID,
+		ProjectID:    source.ProjectID,
+		Source:       "GUI",
+		Tags:         append([]string{}, source.Tags...),
+		Metadata:     metadata,
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		if len(assigneeIDs) > 0 {
+			if err := replaceTaskAssignees(tx, task.ID, assigneeIDs); err != nil {
+				return err
+			}
+		}
+		if !copyChecklist {
+			return nil
+		}
+
+		var items []models.ChecklistItem
+		if err := tx.Where("task_id = ?", source.ID).Order("position ASC").Find(&items).Error; err != nil {
+			return err
+		}
+		for i, item := range items {
+			copied := models.ChecklistItem{TaskID: task.ID, Text: item.Text, Position: i}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if assigneeErr, ok := err.(*assigneeNotFoundError); ok {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_ASSIGNEE", "Assignee not found: "+assigneeErr.userID, nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to duplicate task", nil)
+		return
+	}
+
+	// Reload task with associations
+	h.db.
+		Preload("Creator").
+		Preload("Department").
+		Preload("Project").
+		Preload("ChecklistItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&task, "id = ?", task.ID)
+	task.ChecklistProgress = checklistProgress(task.ChecklistItems)
+
+	// Load assignees
+	tasks := []models.Task{task}
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return
+	}
+	task = tasks[0]
+
+	setTaskETag(c, task)
+	utils.RespondSuccess(c, http.StatusCreated, task, "Task duplicated successfully")
+}
@@ -0,0 +1,77 @@
+// ABOUTME: Facet counts for the task list, grouped by status, priority or assignee
+// ABOUTME: Counts run over the whole filtered and scoped result set rather than one page
+
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// taskFacetColumns maps each groupable facet to its tasks column; assignee is special-cased
+var taskFacetColumns = map[string]string{
+	"status":   "status",
+	"priority": "priority",
+	"assignee": "",
+}
+
+// parseTaskFacets parses the comma-separated facets parameter, rejecting unknown names
+func parseTaskFacets(value string) ([]string, error) {
+	facets := []string{}
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := taskFacetColumns[name]; !ok {
+			return nil, fmt.Errorf("Unsupported facet: %s (use status, priority, assignee)", name)
+		}
+		seen[name] = true
+		facets = append(facets, name)
+	}
+	return facets, nil
+}
+
+// taskFacets counts the tasks matched by query for each facet. query is left untouched, so
+// the caller can go on to count and page it. Assignee counts are keyed by user id, and a task
+// with several assignees counts once for each.
+func taskFacets(db, query *gorm.DB, names []string) (utils.Facets, error) {
+	facets := make(utils.Facets, len(names))
+	for _, name := range names {
+		var rows []struct {
+			Value *string
+			Count int64
+		}
+		var err error
+		if name == "assignee" {
+			matched := query.Session(&gorm.Session{}).Select("id")
+			err = db.Table("task_assignees").
+				Select("user_id AS value, COUNT(*) AS count").
+				Where("task_id IN (?)", matched).
+				Group("user_id").
+				Scan(&rows).Error
+		} else {
+			column := taskFacetColumns[name]
+			err = query.Session(&gorm.Session{}).
+				Select(column + " AS value, COUNT(*) AS count").
+				Group(column).
+				Scan(&rows).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		counts := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			if row.Value != nil {
+				counts[*row.Value] = row.Count
+			}
+		}
+		facets[name] = counts
+	}
+	return facets, nil
+}
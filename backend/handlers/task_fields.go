@@ -0,0 +1,91 @@
+// ABOUTME: Sparse fieldsets for task responses via the ?fields= query parameter
+// ABOUTME: Projects tasks onto the requested top-level JSON fields to keep payloads small
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/synapse/backend/models"
+)
+
+// taskFieldNames is the allowlist for ?fields=: every top-level field a task is serialized
+// with, read from the model's json tags, plus the _links added when it's encoded
+var taskFieldNames = func() map[string]bool {
+	names := jsonFieldNames(reflect.TypeOf(models.Task{}))
+	names["_links"] = true
+	return names
+}()
+
+// parseTaskFields parses the comma-separated fields parameter, rejecting unknown names. id
+// is always included so clients can tell rows apart; an empty parameter returns nil, meaning
+// every field.
+func parseTaskFields(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	fields := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if !taskFieldNames[name] {
+			return nil, fmt.Errorf("Unsupported field: %s", name)
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// selectTaskFields returns each task reduced to fields. Requested fields the task omits,
+// such as an unset due_date, come back as null so every row has the same keys.
+func selectTaskFields(tasks []models.Task, fields []string) ([]map[string]json.RawMessage, error) {
+	selected := make([]map[string]json.RawMessage, 0, len(tasks))
+	for _, task := range tasks {
+		encoded, err := json.Marshal(task)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &all); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				row[field] = value
+			} else {
+				row[field] = json.RawMessage("null")
+			}
+		}
+		selected = append(selected, row)
+	}
+	return selected, nil
+}
+
+// jsonFieldNames returns the names t's exported fields are serialized under
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
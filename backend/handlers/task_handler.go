@@ -5,21 +5,33 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
 	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
 	"github.com/synapse/backend/utils"
 	"gorm.io/gorm"
 )
 
 type TaskHandler struct {
-	db *gorm.DB
+	db    *gorm.DB
+	tasks repository.TaskRepository
+	users repository.UserRepository
 }
 
 func NewTaskHandler(db *gorm.DB) *TaskHandler {
-	return &TaskHandler{db: db}
+	return NewTaskHandlerWithRepositories(db, repository.NewTaskRepository(db), repository.NewUserRepository(db))
+}
+
+// NewTaskHandlerWithRepositories builds a TaskHandler on the given stores. Handlers still use db
+// for list queries and transactional writes that have no repository method yet.
+func NewTaskHandlerWithRepositories(db *gorm.DB, tasks repository.TaskRepository, users repository.UserRepository) *TaskHandler {
+	return &TaskHandler{db: db, tasks: tasks, users: users}
 }
 
 // CreateTaskRequest represents the task creation request body
@@ -34,6 +46,8 @@ type CreateTaskRequest struct {
 	DueDate     *string   `json:"due_date"` // ISO 8601 format
 	Tags        []string  `json:"tags"`
 	Source      string    `json:"source"`
+	EstimatedMinutes *int `json:"estimated_minutes" binding:"omitempty,min=0"`
+	CustomFields map[string]interface{} `json:"custom_fields"` // Values by custom field name
 }
 
 // UpdateTaskRequest represents the task update request body
@@ -47,55 +61,152 @@ type UpdateTaskRequest struct {
 	ProjectID   *string   `json:"project_id"`
 	DueDate     *string   `json:"due_date"`
 	Tags        []string  `json:"tags"`
+	EstimatedMinutes *int `json:"estimated_minutes" binding:"omitempty,min=0"`
+	CreatorID   *string   `json:"creator_id"` // Admin only
+	CustomFields map[string]interface{} `json:"custom_fields"` // Merged into the stored values; null clears one
 }
 
+// filterNone as a filter value matches tasks where the field is unset
+const filterNone = "none"
+
+// due_date filter values besides none: open tasks past their due time, and tasks due on the
+// caller's current calendar day
+const (
+	dueFilterOverdue = "overdue"
+	dueFilterToday   = "today"
+)
+
 // Valid values for validation
 var (
-	validStatuses  = map[string]bool{"To Do": true, "In Progress": true, "In Review": true, "Blocked": true, "Done": true}
-	validPriorities = map[string]bool{"Low": true, "Medium": true, "High": true, "Urgent": true}
-	validSources   = map[string]bool{"GUI": true, "Email": true, "API": true, "Document": true, "NLP": true}
+	validPriorities = enumSet(TaskPriorities)
 )
 
-// GetTasks returns a paginated list of tasks with filters
+// GetTasks returns a paginated list of tasks with filters, including created and updated
+// date ranges. ?fields= limits each task to the named top-level fields and ?with_counts=true
+// adds subtask_count and comment_count. Unchanged pages answer 304 to If-None-Match.
 func (h *TaskHandler) GetTasks(c *gin.Context) {
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
+	page, perPage := utils.ParsePagination(c)
+
+	facetNames, err := parseTaskFacets(c.Query("facets"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+	fields, err := parseTaskFields(c.Query("fields"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
 	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
+
+	query, ok := filteredTaskQuery(c, h.db)
+	if !ok {
+		return
 	}
+	db := h.db.WithContext(c.Request.Context())
 
+	// Count total
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		respondQueryError(c, err, "Failed to count tasks")
+		return
+	}
+
+	// Facets count the same scoped, filtered set as total
+	var facets utils.Facets
+	if len(facetNames) > 0 {
+		if facets, err = taskFacets(db, query, facetNames); err != nil {
+			respondQueryError(c, err, "Failed to count task facets")
+			return
+		}
+	}
+
+	// Apply pagination and sorting
+	offset := (page - 1) * perPage
+	var tasks []models.Task
+	if err := query.
+		Preload("Creator").
+		Preload("Department").
+		Preload("Project").
+		Order(taskListOrder(c, "created_at")).
+		Limit(perPage).
+		Offset(offset).
+		Find(&tasks).Error; err != nil {
+		respondQueryError(c, err, "Failed to fetch tasks")
+		return
+	}
+
+	// Load assignees for all tasks
+	if !loadListAssignees(c, db, tasks) {
+		return
+	}
+
+	// Load checklist progress for all tasks
+	if err := loadChecklistProgress(db, &tasks); err != nil {
+		respondQueryError(c, err, "Failed to load checklist progress")
+		return
+	}
+	if wantsCounts(c) {
+		if err := loadTaskCounts(db, tasks); err != nil {
+			respondQueryError(c, err, "Failed to count subtasks and comments")
+			return
+		}
+	}
+
+	if fields != nil {
+		selected, err := selectTaskFields(tasks, fields)
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to encode tasks", nil)
+			return
+		}
+		utils.RespondPaginatedWithETag(c, selected, page, perPage, total, facets)
+		return
+	}
+	utils.RespondPaginatedWithETag(c, tasks, page, perPage, total, facets)
+}
+
+// filteredTaskQuery builds the task query task listings share: the caller's role scope, their
+// default view when unfiltered, and the ?status=, ?priority=, ?assignee_id=, ?department_id=,
+// ?project_id=, ?due_date=, ?tag=, ?search= and date range filters, bound to the request's
+// context. It writes the error response and returns false when a filter is invalid.
+func filteredTaskQuery(c *gin.Context, db *gorm.DB) (*gorm.DB, bool) {
 	// Get filter parameters
 	status := c.Query("status")
 	priority := c.Query("priority")
 	assigneeID := c.Query("assignee_id")
 	departmentID := c.Query("department_id")
 	projectID := c.Query("project_id")
+	dueDate := c.Query("due_date")
+	tag := normalizeTag(c.Query("tag"))
 	search := c.Query("search")
-	sortBy := c.DefaultQuery("sort_by", "created_at")
-	sortOrder := c.DefaultQuery("sort_order", "desc")
+	if dueDate != "" && dueDate != filterNone && dueDate != dueFilterOverdue && dueDate != dueFilterToday {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "due_date filter supports none, overdue or today", nil)
+		return nil, false
+	}
+	bounds, ok := parseTimestampBounds(c)
+	if !ok {
+		return nil, false
+	}
 
-	// Get user context
-	userID, _ := c.Get("user_id")
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	// Queries stop when the client goes away or the request deadline passes
+	db = db.WithContext(c.Request.Context())
 
 	// Build query
-	query := h.db.Model(&models.Task{})
+	query := db.Model(&models.Task{})
 
 	// Apply role-based filtering
-	if userRole == "Member" || userRole == "Viewer" {
-		// Members and Viewers can only see tasks in their department or assigned to them
-		query = query.Where("creator_id = ? OR department_id = ? OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)",
-			userID, userDepartmentID, userID)
-	} else if userRole == "Manager" {
-		// Managers can see all tasks in their department
-		query = query.Where("department_id = ?", userDepartmentID)
+	principal := auth.FromContext(c)
+	query = auth.ScopeTasks(query, principal)
+
+	// Unfiltered lists land on the caller's role default
+	filtered := status != "" || priority != "" || assigneeID != "" || departmentID != "" ||
+		projectID != "" || dueDate != "" || tag != "" || search != "" || bounds.set()
+	view, ok := resolveTaskView(c, principal, filtered)
+	if !ok {
+		return nil, false
 	}
-	// Admins can see all tasks (no additional filter)
+	query = applyTaskView(query, view, principal)
+	c.Header(taskViewHeader, view)
 
 	// Apply filters
 	if status != "" {
@@ -104,27 +215,59 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 	if priority != "" {
 		query = query.Where("priority = ?", priority)
 	}
-	if assigneeID != "" {
+	// "none" matches tasks without a value, for triaging unassigned or unplanned work
+	switch assigneeID {
+	case "":
+	case filterNone:
+		query = query.Where("NOT EXISTS (SELECT 1 FROM task_assignees WHERE task_assignees.task_id = tasks.id)")
+	default:
 		query = query.Where("id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", assigneeID)
 	}
-	if departmentID != "" {
+	switch departmentID {
+	case "":
+	case filterNone:
+		query = query.Where("department_id IS NULL")
+	default:
 		query = query.Where("department_id = ?", departmentID)
 	}
-	if projectID != "" {
+	switch projectID {
+	case "":
+	case filterNone:
+		query = query.Where("project_id IS NULL")
+	default:
 		query = query.Where("project_id = ?", projectID)
 	}
+	switch dueDate {
+	case filterNone:
+		query = query.Where("due_date IS NULL")
+	case dueFilterOverdue:
+		query = query.Where("due_date < ? AND status <> ?", time.Now().UTC(), "Done")
+	case dueFilterToday:
+		// Today is the caller's calendar day, in their timezone preference
+		loc, err := userLocation(db, principal.ID)
+		if err != nil {
+			respondQueryError(c, err, "Failed to load timezone")
+			return nil, false
+		}
+		start, end := utils.DayBounds(time.Now(), loc)
+		query = query.Where("due_date >= ? AND due_date < ?", start.UTC(), end.UTC())
+	}
+	if tag != "" {
+		// Stored tags are normalized, so the filter is too
+		query = query.Where("? = ANY(tags)", tag)
+	}
 	if search != "" {
 		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
+	return bounds.apply(query), true
+}
 
-	// Count total
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to count tasks", nil)
-		return
-	}
+// taskListOrder returns the ORDER BY for ?sort_by= and ?sort_order=, sorting by defaultSort
+// when sort_by is missing or unknown
+func taskListOrder(c *gin.Context, defaultSort string) string {
+	sortBy := c.DefaultQuery("sort_by", defaultSort)
+	sortOrder := c.DefaultQuery("sort_order", "desc")
 
-	// Apply sorting
 	validSortFields := map[string]bool{
 		"created_at": true,
 		"updated_at": true,
@@ -132,49 +275,38 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 		"priority":   true,
 		"status":     true,
 		"title":      true,
+		"rank":       true,
 	}
 	if !validSortFields[sortBy] {
-		sortBy = "created_at"
+		sortBy = defaultSort
+	}
+	if sortBy == "rank" && c.Query("sort_order") == "" {
+		// Manual board order reads top to bottom
+		sortOrder = "asc"
 	}
 	if sortOrder != "asc" && sortOrder != "desc" {
 		sortOrder = "desc"
 	}
-
-	// Apply pagination and sorting
-	offset := (page - 1) * perPage
-	var tasks []models.Task
-	if err := query.
-		Preload("Creator").
-		Preload("Department").
-		Preload("Project").
-		Order(sortBy + " " + sortOrder).
-		Limit(perPage).
-		Offset(offset).
-		Find(&tasks).Error; err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch tasks", nil)
-		return
-	}
-
-	// Load assignees for all tasks
-	if err := h.loadTaskAssignees(&tasks); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
-		return
+	if sortBy == "rank" {
+		// Tasks that were never dragged go after ranked ones, oldest first
+		return "rank " + sortOrder + " NULLS LAST, created_at ASC, id ASC"
 	}
-
-	utils.RespondSuccessWithPagination(c, tasks, page, perPage, total)
+	// id breaks ties so rows sharing a sort value keep their place between pages
+	return sortBy + " " + sortOrder + ", id " + sortOrder
 }
 
-// GetTask returns a single task by ID
+// GetTask returns a single task by ID, limited to the ?fields= named when given
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	taskID := c.Param("id")
+	fields, err := parseTaskFields(c.Query("fields"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
 
-	var task models.Task
-	if err := h.db.
-		Preload("Creator").
-		Preload("Department").
-		Preload("Project").
-		First(&task, "id = ?", taskID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	task, err := h.tasks.FindDetailed(taskID)
+	if err != nil {
+		if err == repository.ErrNotFound {
 			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
 			return
 		}
@@ -182,162 +314,110 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	userID, _ := c.Get("user_id")
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
-
-	if !canAccessTask(task, userID.(string), userRole.(string), userDepartmentID) {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to view this task", nil)
-		return
-	}
-
-	// Load assignees for this task
+	// Load assignees before checking permissions, since assignees may view the task
 	tasks := []models.Task{task}
-	if err := h.loadTaskAssignees(&tasks); err != nil {
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
 		return
 	}
 	task = tasks[0]
 
-	utils.RespondSuccess(c, http.StatusOK, task, "Task retrieved successfully")
-}
-
-// CreateTask creates a new task
-func (h *TaskHandler) CreateTask(c *gin.Context) {
-	var req CreateTaskRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
-		return
-	}
-
-	// Get user context
-	userID, _ := c.Get("user_id")
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
-
 	// Check permissions
-	if userRole == "Viewer" {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Viewers cannot create tasks", nil)
+	if !auth.CanAccessTask(auth.FromContext(c), task) {
+		respondHidden(c, hiddenTask, "You don't have permission to view this task")
 		return
 	}
 
-	// Validate and set defaults
-	status := "To Do"
-	if req.Status != "" {
-		if !validStatuses[req.Status] {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid status value", nil)
+	if wantsExpand(c, "assignees") {
+		if err := h.tasks.LoadAssigneeDetails(tasks); err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
 			return
 		}
-		status = req.Status
+		task = tasks[0]
 	}
 
-	priority := "Medium"
-	if req.Priority != "" {
-		if !validPriorities[req.Priority] {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid priority value", nil)
-			return
-		}
-		priority = req.Priority
-	}
-
-	source := "GUI"
-	if req.Source != "" {
-		if !validSources[req.Source] {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid source value", nil)
-			return
-		}
-		source = req.Source
-	}
+	// Embed checklist progress alongside the items
+	task.ChecklistProgress = checklistProgress(task.ChecklistItems)
 
-	// Parse due date if provided
-	var dueDate *time.Time
-	if req.DueDate != nil && *req.DueDate != "" {
-		parsed, err := time.Parse(time.RFC3339, *req.DueDate)
+	setTaskETag(c, task)
+	if fields != nil {
+		selected, err := selectTaskFields([]models.Task{task}, fields)
 		if err != nil {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid due_date format, use ISO 8601", nil)
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to encode task", nil)
 			return
 		}
-		dueDate = &parsed
+		utils.RespondSuccess(c, http.StatusOK, selected[0], "Task retrieved successfully")
+		return
 	}
+	utils.RespondSuccess(c, http.StatusOK, task, "Task retrieved successfully")
+}
 
-	// Create task
-	task := models.Task{
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      status,
-		Priority:    priority,
-		CreatorID:   userID.(string),
-		DepartmentID: req.DepartmentID,
-		ProjectID:   req.ProjectID,
-		DueDate:     dueDate,
-		Source:      source,
-		Tags:        req.Tags,
+// CreateTask creates a new task
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	var req CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
 	}
 
-	// If no department specified, use user's department
-	if task.DepartmentID == nil && userDepartmentID != nil {
-		// userDepartmentID is *string from JWT claims
-		deptIDPtr, ok := userDepartmentID.(*string)
-		if ok && deptIDPtr != nil {
-			task.DepartmentID = deptIDPtr
-		}
-	}
+	// The route requires tasks.create; CanCreateTask below checks the department
+	principal := auth.FromContext(c)
 
-	// Start transaction
-	tx := h.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+	// Validate and set defaults
+	task, detail := buildTask(req, principal.ID, principal.DepartmentID)
+	if detail != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, nil)
+		return
+	}
+	metadata, details := applyCustomFields(task.Metadata, task.DepartmentID, req.CustomFields)
+	if len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+	task.Metadata = metadata
+	if !auth.CanCreateTask(principal, task.DepartmentID) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You can only create tasks in your own department", nil)
+		return
+	}
 
-	// Create task
-	if err := tx.Create(&task).Error; err != nil {
-		tx.Rollback()
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create task", nil)
+	// Reject oversized assignee lists before checking each assignee exists
+	if !h.checkAssigneeLimit(c, req.AssigneeIDs) {
 		return
 	}
 
-	// Assign users if provided
-	if len(req.AssigneeIDs) > 0 {
-		// Validate all assignees exist
-		for _, assigneeID := range req.AssigneeIDs {
-			var user models.User
-			if err := tx.First(&user, "id = ?", assigneeID).Error; err != nil {
-				tx.Rollback()
-				utils.RespondError(c, http.StatusBadRequest, "INVALID_ASSIGNEE", "Assignee not found: "+assigneeID, nil)
-				return
-			}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&task).Error; err != nil {
+			return err
 		}
-
-		// Manually insert into task_assignees table
-		for _, assigneeID := range req.AssigneeIDs {
-			if err := tx.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assigneeID).Error; err != nil {
-				tx.Rollback()
-				utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to assign users", nil)
-				return
-			}
+		// Assign users if provided
+		if len(req.AssigneeIDs) > 0 {
+			return replaceTaskAssignees(tx, task.ID, req.AssigneeIDs)
 		}
+		return nil
+	})
+	if err != nil {
+		if assigneeErr, ok := err.(*assigneeNotFoundError); ok {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_ASSIGNEE", "Assignee not found: "+assigneeErr.userID, nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create task", nil)
+		return
 	}
 
-	tx.Commit()
-
 	// Reload task with associations
-	h.db.
-		Preload("Creator").
-		Preload("Department").
-		Preload("Project").
-		First(&task, "id = ?", task.ID)
+	if reloaded, err := h.tasks.FindWithRelations(task.ID); err == nil {
+		task = reloaded
+	}
 
 	// Load assignees
 	tasks := []models.Task{task}
-	if err := h.loadTaskAssignees(&tasks); err != nil {
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
 		return
 	}
 	task = tasks[0]
 
+	setTaskETag(c, task)
 	utils.RespondSuccess(c, http.StatusCreated, task, "Task created successfully")
 }
 
@@ -347,19 +427,17 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 
 	var req UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
 		return
 	}
 
 	// Get user context
-	userID, _ := c.Get("user_id")
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	principal := auth.FromContext(c)
 
 	// Fetch existing task
-	var task models.Task
-	if err := h.db.First(&task, "id = ?", taskID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	task, err := h.tasks.FindByID(taskID)
+	if err != nil {
+		if err == repository.ErrNotFound {
 			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
 			return
 		}
@@ -368,8 +446,13 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	}
 
 	// Check permissions
-	if !canModifyTask(task, userID.(string), userRole.(string), userDepartmentID) {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to update this task", nil)
+	if !auth.CanModifyTask(principal, task) {
+		respondDenied(c, hiddenTask, "You don't have permission to update this task", h.taskVisible(principal, task))
+		return
+	}
+
+	// Reject stale writes
+	if !h.checkTaskPrecondition(c, task, true) {
 		return
 	}
 
@@ -380,21 +463,9 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	if req.Description != nil {
 		task.Description = req.Description
 	}
-	if req.Status != nil {
-		if !validStatuses[*req.Status] {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid status value", nil)
-			return
-		}
-		task.Status = *req.Status
-		// Set completion date if status is Done
-		if *req.Status == "Done" && task.CompletionDate == nil {
-			now := time.Now()
-			task.CompletionDate = &now
-		}
-	}
 	if req.Priority != nil {
 		if !validPriorities[*req.Priority] {
-			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid priority value", nil)
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Invalid priority value", nil)
 			return
 		}
 		task.Priority = *req.Priority
@@ -402,6 +473,31 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	if req.DepartmentID != nil {
 		task.DepartmentID = req.DepartmentID
 	}
+	// The final status is checked against the workflow of the department the task ends up in,
+	// so moving a task can't leave it in a status its new department doesn't have
+	finalStatus := task.Status
+	if req.Status != nil {
+		finalStatus = *req.Status
+	}
+	if detail := checkWorkflowStatus(task.DepartmentID, finalStatus); detail != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, nil)
+		return
+	}
+	if req.Status != nil {
+		if !checkStatusTransition(c, principal, task.Status, *req.Status) {
+			return
+		}
+		setTaskStatus(&task, *req.Status)
+	}
+	// Custom fields are checked against the task's department too, required ones included
+	if req.CustomFields != nil {
+		metadata, details := applyCustomFields(task.Metadata, task.DepartmentID, req.CustomFields)
+		if len(details) > 0 {
+			utils.RespondValidationError(c, details)
+			return
+		}
+		task.Metadata = metadata
+	}
 	if req.ProjectID != nil {
 		task.ProjectID = req.ProjectID
 	}
@@ -411,75 +507,108 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		} else {
 			parsed, err := time.Parse(time.RFC3339, *req.DueDate)
 			if err != nil {
-				utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid due_date format", nil)
+				utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Invalid due_date format", nil)
 				return
 			}
 			task.DueDate = &parsed
 		}
 	}
 	if req.Tags != nil {
-		task.Tags = req.Tags
+		tags, detail := normalizeTags(req.Tags)
+		if detail != nil {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, nil)
+			return
+		}
+		task.Tags = tags
+	}
+	if req.EstimatedMinutes != nil {
+		task.EstimatedMinutes = req.EstimatedMinutes
 	}
 
-	// Start transaction
-	tx := h.db.Begin()
+	// Only Admins may transfer a task to a new creator within the task's department
+	previousCreatorID := ""
+	if req.CreatorID != nil && *req.CreatorID != task.CreatorID {
+		if !auth.CanReassignTaskCreator(principal) {
+			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only admins can change a task's creator", nil)
+			return
+		}
+		creator, err := h.users.FindByID(*req.CreatorID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_CREATOR", "Creator user not found", nil)
+				return
+			}
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate creator", nil)
+			return
+		}
+		if task.DepartmentID != nil && (creator.DepartmentID == nil || *creator.DepartmentID != *task.DepartmentID) {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_CREATOR", "New creator must belong to the task's department", nil)
+			return
+		}
+		previousCreatorID = task.CreatorID
+		task.CreatorID = creator.ID
+	}
 
-	// Update task
-	if err := tx.Save(&task).Error; err != nil {
-		tx.Rollback()
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update task", nil)
+	// Reject oversized assignee lists before checking each assignee exists
+	if req.AssigneeIDs != nil && !h.checkAssigneeLimit(c, req.AssigneeIDs) {
 		return
 	}
 
-	// Update assignees if provided
-	if req.AssigneeIDs != nil {
-		// Clear existing assignees from task_assignees table
-		if err := tx.Exec("DELETE FROM task_assignees WHERE task_id = ?", task.ID).Error; err != nil {
-			tx.Rollback()
-			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update assignees", nil)
-			return
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Claim the next version; fails if another writer saved since we read the task
+		if err := bumpTaskVersion(tx, &task); err != nil {
+			return err
 		}
 
-		// Add new assignees
-		if len(req.AssigneeIDs) > 0 {
-			// Validate all assignees exist
-			for _, assigneeID := range req.AssigneeIDs {
-				var user models.User
-				if err := tx.First(&user, "id = ?", assigneeID).Error; err != nil {
-					tx.Rollback()
-					utils.RespondError(c, http.StatusBadRequest, "INVALID_ASSIGNEE", "Assignee not found: "+assigneeID, nil)
-					return
-				}
-			}
+		// Update task
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
 
-			// Manually insert into task_assignees table
-			for _, assigneeID := range req.AssigneeIDs {
-				if err := tx.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assigneeID).Error; err != nil {
-					tx.Rollback()
-					utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to assign users", nil)
-					return
-				}
+		// Record ownership transfers
+		if previousCreatorID != "" {
+			if err := recordActivity(tx, principal.ID, activityEntityTask, task.ID, activityOwnershipTransferred, map[string]interface{}{
+				"field": "creator_id",
+				"from":  previousCreatorID,
+				"to":    task.CreatorID,
+			}); err != nil {
+				return err
 			}
 		}
-	}
 
-	tx.Commit()
+		// Update assignees if provided
+		if req.AssigneeIDs != nil {
+			return reassignTask(tx, task, req.AssigneeIDs, principal.ID)
+		}
+		return nil
+	})
+	if err == errVersionConflict {
+		h.respondVersionConflict(c, task.ID)
+		return
+	}
+	if err != nil {
+		if assigneeErr, ok := err.(*assigneeNotFoundError); ok {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_ASSIGNEE", "Assignee not found: "+assigneeErr.userID, nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update task", nil)
+		return
+	}
 
 	// Reload task with associations
-	h.db.
-		Preload("Creator").
-		Preload("Department").
-		Preload("Project").
-		First(&task, "id = ?", task.ID)
+	if reloaded, err := h.tasks.FindWithRelations(task.ID); err == nil {
+		task = reloaded
+	}
 
 	// Load assignees
 	tasks := []models.Task{task}
-	if err := h.loadTaskAssignees(&tasks); err != nil {
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
 		return
 	}
 	task = tasks[0]
 
+	setTaskETag(c, task)
 	utils.RespondSuccess(c, http.StatusOK, task, "Task updated successfully")
 }
 
@@ -488,13 +617,12 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	taskID := c.Param("id")
 
 	// Get user context
-	userID, _ := c.Get("user_id")
-	userRole, _ := c.Get("user_role")
+	principal := auth.FromContext(c)
 
 	// Fetch existing task
-	var task models.Task
-	if err := h.db.First(&task, "id = ?", taskID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	task, err := h.tasks.FindByID(taskID)
+	if err != nil {
+		if err == repository.ErrNotFound {
 			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
 			return
 		}
@@ -503,13 +631,13 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	}
 
 	// Check permissions - only admins and task creators can delete
-	if userRole != "Admin" && task.CreatorID != userID.(string) {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only admins and task creators can delete tasks", nil)
+	if !auth.CanDeleteTask(principal, task) {
+		respondDenied(c, hiddenTask, "Only admins and task creators can delete tasks", h.taskVisible(principal, task))
 		return
 	}
 
 	// Delete task
-	if err := h.db.Delete(&task).Error; err != nil {
+	if err := h.tasks.Delete(&task); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete task", nil)
 		return
 	}
@@ -525,25 +653,17 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 		Status string `json:"status" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid status", nil)
-		return
-	}
-
-	// Validate status
-	if !validStatuses[req.Status] {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid status value", nil)
+		utils.RespondBindingError(c, err)
 		return
 	}
 
 	// Get user context
-	userID, _ := c.Get("user_id")
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	principal := auth.FromContext(c)
 
 	// Fetch existing task
-	var task models.Task
-	if err := h.db.First(&task, "id = ?", taskID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	task, err := h.tasks.FindByID(taskID)
+	if err != nil {
+		if err == repository.ErrNotFound {
 			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
 			return
 		}
@@ -552,131 +672,164 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 	}
 
 	// Check permissions
-	if !canModifyTask(task, userID.(string), userRole.(string), userDepartmentID) {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to update this task", nil)
+	if !auth.CanModifyTask(principal, task) {
+		respondDenied(c, hiddenTask, "You don't have permission to update this task", h.taskVisible(principal, task))
 		return
 	}
 
-	// Update status
-	task.Status = req.Status
-	if req.Status == "Done" && task.CompletionDate == nil {
-		now := time.Now()
-		task.CompletionDate = &now
+	// Reject stale writes
+	if !h.checkTaskPrecondition(c, task, true) {
+		return
+	}
+
+	// Only statuses in the task's workflow, and only transitions it allows
+	if detail := checkWorkflowStatus(task.DepartmentID, req.Status); detail != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, nil)
+		return
+	}
+	if !checkStatusTransition(c, principal, task.Status, req.Status) {
+		return
 	}
 
-	if err := h.db.Save(&task).Error; err != nil {
+	// Update status
+	setTaskStatus(&task, req.Status)
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpTaskVersion(tx, &task); err != nil {
+			return err
+		}
+		return tx.Save(&task).Error
+	})
+	if err == errVersionConflict {
+		h.respondVersionConflict(c, task.ID)
+		return
+	}
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update task status", nil)
 		return
 	}
 
 	// Reload task with associations
-	h.db.
-		Preload("Creator").
-		Preload("Department").
-		Preload("Project").
-		First(&task, "id = ?", task.ID)
+	if reloaded, err := h.tasks.FindWithRelations(task.ID); err == nil {
+		task = reloaded
+	}
 
 	// Load assignees
 	tasks := []models.Task{task}
-	if err := h.loadTaskAssignees(&tasks); err != nil {
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
 		return
 	}
 	task = tasks[0]
 
+	setTaskETag(c, task)
 	utils.RespondSuccess(c, http.StatusOK, task, "Task status updated successfully")
 }
 
 // Helper functions
 
-// loadTaskAssignees loads assignee IDs from task_assignees table
-func (h *TaskHandler) loadTaskAssignees(tasks *[]models.Task) error {
-	if len(*tasks) == 0 {
-		return nil
-	}
-
-	// Collect all task IDs
-	taskIDs := make([]string, len(*tasks))
-	taskMap := make(map[string]*models.Task)
-	for i := range *tasks {
-		taskIDs[i] = (*tasks)[i].ID
-		taskMap[(*tasks)[i].ID] = &(*tasks)[i]
-		// Initialize empty slice to avoid null
-		(*tasks)[i].Assignees = []string{}
+// buildTask validates a create request and returns the task it describes,
+// applying the same defaults for status, priority, source and department as CreateTask.
+// The status must be in the task's department workflow and defaults to its first status.
+func buildTask(req CreateTaskRequest, creatorID string, userDepartmentID *string) (models.Task, *utils.ErrorDetail) {
+	priority := "Medium"
+	if req.Priority != "" {
+		if !validPriorities[req.Priority] {
+			return models.Task{}, &utils.ErrorDetail{Field: "priority", Message: "Invalid priority value"}
+		}
+		priority = req.Priority
 	}
 
-	// Query assignees for all tasks using IN clause
-	var results []struct {
-		TaskID string `gorm:"column:task_id"`
-		UserID string `gorm:"column:user_id"`
-	}
-	if err := h.db.Raw("SELECT task_id, user_id FROM task_assignees WHERE task_id IN ?", taskIDs).Scan(&results).Error; err != nil {
-		return err
+	source := "GUI"
+	if req.Source != "" {
+		if !slices.Contains(taskSources(), req.Source) {
+			return models.Task{}, &utils.ErrorDetail{Field: "source", Message: "Invalid source value"}
+		}
+		source = req.Source
 	}
 
-	// Populate assignees
-	for _, result := range results {
-		if task, ok := taskMap[result.TaskID]; ok {
-			task.Assignees = append(task.Assignees, result.UserID)
+	// Parse due date if provided
+	var dueDate *time.Time
+	if req.DueDate != nil && *req.DueDate != "" {
+		parsed, err := time.Parse(time.RFC3339, *req.DueDate)
+		if err != nil {
+			return models.Task{}, &utils.ErrorDetail{Field: "due_date", Message: "Invalid due_date format, use ISO 8601"}
 		}
+		// Only new tasks are checked; updates may still move a date into the past
+		if config.GetConfig().RejectPastDueDates && parsed.Before(time.Now().UTC()) {
+			return models.Task{}, &utils.ErrorDetail{Field: "due_date", Message: "due_date cannot be in the past"}
+		}
+		dueDate = &parsed
 	}
 
-	return nil
-}
-
-func canAccessTask(task models.Task, userID, userRole string, userDepartmentID interface{}) bool {
-	// Admins can access all tasks
-	if userRole == "Admin" {
-		return true
+	tags, detail := normalizeTags(req.Tags)
+	if detail != nil {
+		return models.Task{}, detail
 	}
 
-	// Managers can access tasks in their department
-	if userRole == "Manager" {
-		deptIDPtr, ok := userDepartmentID.(*string)
-		if ok && deptIDPtr != nil && task.DepartmentID != nil {
-			return *task.DepartmentID == *deptIDPtr
-		}
+	task := models.Task{
+		Title:        req.Title,
+		Description:  req.Description,
+		Status:       req.Status,
+		Priority:     priority,
+		CreatorID:    creatorID,
+		DepartmentID: req.DepartmentID,
+		ProjectID:    req.ProjectID,
+		DueDate:      dueDate,
+		Source:       source,
+		Tags:         tags,
+		EstimatedMinutes: req.EstimatedMinutes,
 	}
 
-	// Members and Viewers can access tasks they created or are assigned to or in their department
-	if task.CreatorID == userID {
-		return true
+	// If no department specified, use user's department
+	if task.DepartmentID == nil {
+		task.DepartmentID = userDepartmentID
 	}
-	deptIDPtr, ok := userDepartmentID.(*string)
-	if ok && deptIDPtr != nil && task.DepartmentID != nil && *task.DepartmentID == *deptIDPtr {
-		return true
+
+	if task.Status == "" {
+		task.Status = initialWorkflowStatus(task.DepartmentID)
+	} else if detail := checkWorkflowStatus(task.DepartmentID, task.Status); detail != nil {
+		return models.Task{}, detail
 	}
 
-	// TODO: Check if user is assigned to the task (requires loading assignees)
-	return false
+	return task, nil
 }
 
-func canModifyTask(task models.Task, userID, userRole string, userDepartmentID interface{}) bool {
-	// Viewers cannot modify tasks
-	if userRole == "Viewer" {
-		return false
+// wantsExpand reports whether the comma-separated ?expand= list includes name
+func wantsExpand(c *gin.Context, name string) bool {
+	for _, value := range strings.Split(c.Query("expand"), ",") {
+		if strings.TrimSpace(value) == name {
+			return true
+		}
 	}
+	return false
+}
 
-	// Admins can modify all tasks
-	if userRole == "Admin" {
-		return true
+// loadListAssignees fills in the assignees of a page of listed tasks from task_assignees,
+// with full user objects for ?expand=assignees. It writes the error response and returns
+// false when loading fails.
+func loadListAssignees(c *gin.Context, db *gorm.DB, tasks []models.Task) bool {
+	if err := repository.LoadTaskAssignees(db, tasks); err != nil {
+		respondQueryError(c, err, "Failed to load task assignees")
+		return false
 	}
-
-	// Managers can modify tasks in their department
-	if userRole == "Manager" {
-		deptIDPtr, ok := userDepartmentID.(*string)
-		if ok && deptIDPtr != nil && task.DepartmentID != nil {
-			return *task.DepartmentID == *deptIDPtr
+	if wantsExpand(c, "assignees") {
+		if err := repository.LoadTaskAssigneeDetails(db, tasks); err != nil {
+			respondQueryError(c, err, "Failed to load task assignees")
+			return false
 		}
 	}
+	return true
+}
 
-	// Members can modify tasks they created or are assigned to
-	if userRole == "Member" {
-		if task.CreatorID == userID {
-			return true
+// taskVisible checks, when asked, whether principal can see task at all; assignees may view
+// tasks they can't change, so they are loaded first
+func (h *TaskHandler) taskVisible(principal auth.Principal, task models.Task) func() (bool, error) {
+	return func() (bool, error) {
+		tasks := []models.Task{task}
+		if err := h.tasks.LoadAssignees(tasks); err != nil {
+			return false, err
 		}
-		// TODO: Check if user is assigned to the task
+		return auth.CanAccessTask(principal, tasks[0]), nil
 	}
-
-	return false
 }
@@ -0,0 +1,370 @@
+// ABOUTME: Bulk task import handler accepting JSON arrays or CSV uploads
+// ABOUTME: Validates each row like CreateTask and inserts valid rows in batches
+
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxImportRows caps the number of rows accepted in a single import request
+	maxImportRows = 1000
+	// importBatchSize is the number of tasks inserted per INSERT statement
+	importBatchSize = 100
+	// csvListSeparator separates multiple values inside a single CSV cell (tags, assignee_ids)
+	csvListSeparator = ";"
+)
+
+// ImportRowResult reports the outcome for a single imported row
+type ImportRowResult struct {
+	Index  int                 `json:"index"`  // zero-based position in the JSON array or CSV data rows
+	Status string              `json:"status"` // "created", "valid" (dry run) or "invalid"
+	TaskID string              `json:"task_id,omitempty"`
+	Errors []utils.ErrorDetail `json:"errors,omitempty"`
+}
+
+// ImportTasksResponse summarizes a bulk import
+type ImportTasksResponse struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Invalid int               `json:"invalid"`
+	Created int               `json:"created"`
+	Results []ImportRowResult `json:"results"`
+}
+
+// importCandidate is a row that passed field validation and is ready for insertion
+type importCandidate struct {
+	index       int
+	task        models.Task
+	assigneeIDs []string
+}
+
+// ImportTasks creates many tasks at once from a JSON array or CSV body
+func (h *TaskHandler) ImportTasks(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	// Get user context; the route requires tasks.create and each row's department is checked below
+	principal := auth.FromContext(c)
+
+	// Decode rows based on content type
+	var rows []CreateTaskRequest
+	var err error
+	switch c.ContentType() {
+	case "text/csv", "application/csv":
+		rows, err = parseImportCSV(c.Request.Body)
+	case "application/json", "":
+		err = json.NewDecoder(c.Request.Body).Decode(&rows)
+	default:
+		utils.RespondError(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "Content-Type must be application/json or text/csv", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid import data: "+err.Error(), nil)
+		return
+	}
+	if len(rows) == 0 {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Import contains no rows", nil)
+		return
+	}
+	if len(rows) > maxImportRows {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", fmt.Sprintf("Import is limited to %d rows", maxImportRows), nil)
+		return
+	}
+
+	results := make([]ImportRowResult, len(rows))
+	var candidates []importCandidate
+
+	// Validate each row with the same rules as CreateTask
+	for i, row := range rows {
+		results[i] = ImportRowResult{Index: i}
+
+		if details := utils.ValidateStruct(&row); details != nil {
+			results[i].Errors = details
+			continue
+		}
+
+		task, detail := buildTask(row, principal.ID, principal.DepartmentID)
+		if detail != nil {
+			results[i].Errors = []utils.ErrorDetail{*detail}
+			continue
+		}
+		metadata, details := applyCustomFields(task.Metadata, task.DepartmentID, row.CustomFields)
+		if len(details) > 0 {
+			results[i].Errors = details
+			continue
+		}
+		task.Metadata = metadata
+
+		// Non-admins may only import into their own department
+		if !auth.CanCreateTask(principal, task.DepartmentID) {
+			results[i].Errors = []utils.ErrorDetail{{Field: "department_id", Message: "You can only import tasks into your own department"}}
+			continue
+		}
+
+		candidates = append(candidates, importCandidate{index: i, task: task, assigneeIDs: row.AssigneeIDs})
+	}
+
+	// Check referenced users, departments and projects exist using one query per table
+	candidates, err = h.validateImportReferences(candidates, results)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate import references", nil)
+		return
+	}
+
+	if !dryRun && len(candidates) > 0 {
+		if err := h.insertImportedTasks(candidates); err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to import tasks", nil)
+			return
+		}
+	}
+
+	// Build summary
+	response := ImportTasksResponse{DryRun: dryRun, Total: len(rows), Results: results}
+	for _, candidate := range candidates {
+		if dryRun {
+			results[candidate.index].Status = "valid"
+		} else {
+			results[candidate.index].Status = "created"
+			results[candidate.index].TaskID = candidate.task.ID
+			response.Created++
+		}
+	}
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Status = "invalid"
+			response.Invalid++
+		} else {
+			response.Valid++
+		}
+	}
+
+	statusCode := http.StatusOK
+	message := "Import validated successfully"
+	if !dryRun {
+		message = "Import completed"
+		if response.Created > 0 {
+			statusCode = http.StatusCreated
+		}
+	}
+
+	utils.RespondSuccess(c, statusCode, response, message)
+}
+
+// validateImportReferences drops candidates whose assignees, department or project are malformed
+// or don't exist, recording the error on the matching result
+func (h *TaskHandler) validateImportReferences(candidates []importCandidate, results []ImportRowResult) ([]importCandidate, error) {
+	// Malformed ids would fail the uuid cast and the whole lookup with it, so they're row errors
+	wellFormed := candidates[:0]
+	for _, candidate := range candidates {
+		if details := malformedImportIDs(candidate); len(details) > 0 {
+			results[candidate.index].Errors = details
+			continue
+		}
+		wellFormed = append(wellFormed, candidate)
+	}
+	candidates = wellFormed
+
+	var userIDs, departmentIDs, projectIDs []string
+	for _, candidate := range candidates {
+		userIDs = append(userIDs, candidate.assigneeIDs...)
+		if candidate.task.DepartmentID != nil {
+			departmentIDs = append(departmentIDs, *candidate.task.DepartmentID)
+		}
+		if candidate.task.ProjectID != nil {
+			projectIDs = append(projectIDs, *candidate.task.ProjectID)
+		}
+	}
+
+	existingUsers, err := h.existingIDs(&models.User{}, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	existingDepartments, err := h.existingIDs(&models.Department{}, departmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	existingProjects, err := h.existingIDs(&models.Project{}, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The assignee limit is only needed once some row names assignees
+	assigneeLimit := 0
+	if len(userIDs) > 0 {
+		if assigneeLimit, err = intSetting(h.db, settingMaxTaskAssignees); err != nil {
+			return nil, err
+		}
+	}
+
+	valid := candidates[:0]
+	for _, candidate := range candidates {
+		if distinctCount(candidate.assigneeIDs) > assigneeLimit {
+			results[candidate.index].Errors = []utils.ErrorDetail{{Field: "assignee_ids", Message: fmt.Sprintf("A task can have at most %d assignees", assigneeLimit)}}
+			continue
+		}
+
+		var details []utils.ErrorDetail
+		for _, assigneeID := range candidate.assigneeIDs {
+			if !existingUsers[assigneeID] {
+				details = append(details, utils.ErrorDetail{Field: "assignee_ids", Message: "Assignee not found: " + assigneeID})
+			}
+		}
+		if candidate.task.DepartmentID != nil && !existingDepartments[*candidate.task.DepartmentID] {
+			details = append(details, utils.ErrorDetail{Field: "department_id", Message: "Department not found"})
+		}
+		if candidate.task.ProjectID != nil && !existingProjects[*candidate.task.ProjectID] {
+			details = append(details, utils.ErrorDetail{Field: "project_id", Message: "Project not found"})
+		}
+
+		if len(details) > 0 {
+			results[candidate.index].Errors = details
+			continue
+		}
+		valid = append(valid, candidate)
+	}
+
+	return valid, nil
+}
+
+// malformedImportIDs reports the candidate's assignee, department and project ids that aren't UUIDs
+func malformedImportIDs(candidate importCandidate) []utils.ErrorDetail {
+	var details []utils.ErrorDetail
+	for _, assigneeID := range candidate.assigneeIDs {
+		if !utils.IsUUID(assigneeID) {
+			details = append(details, utils.ErrorDetail{Field: "assignee_ids", Message: "Assignee ID must be a UUID: " + assigneeID})
+		}
+	}
+	if candidate.task.DepartmentID != nil && !utils.IsUUID(*candidate.task.DepartmentID) {
+		details = append(details, utils.ErrorDetail{Field: "department_id", Message: "department_id must be a UUID"})
+	}
+	if candidate.task.ProjectID != nil && !utils.IsUUID(*candidate.task.ProjectID) {
+		details = append(details, utils.ErrorDetail{Field: "project_id", Message: "project_id must be a UUID"})
+	}
+	return details
+}
+
+// existingIDs returns the subset of ids present in the given model's table
+func (h *TaskHandler) existingIDs(model interface{}, ids []string) (map[string]bool, error) {
+	found := make(map[string]bool)
+	if len(ids) == 0 {
+		return found, nil
+	}
+
+	var rows []string
+	if err := h.db.Model(model).Where("id IN ?", ids).Pluck("id", &rows).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range rows {
+		found[id] = true
+	}
+	return found, nil
+}
+
+// insertImportedTasks inserts the candidate tasks and their assignees in a single transaction
+func (h *TaskHandler) insertImportedTasks(candidates []importCandidate) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		tasks := make([]models.Task, len(candidates))
+		for i := range candidates {
+			tasks[i] = candidates[i].task
+		}
+
+		if err := tx.CreateInBatches(&tasks, importBatchSize).Error; err != nil {
+			return err
+		}
+
+		for i := range candidates {
+			candidates[i].task.ID = tasks[i].ID
+			for _, assigneeID := range candidates[i].assigneeIDs {
+				if err := tx.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING", tasks[i].ID, assigneeID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// parseImportCSV reads CSV rows using a header row of CreateTaskRequest JSON field names.
+// List columns (tags, assignee_ids) hold multiple values separated by semicolons.
+func parseImportCSV(r io.Reader) ([]CreateTaskRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("missing required title column")
+	}
+
+	var rows []CreateTaskRequest
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		optional := func(column string) *string {
+			if v := value(column); v != "" {
+				return &v
+			}
+			return nil
+		}
+		list := func(column string) []string {
+			var values []string
+			for _, v := range strings.Split(value(column), csvListSeparator) {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+			return values
+		}
+
+		rows = append(rows, CreateTaskRequest{
+			Title:        value("title"),
+			Description:  optional("description"),
+			Status:       value("status"),
+			Priority:     value("priority"),
+			AssigneeIDs:  list("assignee_ids"),
+			DepartmentID: optional("department_id"),
+			ProjectID:    optional("project_id"),
+			DueDate:      optional("due_date"),
+			Tags:         list("tags"),
+			Source:       value("source"),
+		})
+	}
+
+	return rows, nil
+}
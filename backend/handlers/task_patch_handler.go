@@ -0,0 +1,331 @@
+// ABOUTME: Partial task update handler with JSON merge semantics
+// ABOUTME: Distinguishes omitted keys (left unchanged) from explicit nulls (cleared)
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// PatchTaskResponse is the patched task plus which keys were applied.
+// Keys omitted from the request are left untouched; keys sent as null are cleared.
+type PatchTaskResponse struct {
+	models.Task
+	UpdatedFields []string `json:"updated_fields"`
+	ClearedFields []string `json:"cleared_fields"`
+}
+
+// taskPatch holds the outcome of applying a raw JSON patch to a task
+type taskPatch struct {
+	updated     []string
+	cleared     []string
+	assigneeIDs *[]string // nil when assignee_ids was omitted
+}
+
+// PatchTask partially updates a task, only touching keys present in the request body
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	taskID := c.Param("id")
+
+	var fields map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&fields); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Request body must be a JSON object", nil)
+		return
+	}
+
+	// Get user context
+	principal := auth.FromContext(c)
+
+	// Fetch existing task
+	task, err := h.tasks.FindByID(taskID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task", nil)
+		return
+	}
+
+	// Check permissions
+	if !auth.CanModifyTask(principal, task) {
+		respondDenied(c, hiddenTask, "You don't have permission to update this task", h.taskVisible(principal, task))
+		return
+	}
+
+	// If-Match is optional for merge patches, but honored when sent
+	if !h.checkTaskPrecondition(c, task, false) {
+		return
+	}
+
+	previousStatus := task.Status
+	patch, details := applyTaskPatch(&task, fields)
+	// The final status must be in the final department's workflow even when only the
+	// department changed
+	if detail := checkWorkflowStatus(task.DepartmentID, task.Status); detail != nil {
+		details = append(details, *detail)
+	}
+	if len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+	if !checkStatusTransition(c, principal, previousStatus, task.Status) {
+		return
+	}
+	if patch.assigneeIDs != nil && !h.checkAssigneeLimit(c, *patch.assigneeIDs) {
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpTaskVersion(tx, &task); err != nil {
+			return err
+		}
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+		if patch.assigneeIDs != nil {
+			return reassignTask(tx, task, *patch.assigneeIDs, principal.ID)
+		}
+		return nil
+	})
+	if err == errVersionConflict {
+		h.respondVersionConflict(c, task.ID)
+		return
+	}
+	if err != nil {
+		if assigneeErr, ok := err.(*assigneeNotFoundError); ok {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_ASSIGNEE", "Assignee not found: "+assigneeErr.userID, nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update task", nil)
+		return
+	}
+
+	// Reload task with associations
+	if reloaded, err := h.tasks.FindWithRelations(task.ID); err == nil {
+		task = reloaded
+	}
+
+	// Load assignees
+	tasks := []models.Task{task}
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return
+	}
+
+	setTaskETag(c, tasks[0])
+	utils.RespondSuccess(c, http.StatusOK, PatchTaskResponse{
+		Task:          tasks[0],
+		UpdatedFields: patch.updated,
+		ClearedFields: patch.cleared,
+	}, "Task updated successfully; omitted fields were left unchanged and null fields were cleared")
+}
+
+// applyTaskPatch applies the keys present in fields to task, collecting per-field validation errors
+func applyTaskPatch(task *models.Task, fields map[string]json.RawMessage) (taskPatch, []utils.ErrorDetail) {
+	patch := taskPatch{updated: []string{}, cleared: []string{}}
+	var details []utils.ErrorDetail
+
+	// Iterate in a stable order so responses are deterministic
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw := fields[key]
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		fail := func(message string) {
+			details = append(details, utils.ErrorDetail{Field: key, Message: message})
+		}
+
+		switch key {
+		case "title", "status", "priority":
+			if isNull {
+				fail(key + " cannot be null")
+				continue
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				fail(key + " must be a string")
+				continue
+			}
+			switch key {
+			case "title":
+				if value == "" || len(value) > 255 {
+					fail("title must be between 1 and 255 characters")
+					continue
+				}
+				task.Title = value
+			case "status":
+				// Checked against the workflow once department_id has been applied too
+				setTaskStatus(task, value)
+			case "priority":
+				if !validPriorities[value] {
+					fail("Invalid priority value")
+					continue
+				}
+				task.Priority = value
+			}
+
+		case "description", "department_id", "project_id":
+			var target **string
+			switch key {
+			case "description":
+				target = &task.Description
+			case "department_id":
+				target = &task.DepartmentID
+			case "project_id":
+				target = &task.ProjectID
+			}
+			if isNull {
+				*target = nil
+				patch.cleared = append(patch.cleared, key)
+				break
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				fail(key + " must be a string or null")
+				continue
+			}
+			*target = &value
+
+		case "due_date":
+			if isNull {
+				task.DueDate = nil
+				patch.cleared = append(patch.cleared, key)
+				break
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				fail("due_date must be an ISO 8601 string or null")
+				continue
+			}
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				fail("Invalid due_date format, use ISO 8601")
+				continue
+			}
+			task.DueDate = &parsed
+
+		case "estimated_minutes":
+			if isNull {
+				task.EstimatedMinutes = nil
+				patch.cleared = append(patch.cleared, key)
+				break
+			}
+			var value int
+			if err := json.Unmarshal(raw, &value); err != nil || value < 0 {
+				fail("estimated_minutes must be a non-negative integer or null")
+				continue
+			}
+			task.EstimatedMinutes = &value
+
+		case "tags", "assignee_ids":
+			values := []string{}
+			if !isNull {
+				if err := json.Unmarshal(raw, &values); err != nil {
+					fail(key + " must be an array of strings or null")
+					continue
+				}
+			}
+			if key == "tags" {
+				tags, detail := normalizeTags(values)
+				if detail != nil {
+					fail(detail.Message)
+					continue
+				}
+				task.Tags = tags
+			} else {
+				patch.assigneeIDs = &values
+			}
+			if isNull {
+				patch.cleared = append(patch.cleared, key)
+			}
+
+		default:
+			fail("Unknown or read-only field")
+			continue
+		}
+
+		patch.updated = append(patch.updated, key)
+	}
+
+	return patch, details
+}
+
+// assigneeNotFoundError reports an assignee ID that doesn't match any user
+type assigneeNotFoundError struct {
+	userID string
+}
+
+func (e *assigneeNotFoundError) Error() string {
+	return "assignee not found: " + e.userID
+}
+
+// reassignTask replaces the task's assignees within tx and notifies the users who were added
+// or removed; users in both the old and new sets hear nothing
+func reassignTask(tx *gorm.DB, task models.Task, assigneeIDs []string, actorID string) error {
+	var previous []string
+	if err := tx.Raw("SELECT user_id FROM task_assignees WHERE task_id = ?", task.ID).Scan(&previous).Error; err != nil {
+		return err
+	}
+	if err := replaceTaskAssignees(tx, task.ID, assigneeIDs); err != nil {
+		return err
+	}
+
+	wasAssigned := make(map[string]bool, len(previous))
+	for _, userID := range previous {
+		wasAssigned[userID] = true
+	}
+	isAssigned := make(map[string]bool, len(assigneeIDs))
+	var added, removed []string
+	for _, userID := range assigneeIDs {
+		if !isAssigned[userID] && !wasAssigned[userID] {
+			added = append(added, userID)
+		}
+		isAssigned[userID] = true
+	}
+	for _, userID := range previous {
+		if !isAssigned[userID] {
+			removed = append(removed, userID)
+		}
+	}
+	return notifyAssigneeChanges(tx, task, actorID, added, removed)
+}
+
+// replaceTaskAssignees swaps the task's assignees for the given users within tx
+func replaceTaskAssignees(tx *gorm.DB, taskID string, assigneeIDs []string) error {
+	// Validate all assignees exist
+	for _, assigneeID := range assigneeIDs {
+		var user models.User
+		if err := tx.First(&user, "id = ?", assigneeID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &assigneeNotFoundError{userID: assigneeID}
+			}
+			return err
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM task_assignees WHERE task_id = ?", taskID).Error; err != nil {
+		return err
+	}
+	for _, assigneeID := range assigneeIDs {
+		if err := tx.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING", taskID, assigneeID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+// ABOUTME: Manual board ordering for tasks within a department's status column
+// ABOUTME: Computes a rank between neighbors and rebalances the column when gaps run out
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// rankStep is the gap between neighbors after a rebalance and when moving to an end
+	rankStep = 1024.0
+	// minRankGap is the smallest gap we split before rebalancing the column
+	minRankGap = 1e-6
+)
+
+var (
+	// errRankNeighbor signals a neighbor that doesn't exist, is hidden from the caller or sits
+	// in another column
+	errRankNeighbor = errors.New("invalid rank neighbor")
+	// errRankOrder signals an after_id task that is ranked below the before_id task
+	errRankOrder = errors.New("rank neighbors out of order")
+)
+
+// UpdateTaskRankRequest places a task after after_id and/or before before_id.
+// With neither set, the task moves to the end of its column.
+type UpdateTaskRankRequest struct {
+	AfterID  *string `json:"after_id"`
+	BeforeID *string `json:"before_id"`
+}
+
+// UpdateTaskRank moves a task between two neighbors in its column: the tasks sharing its status
+// and department, as each department has its own board
+func (h *TaskHandler) UpdateTaskRank(c *gin.Context) {
+	var req UpdateTaskRankRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	task, ok := h.loadModifiableTask(c)
+	if !ok {
+		return
+	}
+
+	if (req.AfterID != nil && *req.AfterID == task.ID) || (req.BeforeID != nil && *req.BeforeID == task.ID) {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "A task cannot be ranked relative to itself", nil)
+		return
+	}
+	// Malformed ids can't name a neighbor, and would fail the uuid lookups in the transaction
+	if (req.AfterID != nil && !utils.IsUUID(*req.AfterID)) || (req.BeforeID != nil && !utils.IsUUID(*req.BeforeID)) {
+		respondRankNeighbor(c)
+		return
+	}
+
+	principal := auth.FromContext(c)
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		rank, err := rankForMove(tx, principal, task, req)
+		if err != nil {
+			return err
+		}
+		if rank == nil {
+			// The gap is exhausted or a neighbor was never ranked; spread the column out and retry
+			if err := rebalanceRanks(tx, task); err != nil {
+				return err
+			}
+			if rank, err = rankForMove(tx, principal, task, req); err != nil {
+				return err
+			}
+			if rank == nil {
+				return errors.New("rank gap exhausted after rebalance")
+			}
+		}
+
+		task.Rank = rank
+		return tx.Model(&models.Task{}).Where("id = ?", task.ID).UpdateColumn("rank", *rank).Error
+	})
+	if err == errRankNeighbor {
+		respondRankNeighbor(c)
+		return
+	}
+	if err == errRankOrder {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "after_id must be ranked above before_id", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update task rank", nil)
+		return
+	}
+
+	tasks := []models.Task{task}
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, tasks[0], "Task rank updated successfully")
+}
+
+// respondRankNeighbor rejects an after_id or before_id that isn't a task in the column
+func respondRankNeighbor(c *gin.Context) {
+	utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Neighbor tasks must exist and share the task's status and department", nil)
+}
+
+// rankForMove computes the task's new rank from its requested neighbors, locking them for the
+// rest of the transaction. Neighbors must be in the task's column and visible to principal.
+// It returns nil when the column needs rebalancing first.
+func rankForMove(tx *gorm.DB, principal auth.Principal, task models.Task, req UpdateTaskRankRequest) (*float64, error) {
+	neighborRank := func(id *string) (*float64, bool, error) {
+		if id == nil {
+			return nil, false, nil
+		}
+		var neighbor models.Task
+		query := auth.ScopeTasks(rankColumn(tx.Clauses(clause.Locking{Strength: "UPDATE"}), task), principal)
+		if err := query.First(&neighbor, "id = ?", *id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, false, errRankNeighbor
+			}
+			return nil, false, err
+		}
+		return neighbor.Rank, true, nil
+	}
+
+	after, hasAfter, err := neighborRank(req.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	before, hasBefore, err := neighborRank(req.BeforeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unranked neighbors have no position to measure from yet
+	if (hasAfter && after == nil) || (hasBefore && before == nil) {
+		return nil, nil
+	}
+
+	if hasAfter && hasBefore && *after > *before {
+		return nil, errRankOrder
+	}
+
+	// Moving to the end of the column
+	if !hasAfter && !hasBefore {
+		var column struct {
+			Last     *float64
+			Unranked int64
+		}
+		if err := rankColumn(tx.Model(&models.Task{}), task).
+			Where("id <> ?", task.ID).
+			Select("MAX(rank) AS last, COUNT(*) FILTER (WHERE rank IS NULL) AS unranked").
+			Scan(&column).Error; err != nil {
+			return nil, err
+		}
+		// Unranked tasks sort last, so they need ranks before anything can follow them
+		if column.Unranked > 0 {
+			return nil, nil
+		}
+		after = column.Last
+	}
+
+	rank, ok := rankBetween(after, before)
+	if !ok {
+		return nil, nil
+	}
+	return &rank, nil
+}
+
+// rankBetween returns a rank strictly between after and before (either may be nil for an
+// open end), or false when the gap is too small to split
+func rankBetween(after, before *float64) (float64, bool) {
+	switch {
+	case after == nil && before == nil:
+		return rankStep, true
+	case after == nil:
+		return *before - rankStep, true
+	case before == nil:
+		return *after + rankStep, true
+	}
+
+	if *before-*after < minRankGap {
+		return 0, false
+	}
+	return *after + (*before-*after)/2, true
+}
+
+// rankColumn restricts query to the tasks in task's column: its status within its department
+func rankColumn(query *gorm.DB, task models.Task) *gorm.DB {
+	return query.Where("status = ? AND department_id IS NOT DISTINCT FROM ?", task.Status, task.DepartmentID)
+}
+
+// rebalanceRanks renumbers every task in task's column at even rankStep intervals, keeping the
+// current order and placing unranked tasks last by creation time
+func rebalanceRanks(tx *gorm.DB, task models.Task) error {
+	return tx.Exec(`
+		UPDATE tasks SET rank = ordered.position * ?
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY rank ASC NULLS LAST, created_at ASC, id ASC) AS position
+			FROM tasks WHERE status = ? AND department_id IS NOT DISTINCT FROM ?
+		) AS ordered
+		WHERE tasks.id = ordered.id`, rankStep, task.Status, task.DepartmentID).Error
+}
@@ -0,0 +1,50 @@
+// ABOUTME: Tag normalization shared by every path that writes or filters task tags
+// ABOUTME: Trims, collapses spacing, lowercases and de-duplicates tags, enforcing length and count limits
+
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/synapse/backend/utils"
+)
+
+const (
+	// maxTagLength is the longest tag accepted, in characters, after normalization
+	maxTagLength = 50
+	// maxTagsPerTask caps the distinct tags a task or template may carry
+	maxTagsPerTask = 20
+)
+
+// normalizeTag trims a tag, collapses internal whitespace to single spaces and lowercases it
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags normalizes each tag and drops blanks and duplicates, keeping first-seen order.
+// It returns a detail naming the field when a tag is too long or there are too many.
+func normalizeTags(tags []string) ([]string, *utils.ErrorDetail) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len([]rune(tag)) > maxTagLength {
+			return nil, &utils.ErrorDetail{Field: "tags", Message: fmt.Sprintf("Tags must be at most %d characters", maxTagLength)}
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxTagsPerTask {
+		return nil, &utils.ErrorDetail{Field: "tags", Message: fmt.Sprintf("A task can have at most %d tags", maxTagsPerTask)}
+	}
+	return normalized, nil
+}
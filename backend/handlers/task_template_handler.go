@@ -0,0 +1,267 @@
+// ABOUTME: Task template handlers for repeatable task shapes
+// ABOUTME: Handles creating and listing templates and instantiating tasks from them
+
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// CreateTaskTemplateRequest represents the template creation request body
+type CreateTaskTemplateRequest struct {
+	Name         string   `json:"name" binding:"required,max=255"`
+	Title        string   `json:"title" binding:"required,max=255"`
+	Description  *string  `json:"description"`
+	Status       string   `json:"status"`
+	Priority     string   `json:"priority"`
+	Tags         []string `json:"tags"`
+	Checklist    []string `json:"checklist" binding:"omitempty,dive,required,max=500"`
+	DepartmentID *string  `json:"department_id"`
+}
+
+// CreateTaskFromTemplateRequest holds the fields a caller may override when instantiating a template
+type CreateTaskFromTemplateRequest struct {
+	Title        *string                `json:"title" binding:"omitempty,min=1,max=255"`
+	DueDate      *string                `json:"due_date"` // ISO 8601 format
+	AssigneeIDs  []string               `json:"assignee_ids"`
+	CustomFields map[string]interface{} `json:"custom_fields"` // Values by custom field name
+}
+
+// GetTaskTemplates returns the templates the caller can use: Admins see all,
+// everyone else sees global templates and their department's
+func (h *TaskHandler) GetTaskTemplates(c *gin.Context) {
+	departmentID := c.Query("department_id")
+
+	query := auth.ScopeTemplates(h.db.Model(&models.TaskTemplate{}), auth.FromContext(c))
+	if departmentID != "" {
+		query = query.Where("department_id = ?", departmentID)
+	}
+
+	templates := []models.TaskTemplate{}
+	if err := query.Preload("Department").Order("name ASC, id ASC").Find(&templates).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task templates", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, templates, "Task templates retrieved successfully")
+}
+
+// CreateTaskTemplate creates a template. Managers create templates for their own
+// department; Admins may target any department or leave it global.
+func (h *TaskHandler) CreateTaskTemplate(c *gin.Context) {
+	var req CreateTaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	principal := auth.FromContext(c)
+
+	// Check permissions
+	if !principal.IsAdmin() && !principal.IsManager() {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Only Managers and Admins can create task templates", nil)
+		return
+	}
+	if principal.IsManager() && req.DepartmentID == nil {
+		req.DepartmentID = principal.DepartmentID
+	}
+	if !auth.CanCreateTemplate(principal, req.DepartmentID) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Managers can only create templates for their own department", nil)
+		return
+	}
+
+	// Validate and set defaults
+	status := "To Do"
+	if req.Status != "" {
+		status = req.Status
+	}
+	priority := "Medium"
+	if req.Priority != "" {
+		if !validPriorities[req.Priority] {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Invalid priority value", nil)
+			return
+		}
+		priority = req.Priority
+	}
+
+	tags, detail := normalizeTags(req.Tags)
+	if detail != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, nil)
+		return
+	}
+
+	// Validate department exists if provided
+	if req.DepartmentID != nil {
+		var department models.Department
+		if err := h.db.First(&department, "id = ?", *req.DepartmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_DEPARTMENT", "Department not found", nil)
+				return
+			}
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department", nil)
+			return
+		}
+	}
+
+	// A set status must be in the workflow of the template's department
+	if req.Status != "" {
+		if detail := checkWorkflowStatus(req.DepartmentID, req.Status); detail != nil {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, nil)
+			return
+		}
+	}
+
+	template := models.TaskTemplate{
+		Name:         req.Name,
+		Title:        req.Title,
+		Description:  req.Description,
+		Status:       status,
+		Priority:     priority,
+		Tags:         tags,
+		Checklist:    req.Checklist,
+		DepartmentID: req.DepartmentID,
+		CreatorID:    principal.ID,
+	}
+	if template.Tags == nil {
+		template.Tags = []string{}
+	}
+	if template.Checklist == nil {
+		template.Checklist = []string{}
+	}
+
+	if err := h.db.Create(&template).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create task template", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, template, "Task template created successfully")
+}
+
+// CreateTaskFromTemplate instantiates a new task from a template, applying any
+// title, due date and assignee overrides from the request
+func (h *TaskHandler) CreateTaskFromTemplate(c *gin.Context) {
+	var req CreateTaskFromTemplateRequest
+	// An empty body means "use the template as-is"
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	// The route requires tasks.create
+	principal := auth.FromContext(c)
+
+	var template models.TaskTemplate
+	if err := h.db.First(&template, "id = ?", c.Param("templateId")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "TEMPLATE_NOT_FOUND", "Task template not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task template", nil)
+		return
+	}
+
+	// Department templates are only usable within that department
+	if !auth.CanAccessTemplate(principal, template) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to use this template", nil)
+		return
+	}
+
+	taskReq := templateTaskRequest(template, req, time.Now())
+	task, detail := buildTask(taskReq, principal.ID, principal.DepartmentID)
+	if detail != nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", detail.Message, nil)
+		return
+	}
+	metadata, details := applyCustomFields(task.Metadata, task.DepartmentID, taskReq.CustomFields)
+	if len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+	task.Metadata = metadata
+	if !auth.CanCreateTask(principal, task.DepartmentID) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You can only create tasks in your own department", nil)
+		return
+	}
+	if !h.checkAssigneeLimit(c, req.AssigneeIDs) {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		if len(req.AssigneeIDs) > 0 {
+			if err := replaceTaskAssignees(tx, task.ID, req.AssigneeIDs); err != nil {
+				return err
+			}
+		}
+		for i, text := range template.Checklist {
+			item := models.ChecklistItem{TaskID: task.ID, Text: text, Position: i}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if assigneeErr, ok := err.(*assigneeNotFoundError); ok {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_ASSIGNEE", "Assignee not found: "+assigneeErr.userID, nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create task", nil)
+		return
+	}
+
+	// Reload task with associations
+	h.db.
+		Preload("Creator").
+		Preload("Department").
+		Preload("Project").
+		Preload("ChecklistItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&task, "id = ?", task.ID)
+	task.ChecklistProgress = checklistProgress(task.ChecklistItems)
+
+	// Load assignees
+	tasks := []models.Task{task}
+	if err := h.tasks.LoadAssignees(tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return
+	}
+	task = tasks[0]
+
+	setTaskETag(c, task)
+	utils.RespondSuccess(c, http.StatusCreated, task, "Task created from template successfully")
+}
+
+// templateTaskRequest builds the create request a template describes, expanding {date}
+// in the title pattern and applying the caller's overrides
+func templateTaskRequest(template models.TaskTemplate, overrides CreateTaskFromTemplateRequest, now time.Time) CreateTaskRequest {
+	title := strings.ReplaceAll(template.Title, "{date}", now.Format("2006-01-02"))
+	if overrides.Title != nil {
+		title = *overrides.Title
+	}
+
+	return CreateTaskRequest{
+		Title:        title,
+		Description:  template.Description,
+		Status:       template.Status,
+		Priority:     template.Priority,
+		AssigneeIDs:  overrides.AssigneeIDs,
+		DepartmentID: template.DepartmentID,
+		DueDate:      overrides.DueDate,
+		Tags:         append([]string{}, template.Tags...),
+		CustomFields: overrides.CustomFields,
+	}
+}
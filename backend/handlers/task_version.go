@@ -0,0 +1,104 @@
+// ABOUTME: Optimistic concurrency helpers for tasks using ETag/If-Match
+// ABOUTME: Parses version preconditions and atomically bumps the stored version
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// errVersionConflict signals that another writer saved the task after the caller read it
+var errVersionConflict = errors.New("task version conflict")
+
+// taskETag formats a task version as a strong entity tag
+func taskETag(task models.Task) string {
+	return `"` + strconv.Itoa(task.Version) + `"`
+}
+
+// setTaskETag exposes the task's current version in the ETag header
+func setTaskETag(c *gin.Context, task models.Task) {
+	c.Header("ETag", taskETag(task))
+}
+
+// parseIfMatch reads the If-Match header. It returns present=false when the header is missing,
+// and version=0 for the "*" wildcard, which matches any version.
+func parseIfMatch(c *gin.Context) (version int, present bool, err error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, false, nil
+	}
+	if header == "*" {
+		return 0, true, nil
+	}
+
+	header = strings.TrimPrefix(header, "W/")
+	version, err = strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil || version < 1 {
+		return 0, true, errors.New("invalid If-Match header")
+	}
+	return version, true, nil
+}
+
+// checkTaskPrecondition validates If-Match against the task's version, writing the error
+// response and returning false when the request must not proceed
+func (h *TaskHandler) checkTaskPrecondition(c *gin.Context, task models.Task, required bool) bool {
+	expected, present, err := parseIfMatch(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "If-Match must be a task version ETag", nil)
+		return false
+	}
+	if !present {
+		if required {
+			utils.RespondError(c, http.StatusPreconditionRequired, "PRECONDITION_REQUIRED", "If-Match header with the task version is required", nil)
+			return false
+		}
+		return true
+	}
+	if expected != 0 && expected != task.Version {
+		h.respondVersionConflict(c, task.ID)
+		return false
+	}
+	return true
+}
+
+// bumpTaskVersion atomically increments the stored version, failing with errVersionConflict
+// if another writer saved the task since it was read
+func bumpTaskVersion(tx *gorm.DB, task *models.Task) error {
+	result := tx.Model(&models.Task{}).
+		Where("id = ? AND version = ?", task.ID, task.Version).
+		UpdateColumn("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errVersionConflict
+	}
+
+	task.Version++
+	return nil
+}
+
+// respondVersionConflict returns 409 with the task's current state so the client can merge
+func (h *TaskHandler) respondVersionConflict(c *gin.Context, taskID string) {
+	current, err := h.tasks.FindWithRelations(taskID)
+	if err != nil {
+		utils.RespondError(c, http.StatusConflict, "CONFLICT", "Task was modified by someone else", nil)
+		return
+	}
+
+	tasks := []models.Task{current}
+	if err := h.tasks.LoadAssignees(tasks); err == nil {
+		current = tasks[0]
+	}
+
+	setTaskETag(c, current)
+	utils.RespondErrorWithData(c, http.StatusConflict, "CONFLICT", "Task was modified by someone else", current)
+}
@@ -0,0 +1,58 @@
+// ABOUTME: Default task list views resolved per role for unfiltered task lists
+// ABOUTME: Lets each persona's first page load show their work rather than everything in scope
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// taskViewHeader tells clients which view shaped the list they got
+const taskViewHeader = "X-Task-View"
+
+// resolveTaskView picks the view for a task list: ?view= when given, otherwise the caller's
+// role default when the request has no filters, otherwise all
+func resolveTaskView(c *gin.Context, principal auth.Principal, filtered bool) (string, bool) {
+	if view := c.Query("view"); view != "" {
+		if !config.IsTaskView(view) {
+			utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "view must be all, mine or department_open", nil)
+			return "", false
+		}
+		return view, true
+	}
+	if filtered {
+		return config.TaskViewAll, true
+	}
+
+	views, err := config.GetConfig().TaskListViews()
+	if err != nil {
+		// Validate rejects bad settings at startup; fall back to the built-in views regardless
+		views = config.DefaultTaskListViews
+	}
+	if view, ok := views[principal.Role]; ok {
+		return view, true
+	}
+	return config.TaskViewAll, true
+}
+
+// applyTaskView narrows a scoped task query to view. Callers without a department see
+// their open tasks anywhere in scope for department_open.
+func applyTaskView(query *gorm.DB, view string, principal auth.Principal) *gorm.DB {
+	switch view {
+	case config.TaskViewMine:
+		return query.Where("creator_id = ? OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", principal.ID, principal.ID)
+	case config.TaskViewDepartmentOpen:
+		query = query.Where("status <> ?", "Done")
+		if principal.DepartmentID != nil {
+			query = query.Where("department_id = ?", *principal.DepartmentID)
+		}
+		return query
+	}
+	return query
+}
@@ -0,0 +1,345 @@
+// ABOUTME: Time tracking handlers for logging work against tasks
+// ABOUTME: Serves per-task time totals and per-user timesheets
+
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+type TimeLogHandler struct {
+	db *gorm.DB
+}
+
+func NewTimeLogHandler(db *gorm.DB) *TimeLogHandler {
+	return &TimeLogHandler{db: db}
+}
+
+// LogTimeRequest represents the time log creation request body
+type LogTimeRequest struct {
+	Minutes  int     `json:"minutes" binding:"required,min=1,max=1440"`
+	Note     *string `json:"note"`
+	LoggedAt *string `json:"logged_at"` // ISO 8601 format, defaults to now
+}
+
+// UpdateTimeLogRequest represents the time log update request body
+type UpdateTimeLogRequest struct {
+	Minutes  *int    `json:"minutes" binding:"omitempty,min=1,max=1440"`
+	Note     *string `json:"note"`
+	LoggedAt *string `json:"logged_at"`
+}
+
+// TaskTimeResponse is a page of a task's time logs with the total across all pages
+type TaskTimeResponse struct {
+	Entries          []models.TimeLog `json:"entries"`
+	TotalMinutes     int64            `json:"total_minutes"`
+	EstimatedMinutes *int             `json:"estimated_minutes,omitempty"`
+}
+
+// TimesheetResponse is a user's time logs within a date range
+type TimesheetResponse struct {
+	UserID       string           `json:"user_id"`
+	From         *time.Time       `json:"from,omitempty"`
+	To           *time.Time       `json:"to,omitempty"`
+	TotalMinutes int64            `json:"total_minutes"`
+	Entries      []models.TimeLog `json:"entries"`
+}
+
+// LogTaskTime records minutes worked on a task by the current user
+func (h *TimeLogHandler) LogTaskTime(c *gin.Context) {
+	var req LogTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	task, ok := h.loadTask(c)
+	if !ok {
+		return
+	}
+
+	// Only the creator, assignees, and Managers and above may log time
+	principal := auth.FromContext(c)
+	if !auth.CanLogTime(principal, task) {
+		respondDenied(c, hiddenTask, "You don't have permission to log time on this task", func() (bool, error) {
+			return auth.CanAccessTask(principal, task), nil
+		})
+		return
+	}
+
+	loggedAt := time.Now()
+	if req.LoggedAt != nil && *req.LoggedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, *req.LoggedAt)
+		if err != nil {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Invalid logged_at format, use ISO 8601", nil)
+			return
+		}
+		loggedAt = parsed
+	}
+
+	entry := models.TimeLog{
+		TaskID:   task.ID,
+		UserID:   principal.ID,
+		Minutes:  req.Minutes,
+		Note:     req.Note,
+		LoggedAt: loggedAt,
+	}
+	if err := h.db.Create(&entry).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to log time", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, entry, "Time logged successfully")
+}
+
+// GetTaskTime returns a paginated list of a task's time logs with the total logged minutes
+func (h *TimeLogHandler) GetTaskTime(c *gin.Context) {
+	// Get pagination parameters
+	page, perPage := utils.ParsePagination(c)
+
+	task, ok := h.loadTask(c)
+	if !ok {
+		return
+	}
+
+	if !auth.CanAccessTask(auth.FromContext(c), task) {
+		respondHidden(c, hiddenTask, "You don't have permission to view this task's time")
+		return
+	}
+
+	query := h.db.Model(&models.TimeLog{}).Where("task_id = ?", task.ID)
+
+	// Count entries and total minutes
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to count time logs", nil)
+		return
+	}
+	totalMinutes, err := sumMinutes(query)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to total time logs", nil)
+		return
+	}
+
+	// Apply pagination
+	offset := (page - 1) * perPage
+	entries := []models.TimeLog{}
+	if err := query.
+		Preload("User").
+		Order("logged_at DESC, id DESC").
+		Limit(perPage).
+		Offset(offset).
+		Find(&entries).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch time logs", nil)
+		return
+	}
+	clearTimeLogPasswords(entries)
+
+	utils.RespondSuccessWithPagination(c, TaskTimeResponse{
+		Entries:          entries,
+		TotalMinutes:     totalMinutes,
+		EstimatedMinutes: task.EstimatedMinutes,
+	}, page, perPage, total)
+}
+
+// UpdateTimeLog edits one of the current user's own time log entries
+func (h *TimeLogHandler) UpdateTimeLog(c *gin.Context) {
+	var req UpdateTimeLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	entry, ok := h.loadOwnTimeLog(c, "You can only edit your own time entries")
+	if !ok {
+		return
+	}
+
+	if req.Minutes != nil {
+		entry.Minutes = *req.Minutes
+	}
+	if req.Note != nil {
+		entry.Note = req.Note
+	}
+	if req.LoggedAt != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.LoggedAt)
+		if err != nil {
+			utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Invalid logged_at format, use ISO 8601", nil)
+			return
+		}
+		entry.LoggedAt = parsed
+	}
+	entry.UpdatedAt = time.Now()
+
+	if err := h.db.Save(&entry).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update time log", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, entry, "Time log updated successfully")
+}
+
+// DeleteTimeLog removes one of the current user's own time log entries
+func (h *TimeLogHandler) DeleteTimeLog(c *gin.Context) {
+	entry, ok := h.loadOwnTimeLog(c, "You can only delete your own time entries")
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(&entry).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete time log", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Time log deleted successfully")
+}
+
+// GetUserTime returns a user's timesheet, optionally bounded by from/to dates
+func (h *TimeLogHandler) GetUserTime(c *gin.Context) {
+	userID := c.Param("id")
+
+	from, err := parseTimeBound(c.Query("from"), false)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid from date, use YYYY-MM-DD or ISO 8601", nil)
+		return
+	}
+	to, err := parseTimeBound(c.Query("to"), true)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid to date, use YYYY-MM-DD or ISO 8601", nil)
+		return
+	}
+
+	// Check if user exists
+	var user models.User
+	if err := h.db.First(&user, "id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch user", nil)
+		return
+	}
+
+	// Users can view their own timesheet, Admins anyone's, Managers their department's
+	principal := auth.FromContext(c)
+	if !auth.CanViewUserWork(principal, user) {
+		respondDenied(c, hiddenUser, "You don't have permission to view this user's time", func() (bool, error) {
+			return auth.CanAccessUser(principal, user), nil
+		})
+		return
+	}
+
+	query := h.db.Model(&models.TimeLog{}).Where("user_id = ?", userID)
+	if from != nil {
+		query = query.Where("logged_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("logged_at < ?", *to)
+	}
+
+	totalMinutes, err := sumMinutes(query)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to total time logs", nil)
+		return
+	}
+
+	entries := []models.TimeLog{}
+	if err := query.Order("logged_at ASC, id ASC").Find(&entries).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch time logs", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, TimesheetResponse{
+		UserID:       userID,
+		From:         from,
+		To:           to,
+		TotalMinutes: totalMinutes,
+		Entries:      entries,
+	}, "Timesheet retrieved successfully")
+}
+
+// Helper functions
+
+// loadTask fetches the :id task with its assignees, writing the error response and
+// returning false if it can't
+func (h *TimeLogHandler) loadTask(c *gin.Context) (models.Task, bool) {
+	var task models.Task
+	if err := h.db.First(&task, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found", nil)
+			return task, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch task", nil)
+		return task, false
+	}
+
+	// Assignees may log and view time, so permission checks need them
+	tasks := []models.Task{task}
+	if err := repository.LoadTaskAssignees(h.db, tasks); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+		return task, false
+	}
+	return tasks[0], true
+}
+
+// loadOwnTimeLog fetches the :logId entry on the :id task and checks it belongs to the caller
+func (h *TimeLogHandler) loadOwnTimeLog(c *gin.Context, forbiddenMessage string) (models.TimeLog, bool) {
+	var entry models.TimeLog
+	if err := h.db.First(&entry, "id = ? AND task_id = ?", c.Param("logId"), c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "TIME_LOG_NOT_FOUND", "Time log not found", nil)
+			return entry, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch time log", nil)
+		return entry, false
+	}
+
+	if !auth.CanModifyTimeLog(auth.FromContext(c), entry) {
+		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", forbiddenMessage, nil)
+		return entry, false
+	}
+	return entry, true
+}
+
+// sumMinutes totals the minutes column for the time logs matched by query
+func sumMinutes(query *gorm.DB) (int64, error) {
+	var total int64
+	err := query.Session(&gorm.Session{}).Select("COALESCE(SUM(minutes), 0)").Scan(&total).Error
+	return total, err
+}
+
+// parseTimeBound parses a YYYY-MM-DD or RFC3339 query bound. Date-only upper bounds
+// are moved to the start of the next day so the whole day is included.
+func parseTimeBound(value string, upper bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if upper {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return &parsed, nil
+}
+
+// clearTimeLogPasswords strips password hashes from preloaded users
+func clearTimeLogPasswords(entries []models.TimeLog) {
+	for i := range entries {
+		if entries[i].User != nil {
+			entries[i].User.PasswordHash = nil
+		}
+	}
+}
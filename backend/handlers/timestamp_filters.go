@@ -0,0 +1,81 @@
+// ABOUTME: Created and updated date range filters shared by the task and project lists
+// ABOUTME: Parses ?created_after=, ?created_before=, ?updated_after= and ?updated_before= in UTC
+
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// timestampBounds limits a list to rows created or updated in a range. After bounds are
+// inclusive and before bounds exclusive, so created_after=2026-10-05&created_before=2026-10-12
+// is exactly that week.
+type timestampBounds struct {
+	createdAfter, createdBefore *time.Time
+	updatedAfter, updatedBefore *time.Time
+}
+
+// parseTimestampBounds reads the created/updated range parameters as YYYY-MM-DD or ISO 8601.
+// Malformed values and empty ranges are reported against their parameter.
+func parseTimestampBounds(c *gin.Context) (timestampBounds, bool) {
+	var details []utils.ErrorDetail
+	parse := func(param string) *time.Time {
+		bound, err := parseTimeBound(c.Query(param), false)
+		if err != nil {
+			details = append(details, utils.ErrorDetail{Field: param, Message: "Invalid " + param + " date, use YYYY-MM-DD or ISO 8601"})
+			return nil
+		}
+		if bound != nil {
+			utc := bound.UTC()
+			bound = &utc
+		}
+		return bound
+	}
+	checkRange := func(after, before *time.Time, afterParam, beforeParam string) {
+		if after != nil && before != nil && !after.Before(*before) {
+			details = append(details, utils.ErrorDetail{Field: beforeParam, Message: afterParam + " must be before " + beforeParam})
+		}
+	}
+
+	bounds := timestampBounds{
+		createdAfter:  parse("created_after"),
+		createdBefore: parse("created_before"),
+		updatedAfter:  parse("updated_after"),
+		updatedBefore: parse("updated_before"),
+	}
+	checkRange(bounds.createdAfter, bounds.createdBefore, "created_after", "created_before")
+	checkRange(bounds.updatedAfter, bounds.updatedBefore, "updated_after", "updated_before")
+
+	if len(details) > 0 {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid date range filter", details)
+		return timestampBounds{}, false
+	}
+	return bounds, true
+}
+
+// set reports whether any bound was given
+func (b timestampBounds) set() bool {
+	return b.createdAfter != nil || b.createdBefore != nil || b.updatedAfter != nil || b.updatedBefore != nil
+}
+
+// apply narrows query to rows inside the bounds
+func (b timestampBounds) apply(query *gorm.DB) *gorm.DB {
+	if b.createdAfter != nil {
+		query = query.Where("created_at >= ?", *b.createdAfter)
+	}
+	if b.createdBefore != nil {
+		query = query.Where("created_at < ?", *b.createdBefore)
+	}
+	if b.updatedAfter != nil {
+		query = query.Where("updated_at >= ?", *b.updatedAfter)
+	}
+	if b.updatedBefore != nil {
+		query = query.Where("updated_at < ?", *b.updatedBefore)
+	}
+	return query
+}
@@ -0,0 +1,23 @@
+// ABOUTME: Lookup of the caller's timezone for features that depend on calendar days
+// ABOUTME: Falls back to UTC for users without a timezone preference
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// userLocation returns the timezone preference of userID, UTC when unset or the user is gone
+func userLocation(db *gorm.DB, userID string) (*time.Location, error) {
+	var user models.User
+	if err := db.Select("id, preferences").First(&user, "id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return time.UTC, nil
+		}
+		return nil, err
+	}
+	return user.Location(), nil
+}
@@ -0,0 +1,349 @@
+// ABOUTME: Two-factor authentication handlers for TOTP enrollment and the login code step
+// ABOUTME: Users with 2FA enabled trade a password-step challenge token and a code for their tokens
+
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// twoFactorIssuer names the account in authenticator apps
+const twoFactorIssuer = "Synapse"
+
+// recoveryCodeCount is how many recovery codes enabling 2FA hands out
+const recoveryCodeCount = 10
+
+// twoFactorChallengeTTL is how long a login has to supply its code after the password step
+const twoFactorChallengeTTL = 5 * time.Minute
+
+// Guessing limits for the login code step: a challenge takes maxChallengeCodeAttempts codes
+// before the password step has to be repeated, and maxTwoFactorFailures wrong codes in a row
+// lock the code step for twoFactorLockout
+const (
+	maxChallengeCodeAttempts = 3
+	maxTwoFactorFailures     = 10
+	twoFactorLockout         = 15 * time.Minute
+)
+
+// TwoFactorCodeRequest carries a code from the caller's authenticator app
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorLoginRequest completes a login with either an authenticator code or a recovery code
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+// TwoFactorEnrollResponse is the secret to add to an authenticator app, raw and as a URI
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse lists freshly issued recovery codes; they are not shown again
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallengeResponse is Login's answer for users with 2FA enabled. The challenge
+// token is exchanged, with a code, at /auth/2fa/login.
+type TwoFactorChallengeResponse struct {
+	Code           string `json:"code"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int    `json:"expires_in"` // seconds
+}
+
+// EnrollTwoFactor generates a new TOTP secret for the caller. 2FA stays off until a code
+// from it is confirmed with VerifyTwoFactor; enrolling again replaces an unconfirmed secret.
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	user, ok := h.loadTwoFactorUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		utils.RespondError(c, http.StatusConflict, "TWO_FACTOR_ENABLED", "Two-factor authentication is already enabled", nil)
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate two-factor secret", nil)
+		return
+	}
+	encrypted, err := utils.EncryptTOTPSecret(secret, config.GetConfig().TOTPKey())
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to store two-factor secret", nil)
+		return
+	}
+	if err := h.db.Model(&models.User{}).Where("id = ?", user.ID).
+		UpdateColumns(map[string]interface{}{"totp_secret": encrypted, "totp_last_step": nil}).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to store two-factor secret", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, TwoFactorEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(twoFactorIssuer, user.Email, secret),
+	}, "Add the secret to your authenticator app, then verify a code to enable two-factor authentication")
+}
+
+// VerifyTwoFactor confirms the caller's enrolled secret with a code, enables 2FA and returns
+// a new set of recovery codes
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+
+	user, ok := h.loadTwoFactorUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		utils.RespondError(c, http.StatusConflict, "TWO_FACTOR_ENABLED", "Two-factor authentication is already enabled", nil)
+		return
+	}
+	if user.TOTPSecret == nil {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "TWO_FACTOR_NOT_ENROLLED", "Enroll in two-factor authentication first", nil)
+		return
+	}
+
+	step, ok := h.checkTOTPCode(c, user, req.Code)
+	if !ok {
+		return
+	}
+
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to generate recovery codes", nil)
+		return
+	}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).
+			UpdateColumns(map[string]interface{}{"totp_enabled": true, "totp_last_step": step}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		rows := make([]models.RecoveryCode, len(codes))
+		for i, code := range codes {
+			rows[i] = models.RecoveryCode{UserID: user.ID, CodeHash: utils.HashRecoveryCode(code)}
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to enable two-factor authentication", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes},
+		"Two-factor authentication enabled; store the recovery codes somewhere safe")
+}
+
+// LoginTwoFactor finishes the login of a user with 2FA enabled. It takes the challenge
+// token Login issued and either a current authenticator code or an unused recovery code.
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if (req.Code == "") == (req.RecoveryCode == "") {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Provide either code or recovery_code", nil)
+		return
+	}
+
+	cfg := config.GetConfig()
+	challenge, err := utils.ValidateTwoFactorChallenge(req.ChallengeToken, cfg.JWTSecret, cfg.PreviousJWTSecrets()...)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or expired two-factor challenge", nil)
+		return
+	}
+
+	var user models.User
+	if err := h.db.Select("*").First(&user, "id = ?", challenge.Subject).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or expired two-factor challenge", nil)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to query user", nil)
+		return
+	}
+	if !user.IsActive {
+		utils.RespondError(c, http.StatusForbidden, "ACCOUNT_DISABLED", "Account has been disabled", nil)
+		return
+	}
+	if !user.TOTPEnabled || user.TOTPSecret == nil {
+		utils.RespondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or expired two-factor challenge", nil)
+		return
+	}
+
+	// Every code tried counts as a failure until it succeeds, so parallel guesses can't slip
+	// past the limits
+	attempts, ok := h.countTwoFactorAttempt(c, user)
+	if !ok {
+		return
+	}
+	if attempts <= challenge.Failures || attempts-challenge.Failures > maxChallengeCodeAttempts {
+		utils.RespondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or expired two-factor challenge", nil)
+		return
+	}
+
+	if req.Code != "" {
+		secret, err := h.readTOTPSecret(user)
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to read two-factor secret", nil)
+			return
+		}
+		step, ok := utils.VerifyTOTP(secret, req.Code, time.Now())
+		if !ok {
+			h.rejectTwoFactorCode(c, user, attempts, "Invalid two-factor code")
+			return
+		}
+		// Each time step is accepted once, so an observed code can't be replayed
+		result := h.db.Model(&models.User{}).
+			Where("id = ? AND (totp_last_step IS NULL OR totp_last_step < ?)", user.ID, step).
+			UpdateColumns(map[string]interface{}{"totp_last_step": step, "totp_failed_attempts": 0})
+		if result.Error != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to record two-factor code", nil)
+			return
+		}
+		if result.RowsAffected == 0 {
+			h.rejectTwoFactorCode(c, user, attempts, "Two-factor code has already been used")
+			return
+		}
+	} else {
+		result := h.db.Model(&models.RecoveryCode{}).
+			Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.HashRecoveryCode(req.RecoveryCode)).
+			UpdateColumn("used_at", time.Now().UTC())
+		if result.Error != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check recovery code", nil)
+			return
+		}
+		if result.RowsAffected == 0 {
+			h.rejectTwoFactorCode(c, user, attempts, "Invalid or already used recovery code")
+			return
+		}
+		if err := h.db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("totp_failed_attempts", 0).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to record two-factor code", nil)
+			return
+		}
+	}
+
+	// The password was checked in the first step, so there's nothing to rehash here
+	h.recordLogin(&user, "", cfg.BcryptCost)
+	h.respondWithTokens(c, user, "Login successful")
+}
+
+// loadTwoFactorUser fetches the authenticated caller, including their two-factor columns
+func (h *AuthHandler) loadTwoFactorUser(c *gin.Context) (models.User, bool) {
+	var user models.User
+	if err := h.db.Select("*").First(&user, "id = ?", c.GetString("user_id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil)
+			return user, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to query user", nil)
+		return user, false
+	}
+	return user, true
+}
+
+// countTwoFactorAttempt adds a code attempt to user's failure count and returns the new count,
+// responding 429 TOO_MANY_ATTEMPTS instead while the code step is locked
+func (h *AuthHandler) countTwoFactorAttempt(c *gin.Context, user models.User) (int, bool) {
+	var attempts []int
+	if err := h.db.Raw(`UPDATE users SET totp_failed_attempts = totp_failed_attempts + 1
+		WHERE id = ? AND (totp_locked_until IS NULL OR totp_locked_until <= ?)
+		RETURNING totp_failed_attempts`, user.ID, time.Now().UTC()).Scan(&attempts).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to record two-factor attempt", nil)
+		return 0, false
+	}
+	if len(attempts) == 0 {
+		respondTwoFactorLocked(c)
+		return 0, false
+	}
+	return attempts[0], true
+}
+
+// rejectTwoFactorCode responds 401 INVALID_2FA_CODE to a wrong code, first locking the code
+// step when attempts reaches maxTwoFactorFailures. The lock resets the count, which also
+// retires every outstanding challenge.
+func (h *AuthHandler) rejectTwoFactorCode(c *gin.Context, user models.User, attempts int, message string) {
+	if attempts >= maxTwoFactorFailures {
+		if err := h.db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{
+			"totp_failed_attempts": 0,
+			"totp_locked_until":    time.Now().UTC().Add(twoFactorLockout),
+		}).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to record two-factor attempt", nil)
+			return
+		}
+	}
+	utils.RespondError(c, http.StatusUnauthorized, "INVALID_2FA_CODE", message, nil)
+}
+
+// twoFactorLocked reports whether too many wrong codes have locked user's code step
+func twoFactorLocked(user models.User) bool {
+	return user.TOTPLockedUntil != nil && user.TOTPLockedUntil.After(time.Now())
+}
+
+// respondTwoFactorLocked rejects a login whose code step is locked after too many wrong codes
+func respondTwoFactorLocked(c *gin.Context) {
+	utils.RespondError(c, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS",
+		"Too many wrong two-factor codes; try again later", nil)
+}
+
+// readTOTPSecret decrypts the user's stored TOTP secret. A secret sealed under a key retired by
+// a JWT_SECRET rotation is still read, and re-sealed under the current key so the old key can
+// eventually be dropped.
+func (h *AuthHandler) readTOTPSecret(user models.User) (string, error) {
+	cfg := config.GetConfig()
+	secret, err := utils.DecryptTOTPSecret(*user.TOTPSecret, cfg.TOTPKey())
+	if err == nil {
+		return secret, nil
+	}
+	for _, key := range cfg.PreviousTOTPKeys() {
+		previous, prevErr := utils.DecryptTOTPSecret(*user.TOTPSecret, key)
+		if prevErr != nil {
+			continue
+		}
+		encrypted, encErr := utils.EncryptTOTPSecret(previous, cfg.TOTPKey())
+		if encErr == nil {
+			encErr = h.db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("totp_secret", encrypted).Error
+		}
+		if encErr != nil {
+			log.Printf("failed to re-encrypt two-factor secret for user %s: %v", user.ID, encErr)
+		}
+		return previous, nil
+	}
+	return "", err
+}
+
+// checkTOTPCode verifies code against the user's stored secret, responding when it doesn't
+// match, and returns the time step it matched
+func (h *AuthHandler) checkTOTPCode(c *gin.Context, user models.User, code string) (int64, bool) {
+	secret, err := h.readTOTPSecret(user)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to read two-factor secret", nil)
+		return 0, false
+	}
+	step, ok := utils.VerifyTOTP(secret, code, time.Now())
+	if !ok {
+		utils.RespondError(c, http.StatusUnauthorized, "INVALID_2FA_CODE", "Invalid two-factor code", nil)
+		return 0, false
+	}
+	return step, true
+}
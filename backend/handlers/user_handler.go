@@ -4,15 +4,22 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
 	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/storage"
 	"github.com/synapse/backend/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errLastAdmin signals an update that would leave no active Admin
+var errLastAdmin = errors.New("last active admin")
+
 type UserHandler struct {
 	db *gorm.DB
 }
@@ -27,41 +34,37 @@ type UpdateUserRequest struct {
 	AvatarURL    *string `json:"avatar_url"`
 	JobTitle     *string `json:"job_title"`
 	DepartmentID *string `json:"department_id"`
-	Role         *string `json:"role" binding:"omitempty,oneof=Admin Manager Member Viewer"`
+	Role         *string `json:"role"` // Any built-in or custom role
 	IsActive     *bool   `json:"is_active"`
+	Timezone     *string `json:"timezone"` // IANA name like Europe/Berlin; empty clears it
 }
 
 // GetUsers returns a paginated list of users
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := utils.ParsePagination(c)
 
 	// Get filter parameters
 	departmentID := c.Query("department_id")
 	role := c.Query("role")
 	isActive := c.Query("is_active")
 	search := c.Query("search")
-
-	// Get user context for access control
-	userRole, _ := c.Get("user_role")
-	userDepartmentID, _ := c.Get("user_department_id")
+	permission := c.Query("permission")
+	inactiveSince, err := parseTimeBound(c.Query("inactive_since"), false)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid inactive_since, use YYYY-MM-DD or ISO 8601", nil)
+		return
+	}
+	if permission != "" && !auth.IsKnownPermission(permission) {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Unknown permission: "+permission, nil)
+		return
+	}
 
 	// Build query
 	query := h.db.Model(&models.User{})
 
 	// Apply role-based filtering
-	if userRole == "Manager" {
-		// Managers can only see users in their department
-		query = query.Where("department_id = ?", userDepartmentID)
-	}
-	// Admins can see all users (no additional filter)
+	query = auth.ScopeUsers(query, auth.FromContext(c))
 
 	// Apply filters
 	if departmentID != "" {
@@ -70,6 +73,15 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	if role != "" {
 		query = query.Where("role = ?", role)
 	}
+	if permission != "" {
+		// Permissions come from roles, so this is a filter on the roles that grant it
+		roles, unknownRoles := auth.RolesWithPermission(permission)
+		if unknownRoles {
+			query = query.Where("role IN ? OR role NOT IN ?", roles, auth.Roles())
+		} else {
+			query = query.Where("role IN ?", roles)
+		}
+	}
 	if isActive != "" {
 		if isActive == "true" {
 			query = query.Where("is_active = ?", true)
@@ -77,6 +89,10 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 			query = query.Where("is_active = ?", false)
 		}
 	}
+	if inactiveSince != nil {
+		// Users who never logged in count as inactive too
+		query = query.Where("last_login IS NULL OR last_login < ?", *inactiveSince)
+	}
 	if search != "" {
 		query = query.Where("full_name ILIKE ? OR email ILIKE ? OR username ILIKE ?",
 			"%"+search+"%", "%"+search+"%", "%"+search+"%")
@@ -94,7 +110,7 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	var users []models.User
 	if err := query.
 		Preload("Department").
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(perPage).
 		Offset(offset).
 		Find(&users).Error; err != nil {
@@ -126,32 +142,10 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	requestUserID, _ := c.Get("user_id")
-	requestUserRole, _ := c.Get("user_role")
-	requestUserDepartmentID, _ := c.Get("user_department_id")
-
-	// Users can view their own profile
-	// Admins can view any user
-	// Managers can view users in their department
-	// Members and Viewers can view users in their department
-	if requestUserID.(string) != userID {
-		if requestUserRole == "Manager" {
-			reqDeptID, _ := requestUserDepartmentID.(*string)
-			if reqDeptID == nil || user.DepartmentID == nil ||
-				*user.DepartmentID != *reqDeptID {
-				utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to view this user", nil)
-				return
-			}
-		} else if requestUserRole != "Admin" {
-			// Members and Viewers can only view users in same department
-			reqDeptID, _ := requestUserDepartmentID.(*string)
-			if reqDeptID == nil || user.DepartmentID == nil ||
-				*user.DepartmentID != *reqDeptID {
-				utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to view this user", nil)
-				return
-			}
-		}
+	// Users can view their own profile, Admins any user, everyone else their department
+	if !auth.CanAccessUser(auth.FromContext(c), user) {
+		respondHidden(c, hiddenUser, "You don't have permission to view this user")
+		return
 	}
 
 	// Clear password hash
@@ -166,13 +160,12 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", nil)
+		utils.RespondBindingError(c, err)
 		return
 	}
 
 	// Get requesting user context
-	requestUserID, _ := c.Get("user_id")
-	requestUserRole, _ := c.Get("user_role")
+	principal := auth.FromContext(c)
 
 	// Fetch existing user
 	var user models.User
@@ -184,15 +177,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch user", nil)
 		return
 	}
-
-	// Check permissions
-	isSelf := requestUserID.(string) == userID
-	isAdmin := requestUserRole == "Admin"
+	wasActiveAdmin := user.Role == auth.RoleAdmin && user.IsActive
 
 	// Users can update their own profile (limited fields)
 	// Admins can update any user (all fields)
-	if !isSelf && !isAdmin {
-		utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to update this user", nil)
+	if !auth.CanModifyUser(principal, user) {
+		respondDenied(c, hiddenUser, "You don't have permission to update this user", func() (bool, error) {
+			return auth.CanAccessUser(principal, user), nil
+		})
 		return
 	}
 
@@ -201,19 +193,58 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		user.FullName = *req.FullName
 	}
 	if req.AvatarURL != nil {
+		// Uploaded images are only set through the upload endpoint; resending the current one is fine
+		unchanged := user.AvatarURL != nil && *user.AvatarURL == *req.AvatarURL
+		if !unchanged && storage.IsUploadURL(*req.AvatarURL, config.GetConfig().PublicBaseURL) {
+			utils.RespondValidationError(c, []utils.ErrorDetail{{Field: "avatar_url", Message: "Upload avatar images with POST /users/:id/avatar"}})
+			return
+		}
 		user.AvatarURL = req.AvatarURL
 	}
 	if req.JobTitle != nil {
 		user.JobTitle = req.JobTitle
 	}
+	if req.Timezone != nil {
+		if _, err := models.LoadTimezone(*req.Timezone); err != nil {
+			utils.RespondValidationError(c, []utils.ErrorDetail{{Field: "timezone", Message: "Unknown timezone, use an IANA name like Europe/Berlin"}})
+			return
+		}
+		if err := user.SetTimezone(*req.Timezone); err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update preferences", nil)
+			return
+		}
+	}
 
 	// Only admins can change role, department, and active status
-	if isAdmin {
+	if auth.CanManageUserAccount(principal) {
 		if req.Role != nil {
+			if !auth.IsKnownRole(*req.Role) {
+				utils.RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Unknown role: "+*req.Role, nil)
+				return
+			}
 			user.Role = *req.Role
 		}
 		if req.DepartmentID != nil {
-			user.DepartmentID = req.DepartmentID
+			// Validate department; an empty string clears it
+			if *req.DepartmentID == "" {
+				user.DepartmentID = nil
+			} else {
+				// A malformed id would fail the uuid cast rather than match nothing
+				if !utils.IsUUID(*req.DepartmentID) {
+					utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_DEPARTMENT", "department_id must be a UUID", nil)
+					return
+				}
+				var dept models.Department
+				if err := h.db.First(&dept, "id = ?", *req.DepartmentID).Error; err != nil {
+					if err == gorm.ErrRecordNotFound {
+						utils.RespondError(c, http.StatusUnprocessableEntity, "INVALID_DEPARTMENT", "Department not found", nil)
+						return
+					}
+					utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to validate department", nil)
+					return
+				}
+				user.DepartmentID = req.DepartmentID
+			}
 		}
 		if req.IsActive != nil {
 			user.IsActive = *req.IsActive
@@ -226,8 +257,30 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		}
 	}
 
-	// Save user
-	if err := h.db.Save(&user).Error; err != nil {
+	// Save user; a user moved out of a department stops heading it
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if wasActiveAdmin && (user.Role != auth.RoleAdmin || !user.IsActive) {
+			if err := ensureAnotherActiveAdmin(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if req.DepartmentID == nil {
+			return nil
+		}
+		headed := tx.Model(&models.Department{}).Where("head_id = ?", user.ID)
+		if user.DepartmentID != nil {
+			headed = headed.Where("id <> ?", *user.DepartmentID)
+		}
+		return headed.Update("head_id", nil).Error
+	})
+	if err == errLastAdmin {
+		utils.RespondError(c, http.StatusConflict, "LAST_ADMIN", "At least one active Admin must remain", nil)
+		return
+	}
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update user", nil)
 		return
 	}
@@ -241,23 +294,21 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, user, "User updated successfully")
 }
 
-// GetUserTasks returns tasks for a specific user
+// GetUserTasks returns tasks for a specific user; ?expand=assignees adds assignee users
 func (h *UserHandler) GetUserTasks(c *gin.Context) {
 	userID := c.Param("id")
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := utils.ParsePagination(c)
 
 	// Get filter parameters
 	status := c.Query("status")
 	priority := c.Query("priority")
+	relation := c.DefaultQuery("relation", userTaskRelationBoth)
+	if relation != userTaskRelationCreated && relation != userTaskRelationAssigned && relation != userTaskRelationBoth {
+		utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "relation must be created, assigned or both", nil)
+		return
+	}
 
 	// Check if user exists
 	var user models.User
@@ -270,33 +321,28 @@ func (h *UserHandler) GetUserTasks(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	requestUserID, _ := c.Get("user_id")
-	requestUserRole, _ := c.Get("user_role")
-	requestUserDepartmentID, _ := c.Get("user_department_id")
-
-	// Users can view their own tasks
-	// Admins can view any user's tasks
-	// Managers can view tasks of users in their department
-	if requestUserID.(string) != userID && requestUserRole != "Admin" {
-		if requestUserRole == "Manager" {
-			reqDeptID, _ := requestUserDepartmentID.(*string)
-			if reqDeptID == nil || user.DepartmentID == nil ||
-				*user.DepartmentID != *reqDeptID {
-				utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to view this user's tasks", nil)
-				return
-			}
-		} else {
-			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "You don't have permission to view this user's tasks", nil)
-			return
-		}
+	// Users can view their own tasks, Admins anyone's, Managers their department's
+	principal := auth.FromContext(c)
+	if !auth.CanViewUserWork(principal, user) {
+		respondDenied(c, hiddenUser, "You don't have permission to view this user's tasks", func() (bool, error) {
+			return auth.CanAccessUser(principal, user), nil
+		})
+		return
 	}
 
-	// Build query for tasks created by or assigned to the user
-	query := h.db.Model(&models.Task{}).Where(
-		"creator_id = ? OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)",
-		userID, userID,
-	)
+	// Build query for tasks created by and/or assigned to the user
+	query := h.db.Model(&models.Task{})
+	switch relation {
+	case userTaskRelationCreated:
+		query = query.Where("creator_id = ?", userID)
+	case userTaskRelationAssigned:
+		query = query.Where("id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", userID)
+	default:
+		query = query.Where(
+			"creator_id = ? OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)",
+			userID, userID,
+		)
+	}
 
 	// Apply filters
 	if status != "" {
@@ -320,7 +366,7 @@ func (h *UserHandler) GetUserTasks(c *gin.Context) {
 		Preload("Creator").
 		Preload("Department").
 		Preload("Project").
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(perPage).
 		Offset(offset).
 		Find(&tasks).Error; err != nil {
@@ -329,45 +375,57 @@ func (h *UserHandler) GetUserTasks(c *gin.Context) {
 	}
 
 	// Load assignees for all tasks
-	if err := h.loadTaskAssignees(&tasks); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to load task assignees", nil)
+	if !loadListAssignees(c, h.db, tasks) {
 		return
 	}
+	for i := range tasks {
+		tasks[i].Relation = userTaskRelation(tasks[i], userID)
+	}
 
 	utils.RespondSuccessWithPagination(c, tasks, page, perPage, total)
 }
 
-// loadTaskAssignees loads assignee IDs from task_assignees table
-func (h *UserHandler) loadTaskAssignees(tasks *[]models.Task) error {
-	if len(*tasks) == 0 {
-		return nil
-	}
+// Values for GetUserTasks' ?relation= filter and each task's derived relation field
+const (
+	userTaskRelationCreated  = "created"
+	userTaskRelationAssigned = "assigned"
+	userTaskRelationBoth     = "both"
+)
 
-	// Collect all task IDs
-	taskIDs := make([]string, len(*tasks))
-	taskMap := make(map[string]*models.Task)
-	for i := range *tasks {
-		taskIDs[i] = (*tasks)[i].ID
-		taskMap[(*tasks)[i].ID] = &(*tasks)[i]
-		// Initialize empty slice to avoid null
-		(*tasks)[i].Assignees = []string{}
+// userTaskRelation says how userID is connected to task: its creator, an assignee, or both
+func userTaskRelation(task models.Task, userID string) string {
+	created := task.CreatorID == userID
+	assigned := false
+	for _, id := range task.Assignees {
+		if id == userID {
+			assigned = true
+			break
+		}
 	}
-
-	// Query assignees for all tasks using IN clause
-	var results []struct {
-		TaskID string `gorm:"column:task_id"`
-		UserID string `gorm:"column:user_id"`
+	switch {
+	case created && assigned:
+		return userTaskRelationBoth
+	case assigned:
+		return userTaskRelationAssigned
+	default:
+		return userTaskRelationCreated
 	}
-	if err := h.db.Raw("SELECT task_id, user_id FROM task_assignees WHERE task_id IN ?", taskIDs).Scan(&results).Error; err != nil {
+}
+
+// ensureAnotherActiveAdmin returns errLastAdmin unless an active Admin other than userID exists.
+// It locks the active Admins for the rest of tx, so concurrent demotions can't both pass.
+func ensureAnotherActiveAdmin(tx *gorm.DB, userID string) error {
+	var adminIDs []string
+	if err := tx.Model(&models.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND is_active = ?", auth.RoleAdmin, true).
+		Pluck("id", &adminIDs).Error; err != nil {
 		return err
 	}
-
-	// Populate assignees
-	for _, result := range results {
-		if task, ok := taskMap[result.TaskID]; ok {
-			task.Assignees = append(task.Assignees, result.UserID)
+	for _, id := range adminIDs {
+		if id != userID {
+			return nil
 		}
 	}
-
-	return nil
+	return errLastAdmin
 }
@@ -0,0 +1,56 @@
+// ABOUTME: Responses for resources outside the caller's scope
+// ABOUTME: Answers 403 or, when HIDDEN_RESOURCE_STATUS is 404, not found so ids can't be enumerated
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/utils"
+)
+
+// hiddenResource names the not-found response a concealed resource answers with
+type hiddenResource struct {
+	code    string
+	message string
+}
+
+var (
+	hiddenTask    = hiddenResource{code: "TASK_NOT_FOUND", message: "Task not found"}
+	hiddenProject = hiddenResource{code: "PROJECT_NOT_FOUND", message: "Project not found"}
+	hiddenUser    = hiddenResource{code: "USER_NOT_FOUND", message: "User not found"}
+)
+
+// concealHiddenResources reports whether out-of-scope resources should look like missing ones
+func concealHiddenResources() bool {
+	return config.GetConfig().HiddenResourceStatus == http.StatusNotFound
+}
+
+// respondHidden answers a read of a resource the caller can't see
+func respondHidden(c *gin.Context, resource hiddenResource, forbiddenMessage string) {
+	if concealHiddenResources() {
+		utils.RespondError(c, http.StatusNotFound, resource.code, resource.message, nil)
+		return
+	}
+	utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", forbiddenMessage, nil)
+}
+
+// respondDenied answers a change the caller isn't allowed to make. When concealing, callers
+// who can't see the resource at all get the not-found response instead, so a denied write
+// reveals no more than a denied read. visible is only consulted in that mode.
+func respondDenied(c *gin.Context, resource hiddenResource, forbiddenMessage string, visible func() (bool, error)) {
+	if concealHiddenResources() {
+		ok, err := visible()
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check access", nil)
+			return
+		}
+		if !ok {
+			utils.RespondError(c, http.StatusNotFound, resource.code, resource.message, nil)
+			return
+		}
+	}
+	utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", forbiddenMessage, nil)
+}
@@ -0,0 +1,378 @@
+// ABOUTME: Workflow status handlers and the lookups that validate task statuses against them
+// ABOUTME: A department may define its own ordered statuses; other departments use the default workflow
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// maxStatusNameLength matches tasks.status
+const maxStatusNameLength = 20
+
+// statusColorPattern accepts #RRGGBB colors
+var statusColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+var errStatusExists = errors.New("status already exists")
+
+type WorkflowStatusHandler struct {
+	db *gorm.DB
+}
+
+func NewWorkflowStatusHandler(db *gorm.DB) *WorkflowStatusHandler {
+	return &WorkflowStatusHandler{db: db}
+}
+
+// CreateWorkflowStatusRequest adds a status to the default workflow, or to a department's
+// workflow when department_id is set. Position defaults to the end of the workflow.
+type CreateWorkflowStatusRequest struct {
+	Name         string  `json:"name" binding:"required"`
+	DepartmentID *string `json:"department_id"`
+	Position     *int    `json:"position" binding:"omitempty,min=0"`
+	Color        *string `json:"color"`
+}
+
+// UpdateWorkflowStatusRequest changes a status; omitted fields are left unchanged and an
+// empty color clears it
+type UpdateWorkflowStatusRequest struct {
+	Name     *string `json:"name"`
+	Position *int    `json:"position" binding:"omitempty,min=0"`
+	Color    *string `json:"color"`
+}
+
+// GetWorkflowStatuses lists a workflow's statuses in board order: the default workflow, or
+// with ?department_id= the workflow that department's tasks use
+func (h *WorkflowStatusHandler) GetWorkflowStatuses(c *gin.Context) {
+	var departmentID *string
+	if value := c.Query("department_id"); value != "" {
+		if !checkDepartmentExists(c, h.db, value, http.StatusBadRequest) {
+			return
+		}
+		departmentID = &value
+	}
+
+	statuses, err := loadWorkflow(h.db.WithContext(c.Request.Context()), departmentID)
+	if err != nil {
+		respondQueryError(c, err, "Failed to fetch workflow statuses")
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, statuses, "Workflow statuses retrieved successfully")
+}
+
+// CreateWorkflowStatus adds a status to a workflow. A department's first status starts its own
+// workflow as a copy of the default one, so its tasks keep the statuses they already have.
+func (h *WorkflowStatusHandler) CreateWorkflowStatus(c *gin.Context) {
+	var req CreateWorkflowStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if details := validateWorkflowStatus(&name, req.Color); len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+	if req.DepartmentID != nil && !checkDepartmentExists(c, h.db, *req.DepartmentID, http.StatusUnprocessableEntity) {
+		return
+	}
+
+	status := models.WorkflowStatus{Name: name, DepartmentID: req.DepartmentID, Color: emptyToNil(req.Color)}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if req.DepartmentID != nil {
+			if err := forkDefaultWorkflow(tx, *req.DepartmentID); err != nil {
+				return err
+			}
+		}
+		if err := checkStatusNameFree(tx, req.DepartmentID, name, ""); err != nil {
+			return err
+		}
+		if req.Position != nil {
+			status.Position = *req.Position
+		} else if err := workflowScope(tx.Model(&models.WorkflowStatus{}), req.DepartmentID).
+			Select("COALESCE(MAX(position) + 1, 0)").Row().Scan(&status.Position); err != nil {
+			return err
+		}
+		return tx.Create(&status).Error
+	})
+	if err == errStatusExists {
+		utils.RespondError(c, http.StatusConflict, "STATUS_EXISTS", "This workflow already has a status with that name", nil)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to create workflow status", nil)
+		return
+	}
+
+	if !h.reloadWorkflows(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, status, "Workflow status created successfully")
+}
+
+// UpdateWorkflowStatus renames, reorders or recolors a status. Only statuses no task is in
+// may be renamed, since tasks store the status by name.
+func (h *WorkflowStatusHandler) UpdateWorkflowStatus(c *gin.Context) {
+	var req UpdateWorkflowStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBindingError(c, err)
+		return
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+	if details := validateWorkflowStatus(req.Name, req.Color); len(details) > 0 {
+		utils.RespondValidationError(c, details)
+		return
+	}
+
+	status, ok := h.loadStatus(c)
+	if !ok {
+		return
+	}
+
+	if req.Name != nil && *req.Name != status.Name {
+		if !h.checkStatusUnused(c, status, "renamed") {
+			return
+		}
+		if err := checkStatusNameFree(h.db, status.DepartmentID, *req.Name, status.ID); err != nil {
+			if err == errStatusExists {
+				utils.RespondError(c, http.StatusConflict, "STATUS_EXISTS", "This workflow already has a status with that name", nil)
+				return
+			}
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update workflow status", nil)
+			return
+		}
+		status.Name = *req.Name
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
+	if req.Color != nil {
+		status.Color = emptyToNil(req.Color)
+	}
+
+	if err := h.db.Model(&status).Updates(map[string]interface{}{
+		"name":       status.Name,
+		"position":   status.Position,
+		"color":      status.Color,
+		"updated_at": gorm.Expr("NOW()"),
+	}).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to update workflow status", nil)
+		return
+	}
+
+	if !h.reloadWorkflows(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, status, "Workflow status updated successfully")
+}
+
+// DeleteWorkflowStatus removes a status no task is in. The default workflow keeps at least one
+// status; a department whose last status goes returns to the default workflow.
+func (h *WorkflowStatusHandler) DeleteWorkflowStatus(c *gin.Context) {
+	status, ok := h.loadStatus(c)
+	if !ok {
+		return
+	}
+	if !h.checkStatusUnused(c, status, "deleted") {
+		return
+	}
+
+	if status.DepartmentID == nil {
+		var remaining int64
+		if err := h.db.Model(&models.WorkflowStatus{}).Where("department_id IS NULL").Count(&remaining).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check workflow statuses", nil)
+			return
+		}
+		if remaining <= 1 {
+			utils.RespondError(c, http.StatusConflict, "LAST_STATUS", "The default workflow needs at least one status", nil)
+			return
+		}
+	}
+
+	if err := h.db.Delete(&status).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to delete workflow status", nil)
+		return
+	}
+
+	if !h.reloadWorkflows(c) {
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "Workflow status deleted successfully")
+}
+
+// reloadWorkflows refreshes the cached workflows after a change so task validation reflects it
+func (h *WorkflowStatusHandler) reloadWorkflows(c *gin.Context) bool {
+	if err := repository.LoadWorkflows(h.db); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Status saved but failed to reload workflows", nil)
+		return false
+	}
+	return true
+}
+
+// loadStatus fetches the :id status, responding 404 when it doesn't exist
+func (h *WorkflowStatusHandler) loadStatus(c *gin.Context) (models.WorkflowStatus, bool) {
+	var status models.WorkflowStatus
+	if err := h.db.First(&status, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, http.StatusNotFound, "STATUS_NOT_FOUND", "Workflow status not found", nil)
+			return status, false
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to fetch workflow status", nil)
+		return status, false
+	}
+	return status, true
+}
+
+// checkDepartmentExists responds INVALID_DEPARTMENT with status when departmentID doesn't
+// exist: 400 for a query parameter, 422 for a request body field
+func checkDepartmentExists(c *gin.Context, db *gorm.DB, departmentID string, status int) bool {
+	var department models.Department
+	if err := db.First(&department, "id = ?", departmentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.RespondError(c, status, "INVALID_DEPARTMENT", "Department not found", nil)
+			return false
+		}
+		respondQueryError(c, err, "Failed to fetch department")
+		return false
+	}
+	return true
+}
+
+// checkStatusUnused responds 409 STATUS_IN_USE when tasks in the status's workflow are in it
+func (h *WorkflowStatusHandler) checkStatusUnused(c *gin.Context, status models.WorkflowStatus, action string) bool {
+	tasks := h.db.Model(&models.Task{}).Where("status = ?", status.Name)
+	if status.DepartmentID != nil {
+		tasks = tasks.Where("department_id = ?", *status.DepartmentID)
+	} else {
+		// Default statuses cover tasks with no department and departments without their own workflow
+		forked := h.db.Model(&models.WorkflowStatus{}).Select("department_id").Where("department_id IS NOT NULL")
+		tasks = tasks.Where("department_id IS NULL OR department_id NOT IN (?)", forked)
+	}
+	var count int64
+	if err := tasks.Count(&count).Error; err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check tasks in status", nil)
+		return false
+	}
+	if count > 0 {
+		utils.RespondError(c, http.StatusConflict, "STATUS_IN_USE",
+			"A status can't be "+action+" while tasks are in it; move them to another status first", nil)
+		return false
+	}
+	return true
+}
+
+// validateWorkflowStatus checks a status name and color, either of which may be nil
+func validateWorkflowStatus(name, color *string) []utils.ErrorDetail {
+	var details []utils.ErrorDetail
+	if name != nil && (*name == "" || len(*name) > maxStatusNameLength) {
+		details = append(details, utils.ErrorDetail{Field: "name", Message: "name must be between 1 and 20 characters"})
+	}
+	if color != nil && *color != "" && !statusColorPattern.MatchString(*color) {
+		details = append(details, utils.ErrorDetail{Field: "color", Message: "color must be a hex color like #3B82F6"})
+	}
+	return details
+}
+
+// emptyToNil treats an empty string as unset
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
+// workflowScope narrows query to the default workflow, or to departmentID's own statuses
+func workflowScope(query *gorm.DB, departmentID *string) *gorm.DB {
+	if departmentID == nil {
+		return query.Where("department_id IS NULL")
+	}
+	return query.Where("department_id = ?", *departmentID)
+}
+
+// checkStatusNameFree returns errStatusExists when the workflow already has a status with
+// name, ignoring case and the status exceptID
+func checkStatusNameFree(db *gorm.DB, departmentID *string, name, exceptID string) error {
+	query := workflowScope(db.Model(&models.WorkflowStatus{}), departmentID).Where("LOWER(name) = LOWER(?)", name)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errStatusExists
+	}
+	return nil
+}
+
+// forkDefaultWorkflow copies the default statuses to departmentID unless it already has its own
+func forkDefaultWorkflow(db *gorm.DB, departmentID string) error {
+	return db.Exec(`INSERT INTO workflow_statuses (name, department_id, position, color)
+		SELECT name, CAST(? AS UUID), position, color FROM workflow_statuses
+		WHERE department_id IS NULL
+		AND NOT EXISTS (SELECT 1 FROM workflow_statuses WHERE department_id = ?)`,
+		departmentID, departmentID).Error
+}
+
+// loadWorkflow reads the workflow departmentID's tasks use from the database, in board order
+func loadWorkflow(db *gorm.DB, departmentID *string) ([]models.WorkflowStatus, error) {
+	statuses := []models.WorkflowStatus{}
+	if departmentID != nil {
+		if err := db.Where("department_id = ?", *departmentID).Order("position, name").Find(&statuses).Error; err != nil {
+			return nil, err
+		}
+		if len(statuses) > 0 {
+			return statuses, nil
+		}
+	}
+	err := db.Where("department_id IS NULL").Order("position, name").Find(&statuses).Error
+	return statuses, err
+}
+
+// workflowStatusNames lists the names of statuses, keeping their order
+func workflowStatusNames(statuses []models.WorkflowStatus) []string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = status.Name
+	}
+	return names
+}
+
+// checkWorkflowStatus returns a status validation detail when status isn't in the workflow
+// departmentID's tasks use
+func checkWorkflowStatus(departmentID *string, status string) *utils.ErrorDetail {
+	statuses := repository.Workflow(departmentID)
+	for _, candidate := range statuses {
+		if candidate.Name == status {
+			return nil
+		}
+	}
+	return &utils.ErrorDetail{
+		Field:   "status",
+		Message: "Invalid status value; this workflow allows " + strings.Join(workflowStatusNames(statuses), ", "),
+	}
+}
+
+// initialWorkflowStatus is the status new tasks in departmentID start in, the first of its workflow
+func initialWorkflowStatus(departmentID *string) string {
+	statuses := repository.Workflow(departmentID)
+	if len(statuses) == 0 {
+		return "To Do"
+	}
+	return statuses[0].Name
+}
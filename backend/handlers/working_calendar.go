@@ -0,0 +1,135 @@
+// ABOUTME: Per-department working calendars of weekend days and holidays
+// ABOUTME: Stored under working_calendar in department metadata and used to count business days
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/synapse/backend/utils"
+)
+
+// workingCalendarKey is where a department's calendar lives in its metadata
+const workingCalendarKey = "working_calendar"
+
+// dateLayout is the YYYY-MM-DD format for holidays and suggested due dates
+const dateLayout = "2006-01-02"
+
+// WorkingCalendar lists the days a department doesn't work. Departments without one get
+// Saturday and Sunday off and no holidays.
+type WorkingCalendar struct {
+	WeekendDays []string `json:"weekend_days"` // Weekday names, e.g. "Saturday"
+	Holidays    []string `json:"holidays"`     // YYYY-MM-DD dates
+}
+
+// defaultWorkingCalendar is the calendar for departments that haven't set one
+var defaultWorkingCalendar = WorkingCalendar{WeekendDays: []string{"Saturday", "Sunday"}, Holidays: []string{}}
+
+// validate normalizes weekday names and checks that holidays are dates and that at least
+// one weekday is left to work on
+func (wc *WorkingCalendar) validate() *utils.ErrorDetail {
+	seen := make(map[time.Weekday]bool)
+	weekendDays := []string{}
+	for _, name := range wc.WeekendDays {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return &utils.ErrorDetail{Field: "working_calendar.weekend_days", Message: fmt.Sprintf("Unknown weekday: %s", name)}
+		}
+		if !seen[day] {
+			seen[day] = true
+			weekendDays = append(weekendDays, day.String())
+		}
+	}
+	if len(seen) == 7 {
+		return &utils.ErrorDetail{Field: "working_calendar.weekend_days", Message: "At least one day of the week must be a working day"}
+	}
+
+	holidays := []string{}
+	for _, holiday := range wc.Holidays {
+		if _, err := time.Parse(dateLayout, holiday); err != nil {
+			return &utils.ErrorDetail{Field: "working_calendar.holidays", Message: fmt.Sprintf("Invalid holiday %q, use YYYY-MM-DD", holiday)}
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	wc.WeekendDays = weekendDays
+	wc.Holidays = holidays
+	return nil
+}
+
+// addWorkingDays returns the date days working days after from, skipping weekend days and
+// holidays. from itself is never counted.
+func (wc WorkingCalendar) addWorkingDays(from time.Time, days int) time.Time {
+	weekend := make(map[time.Weekday]bool)
+	for _, name := range wc.WeekendDays {
+		if day, ok := parseWeekday(name); ok {
+			weekend[day] = true
+		}
+	}
+	holidays := make(map[string]bool)
+	for _, holiday := range wc.Holidays {
+		holidays[holiday] = true
+	}
+
+	date := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	for added := 0; added < days; {
+		date = date.AddDate(0, 0, 1)
+		if !weekend[date.Weekday()] && !holidays[date.Format(dateLayout)] {
+			added++
+		}
+	}
+	return date
+}
+
+// departmentWorkingCalendar reads the calendar from a department's metadata, falling back
+// to the default when none is stored or it can't be read
+func departmentWorkingCalendar(metadata string) WorkingCalendar {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(metadata), &fields); err != nil {
+		return defaultWorkingCalendar
+	}
+	raw, ok := fields[workingCalendarKey]
+	if !ok {
+		return defaultWorkingCalendar
+	}
+	var calendar WorkingCalendar
+	if err := json.Unmarshal(raw, &calendar); err != nil || calendar.validate() != nil {
+		return defaultWorkingCalendar
+	}
+	return calendar
+}
+
+// withWorkingCalendar returns metadata with its working_calendar entry replaced, keeping
+// any other keys
+func withWorkingCalendar(metadata string, calendar WorkingCalendar) (string, error) {
+	fields := make(map[string]json.RawMessage)
+	if strings.TrimSpace(metadata) != "" {
+		if err := json.Unmarshal([]byte(metadata), &fields); err != nil {
+			return "", err
+		}
+	}
+	encoded, err := json.Marshal(calendar)
+	if err != nil {
+		return "", err
+	}
+	fields[workingCalendarKey] = encoded
+
+	updated, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(updated), nil
+}
+
+// parseWeekday matches a weekday name case-insensitively
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(strings.TrimSpace(name), day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
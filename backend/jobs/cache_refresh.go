@@ -0,0 +1,66 @@
+// ABOUTME: Periodic reload of the settings each instance caches in memory
+// ABOUTME: Lets a change saved through one instance reach every other within CACHE_REFRESH_SEC
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/synapse/backend/config"
+	"gorm.io/gorm"
+)
+
+// CacheLoader reads one in-memory cache, such as the task workflows, from the database
+type CacheLoader struct {
+	Name string
+	Load func(db *gorm.DB) error
+}
+
+// CacheRefreshJob re-runs its loaders on an interval. Handlers reload a cache straight after
+// changing it; the job is what brings the change to the other instances.
+type CacheRefreshJob struct {
+	db       *gorm.DB
+	interval time.Duration
+	loaders  []CacheLoader
+}
+
+// NewCacheRefreshJob builds the refresh job from CACHE_REFRESH_SEC
+func NewCacheRefreshJob(db *gorm.DB, cfg *config.Config, loaders ...CacheLoader) *CacheRefreshJob {
+	return &CacheRefreshJob{
+		db:       db,
+		interval: time.Duration(cfg.CacheRefreshSec) * time.Second,
+		loaders:  loaders,
+	}
+}
+
+// LoadAll runs every loader once, stopping at the first failure
+func (j *CacheRefreshJob) LoadAll(ctx context.Context) error {
+	for _, loader := range j.loaders {
+		if err := loader.Load(j.db.WithContext(ctx)); err != nil {
+			return fmt.Errorf("load %s: %w", loader.Name, err)
+		}
+	}
+	return nil
+}
+
+// Start reloads every cache each interval until ctx is cancelled. A failed load keeps the
+// cache's previous contents and is retried on the next tick.
+func (j *CacheRefreshJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, loader := range j.loaders {
+			if err := loader.Load(j.db.WithContext(ctx)); err != nil {
+				log.Printf("cache refresh: failed to reload %s: %v", loader.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// ABOUTME: Daily digest job summarizing each Manager's overdue and due-today department tasks
+// ABOUTME: Posts Slack-compatible webhook messages or in-app notifications, once per manager per day
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// notificationDigest is the notification type of in-app digests
+const notificationDigest = "digest"
+
+// webhookTimeout bounds a single webhook post
+const webhookTimeout = 10 * time.Second
+
+// DigestTask is a task listed in a digest
+type DigestTask struct {
+	ID       string
+	Title    string
+	Priority string
+	DueDate  time.Time // In the manager's timezone
+}
+
+// ManagerDigest is one Manager's summary of their department's unfinished tasks that are
+// overdue or due later on Date
+type ManagerDigest struct {
+	Manager    models.User
+	Department string
+	Date       time.Time
+	Overdue    []DigestTask
+	DueToday   []DigestTask
+}
+
+// BuildManagerDigests compiles a digest for every active Manager with a department, for the
+// day containing now in the manager's timezone preference (the UTC day without one). Managers
+// whose department has nothing overdue or due today are left out.
+func BuildManagerDigests(db *gorm.DB, now time.Time) ([]ManagerDigest, error) {
+	now = now.UTC()
+
+	var managers []models.User
+	if err := db.Preload("Department").
+		Where("role = ? AND is_active = ? AND department_id IS NOT NULL", "Manager", true).
+		Order("full_name ASC, id ASC").
+		Find(&managers).Error; err != nil {
+		return nil, err
+	}
+	if len(managers) == 0 {
+		return nil, nil
+	}
+
+	// Fetch up to the end of the latest manager's day; earlier days are trimmed per manager
+	departmentIDs := make([]string, 0, len(managers))
+	var latestEnd time.Time
+	for _, manager := range managers {
+		departmentIDs = append(departmentIDs, *manager.DepartmentID)
+		if _, endOfDay := utils.DayBounds(now, manager.Location()); endOfDay.After(latestEnd) {
+			latestEnd = endOfDay
+		}
+	}
+	var tasks []models.Task
+	if err := db.Select("id, title, priority, due_date, department_id").
+		Where("department_id IN ? AND status <> ? AND due_date < ?", departmentIDs, "Done", latestEnd).
+		Order("due_date ASC, id ASC").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	byDepartment := make(map[string][]models.Task)
+	for _, task := range tasks {
+		byDepartment[*task.DepartmentID] = append(byDepartment[*task.DepartmentID], task)
+	}
+
+	var digests []ManagerDigest
+	for _, manager := range managers {
+		loc := manager.Location()
+		startOfDay, endOfDay := utils.DayBounds(now, loc)
+		digest := ManagerDigest{Manager: manager, Date: startOfDay}
+		if manager.Department != nil {
+			digest.Department = manager.Department.Name
+		}
+		for _, task := range byDepartment[*manager.DepartmentID] {
+			if !task.DueDate.Before(endOfDay) {
+				continue
+			}
+			item := DigestTask{ID: task.ID, Title: task.Title, Priority: task.Priority, DueDate: task.DueDate.In(loc)}
+			if task.DueDate.Before(now) {
+				digest.Overdue = append(digest.Overdue, item)
+			} else {
+				digest.DueToday = append(digest.DueToday, item)
+			}
+		}
+		if len(digest.Overdue) > 0 || len(digest.DueToday) > 0 {
+			digests = append(digests, digest)
+		}
+	}
+	return digests, nil
+}
+
+// Text renders the digest as a message in Slack's mrkdwn format
+func (d ManagerDigest) Text() string {
+	var b strings.Builder
+	heading := d.Manager.FullName
+	if d.Department != "" {
+		heading += " (" + d.Department + ")"
+	}
+	fmt.Fprintf(&b, "*Daily digest for %s, %s*", heading, d.Date.Format("Monday 2 Jan 2006"))
+
+	if len(d.Overdue) > 0 {
+		fmt.Fprintf(&b, "\n*Overdue (%d)*", len(d.Overdue))
+		for _, task := range d.Overdue {
+			fmt.Fprintf(&b, "\n• %s (%s, due %s)", task.Title, task.Priority, task.DueDate.Format("2006-01-02"))
+		}
+	}
+	if len(d.DueToday) > 0 {
+		fmt.Fprintf(&b, "\n*Due today (%d)*", len(d.DueToday))
+		for _, task := range d.DueToday {
+			fmt.Fprintf(&b, "\n• %s (%s, due %s)", task.Title, task.Priority, task.DueDate.Format("15:04 MST"))
+		}
+	}
+	return b.String()
+}
+
+// SlackPayload is the body posted to the webhook; Slack incoming webhooks and most
+// Slack-compatible receivers read the text field
+func (d ManagerDigest) SlackPayload() map[string]interface{} {
+	return map[string]interface{}{"text": d.Text()}
+}
+
+// DigestJob sends the manager digests once a day
+type DigestJob struct {
+	db         *gorm.DB
+	webhookURL string
+	sendAt     time.Duration // Offset from UTC midnight
+	client     *http.Client
+}
+
+// NewDigestJob builds the digest job from the DIGEST_* settings
+func NewDigestJob(db *gorm.DB, cfg *config.Config) (*DigestJob, error) {
+	sendAt, err := cfg.DigestClock()
+	if err != nil {
+		return nil, err
+	}
+	return &DigestJob{
+		db:         db,
+		webhookURL: cfg.DigestWebhookURL,
+		sendAt:     sendAt,
+		client:     &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// Start sends digests at the configured time each day until ctx is cancelled. A server
+// started after today's send time catches up straight away; managers whose digest already
+// went out today are skipped.
+func (j *DigestJob) Start(ctx context.Context) {
+	now := time.Now().UTC()
+	if !now.Before(j.sendTime(now)) {
+		j.runAndLog(ctx, now)
+	}
+	for {
+		now = time.Now().UTC()
+		next := j.sendTime(now)
+		if !now.Before(next) {
+			next = next.AddDate(0, 0, 1)
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		j.runAndLog(ctx, time.Now().UTC())
+	}
+}
+
+// RunOnce sends today's digests and returns how many went out. Each manager's digest is
+// claimed in digest_runs before sending, so a second run on the same day sends nothing; a
+// failed delivery releases the claim so the next run retries it.
+func (j *DigestJob) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	db := j.db.WithContext(ctx)
+	digests, err := BuildManagerDigests(db, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var firstErr error
+	for _, digest := range digests {
+		claim := db.Exec("INSERT INTO digest_runs (manager_id, digest_date) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			digest.Manager.ID, digest.Date.Format("2006-01-02"))
+		if claim.Error != nil {
+			return sent, claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		if err := j.deliver(ctx, digest); err != nil {
+			db.Where("manager_id = ? AND digest_date = ?", digest.Manager.ID, digest.Date.Format("2006-01-02")).
+				Delete(&models.DigestRun{})
+			if firstErr == nil {
+				firstErr = fmt.Errorf("digest for %s: %w", digest.Manager.ID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, firstErr
+}
+
+// deliver posts the digest to the webhook, or notifies the manager in-app without one
+func (j *DigestJob) deliver(ctx context.Context, digest ManagerDigest) error {
+	if j.webhookURL == "" {
+		return j.db.WithContext(ctx).Create(&models.Notification{
+			UserID:  digest.Manager.ID,
+			Type:    notificationDigest,
+			Message: digest.Text(),
+		}).Error
+	}
+
+	body, err := json.Marshal(digest.SlackPayload())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// sendTime is the configured send time on now's UTC day
+func (j *DigestJob) sendTime(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(j.sendAt)
+}
+
+func (j *DigestJob) runAndLog(ctx context.Context, now time.Time) {
+	sent, err := j.RunOnce(ctx, now)
+	if err != nil {
+		log.Printf("manager digest: %v", err)
+	}
+	if sent > 0 {
+		log.Printf("manager digest: sent %d digests", sent)
+	}
+}
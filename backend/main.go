@@ -4,11 +4,17 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/synapse/backend/auth"
 	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/jobs"
+	"github.com/synapse/backend/migrations"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
 	"github.com/synapse/backend/routes"
 )
 
@@ -20,14 +26,51 @@ func main() {
 
 	// Get configuration
 	cfg := config.GetConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	// Resolve how users without a stored avatar and resource links are serialized
+	models.SetAvatarStyle(cfg.AvatarStyle)
+	models.SetPublicBaseURL(cfg.PublicBaseURL)
 
 	// Setup database
-	db, err := config.SetupDatabase(cfg.DatabaseURL)
+	db, err := config.SetupDatabase(cfg)
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
 	log.Println("✓ database connected successfully")
 
+	// Apply pending migrations; never serve against a partially migrated schema
+	applied, err := migrations.Run(db)
+	if err != nil {
+		log.Fatalf("failed to run migrations: %v", err)
+	}
+	log.Printf("✓ database migrated (%d applied)", len(applied))
+
+	// Load the role to permission mapping tokens and permission checks are built from, and the
+	// task workflows and custom task field definitions task values are validated against, then
+	// keep re-reading them so changes saved through other instances reach this one
+	cacheRefresh := jobs.NewCacheRefreshJob(db, cfg,
+		jobs.CacheLoader{Name: "roles", Load: auth.LoadRoles},
+		jobs.CacheLoader{Name: "task workflows", Load: repository.LoadWorkflows},
+		jobs.CacheLoader{Name: "custom fields", Load: repository.LoadCustomFields},
+	)
+	if err := cacheRefresh.LoadAll(context.Background()); err != nil {
+		log.Fatalf("failed to load cached settings: %v", err)
+	}
+	go cacheRefresh.Start(context.Background())
+
+	// Send the daily manager digest in the background
+	if cfg.DigestEnabled {
+		digestJob, err := jobs.NewDigestJob(db, cfg)
+		if err != nil {
+			log.Fatalf("failed to configure manager digest: %v", err)
+		}
+		go digestJob.Start(context.Background())
+		log.Println("✓ manager digest scheduled")
+	}
+
 	// Set Gin mode
 	if cfg.GinMode != "" {
 		gin.SetMode(cfg.GinMode)
@@ -40,13 +83,9 @@ func main() {
 	routes.SetupRoutes(router, db)
 
 	// Start server
-	port := cfg.Port
-	if port == "" {
-		port = "8080"
-	}
-
-	log.Printf("✓ server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
+	server := config.NewServer(cfg, router)
+	log.Printf("✓ server starting on %s", server.Addr)
+	if err := server.ListenAndServe(); err != nil {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
@@ -6,13 +6,17 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
 	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
 )
 
 // RequireAuth validates JWT token and sets user context
-func RequireAuth(jwtSecret string) gin.HandlerFunc {
+func RequireAuth(keys *utils.JWTKeys) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -38,7 +42,7 @@ func RequireAuth(jwtSecret string) gin.HandlerFunc {
 		}
 
 		// Validate token
-		claims, err := utils.ValidateJWT(tokenString, jwtSecret)
+		claims, err := utils.ValidateJWT(tokenString, keys)
 		if err != nil {
 			utils.RespondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid or expired token", nil)
 			c.Abort()
@@ -52,39 +56,61 @@ func RequireAuth(jwtSecret string) gin.HandlerFunc {
 		c.Set("user_role", claims.Role)
 		c.Set("user_department_id", claims.DepartmentID)
 		c.Set("user_permissions", claims.Permissions)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
 }
 
-// RequirePermission checks if user has a specific permission
+// RequireActiveSession rejects access tokens whose login session has been revoked or has
+// lapsed, so logging out a session or changing the password cuts off its access tokens
+// too. It runs after RequireAuth; tokens issued without a session are let through.
+func RequireActiveSession(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID := c.GetString("session_id")
+		if sessionID == "" {
+			c.Next()
+			return
+		}
+
+		var count int64
+		if err := db.WithContext(c.Request.Context()).Model(&models.Session{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, c.GetString("user_id"), time.Now().UTC()).
+			Count(&count).Error; err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to check session", nil)
+			c.Abort()
+			return
+		}
+		if count == 0 {
+			utils.RespondError(c, http.StatusUnauthorized, "SESSION_REVOKED", "Session has been revoked or has expired", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission checks that the user's role grants a specific permission
 func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get permissions from context (set by RequireAuth middleware)
-		permsInterface, exists := c.Get("user_permissions")
+		// Get role from context (set by RequireAuth middleware); permissions come from the
+		// current role mapping rather than the token, so mapping changes apply immediately
+		role, exists := c.Get("user_role")
 		if !exists {
-			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "No permissions found", nil)
+			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "No role found", nil)
 			c.Abort()
 			return
 		}
 
-		permissions, ok := permsInterface.([]string)
+		userRole, ok := role.(string)
 		if !ok {
-			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Invalid permissions format", nil)
+			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Invalid role format", nil)
 			c.Abort()
 			return
 		}
 
-		// Check if user has required permission
-		hasPermission := false
-		for _, perm := range permissions {
-			if perm == permission {
-				hasPermission = true
-				break
-			}
-		}
-
-		if !hasPermission {
+		if !auth.HasPermission(userRole, permission) {
 			utils.RespondError(c, http.StatusForbidden, "FORBIDDEN", "Insufficient permissions", nil)
 			c.Abort()
 			return
@@ -132,7 +158,7 @@ func RequireRole(allowedRoles ...string) gin.HandlerFunc {
 }
 
 // OptionalAuth validates JWT if present but doesn't require it
-func OptionalAuth(jwtSecret string) gin.HandlerFunc {
+func OptionalAuth(keys *utils.JWTKeys) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -143,7 +169,7 @@ func OptionalAuth(jwtSecret string) gin.HandlerFunc {
 
 		if strings.HasPrefix(authHeader, "Bearer ") {
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-			claims, err := utils.ValidateJWT(tokenString, jwtSecret)
+			claims, err := utils.ValidateJWT(tokenString, keys)
 			if err == nil {
 				// Valid token, set user context
 				c.Set("user_id", claims.UserID)
@@ -0,0 +1,158 @@
+// ABOUTME: Gzip response compression for clients that accept it
+// ABOUTME: Buffers each response until it is large enough to be worth compressing
+
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// incompressibleTypes are content types that are already compressed; gzipping them again
+// costs CPU without saving bytes
+var incompressibleTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp", "image/avif",
+	"video/", "audio/", "font/woff",
+	"application/zip", "application/gzip", "application/x-gzip", "application/pdf",
+}
+
+// Compress gzips responses of at least minBytes for clients that send Accept-Encoding: gzip.
+// Smaller responses, already-compressed content types and responses that set their own
+// Content-Encoding are sent as they are.
+func Compress(minBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, minBytes: minBytes}
+		c.Writer = writer
+		defer writer.finish()
+		c.Next()
+	}
+}
+
+// compressWriter holds back the start of a response until it knows whether to compress it
+type compressWriter struct {
+	gin.ResponseWriter
+	minBytes int
+	buffer   bytes.Buffer
+	decided  bool
+	gzip     *gzip.Writer
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buffer.Write(data)
+		if w.buffer.Len() < w.minBytes {
+			return len(data), nil
+		}
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.gzip != nil {
+		return w.gzip.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends whatever has been buffered so streamed responses still reach the client
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.start()
+	}
+	if w.gzip != nil {
+		w.gzip.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// start decides whether to compress from what has been buffered and sends it on
+func (w *compressWriter) start() error {
+	w.decided = true
+	if w.shouldCompress() {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gzip = gzip.NewWriter(w.ResponseWriter)
+		_, err := w.gzip.Write(w.buffer.Bytes())
+		return err
+	}
+	_, err := w.ResponseWriter.Write(w.buffer.Bytes())
+	return err
+}
+
+func (w *compressWriter) shouldCompress() bool {
+	if w.buffer.Len() < w.minBytes {
+		return false
+	}
+	status := w.Status()
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, skipped := range incompressibleTypes {
+		if strings.HasPrefix(contentType, skipped) {
+			return false
+		}
+	}
+	return true
+}
+
+// finish sends a response that never reached the threshold and closes the gzip stream
+func (w *compressWriter) finish() {
+	if !w.decided {
+		if w.buffer.Len() == 0 {
+			w.decided = true
+			return
+		}
+		w.start()
+	}
+	if w.gzip != nil {
+		w.gzip.Close()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip. An explicit gzip entry
+// wins over *, and q=0 refuses the coding.
+func acceptsGzip(acceptEncoding string) bool {
+	gzipQuality, anyQuality := -1.0, -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(fields[0])) {
+		case "gzip":
+			gzipQuality = quality
+		case "*":
+			anyQuality = quality
+		}
+	}
+	if gzipQuality >= 0 {
+		return gzipQuality > 0
+	}
+	return anyQuality > 0
+}
@@ -1,10 +1,12 @@
 // ABOUTME: CORS middleware configuration for cross-origin requests
-// ABOUTME: Allows frontend applications to access the API from configured origins
+// ABOUTME: Allows frontend applications to access the API from configured origins, methods and headers
 
 package middleware
 
 import (
+	"log"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -12,27 +14,120 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// regexOriginPrefix marks a CORS_ORIGINS entry as a regular expression, e.g. regex:^https://pr-\d+\.example\.com$
+const regexOriginPrefix = "regex:"
+
+// wildcardLabels is what a * in an origin pattern expands to: one or more DNS labels
+const wildcardLabels = `[a-z0-9-]+(?:\.[a-z0-9-]+)*`
+
+// Default CORS method and header lists, replaced by CORS_ALLOW_METHODS, CORS_ALLOW_HEADERS
+// and CORS_EXPOSE_HEADERS
+var (
+	defaultAllowMethods  = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultAllowHeaders  = []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match", "If-None-Match", "X-Request-ID", "Idempotency-Key"}
+	defaultExposeHeaders = []string{"Content-Length", "ETag", "X-Per-Page-Clamped"}
+)
+
+// requiredExposeHeaders are always exposed so clients can correlate and safely retry requests
+var requiredExposeHeaders = []string{"X-Request-ID", "Idempotency-Key"}
+
+// CORS allows the default local frontends plus the comma-separated CORS_ORIGINS entries.
+// Entries may be exact origins, wildcard patterns like https://*.example.com, or
+// regex:-prefixed regular expressions. Exact origins are matched first. Allowed methods and
+// headers and exposed headers come from comma-separated env lists, falling back to the defaults.
 func CORS() gin.HandlerFunc {
 	allowedOrigins := []string{"http://localhost:3000", "http://localhost:3001"}
+	var patterns []*regexp.Regexp
 
 	// Read from environment variable if set
 	if corsOrigins := os.Getenv("CORS_ORIGINS"); corsOrigins != "" {
 		origins := strings.Split(corsOrigins, ",")
 		for _, origin := range origins {
 			trimmed := strings.TrimSpace(origin)
-			if trimmed != "" {
+			if trimmed == "" {
+				continue
+			}
+			if trimmed == "*" {
+				// Credentials are allowed, so every origin would be trusted with them
+				log.Println("ignoring CORS_ORIGINS entry \"*\": wildcard origins cannot be used with credentials")
+				continue
+			}
+			if !strings.HasPrefix(trimmed, regexOriginPrefix) && !strings.Contains(trimmed, "*") {
 				allowedOrigins = append(allowedOrigins, trimmed)
+				continue
 			}
+			pattern, err := compileOriginPattern(trimmed)
+			if err != nil {
+				log.Printf("ignoring invalid CORS_ORIGINS entry %q: %v", trimmed, err)
+				continue
+			}
+			patterns = append(patterns, pattern)
 		}
 	}
 
 	config := cors.Config{
 		AllowOrigins:     allowedOrigins,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     envList("CORS_ALLOW_METHODS", defaultAllowMethods),
+		AllowHeaders:     envList("CORS_ALLOW_HEADERS", defaultAllowHeaders),
+		ExposeHeaders:    withHeaders(envList("CORS_EXPOSE_HEADERS", defaultExposeHeaders), requiredExposeHeaders),
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
+	if len(patterns) > 0 {
+		// Only consulted when no exact origin matched
+		config.AllowOriginFunc = func(origin string) bool {
+			for _, pattern := range patterns {
+				if pattern.MatchString(origin) {
+					return true
+				}
+			}
+			return false
+		}
+	}
 	return cors.New(config)
 }
+
+// envList splits the comma-separated env variable name, or returns defaults when it's unset
+// or lists nothing
+func envList(name string, defaults []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	if len(values) == 0 {
+		return append([]string{}, defaults...)
+	}
+	return values
+}
+
+// withHeaders appends each of required that headers doesn't already list, ignoring case
+func withHeaders(headers, required []string) []string {
+	for _, header := range required {
+		found := false
+		for _, existing := range headers {
+			if strings.EqualFold(existing, header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
+
+// compileOriginPattern turns a wildcard or regex: entry into an anchored, case-insensitive regexp
+func compileOriginPattern(entry string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(entry, regexOriginPrefix) {
+		return regexp.Compile("(?i)^(?:" + strings.TrimPrefix(entry, regexOriginPrefix) + ")$")
+	}
+
+	parts := strings.Split(entry, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile("(?i)^" + strings.Join(parts, wildcardLabels) + "$")
+}
@@ -0,0 +1,37 @@
+// ABOUTME: Path parameter validation middleware for resource identifiers
+// ABOUTME: Rejects malformed UUIDs with a 400 before handlers pass them to Postgres
+
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/utils"
+)
+
+// ValidateIDParams rejects requests whose :id or :<name>Id path params are not UUIDs.
+// Without it Postgres fails the cast and the handler reports a 500.
+func ValidateIDParams() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, param := range c.Params {
+			if !isIDParam(param.Key) {
+				continue
+			}
+			if !utils.IsUUID(param.Value) {
+				utils.RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid id format", []utils.ErrorDetail{
+					{Field: param.Key, Message: "must be a UUID"},
+				})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// isIDParam reports whether a path param names a resource id, like id, userId or itemId
+func isIDParam(key string) bool {
+	return key == "id" || strings.HasSuffix(key, "Id")
+}
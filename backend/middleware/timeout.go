@@ -0,0 +1,28 @@
+// ABOUTME: Request deadline middleware bounding how long handlers may spend on queries
+// ABOUTME: Handlers pass the request context to GORM so queries stop at the deadline or on disconnect
+
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeout gives each request's context a deadline of d. The context is already cancelled
+// when the client disconnects; this adds an upper bound for clients that wait. d <= 0 disables it.
+func RequestTimeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
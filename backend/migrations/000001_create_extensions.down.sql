@@ -1,4 +1,4 @@
 -- Rollback extensions
-DROP EXTENSION IF EXISTS pg_cron;
 DROP EXTENSION IF EXISTS pg_trgm;
+DROP EXTENSION IF EXISTS pgcrypto;
 DROP EXTENSION IF EXISTS "uuid-ossp";
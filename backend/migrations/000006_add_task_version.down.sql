@@ -0,0 +1,2 @@
+-- Rollback task version column
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
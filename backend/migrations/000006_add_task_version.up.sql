@@ -0,0 +1,2 @@
+-- Add optimistic concurrency version to tasks
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
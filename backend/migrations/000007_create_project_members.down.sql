@@ -0,0 +1,2 @@
+-- Rollback project_members table
+DROP TABLE IF EXISTS project_members;
@@ -0,0 +1,12 @@
+-- Create project_members table for explicit per-project access
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'Contributor',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id),
+    CONSTRAINT chk_project_member_role CHECK (role IN ('Lead', 'Contributor', 'Viewer'))
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_project_members_user_id ON project_members(user_id);
@@ -0,0 +1,3 @@
+-- Rollback time tracking
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimated_minutes;
+DROP TABLE IF EXISTS time_logs;
@@ -0,0 +1,19 @@
+-- Create time_logs table for recording work against tasks
+CREATE TABLE IF NOT EXISTS time_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    minutes INTEGER NOT NULL,
+    note TEXT,
+    logged_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT chk_time_log_minutes CHECK (minutes > 0)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_time_logs_task_id ON time_logs(task_id);
+CREATE INDEX IF NOT EXISTS idx_time_logs_user_logged_at ON time_logs(user_id, logged_at);
+
+-- Estimate to compare logged time against
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimated_minutes INTEGER;
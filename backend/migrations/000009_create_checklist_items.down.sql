@@ -0,0 +1,2 @@
+-- Rollback checklist_items table
+DROP TABLE IF EXISTS checklist_items;
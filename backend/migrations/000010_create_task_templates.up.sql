@@ -0,0 +1,18 @@
+-- Create task_templates table for repeatable task shapes
+CREATE TABLE IF NOT EXISTS task_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'To Do',
+    priority VARCHAR(10) NOT NULL DEFAULT 'Medium',
+    tags TEXT[] DEFAULT '{}',
+    checklist TEXT[] DEFAULT '{}',
+    department_id UUID REFERENCES departments(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_task_templates_department_id ON task_templates(department_id);
@@ -0,0 +1,3 @@
+-- Rollback task rank column
+DROP INDEX IF EXISTS idx_tasks_status_rank;
+ALTER TABLE tasks DROP COLUMN IF EXISTS rank;
@@ -0,0 +1,4 @@
+-- Add manual board order to tasks, scoped per status column
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_status_rank ON tasks(status, rank);
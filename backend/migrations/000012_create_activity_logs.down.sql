@@ -0,0 +1,2 @@
+-- Rollback activity_logs table
+DROP TABLE IF EXISTS activity_logs;
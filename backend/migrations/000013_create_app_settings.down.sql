@@ -0,0 +1,2 @@
+-- Rollback app_settings table
+DROP TABLE IF EXISTS app_settings;
@@ -0,0 +1,3 @@
+-- Rollback task_assignees and task_dependencies tables
+DROP TABLE IF EXISTS task_dependencies;
+DROP TABLE IF EXISTS task_assignees;
@@ -0,0 +1,3 @@
+-- Rollback roles and role_permissions tables
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,41 @@
+-- Create roles and role_permissions, seeded with the built-in roles
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(20) PRIMARY KEY,
+    is_builtin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_name VARCHAR(20) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_name, permission)
+);
+
+INSERT INTO roles (name, is_builtin) VALUES
+    ('Admin', TRUE),
+    ('Manager', TRUE),
+    ('Member', TRUE),
+    ('Viewer', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('Admin', 'tasks.create'), ('Admin', 'tasks.read'), ('Admin', 'tasks.update'), ('Admin', 'tasks.delete'),
+    ('Admin', 'users.create'), ('Admin', 'users.read'), ('Admin', 'users.update'), ('Admin', 'users.delete'),
+    ('Admin', 'projects.create'), ('Admin', 'projects.read'), ('Admin', 'projects.update'), ('Admin', 'projects.delete'),
+    ('Admin', 'departments.create'), ('Admin', 'departments.read'), ('Admin', 'departments.update'), ('Admin', 'departments.delete'),
+    ('Admin', 'settings.read'), ('Admin', 'settings.update'),
+    ('Admin', 'roles.create'), ('Admin', 'roles.update'), ('Admin', 'roles.delete'),
+    ('Manager', 'tasks.create'), ('Manager', 'tasks.read'), ('Manager', 'tasks.update'), ('Manager', 'tasks.delete'),
+    ('Manager', 'users.read'),
+    ('Manager', 'projects.create'), ('Manager', 'projects.read'), ('Manager', 'projects.update'),
+    ('Manager', 'departments.read'),
+    ('Member', 'tasks.create'), ('Member', 'tasks.read'), ('Member', 'tasks.update'),
+    ('Member', 'users.read'),
+    ('Member', 'projects.read'),
+    ('Member', 'departments.read'),
+    ('Viewer', 'tasks.read'),
+    ('Viewer', 'users.read'),
+    ('Viewer', 'projects.read'),
+    ('Viewer', 'departments.read')
+ON CONFLICT DO NOTHING;
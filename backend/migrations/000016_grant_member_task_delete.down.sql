@@ -0,0 +1,2 @@
+-- Rollback the Member tasks.delete grant
+DELETE FROM role_permissions WHERE role_name = 'Member' AND permission = 'tasks.delete';
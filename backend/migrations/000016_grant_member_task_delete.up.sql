@@ -0,0 +1,4 @@
+-- Members may delete the tasks they created, so their role needs tasks.delete now that the
+-- delete route checks it
+INSERT INTO role_permissions (role_name, permission) VALUES ('Member', 'tasks.delete')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+-- Rollback comments table
+DROP TABLE IF EXISTS comments;
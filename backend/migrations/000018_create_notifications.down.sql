@@ -0,0 +1,2 @@
+-- Rollback notifications table
+DROP TABLE IF EXISTS notifications;
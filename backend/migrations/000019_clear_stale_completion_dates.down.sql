@@ -0,0 +1,2 @@
+-- The cleared completion dates were stale and can't be restored; nothing to roll back
+SELECT 1;
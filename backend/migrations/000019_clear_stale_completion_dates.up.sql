@@ -0,0 +1,3 @@
+-- Tasks moved out of Done used to keep their completion date; clear it so completion
+-- reports only count tasks that are still done
+UPDATE tasks SET completion_date = NULL WHERE status <> 'Done' AND completion_date IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Rollback department metadata
+ALTER TABLE departments DROP COLUMN IF EXISTS metadata;
@@ -0,0 +1,2 @@
+-- Add a metadata column to departments for settings such as the working calendar
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
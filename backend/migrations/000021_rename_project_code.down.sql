@@ -0,0 +1,5 @@
+-- Rollback project code rename and generation
+ALTER TABLE projects ALTER COLUMN code DROP NOT NULL;
+DROP SEQUENCE IF EXISTS project_code_seq;
+ALTER INDEX idx_projects_code RENAME TO idx_projects_project_id;
+ALTER TABLE projects RENAME COLUMN code TO project_id;
@@ -0,0 +1,18 @@
+-- The Project model reads and writes projects.code; rename the column it was created as
+ALTER TABLE projects RENAME COLUMN project_id TO code;
+ALTER INDEX idx_projects_project_id RENAME TO idx_projects_code;
+
+-- Project codes are generated as PROJ-0001, PROJ-0002, ...; continue after any existing ones
+CREATE SEQUENCE IF NOT EXISTS project_code_seq;
+SELECT setval('project_code_seq',
+    COALESCE(MAX(substring(code FROM '^PROJ-([0-9]+)$')::BIGINT), 1),
+    MAX(substring(code FROM '^PROJ-([0-9]+)$')) IS NOT NULL)
+FROM projects;
+
+-- Give projects created without a code one
+UPDATE projects
+SET code = 'PROJ-' || LPAD(nextval('project_code_seq')::TEXT, 4, '0')
+FROM (SELECT id FROM projects WHERE code IS NULL OR code = '' ORDER BY created_at, id) AS uncoded
+WHERE projects.id = uncoded.id;
+
+ALTER TABLE projects ALTER COLUMN code SET NOT NULL;
@@ -0,0 +1,2 @@
+-- Rollback comment reactions
+DROP TABLE IF EXISTS comment_reactions;
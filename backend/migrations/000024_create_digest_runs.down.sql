@@ -0,0 +1,2 @@
+-- Rollback digest runs
+DROP TABLE IF EXISTS digest_runs;
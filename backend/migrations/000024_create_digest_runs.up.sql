@@ -0,0 +1,8 @@
+-- Create digest_runs table; one row per manager per day records that their digest went out,
+-- so a restarted server doesn't send it twice
+CREATE TABLE IF NOT EXISTS digest_runs (
+    manager_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    digest_date DATE NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (manager_id, digest_date)
+);
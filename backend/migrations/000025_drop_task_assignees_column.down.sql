@@ -0,0 +1,5 @@
+-- Rollback dropping tasks.assignees; the array is rebuilt from task_assignees
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignees UUID[] DEFAULT '{}';
+
+UPDATE tasks t
+SET assignees = ARRAY(SELECT ta.user_id FROM task_assignees ta WHERE ta.task_id = t.id ORDER BY ta.assigned_at, ta.user_id);
@@ -0,0 +1,11 @@
+-- task_assignees is the only record of who is assigned to a task. Copy any assignees still
+-- held in the legacy tasks.assignees array into it, then drop the array so the two can't
+-- diverge.
+INSERT INTO task_assignees (task_id, user_id)
+SELECT t.id, a.user_id
+FROM tasks t
+CROSS JOIN LATERAL unnest(t.assignees) AS a(user_id)
+WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = a.user_id)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS assignees;
@@ -0,0 +1,2 @@
+-- Rollback password history
+DROP TABLE IF EXISTS password_history;
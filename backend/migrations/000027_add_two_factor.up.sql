@@ -0,0 +1,18 @@
+-- Add TOTP two-factor authentication to users. totp_secret is encrypted by the API and set
+-- at enrollment; totp_enabled turns on once a first code is verified. totp_last_step is the
+-- last accepted time step, so a code can't be replayed.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
+
+-- Create recovery_codes table; single-use codes for signing in without the authenticator
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recovery_codes_user_code ON recovery_codes(user_id, code_hash);
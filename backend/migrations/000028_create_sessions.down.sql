@@ -0,0 +1,2 @@
+-- Rollback sessions table
+DROP TABLE IF EXISTS sessions;
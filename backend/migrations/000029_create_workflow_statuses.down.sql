@@ -0,0 +1,4 @@
+-- Rollback workflow_statuses; tasks on custom statuses must be moved back to a built-in
+-- status first or restoring the check fails
+ALTER TABLE tasks ADD CONSTRAINT chk_task_status CHECK (status IN ('To Do', 'In Progress', 'In Review', 'Blocked', 'Done'));
+DROP TABLE IF EXISTS workflow_statuses;
@@ -0,0 +1,32 @@
+-- Create workflow_statuses; the task statuses a department's workflow allows, in board order.
+-- Rows with no department form the default workflow. A department with statuses of its own
+-- uses only those. Seeded with the five statuses tasks have always had.
+CREATE TABLE IF NOT EXISTS workflow_statuses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(20) NOT NULL,
+    department_id UUID REFERENCES departments(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    color VARCHAR(7),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Names are unique within each workflow
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_statuses_default_name
+    ON workflow_statuses(LOWER(name)) WHERE department_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_statuses_department_name
+    ON workflow_statuses(department_id, LOWER(name)) WHERE department_id IS NOT NULL;
+
+INSERT INTO workflow_statuses (name, position, color)
+SELECT seed.name, seed.position, seed.color
+FROM (VALUES
+    ('To Do', 0, '#94A3B8'),
+    ('In Progress', 1, '#3B82F6'),
+    ('In Review', 2, '#A855F7'),
+    ('Blocked', 3, '#EF4444'),
+    ('Done', 4, '#22C55E')
+) AS seed(name, position, color)
+WHERE NOT EXISTS (SELECT 1 FROM workflow_statuses WHERE department_id IS NULL);
+
+-- Task statuses are now checked against the workflow by the API
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_task_status;
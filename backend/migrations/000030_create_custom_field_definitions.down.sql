@@ -0,0 +1,2 @@
+-- Rollback custom_field_definitions; stored values stay in tasks.metadata
+DROP TABLE IF EXISTS custom_field_definitions;
@@ -0,0 +1,20 @@
+-- Create custom_field_definitions; extra task fields an Admin defines, such as "Client" or
+-- "Cost Center". Rows with no department apply to every task, others only to that department's.
+-- Values live in tasks.metadata under "custom", keyed by field name.
+CREATE TABLE IF NOT EXISTS custom_field_definitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT false,
+    department_id UUID REFERENCES departments(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CONSTRAINT chk_custom_field_type CHECK (type IN ('text', 'number', 'date', 'boolean', 'select'))
+);
+
+-- Names key the stored values, so they are unique across all scopes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_field_definitions_name
+    ON custom_field_definitions(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_custom_field_definitions_department
+    ON custom_field_definitions(department_id);
@@ -0,0 +1,3 @@
+-- Rollback the task source check; tasks tagged with configured sources must be moved back to
+-- a built-in source first or restoring the check fails
+ALTER TABLE tasks ADD CONSTRAINT chk_task_source CHECK (source IN ('GUI', 'Email', 'API', 'Document', 'NLP'));
@@ -0,0 +1,3 @@
+-- Task sources are checked by the API, which accepts the built-in sources plus any
+-- configured in TASK_SOURCES
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_task_source;
@@ -0,0 +1,3 @@
+-- Rollback two-factor lockout
+ALTER TABLE users DROP COLUMN IF EXISTS totp_locked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_failed_attempts;
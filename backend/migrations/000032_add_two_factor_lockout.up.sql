@@ -0,0 +1,4 @@
+-- Limit guessing at the login code step. totp_failed_attempts counts codes tried since the
+-- last successful one; too many lock the code step until totp_locked_until.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_locked_until TIMESTAMPTZ;
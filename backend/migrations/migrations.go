@@ -0,0 +1,87 @@
+// ABOUTME: Migration runner applying the numbered SQL files in this directory
+// ABOUTME: Records applied versions in schema_migrations and applies pending ones in order
+
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+//go:embed *.up.sql
+var files embed.FS
+
+// advisoryLockID serializes runners when several instances start at once
+const advisoryLockID = 7_318_402_991
+
+// Run applies every pending *.up.sql migration in version order and returns the versions it
+// applied. All pending migrations run in one transaction, so a failure leaves the schema unchanged.
+func Run(db *gorm.DB) ([]string, error) {
+	versions, err := Versions()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", advisoryLockID).Error; err != nil {
+			return fmt.Errorf("acquiring migration lock: %w", err)
+		}
+		if err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMPTZ DEFAULT NOW()
+		)`).Error; err != nil {
+			return fmt.Errorf("creating schema_migrations: %w", err)
+		}
+
+		var done []string
+		if err := tx.Table("schema_migrations").Pluck("version", &done).Error; err != nil {
+			return fmt.Errorf("reading schema_migrations: %w", err)
+		}
+		doneSet := make(map[string]bool, len(done))
+		for _, version := range done {
+			doneSet[version] = true
+		}
+
+		for _, version := range versions {
+			if doneSet[version] {
+				continue
+			}
+			contents, err := files.ReadFile(version + ".up.sql")
+			if err != nil {
+				return err
+			}
+			if err := tx.Exec(string(contents)).Error; err != nil {
+				return fmt.Errorf("migration %s: %w", version, err)
+			}
+			if err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", version).Error; err != nil {
+				return fmt.Errorf("recording migration %s: %w", version, err)
+			}
+			applied = append(applied, version)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// Versions lists the embedded migration versions (file names without .up.sql) in order
+func Versions() ([]string, error) {
+	names, err := fs.Glob(files, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(names))
+	for _, name := range names {
+		versions = append(versions, strings.TrimSuffix(name, ".up.sql"))
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
@@ -0,0 +1,20 @@
+// ABOUTME: Activity log model recording who changed what on tasks and projects
+// ABOUTME: Details holds action-specific JSON such as previous and new owners
+
+package models
+
+import "time"
+
+type ActivityLog struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ActorID    *string   `gorm:"type:uuid" json:"actor_id,omitempty"`
+	EntityType string    `gorm:"type:varchar(20);not null" json:"entity_type"`
+	EntityID   string    `gorm:"type:uuid;not null" json:"entity_id"`
+	Action     string    `gorm:"type:varchar(50);not null" json:"action"`
+	Details    *string   `gorm:"type:jsonb" json:"details,omitempty"`
+	CreatedAt  time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+func (ActivityLog) TableName() string {
+	return "activity_logs"
+}
@@ -0,0 +1,17 @@
+// ABOUTME: Application setting model for Admin-adjustable limits
+// ABOUTME: Values are stored as text and parsed by the handler that owns each key
+
+package models
+
+import "time"
+
+type AppSetting struct {
+	Key       string    `gorm:"type:varchar(100);primaryKey" json:"key"`
+	Value     string    `gorm:"type:text;not null" json:"value"`
+	UpdatedBy *string   `gorm:"type:uuid" json:"updated_by,omitempty"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+func (AppSetting) TableName() string {
+	return "app_settings"
+}
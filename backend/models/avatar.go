@@ -0,0 +1,70 @@
+// ABOUTME: Default avatar URLs for users who haven't set one
+// ABOUTME: Derived when a user is serialized; the stored avatar_url stays null
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Avatar styles DefaultAvatarURL can derive, matching the values AVATAR_STYLE accepts
+const (
+	AvatarStyleGravatar = "gravatar"
+	AvatarStyleInitials = "initials"
+	AvatarStyleNone     = "none"
+)
+
+// avatarStyle is the style users without a stored avatar are serialized with
+var avatarStyle = AvatarStyleGravatar
+
+// SetAvatarStyle sets the style MarshalJSON derives missing avatars in. main calls it once at
+// startup with the validated AVATAR_STYLE.
+func SetAvatarStyle(style string) {
+	avatarStyle = style
+}
+
+// MarshalJSON fills avatar_url with DefaultAvatarURL when the user has none stored and adds
+// the user's _links
+func (u User) MarshalJSON() ([]byte, error) {
+	// userJSON drops User's methods so encoding it doesn't recurse back here
+	type userJSON User
+	out := userJSON(u)
+	if out.AvatarURL == nil {
+		if avatarURL := DefaultAvatarURL(u, avatarStyle); avatarURL != "" {
+			out.AvatarURL = &avatarURL
+		}
+	}
+	return json.Marshal(struct {
+		userJSON
+		Links Links `json:"_links,omitempty"`
+	}{out, u.links()})
+}
+
+// DefaultAvatarURL derives a stable avatar URL for u in the given avatar style. It returns ""
+// for the none style.
+func DefaultAvatarURL(u User, style string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(u.Email))))
+	hash := hex.EncodeToString(sum[:])
+
+	switch style {
+	case AvatarStyleNone:
+		return ""
+	case AvatarStyleInitials:
+		name := strings.TrimSpace(u.FullName)
+		if name == "" {
+			name = u.Username
+		}
+		query := url.Values{}
+		query.Set("name", name)
+		query.Set("background", hash[:6]) // Same color for the same email on every load
+		query.Set("color", "fff")
+		query.Set("size", "200")
+		return "https://ui-avatars.com/api/?" + query.Encode()
+	default:
+		return "https://www.gravatar.com/avatar/" + hash + "?d=identicon&s=200"
+	}
+}
@@ -0,0 +1,26 @@
+// ABOUTME: Checklist item model for acceptance sub-items within a task
+// ABOUTME: Items are ordered by a zero-based position per task
+
+package models
+
+import "time"
+
+type ChecklistItem struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TaskID    string    `gorm:"type:uuid;not null" json:"task_id"`
+	Text      string    `gorm:"type:varchar(500);not null" json:"text"`
+	Done      bool      `gorm:"not null;default:false" json:"done"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "checklist_items"
+}
+
+// ChecklistProgress summarizes how many of a task's checklist items are done
+type ChecklistProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
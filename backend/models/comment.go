@@ -0,0 +1,74 @@
+// ABOUTME: Comment model for discussion on a task
+// ABOUTME: MentionIDs holds the users resolved from @username mentions in the content
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type Comment struct {
+	ID         string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TaskID     string         `gorm:"type:uuid;not null" json:"task_id"`
+	UserID     string         `gorm:"type:uuid;not null" json:"user_id"`
+	User       *User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Content    string         `gorm:"type:text;not null" json:"content"`
+	MentionIDs pq.StringArray `gorm:"type:uuid[];not null;default:'{}'" json:"mention_ids"`
+	EditedAt   *time.Time     `json:"edited_at,omitempty"`
+	DeletedAt  gorm.DeletedAt `json:"-"` // Deleted comments are hidden but keep their revisions
+	CreatedAt  time.Time      `gorm:"default:now()" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"default:now()" json:"updated_at"`
+
+	// Reactions summarizes comment_reactions for the requesting user; not a column
+	Reactions []ReactionSummary `gorm:"-" json:"reactions"`
+}
+
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// MarshalJSON adds an edited flag so clients needn't infer it from edited_at
+func (c Comment) MarshalJSON() ([]byte, error) {
+	// commentJSON drops Comment's methods so encoding it doesn't recurse back here
+	type commentJSON Comment
+	return json.Marshal(struct {
+		commentJSON
+		Edited bool `json:"edited"`
+	}{commentJSON(c), c.EditedAt != nil})
+}
+
+// CommentRevision is the text a comment had before one of its edits
+type CommentRevision struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CommentID string    `gorm:"type:uuid;not null" json:"comment_id"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	EditedBy  *string   `gorm:"type:uuid" json:"edited_by,omitempty"` // Who replaced this text
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`      // When it was replaced
+}
+
+func (CommentRevision) TableName() string {
+	return "comment_revisions"
+}
+
+// CommentReaction is one user's emoji reaction to a comment
+type CommentReaction struct {
+	CommentID string    `gorm:"type:uuid;primaryKey" json:"comment_id"`
+	UserID    string    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Emoji     string    `gorm:"type:varchar(32);primaryKey" json:"emoji"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+func (CommentReaction) TableName() string {
+	return "comment_reactions"
+}
+
+// ReactionSummary counts one emoji's reactions on a comment and whether the caller is among them
+type ReactionSummary struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
@@ -0,0 +1,25 @@
+// ABOUTME: Custom field definition model, an Admin-defined extra field on tasks
+// ABOUTME: Definitions without a department apply to every task; values live in task metadata
+
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type CustomFieldDefinition struct {
+	ID           string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name         string         `gorm:"type:varchar(100);not null" json:"name"`
+	Type         string         `gorm:"type:varchar(20);not null" json:"type"`
+	Options      pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"options"` // Allowed values of a select field
+	Required     bool           `gorm:"not null;default:false" json:"required"`
+	DepartmentID *string        `gorm:"type:uuid" json:"department_id"`
+	CreatedAt    time.Time      `gorm:"default:now()" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"default:now()" json:"updated_at"`
+}
+
+func (CustomFieldDefinition) TableName() string {
+	return "custom_field_definitions"
+}
@@ -13,6 +13,9 @@ type Department struct {
 	Head        *User     `gorm:"foreignKey:HeadID" json:"head,omitempty"`
 	ParentID    *string   `gorm:"type:uuid" json:"parent_id,omitempty"`
 	Parent      *Department `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Metadata    string    `gorm:"type:jsonb;default:'{}'" json:"metadata,omitempty"` // Holds the working_calendar
+	UserCount   *int      `gorm:"-" json:"user_count,omitempty"` // Only with ?with_counts=true
+	TaskCount   *int      `gorm:"-" json:"task_count,omitempty"` // Only with ?with_counts=true
 	CreatedAt   time.Time `gorm:"default:now()" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"default:now()" json:"updated_at"`
 }
@@ -0,0 +1,16 @@
+// ABOUTME: DigestRun model recording the days a manager's digest was sent
+// ABOUTME: The composite key is claimed before sending so each digest goes out once per day
+
+package models
+
+import "time"
+
+type DigestRun struct {
+	ManagerID  string    `gorm:"type:uuid;primaryKey" json:"manager_id"`
+	DigestDate time.Time `gorm:"type:date;primaryKey" json:"digest_date"`
+	SentAt     time.Time `gorm:"default:now()" json:"sent_at"`
+}
+
+func (DigestRun) TableName() string {
+	return "digest_runs"
+}
@@ -0,0 +1,101 @@
+// ABOUTME: Self and related-resource links added to tasks, projects, users and departments
+// ABOUTME: Links are derived when a resource is serialized, prefixed with PUBLIC_BASE_URL
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Links maps link names, such as self, to API URLs
+type Links map[string]string
+
+// apiBase is the prefix of every link: PUBLIC_BASE_URL, if set, followed by the API version
+var apiBase = "/api/v1"
+
+// SetPublicBaseURL sets the scheme and host links are prefixed with; "" leaves them
+// root-relative. main calls it once at startup with the validated PUBLIC_BASE_URL.
+func SetPublicBaseURL(base string) {
+	apiBase = strings.TrimRight(base, "/") + "/api/v1"
+}
+
+// MarshalJSON adds the task's _links
+func (t Task) MarshalJSON() ([]byte, error) {
+	// taskJSON drops Task's methods so encoding it doesn't recurse back here
+	type taskJSON Task
+	return json.Marshal(struct {
+		taskJSON
+		Links Links `json:"_links,omitempty"`
+	}{taskJSON(t), t.links()})
+}
+
+// MarshalJSON adds the project's _links
+func (p Project) MarshalJSON() ([]byte, error) {
+	type projectJSON Project
+	return json.Marshal(struct {
+		projectJSON
+		Links Links `json:"_links,omitempty"`
+	}{projectJSON(p), p.links()})
+}
+
+// MarshalJSON adds the department's _links
+func (d Department) MarshalJSON() ([]byte, error) {
+	type departmentJSON Department
+	return json.Marshal(struct {
+		departmentJSON
+		Links Links `json:"_links,omitempty"`
+	}{departmentJSON(d), d.links()})
+}
+
+// links are nil for resources that haven't been saved, so they serialize without _links
+func (t Task) links() Links {
+	if t.ID == "" {
+		return nil
+	}
+	self := apiBase + "/tasks/" + t.ID
+	links := Links{
+		"self":      self,
+		"comments":  self + "/comments",
+		"checklist": self + "/checklist",
+	}
+	if t.ProjectID != nil {
+		links["project"] = apiBase + "/projects/" + *t.ProjectID
+	}
+	return links
+}
+
+func (p Project) links() Links {
+	if p.ID == "" {
+		return nil
+	}
+	self := apiBase + "/projects/" + p.ID
+	return Links{
+		"self":    self,
+		"tasks":   self + "/tasks",
+		"members": self + "/members",
+	}
+}
+
+func (u User) links() Links {
+	if u.ID == "" {
+		return nil
+	}
+	self := apiBase + "/users/" + u.ID
+	return Links{
+		"self":  self,
+		"tasks": self + "/tasks",
+	}
+}
+
+func (d Department) links() Links {
+	if d.ID == "" {
+		return nil
+	}
+	self := apiBase + "/departments/" + d.ID
+	return Links{
+		"self":  self,
+		"users": self + "/users",
+		"tasks": self + "/tasks",
+	}
+}
@@ -0,0 +1,22 @@
+// ABOUTME: Notification model for in-app notices addressed to one user
+// ABOUTME: ReadAt stays nil until the recipient marks the notification read
+
+package models
+
+import "time"
+
+type Notification struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null" json:"user_id"`
+	ActorID   *string    `gorm:"type:uuid" json:"actor_id,omitempty"`
+	Type      string     `gorm:"type:varchar(30);not null" json:"type"`
+	TaskID    *string    `gorm:"type:uuid" json:"task_id,omitempty"`
+	CommentID *string    `gorm:"type:uuid" json:"comment_id,omitempty"`
+	Message   string     `gorm:"type:text;not null" json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
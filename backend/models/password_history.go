@@ -0,0 +1,17 @@
+// ABOUTME: PasswordHistory model holding a user's previous password hashes
+// ABOUTME: Checked on password changes so recent passwords aren't reused
+
+package models
+
+import "time"
+
+type PasswordHistory struct {
+	ID           string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string    `gorm:"type:uuid;not null" json:"user_id"`
+	PasswordHash string    `gorm:"type:varchar(255);not null" json:"-"`
+	CreatedAt    time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
@@ -0,0 +1,64 @@
+// ABOUTME: Typed access to the user preferences stored as JSON in users.preferences
+// ABOUTME: Holds the timezone that day boundaries like "due today" are computed in
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	_ "time/tzdata" // timezones resolve even on hosts without a zoneinfo database
+)
+
+// UserPreferences is the known part of User.Preferences
+type UserPreferences struct {
+	// Timezone is an IANA name like Europe/Berlin; empty means UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ParsedPreferences decodes the user's preferences, ignoring malformed JSON
+func (u User) ParsedPreferences() UserPreferences {
+	var prefs UserPreferences
+	_ = json.Unmarshal([]byte(u.Preferences), &prefs)
+	return prefs
+}
+
+// Location returns the user's timezone, or UTC when none is set or it is no longer known
+func (u User) Location() *time.Location {
+	loc, err := LoadTimezone(u.ParsedPreferences().Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SetTimezone stores timezone in the user's preferences, keeping any other keys. An empty
+// timezone clears the preference. The name must already be valid; see LoadTimezone.
+func (u *User) SetTimezone(timezone string) error {
+	prefs := map[string]interface{}{}
+	if u.Preferences != "" {
+		if err := json.Unmarshal([]byte(u.Preferences), &prefs); err != nil || prefs == nil {
+			prefs = map[string]interface{}{}
+		}
+	}
+	if timezone == "" {
+		delete(prefs, "timezone")
+	} else {
+		prefs["timezone"] = timezone
+	}
+	encoded, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+	u.Preferences = string(encoded)
+	return nil
+}
+
+// LoadTimezone resolves an IANA timezone name; empty is UTC. "Local" is refused, since the
+// server's own zone means nothing to the user.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %s", name)
+	}
+	return time.LoadLocation(name)
+}
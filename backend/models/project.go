@@ -18,6 +18,8 @@ type Project struct {
 	StartDate    *time.Time  `json:"start_date,omitempty"`
 	EndDate      *time.Time  `json:"end_date,omitempty"`
 	Metadata     string      `gorm:"type:jsonb;default:'{}'" json:"metadata,omitempty"`
+	Health       string      `gorm:"-" json:"health,omitempty"`     // Derived from the project's tasks on read
+	TaskCount    *int        `gorm:"-" json:"task_count,omitempty"` // Only with ?with_counts=true
 	CreatedAt    time.Time   `gorm:"default:now()" json:"created_at"`
 	UpdatedAt    time.Time   `gorm:"default:now()" json:"updated_at"`
 }
@@ -0,0 +1,18 @@
+// ABOUTME: Project membership model granting per-project access
+// ABOUTME: Members can see a project regardless of their department
+
+package models
+
+import "time"
+
+type ProjectMember struct {
+	ProjectID string    `gorm:"type:uuid;primaryKey" json:"project_id"`
+	UserID    string    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role      string    `gorm:"type:varchar(20);not null;default:'Contributor'" json:"role"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
@@ -0,0 +1,18 @@
+// ABOUTME: RecoveryCode model for single-use two-factor recovery codes
+// ABOUTME: Only a hash of each code is stored; UsedAt is set when a code is spent
+
+package models
+
+import "time"
+
+type RecoveryCode struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
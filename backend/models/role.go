@@ -0,0 +1,27 @@
+// ABOUTME: Role model holding a named set of permissions
+// ABOUTME: Built-in roles are seeded by migration and cannot be deleted
+
+package models
+
+import "time"
+
+type Role struct {
+	Name      string    `gorm:"type:varchar(20);primaryKey" json:"name"`
+	IsBuiltin bool      `gorm:"not null;default:false" json:"is_builtin"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+// RolePermission grants one permission to a role
+type RolePermission struct {
+	RoleName   string `gorm:"type:varchar(20);primaryKey" json:"role_name"`
+	Permission string `gorm:"type:varchar(100);primaryKey" json:"permission"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -0,0 +1,21 @@
+// ABOUTME: Session model for refresh-token sessions, one per login
+// ABOUTME: Records the client that logged in so users can review and revoke their sessions
+
+package models
+
+import "time"
+
+type Session struct {
+	ID         string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID     string     `gorm:"type:uuid;not null" json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `gorm:"type:varchar(45)" json:"ip_address"`
+	CreatedAt  time.Time  `gorm:"default:now()" json:"created_at"`
+	LastUsedAt time.Time  `gorm:"default:now()" json:"last_used_at"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
@@ -11,7 +11,7 @@ import (
 
 type Task struct {
 	ID                       string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	TaskID                   string         `gorm:"-" json:"task_id,omitempty"`
+	TaskID                   string         `gorm:"-" json:"task_id,omitempty"` // Column is unique; unmapped until IDs are generated
 	Title                    string         `gorm:"type:varchar(500);not null" json:"title"`
 	Description              *string        `gorm:"type:text" json:"description,omitempty"`
 	Status                   string         `gorm:"type:varchar(20);not null;default:'To Do'" json:"status"`
@@ -20,7 +20,9 @@ type Task struct {
 	// User relationships
 	CreatorID                string         `gorm:"type:uuid;not null" json:"creator_id"`
 	Creator                  *User          `gorm:"foreignKey:CreatorID" json:"creator,omitempty"`
-	Assignees                pq.StringArray `gorm:"-" json:"assignee_ids"`
+	Assignees                pq.StringArray `gorm:"-" json:"assignee_ids"` // Loaded from task_assignees, the only store of assignees
+	AssigneesDetail          []User         `gorm:"-" json:"assignees_detail,omitempty"` // Only with ?expand=assignees
+	Relation                 string         `gorm:"-" json:"relation,omitempty"` // Only from GetUserTasks: created, assigned or both
 
 	// Organization
 	DepartmentID             *string        `gorm:"type:uuid" json:"department_id,omitempty"`
@@ -32,10 +34,13 @@ type Task struct {
 	DueDate                  *time.Time     `json:"due_date,omitempty"`
 	CompletionDate           *time.Time     `json:"completion_date,omitempty"`
 
+	// Time tracking, compared against logged minutes in time_logs
+	EstimatedMinutes         *int           `json:"estimated_minutes,omitempty"`
+
 	// Source tracking
 	Source                   string         `gorm:"type:varchar(20);not null;default:'GUI'" json:"source"`
-	SourceEmailID            *string        `gorm:"-" json:"source_email_id,omitempty"`
-	SourceDocumentID         *string        `gorm:"-" json:"source_document_id,omitempty"`
+	SourceEmailID            *string        `gorm:"type:varchar(255)" json:"source_email_id,omitempty"`
+	SourceDocumentID         *string        `gorm:"type:varchar(255)" json:"source_document_id,omitempty"`
 
 	// Metadata
 	Tags                     pq.StringArray `gorm:"type:text[];default:'{}'" json:"tags"`
@@ -43,16 +48,30 @@ type Task struct {
 	ConfidenceScore          *float64       `gorm:"type:decimal(3,2)" json:"confidence_score,omitempty"`
 	Metadata                 *string        `gorm:"type:jsonb" json:"metadata,omitempty"`
 
-	// Recurring task fields (not yet implemented in database)
-	IsRecurring              bool           `gorm:"-" json:"is_recurring,omitempty"`
-	RecurrencePattern        *string        `gorm:"-" json:"recurrence_pattern,omitempty"`
-	RecurrenceParentID       *string        `gorm:"-" json:"recurrence_parent_id,omitempty"`
+	// Checklist
+	ChecklistItems           []ChecklistItem    `gorm:"foreignKey:TaskID" json:"checklist_items,omitempty"`
+	ChecklistProgress        *ChecklistProgress `gorm:"-" json:"checklist_progress,omitempty"`
+
+	// Child counts, only with ?with_counts=true; checklist items are a task's subtasks
+	SubtaskCount             *int           `gorm:"-" json:"subtask_count,omitempty"`
+	CommentCount             *int           `gorm:"-" json:"comment_count,omitempty"`
+
+	// Recurring task fields (columns exist; recurrence generation is not implemented yet)
+	IsRecurring              bool           `gorm:"not null;default:false" json:"is_recurring,omitempty"`
+	RecurrencePattern        *string        `gorm:"type:jsonb" json:"recurrence_pattern,omitempty"`
+	RecurrenceParentID       *string        `gorm:"type:uuid" json:"recurrence_parent_id,omitempty"`
 	RecurrenceParent         *Task          `gorm:"-" json:"recurrence_parent,omitempty"`
-	NextOccurrence           *time.Time     `gorm:"-" json:"next_occurrence,omitempty"`
-	SkipDates                pq.StringArray `gorm:"-" json:"skip_dates,omitempty"`
-	RecurrenceEndDate        *time.Time     `gorm:"-" json:"recurrence_end_date,omitempty"`
-	RecurrenceCount          *int           `gorm:"-" json:"recurrence_count,omitempty"`
-	RecurrenceGeneratedCount int            `gorm:"-" json:"recurrence_generated_count,omitempty"`
+	NextOccurrence           *time.Time     `json:"next_occurrence,omitempty"`
+	SkipDates                pq.StringArray `gorm:"type:text[];default:'{}'" json:"skip_dates,omitempty"`
+	RecurrenceEndDate        *time.Time     `json:"recurrence_end_date,omitempty"`
+	RecurrenceCount          *int           `json:"recurrence_count,omitempty"`
+	RecurrenceGeneratedCount int            `gorm:"default:0" json:"recurrence_generated_count,omitempty"`
+
+	// Manual board order within a status column, lower ranks first
+	Rank                     *float64       `gorm:"column:rank" json:"rank,omitempty"`
+
+	// Optimistic concurrency control, incremented on every successful save
+	Version                  int            `gorm:"not null;default:1" json:"version"`
 
 	// Timestamps
 	CreatedAt                time.Time      `gorm:"default:now()" json:"created_at"`
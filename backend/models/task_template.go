@@ -0,0 +1,30 @@
+// ABOUTME: Task template model capturing a reusable task shape
+// ABOUTME: Templates are scoped to a department, or global when no department is set
+
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type TaskTemplate struct {
+	ID           string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name         string         `gorm:"type:varchar(255);not null" json:"name"`
+	Title        string         `gorm:"type:varchar(500);not null" json:"title"` // May contain {date}
+	Description  *string        `gorm:"type:text" json:"description,omitempty"`
+	Status       string         `gorm:"type:varchar(20);not null;default:'To Do'" json:"status"`
+	Priority     string         `gorm:"type:varchar(10);not null;default:'Medium'" json:"priority"`
+	Tags         pq.StringArray `gorm:"type:text[];default:'{}'" json:"tags"`
+	Checklist    pq.StringArray `gorm:"type:text[];default:'{}'" json:"checklist"`
+	DepartmentID *string        `gorm:"type:uuid" json:"department_id,omitempty"`
+	Department   *Department    `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+	CreatorID    string         `gorm:"type:uuid;not null" json:"creator_id"`
+	CreatedAt    time.Time      `gorm:"default:now()" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"default:now()" json:"updated_at"`
+}
+
+func (TaskTemplate) TableName() string {
+	return "task_templates"
+}
@@ -0,0 +1,22 @@
+// ABOUTME: Time log model recording minutes worked on a task
+// ABOUTME: Used for per-task totals and per-user timesheets
+
+package models
+
+import "time"
+
+type TimeLog struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TaskID    string    `gorm:"type:uuid;not null" json:"task_id"`
+	UserID    string    `gorm:"type:uuid;not null" json:"user_id"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Minutes   int       `gorm:"not null" json:"minutes"`
+	Note      *string   `gorm:"type:text" json:"note,omitempty"`
+	LoggedAt  time.Time `gorm:"not null;default:now()" json:"logged_at"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+func (TimeLog) TableName() string {
+	return "time_logs"
+}
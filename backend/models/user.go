@@ -19,7 +19,12 @@ type User struct {
 	DepartmentID           *string        `gorm:"type:uuid" json:"department_id,omitempty"`
 	Department             *Department    `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
 	PasswordHash           *string        `gorm:"type:varchar(255)" json:"-"`
-	IsActive               bool           `gorm:"column:active;default:true" json:"is_active"`
+	TOTPSecret             *string        `gorm:"column:totp_secret;type:text" json:"-"` // Encrypted
+	TOTPEnabled            bool           `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`
+	TOTPLastStep           *int64         `gorm:"column:totp_last_step" json:"-"`
+	TOTPFailedAttempts     int            `gorm:"column:totp_failed_attempts;default:0" json:"-"`
+	TOTPLockedUntil        *time.Time     `gorm:"column:totp_locked_until" json:"-"`
+	IsActive               bool           `gorm:"default:true" json:"is_active"`
 	EmailVerified          bool           `gorm:"default:false" json:"email_verified"`
 	LastLogin              *time.Time     `json:"last_login,omitempty"`
 	Role                   string         `gorm:"type:varchar(20);not null;default:'Member'" json:"role"`
@@ -0,0 +1,20 @@
+// ABOUTME: Workflow status model, one allowed task status in a department's workflow
+// ABOUTME: Statuses without a department make up the default workflow
+
+package models
+
+import "time"
+
+type WorkflowStatus struct {
+	ID           string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name         string    `gorm:"type:varchar(20);not null" json:"name"`
+	DepartmentID *string   `gorm:"type:uuid" json:"department_id"`
+	Position     int       `gorm:"not null;default:0" json:"position"`
+	Color        *string   `gorm:"type:varchar(7)" json:"color"`
+	CreatedAt    time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+func (WorkflowStatus) TableName() string {
+	return "workflow_statuses"
+}
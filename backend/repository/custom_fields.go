@@ -0,0 +1,46 @@
+// ABOUTME: Custom task field definitions, cached from the custom_field_definitions table
+// ABOUTME: A task's fields are the global definitions plus those of its department
+
+package repository
+
+import (
+	"sync"
+
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// customFieldCache holds the definitions last read by LoadCustomFields
+var customFieldCache struct {
+	sync.RWMutex
+	definitions []models.CustomFieldDefinition
+}
+
+// LoadCustomFields reads the custom_field_definitions table into the cache CustomFields serves
+// from. It runs at startup, after every definition change made through this instance, and
+// every CACHE_REFRESH_SEC from the cache refresh job, which is how other instances' changes arrive.
+func LoadCustomFields(db *gorm.DB) error {
+	var definitions []models.CustomFieldDefinition
+	if err := db.Order("name").Find(&definitions).Error; err != nil {
+		return err
+	}
+
+	customFieldCache.Lock()
+	defer customFieldCache.Unlock()
+	customFieldCache.definitions = definitions
+	return nil
+}
+
+// CustomFields returns the definitions that apply to departmentID's tasks, by name: the
+// global ones and the department's own
+func CustomFields(departmentID *string) []models.CustomFieldDefinition {
+	customFieldCache.RLock()
+	defer customFieldCache.RUnlock()
+	definitions := []models.CustomFieldDefinition{}
+	for _, definition := range customFieldCache.definitions {
+		if definition.DepartmentID == nil || (departmentID != nil && *definition.DepartmentID == *departmentID) {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions
+}
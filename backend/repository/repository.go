@@ -0,0 +1,21 @@
+// ABOUTME: Store interfaces the handlers depend on, with GORM implementations
+// ABOUTME: Lets handler tests swap in in-memory fakes instead of a real database
+
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned by repository lookups when no record matches
+var ErrNotFound = errors.New("record not found")
+
+// notFound translates GORM's not-found error to ErrNotFound and passes other errors through
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Shared loaders for task data stored outside the tasks table
+// ABOUTME: Populates assignee IDs and, on request, assignee user objects in batched queries
+
+package repository
+
+import (
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// LoadTaskAssignees fills Assignees for every task with a single IN query.
+// Tasks without assignees get an empty slice so they serialize as [] rather than null.
+func LoadTaskAssignees(db *gorm.DB, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	// Collect all task IDs
+	taskIDs := make([]string, len(tasks))
+	taskMap := make(map[string]*models.Task)
+	for i := range tasks {
+		taskIDs[i] = tasks[i].ID
+		taskMap[tasks[i].ID] = &tasks[i]
+		tasks[i].Assignees = []string{}
+	}
+
+	// Query assignees for all tasks using IN clause, in the order they were assigned
+	var results []struct {
+		TaskID string `gorm:"column:task_id"`
+		UserID string `gorm:"column:user_id"`
+	}
+	if err := db.Raw("SELECT task_id, user_id FROM task_assignees WHERE task_id IN ? ORDER BY assigned_at ASC, user_id ASC", taskIDs).Scan(&results).Error; err != nil {
+		return err
+	}
+
+	// Populate assignees
+	for _, result := range results {
+		if task, ok := taskMap[result.TaskID]; ok {
+			task.Assignees = append(task.Assignees, result.UserID)
+		}
+	}
+
+	return nil
+}
+
+// LoadTaskAssigneeDetails fills AssigneesDetail with full user objects for tasks whose
+// Assignees are already loaded, using one batched query across all tasks.
+// Password hashes are cleared.
+func LoadTaskAssigneeDetails(db *gorm.DB, tasks []models.Task) error {
+	seen := make(map[string]bool)
+	var userIDs []string
+	for i := range tasks {
+		tasks[i].AssigneesDetail = []models.User{}
+		for _, userID := range tasks[i].Assignees {
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var users []models.User
+	if err := db.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return err
+	}
+
+	usersByID := make(map[string]models.User, len(users))
+	for _, user := range users {
+		user.PasswordHash = nil
+		usersByID[user.ID] = user
+	}
+
+	// Keep each task's assignee order
+	for i := range tasks {
+		for _, userID := range tasks[i].Assignees {
+			if user, ok := usersByID[userID]; ok {
+				tasks[i].AssigneesDetail = append(tasks[i].AssigneesDetail, user)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// ABOUTME: Task store interface and its GORM implementation
+// ABOUTME: Covers the single-task reads and writes the task handlers perform
+
+package repository
+
+import (
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// TaskRepository loads and stores individual tasks
+type TaskRepository interface {
+	// FindByID returns the bare task, or ErrNotFound
+	FindByID(id string) (models.Task, error)
+	// FindWithRelations returns the task with its creator, department and project, or ErrNotFound
+	FindWithRelations(id string) (models.Task, error)
+	// FindDetailed is FindWithRelations plus the checklist in position order
+	FindDetailed(id string) (models.Task, error)
+	// FindManyWithRelations returns the tasks among ids that exist, with relations, in no
+	// particular order
+	FindManyWithRelations(ids []string) ([]models.Task, error)
+	// LoadAssignees fills Assignees for each task
+	LoadAssignees(tasks []models.Task) error
+	// LoadAssigneeDetails fills AssigneesDetail for tasks whose Assignees are loaded
+	LoadAssigneeDetails(tasks []models.Task) error
+	// Delete removes the task
+	Delete(task *models.Task) error
+	// DeleteMany removes the tasks and their assignee rows in one transaction
+	DeleteMany(ids []string) error
+}
+
+type gormTaskRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskRepository returns a TaskRepository backed by db
+func NewTaskRepository(db *gorm.DB) TaskRepository {
+	return &gormTaskRepository{db: db}
+}
+
+func (r *gormTaskRepository) FindByID(id string) (models.Task, error) {
+	var task models.Task
+	err := r.db.First(&task, "id = ?", id).Error
+	return task, notFound(err)
+}
+
+func (r *gormTaskRepository) FindWithRelations(id string) (models.Task, error) {
+	var task models.Task
+	err := r.db.
+		Preload("Creator").
+		Preload("Department").
+		Preload("Project").
+		First(&task, "id = ?", id).Error
+	return task, notFound(err)
+}
+
+func (r *gormTaskRepository) FindDetailed(id string) (models.Task, error) {
+	var task models.Task
+	err := r.db.
+		Preload("Creator").
+		Preload("Department").
+		Preload("Project").
+		Preload("ChecklistItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&task, "id = ?", id).Error
+	return task, notFound(err)
+}
+
+func (r *gormTaskRepository) FindManyWithRelations(ids []string) ([]models.Task, error) {
+	tasks := []models.Task{}
+	if len(ids) == 0 {
+		return tasks, nil
+	}
+	err := r.db.
+		Preload("Creator").
+		Preload("Department").
+		Preload("Project").
+		Where("id IN ?", ids).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *gormTaskRepository) LoadAssignees(tasks []models.Task) error {
+	return LoadTaskAssignees(r.db, tasks)
+}
+
+func (r *gormTaskRepository) LoadAssigneeDetails(tasks []models.Task) error {
+	return LoadTaskAssigneeDetails(r.db, tasks)
+}
+
+func (r *gormTaskRepository) Delete(task *models.Task) error {
+	return r.db.Delete(task).Error
+}
+
+func (r *gormTaskRepository) DeleteMany(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM task_assignees WHERE task_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.Task{}).Error
+	})
+}
@@ -0,0 +1,30 @@
+// ABOUTME: User store interface and its GORM implementation
+// ABOUTME: Covers the user lookups handlers perform outside of listings
+
+package repository
+
+import (
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// UserRepository loads individual users
+type UserRepository interface {
+	// FindByID returns the user, or ErrNotFound
+	FindByID(id string) (models.User, error)
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository returns a UserRepository backed by db
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+func (r *gormUserRepository) FindByID(id string) (models.User, error) {
+	var user models.User
+	err := r.db.First(&user, "id = ?", id).Error
+	return user, notFound(err)
+}
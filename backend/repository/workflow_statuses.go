@@ -0,0 +1,67 @@
+// ABOUTME: Task workflows: the statuses tasks may have, cached from the workflow_statuses table
+// ABOUTME: A department's own statuses replace the default workflow once it has any
+
+package repository
+
+import (
+	"sync"
+
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// defaultWorkflow matches the workflow_statuses migration's seed and is used until
+// LoadWorkflows has read the table
+var defaultWorkflow = []models.WorkflowStatus{
+	{Name: "To Do", Position: 0},
+	{Name: "In Progress", Position: 1},
+	{Name: "In Review", Position: 2},
+	{Name: "Blocked", Position: 3},
+	{Name: "Done", Position: 4},
+}
+
+// workflowCache holds the workflows last read by LoadWorkflows
+var workflowCache = struct {
+	sync.RWMutex
+	defaults    []models.WorkflowStatus
+	departments map[string][]models.WorkflowStatus
+}{defaults: defaultWorkflow}
+
+// LoadWorkflows reads the workflow_statuses table into the cache Workflow serves from. It runs
+// at startup, after every status change made through this instance, and every
+// CACHE_REFRESH_SEC from the cache refresh job, which is how other instances' changes arrive.
+func LoadWorkflows(db *gorm.DB) error {
+	var statuses []models.WorkflowStatus
+	if err := db.Order("position, name").Find(&statuses).Error; err != nil {
+		return err
+	}
+
+	defaults := []models.WorkflowStatus{}
+	departments := make(map[string][]models.WorkflowStatus)
+	for _, status := range statuses {
+		if status.DepartmentID == nil {
+			defaults = append(defaults, status)
+		} else {
+			departments[*status.DepartmentID] = append(departments[*status.DepartmentID], status)
+		}
+	}
+
+	workflowCache.Lock()
+	defer workflowCache.Unlock()
+	workflowCache.defaults = defaults
+	workflowCache.departments = departments
+	return nil
+}
+
+// Workflow returns the statuses departmentID's tasks may have, in board order: the
+// department's own when it has any, the default workflow otherwise
+func Workflow(departmentID *string) []models.WorkflowStatus {
+	workflowCache.RLock()
+	defer workflowCache.RUnlock()
+	if departmentID != nil {
+		if statuses, ok := workflowCache.departments[*departmentID]; ok {
+			return append([]models.WorkflowStatus{}, statuses...)
+		}
+	}
+	return append([]models.WorkflowStatus{}, workflowCache.defaults...)
+}
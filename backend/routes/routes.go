@@ -4,63 +4,175 @@
 package routes
 
 import (
+	"log"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/synapse/backend/config"
 	"github.com/synapse/backend/handlers"
 	"github.com/synapse/backend/middleware"
+	"github.com/synapse/backend/storage"
+	"github.com/synapse/backend/utils"
 	"gorm.io/gorm"
 )
 
 func SetupRoutes(router *gin.Engine, db *gorm.DB) {
+	// Get config for JWT secret and request timeout
+	cfg := config.GetConfig()
+
 	// Apply global middleware
 	router.Use(middleware.CORS())
 	router.Use(middleware.Logger())
+	router.Use(middleware.RequestTimeout(time.Duration(cfg.DBQueryTimeoutSec) * time.Second))
+	if cfg.CompressionEnabled {
+		router.Use(middleware.Compress(cfg.CompressionMinBytes))
+	}
+	if cfg.MetricsEnabled {
+		setupMetrics(router, db, cfg.MetricsToken)
+	}
 
-	// Get config for JWT secret
-	cfg := config.GetConfig()
+	// Access tokens are verified with the keys for JWT_ALG
+	jwtKeys, err := utils.LoadJWTKeys(cfg)
+	if err != nil {
+		log.Fatalf("failed to load JWT keys: %v", err)
+	}
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(db)
 	authHandler := handlers.NewAuthHandler(db)
 	taskHandler := handlers.NewTaskHandler(db)
 	userHandler := handlers.NewUserHandler(db)
+	avatarHandler := handlers.NewAvatarHandler(db, storage.NewLocal(cfg.UploadDir, cfg.PublicBaseURL))
 	departmentHandler := handlers.NewDepartmentHandler(db)
 	projectHandler := handlers.NewProjectHandler(db)
+	timeLogHandler := handlers.NewTimeLogHandler(db)
+	searchHandler := handlers.NewSearchHandler(db)
+	settingsHandler := handlers.NewSettingsHandler(db)
+	roleHandler := handlers.NewRoleHandler(db)
+	calendarHandler := handlers.NewCalendarHandler(db)
+	notificationHandler := handlers.NewNotificationHandler(db)
+	meHandler := handlers.NewMeHandler(db)
+	metaHandler := handlers.NewMetaHandler()
+	workflowStatusHandler := handlers.NewWorkflowStatusHandler(db)
+	customFieldHandler := handlers.NewCustomFieldHandler(db)
 
 	// Public routes
 	router.GET("/health", healthHandler.HealthCheck)
 
+	// Uploaded files are public so avatars load in <img> tags; keys are unguessable
+	router.Static(storage.URLPrefix, cfg.UploadDir)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Health check
 		v1.GET("/health", healthHandler.HealthCheck)
+		v1.GET("/version", healthHandler.Version)
+
+		// Public keys other services verify access tokens with
+		v1.GET("/.well-known/jwks.json", authHandler.JWKS)
 
 		// Authentication routes (public)
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/2fa/login", authHandler.LoginTwoFactor)
 			auth.POST("/refresh", authHandler.Refresh)
 			auth.POST("/logout", authHandler.Logout)
 		}
 
+		// Calendar feeds authenticate with a ?token= feed token, since calendar apps can't send headers
+		v1.GET("/users/:id/calendar.ics", middleware.ValidateIDParams(), calendarHandler.GetUserCalendar)
+
 		// Protected routes (require authentication)
 		authenticated := v1.Group("")
-		authenticated.Use(middleware.RequireAuth(cfg.JWTSecret))
+		authenticated.Use(middleware.RequireAuth(jwtKeys))
+		authenticated.Use(middleware.RequireActiveSession(db))
+		authenticated.Use(middleware.ValidateIDParams())
 		{
 			// Auth - get current user
 			authenticated.GET("/auth/me", authHandler.Me)
+			authenticated.POST("/auth/change-password", authHandler.ChangePassword)
+			authenticated.POST("/auth/2fa/enroll", authHandler.EnrollTwoFactor)
+			authenticated.POST("/auth/2fa/verify", authHandler.VerifyTwoFactor)
+			authenticated.GET("/auth/sessions", authHandler.ListSessions)
+			authenticated.DELETE("/auth/sessions", authHandler.RevokeOtherSessions)
+			authenticated.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
+			authenticated.GET("/auth/calendar-token", calendarHandler.GetFeedToken)
 
-			// Task routes
+			// Badge counts for the caller's own work
+			authenticated.GET("/me/counts", meHandler.GetCounts)
+
+			// Accepted enum values, for client dropdowns
+			authenticated.GET("/meta/enums", metaHandler.GetEnums)
+
+			// Global search
+			authenticated.GET("/search", searchHandler.Search)
+
+			// Task routes; handlers still check department, ownership and assignment
+			readTasks := middleware.RequirePermission("tasks.read")
+			createTasks := middleware.RequirePermission("tasks.create")
+			updateTasks := middleware.RequirePermission("tasks.update")
 			tasks := authenticated.Group("/tasks")
 			{
-				tasks.GET("", taskHandler.GetTasks)
-				tasks.POST("", taskHandler.CreateTask)
-				tasks.GET("/:id", taskHandler.GetTask)
-				tasks.PUT("/:id", taskHandler.UpdateTask)
-				tasks.PATCH("/:id/status", taskHandler.UpdateTaskStatus)
-				tasks.DELETE("/:id", taskHandler.DeleteTask)
+				tasks.GET("", readTasks, taskHandler.GetTasks)
+				tasks.POST("", createTasks, taskHandler.CreateTask)
+				tasks.POST("/import", createTasks, taskHandler.ImportTasks)
+				tasks.POST("/batch-get", readTasks, taskHandler.BatchGetTasks)
+				tasks.GET("/board", readTasks, taskHandler.GetTaskBoard)
+				tasks.GET("/suggest-due", readTasks, taskHandler.SuggestDueDate)
+				tasks.POST("/from-template/:templateId", createTasks, taskHandler.CreateTaskFromTemplate)
+				tasks.GET("/:id", readTasks, taskHandler.GetTask)
+				tasks.POST("/:id/duplicate", createTasks, taskHandler.DuplicateTask)
+				tasks.PUT("/:id", updateTasks, taskHandler.UpdateTask)
+				tasks.PATCH("/:id", updateTasks, taskHandler.PatchTask)
+				tasks.PATCH("/:id/status", updateTasks, taskHandler.UpdateTaskStatus)
+				tasks.PATCH("/:id/rank", updateTasks, taskHandler.UpdateTaskRank)
+				tasks.GET("/:id/assignees", readTasks, taskHandler.GetTaskAssignees)
+				tasks.POST("/:id/assignees", updateTasks, taskHandler.AddTaskAssignee)
+				tasks.DELETE("/:id/assignees/:userId", updateTasks, taskHandler.RemoveTaskAssignee)
+				tasks.DELETE("/bulk", middleware.RequirePermission("tasks.delete"), taskHandler.BulkDeleteTasks)
+				tasks.DELETE("/:id", middleware.RequirePermission("tasks.delete"), taskHandler.DeleteTask)
+				tasks.GET("/:id/checklist", readTasks, taskHandler.GetChecklist)
+				tasks.POST("/:id/checklist", updateTasks, taskHandler.AddChecklistItem)
+				tasks.PUT("/:id/checklist/:itemId", updateTasks, taskHandler.UpdateChecklistItem)
+				tasks.PATCH("/:id/checklist/:itemId/toggle", updateTasks, taskHandler.ToggleChecklistItem)
+				tasks.PATCH("/:id/checklist/:itemId/position", updateTasks, taskHandler.MoveChecklistItem)
+				tasks.DELETE("/:id/checklist/:itemId", updateTasks, taskHandler.DeleteChecklistItem)
+				tasks.GET("/:id/comments", readTasks, taskHandler.GetTaskComments)
+				tasks.POST("/:id/comments", readTasks, taskHandler.CreateTaskComment)
+				tasks.GET("/:id/time", readTasks, timeLogHandler.GetTaskTime)
+				tasks.POST("/:id/time", updateTasks, timeLogHandler.LogTaskTime)
+				tasks.PUT("/:id/time/:logId", updateTasks, timeLogHandler.UpdateTimeLog)
+				tasks.DELETE("/:id/time/:logId", updateTasks, timeLogHandler.DeleteTimeLog)
+			}
+
+			// Comment routes; handlers check the comment's task is visible
+			comments := authenticated.Group("/comments")
+			{
+				comments.PUT("/:id", readTasks, taskHandler.UpdateComment)
+				comments.DELETE("/:id", readTasks, taskHandler.DeleteComment)
+				comments.GET("/:id/revisions", readTasks, taskHandler.GetCommentRevisions)
+				comments.POST("/:id/reactions", readTasks, taskHandler.AddCommentReaction)
+				comments.DELETE("/:id/reactions", readTasks, taskHandler.RemoveCommentReaction)
+			}
+
+			// Notification routes (always the caller's own)
+			notifications := authenticated.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.GetNotifications)
+				notifications.POST("/read-all", notificationHandler.MarkAllNotificationsRead)
+				notifications.PATCH("/:id/read", notificationHandler.MarkNotificationRead)
+			}
+
+			// Task template routes
+			templates := authenticated.Group("/task-templates")
+			{
+				templates.GET("", readTasks, taskHandler.GetTaskTemplates)
+				templates.POST("", createTasks, taskHandler.CreateTaskTemplate)
 			}
 
 			// User routes
@@ -69,21 +181,57 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 				users.GET("", userHandler.GetUsers)
 				users.GET("/:id", userHandler.GetUser)
 				users.PUT("/:id", userHandler.UpdateUser)
+				users.POST("/:id/avatar", avatarHandler.UploadAvatar)
 				users.GET("/:id/tasks", userHandler.GetUserTasks)
+				users.GET("/:id/time", timeLogHandler.GetUserTime)
+			}
+
+			// Role routes (changes are Admin only)
+			roles := authenticated.Group("/roles")
+			{
+				roles.GET("", roleHandler.GetRoles)
+				roles.POST("", middleware.RequirePermission("roles.create"), roleHandler.CreateRole)
+				roles.PUT("/:name", middleware.RequirePermission("roles.update"), roleHandler.UpdateRole)
+				roles.DELETE("/:name", middleware.RequirePermission("roles.delete"), roleHandler.DeleteRole)
 			}
 
 			// Department routes
 			departments := authenticated.Group("/departments")
 			{
 				departments.GET("", departmentHandler.GetDepartments)
-				departments.POST("", middleware.RequireRole("Admin"), departmentHandler.CreateDepartment)
+				departments.POST("", middleware.RequirePermission("departments.create"), departmentHandler.CreateDepartment)
 				departments.GET("/:id", departmentHandler.GetDepartment)
-				departments.PUT("/:id", middleware.RequireRole("Admin"), departmentHandler.UpdateDepartment)
-				departments.DELETE("/:id", middleware.RequireRole("Admin"), departmentHandler.DeleteDepartment)
+				departments.PUT("/:id", middleware.RequirePermission("departments.update"), departmentHandler.UpdateDepartment)
+				departments.DELETE("/:id", middleware.RequirePermission("departments.delete"), departmentHandler.DeleteDepartment)
 				departments.GET("/:id/users", departmentHandler.GetDepartmentUsers)
 				departments.GET("/:id/tasks", departmentHandler.GetDepartmentTasks)
 			}
 
+			// Settings routes (Admin only)
+			settings := authenticated.Group("/settings")
+			{
+				settings.GET("", middleware.RequirePermission("settings.read"), settingsHandler.GetSettings)
+				settings.PUT("/:key", middleware.RequirePermission("settings.update"), settingsHandler.UpdateSetting)
+			}
+
+			// Task workflow statuses (changes are Admin only)
+			workflowStatuses := authenticated.Group("/workflow-statuses")
+			{
+				workflowStatuses.GET("", workflowStatusHandler.GetWorkflowStatuses)
+				workflowStatuses.POST("", middleware.RequirePermission("settings.update"), workflowStatusHandler.CreateWorkflowStatus)
+				workflowStatuses.PUT("/:id", middleware.RequirePermission("settings.update"), workflowStatusHandler.UpdateWorkflowStatus)
+				workflowStatuses.DELETE("/:id", middleware.RequirePermission("settings.update"), workflowStatusHandler.DeleteWorkflowStatus)
+			}
+
+			// Custom task field definitions the task form renders (changes are Admin only)
+			customFields := authenticated.Group("/custom-fields")
+			{
+				customFields.GET("", customFieldHandler.GetCustomFields)
+				customFields.POST("", middleware.RequirePermission("settings.update"), customFieldHandler.CreateCustomField)
+				customFields.PUT("/:id", middleware.RequirePermission("settings.update"), customFieldHandler.UpdateCustomField)
+				customFields.DELETE("/:id", middleware.RequirePermission("settings.update"), customFieldHandler.DeleteCustomField)
+			}
+
 			// Project routes
 			projects := authenticated.Group("/projects")
 			{
@@ -91,9 +239,38 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 				projects.POST("", projectHandler.CreateProject)
 				projects.GET("/:id", projectHandler.GetProject)
 				projects.PUT("/:id", projectHandler.UpdateProject)
+				projects.PATCH("/bulk/status", projectHandler.BulkUpdateProjectStatus)
+				projects.PATCH("/:id", projectHandler.PatchProject)
 				projects.DELETE("/:id", projectHandler.DeleteProject)
+				projects.POST("/:id/clone", projectHandler.CloneProject)
+				projects.POST("/:id/move-tasks", projectHandler.MoveProjectTasks)
 				projects.GET("/:id/tasks", projectHandler.GetProjectTasks)
+				projects.GET("/:id/members", projectHandler.GetProjectMembers)
+				projects.POST("/:id/members", projectHandler.AddProjectMember)
+				projects.DELETE("/:id/members/:userId", projectHandler.RemoveProjectMember)
 			}
 		}
 	}
 }
+
+// setupMetrics instruments every route registered after it and serves them on /metrics.
+// Each router gets its own registry so repeated setups never collide on registration.
+func setupMetrics(router *gin.Engine, db *gorm.DB, token string) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if db != nil {
+		// Connection pool gauges (open, in use, idle, waits) from sql.DB.Stats()
+		if sqlDB, err := db.DB(); err != nil {
+			log.Printf("database pool metrics unavailable: %v", err)
+		} else {
+			registry.MustRegister(collectors.NewDBStatsCollector(sqlDB, "synapse"))
+		}
+	}
+
+	metrics := middleware.NewMetrics(registry)
+	router.Use(metrics.Middleware())
+	router.GET("/metrics", metrics.Handler(token))
+}
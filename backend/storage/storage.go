@@ -0,0 +1,122 @@
+// ABOUTME: Pluggable object storage for uploaded files such as avatars and task attachments
+// ABOUTME: Local keeps objects on disk and serves them under /uploads
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// URLPrefix is the path Local objects are served under
+const URLPrefix = "/uploads/"
+
+var errInvalidKey = errors.New("invalid storage key")
+
+// IsUploadURL reports whether rawURL points into the uploads served under URLPrefix, either
+// root-relative or on baseURL's host. Clients can't set such URLs themselves, since they
+// could otherwise claim another user's upload.
+func IsUploadURL(rawURL, baseURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	if parsed.Host != "" {
+		base, err := url.Parse(baseURL)
+		if err != nil || !strings.EqualFold(base.Host, parsed.Host) {
+			return false
+		}
+	}
+	return strings.HasPrefix(path.Clean("/"+parsed.Path)+"/", URLPrefix)
+}
+
+// Store keeps uploaded objects addressed by slash-separated keys like avatars/<id>.png
+type Store interface {
+	// Put writes body under key, replacing any object already there, and returns the URL
+	// clients fetch it from
+	Put(ctx context.Context, key, contentType string, body io.Reader) (string, error)
+	// Delete removes the object at key; a missing object isn't an error
+	Delete(ctx context.Context, key string) error
+	// KeyFromURL returns the key of the object this store serves at url, and false for URLs
+	// it didn't hand out
+	KeyFromURL(url string) (string, bool)
+}
+
+// Local stores objects as files under Dir. BaseURL is prefixed to the URLs it returns;
+// empty leaves them root-relative.
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocal returns a Local store rooted at dir
+func NewLocal(dir, baseURL string) *Local {
+	return &Local{Dir: dir, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *Local) Put(ctx context.Context, key, contentType string, body io.Reader) (string, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return "", err
+	}
+
+	// Write beside the target and rename, so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return "", err
+	}
+	return s.BaseURL + URLPrefix + key, nil
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Local) KeyFromURL(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.BaseURL+URLPrefix)
+	if !ok {
+		return "", false
+	}
+	if _, err := s.path(key); err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// path maps key to a file under Dir, rejecting keys that would escape it
+func (s *Local) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", errInvalidKey
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,85 @@
+// ABOUTME: Tests for the per-task assignee limit and the Admin setting that controls it
+// ABOUTME: Verifies requests over the limit are rejected and requests at the limit succeed
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// setAssigneeLimit overrides max_task_assignees to 3 through the settings endpoint
+func setAssigneeLimit(t *testing.T, db *gorm.DB, admin *models.User) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/settings/:key", asUser(admin), handlers.NewSettingsHandler(db).UpdateSetting)
+
+	w := performJSON(router, "PUT", "/settings/max_task_assignees", map[string]int{"value": 3})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+// createTaskWithAssignees posts a task assigned to count new users as creator
+func createTaskWithAssignees(t *testing.T, db *gorm.DB, creator *models.User, count int) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ids := []string{}
+	for i := 0; i < count; i++ {
+		ids = append(ids, createTestUser(t, db, "Member", nil).ID)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/tasks", asUser(creator), handlers.NewTaskHandler(db).CreateTask)
+
+	return performJSON(router, "POST", "/tasks", map[string]interface{}{
+		"title":        "Assigned task " + nextFixtureID(),
+		"assignee_ids": ids,
+	})
+}
+
+func TestCreateTask_RejectsTooManyAssignees(t *testing.T) {
+	db := setupTestDB(t)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	setAssigneeLimit(t, db, admin)
+
+	w := createTaskWithAssignees(t, db, admin, 4)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "at most 3 assignees")
+}
+
+func TestCreateTask_AllowsAssigneesAtLimit(t *testing.T) {
+	db := setupTestDB(t)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	setAssigneeLimit(t, db, admin)
+
+	w := createTaskWithAssignees(t, db, admin, 3)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+func TestUpdateSetting_ValidatesKeyAndRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.PUT("/settings/:key", withTestUser("admin-1", "Admin", nil), handlers.NewSettingsHandler(nil).UpdateSetting)
+
+	w := performJSON(router, "PUT", "/settings/unknown_setting", map[string]int{"value": 5})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performJSON(router, "PUT", "/settings/max_task_assignees", map[string]int{"value": 0})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = performJSON(router, "PUT", "/settings/max_task_assignees", map[string]interface{}{})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
@@ -0,0 +1,70 @@
+// ABOUTME: Tests for notifications sent when a task's assignees change
+// ABOUTME: Verifies added users hear "assigned", removed users "unassigned" and kept users nothing
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// notificationTypes returns the types of the notifications a user has received
+func notificationTypes(t *testing.T, db *gorm.DB, userID string) []string {
+	t.Helper()
+
+	var types []string
+	require.NoError(t, db.Model(&models.Notification{}).Where("user_id = ?", userID).Pluck("type", &types).Error)
+	return types
+}
+
+func TestUpdateTask_AssigneeChangesNotifyAddedAndRemoved(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	a := createTestUser(t, db, "Member", &dept.ID)
+	b := createTestUser(t, db, "Member", &dept.ID)
+	c := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID})
+	for _, user := range []*models.User{a, b} {
+		require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, user.ID).Error)
+	}
+
+	router := gin.New()
+	router.PUT("/tasks/:id", asUser(admin), handlers.NewTaskHandler(db).UpdateTask)
+
+	w := sendWithIfMatch(router, "PUT", "/tasks/"+task.ID, `{"assignee_ids": ["`+b.ID+`", "`+c.ID+`"]}`, "*")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, []string{"unassigned"}, notificationTypes(t, db, a.ID))
+	assert.Empty(t, notificationTypes(t, db, b.ID))
+	assert.Equal(t, []string{"assigned"}, notificationTypes(t, db, c.ID))
+	assert.Empty(t, notificationTypes(t, db, admin.ID))
+}
+
+func TestPatchTask_AssigneeChangesSkipActor(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: manager.ID, DepartmentID: &dept.ID})
+
+	router := gin.New()
+	router.PATCH("/tasks/:id", asUser(manager), handlers.NewTaskHandler(db).PatchTask)
+
+	w := performJSON(router, "PATCH", "/tasks/"+task.ID, `{"assignee_ids": ["`+manager.ID+`", "`+member.ID+`"]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, []string{"assigned"}, notificationTypes(t, db, member.ID))
+	assert.Empty(t, notificationTypes(t, db, manager.ID))
+}
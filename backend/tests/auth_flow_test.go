@@ -0,0 +1,60 @@
+// ABOUTME: End-to-end auth flow test against the migrated test database
+// ABOUTME: Registers a user, logs in with the new credentials and fetches the current user
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/middleware"
+	"github.com/synapse/backend/utils"
+)
+
+func TestAuthFlow_RegisterThenLogin(t *testing.T) {
+	db := setupTestDB(t)
+	seed := seedTestData(t, db)
+	t.Setenv("JWT_SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+
+	authHandler := handlers.NewAuthHandler(db)
+	router := gin.New()
+	router.POST("/auth/register", authHandler.Register)
+	router.POST("/auth/login", authHandler.Login)
+	router.GET("/auth/me", middleware.RequireAuth(utils.HMACKeys("test-secret")), authHandler.Me)
+
+	email := "flow-" + nextFixtureID() + "@example.com"
+	w := performJSON(router, "POST", "/auth/register", map[string]interface{}{
+		"email":         email,
+		"password":      "correct horse battery",
+		"full_name":     "Flow User",
+		"department_id": seed.Department.ID,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	registered := decodeResponse(t, w)["data"].(map[string]interface{})["user"].(map[string]interface{})
+	assert.Equal(t, "Member", registered["role"])
+
+	w = performJSON(router, "POST", "/auth/login", map[string]string{"email": email, "password": "correct horse battery"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, registered["id"], data["user"].(map[string]interface{})["id"])
+	accessToken := data["access_token"].(string)
+	require.NotEmpty(t, accessToken)
+
+	req, _ := http.NewRequest("GET", "/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	me := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, email, me["email"])
+	assert.Equal(t, seed.Department.ID, me["department_id"])
+
+	w = performJSON(router, "POST", "/auth/login", map[string]string{"email": email, "password": "wrong horse battery"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
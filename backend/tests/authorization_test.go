@@ -0,0 +1,159 @@
+// ABOUTME: Unit tests for the centralized authorization rules in the auth package
+// ABOUTME: Walks each role through every action on tasks, projects, users and templates
+
+package tests
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/models"
+)
+
+var authRoles = []string{auth.RoleAdmin, auth.RoleManager, auth.RoleMember, auth.RoleViewer}
+
+// authCase expects one allowed/denied answer per role, in authRoles order
+type authCase struct {
+	name    string
+	check   func(p auth.Principal) bool
+	allowed [4]bool
+}
+
+func runAuthMatrix(t *testing.T, cases []authCase) {
+	t.Helper()
+	for _, tc := range cases {
+		for i, role := range authRoles {
+			p := auth.Principal{ID: "caller", Role: role, DepartmentID: strPtr("dept-a")}
+			assert.Equal(t, tc.allowed[i], tc.check(p), "%s as %s", tc.name, role)
+		}
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestAuthMatrix_Tasks(t *testing.T) {
+	ownDept := models.Task{CreatorID: "someone", DepartmentID: strPtr("dept-a")}
+	otherDept := models.Task{CreatorID: "someone", DepartmentID: strPtr("dept-b")}
+	createdElsewhere := models.Task{CreatorID: "caller", DepartmentID: strPtr("dept-b")}
+	assignedElsewhere := models.Task{CreatorID: "someone", DepartmentID: strPtr("dept-b"), Assignees: []string{"caller"}}
+
+	runAuthMatrix(t, []authCase{
+		{"create in own department", func(p auth.Principal) bool { return auth.CanCreateTask(p, strPtr("dept-a")) }, [4]bool{true, true, true, false}},
+		{"create in other department", func(p auth.Principal) bool { return auth.CanCreateTask(p, strPtr("dept-b")) }, [4]bool{true, false, false, false}},
+		{"access own department", func(p auth.Principal) bool { return auth.CanAccessTask(p, ownDept) }, [4]bool{true, true, true, true}},
+		{"access other department", func(p auth.Principal) bool { return auth.CanAccessTask(p, otherDept) }, [4]bool{true, false, false, false}},
+		{"access created elsewhere", func(p auth.Principal) bool { return auth.CanAccessTask(p, createdElsewhere) }, [4]bool{true, true, true, true}},
+		{"access assigned elsewhere", func(p auth.Principal) bool { return auth.CanAccessTask(p, assignedElsewhere) }, [4]bool{true, true, true, true}},
+		{"modify own department", func(p auth.Principal) bool { return auth.CanModifyTask(p, ownDept) }, [4]bool{true, true, false, false}},
+		{"modify other department", func(p auth.Principal) bool { return auth.CanModifyTask(p, otherDept) }, [4]bool{true, false, false, false}},
+		{"modify created elsewhere", func(p auth.Principal) bool { return auth.CanModifyTask(p, createdElsewhere) }, [4]bool{true, false, true, false}},
+		{"delete own department", func(p auth.Principal) bool { return auth.CanDeleteTask(p, ownDept) }, [4]bool{true, false, false, false}},
+		{"delete created elsewhere", func(p auth.Principal) bool { return auth.CanDeleteTask(p, createdElsewhere) }, [4]bool{true, true, true, false}},
+		{"reassign creator", auth.CanReassignTaskCreator, [4]bool{true, false, false, false}},
+		{"log time in own department", func(p auth.Principal) bool { return auth.CanLogTime(p, ownDept) }, [4]bool{true, true, false, false}},
+		{"log time when assigned", func(p auth.Principal) bool { return auth.CanLogTime(p, assignedElsewhere) }, [4]bool{true, true, true, false}},
+		{"edit own time log", func(p auth.Principal) bool { return auth.CanModifyTimeLog(p, models.TimeLog{UserID: "caller"}) }, [4]bool{true, true, true, true}},
+		{"edit another's time log", func(p auth.Principal) bool { return auth.CanModifyTimeLog(p, models.TimeLog{UserID: "someone"}) }, [4]bool{false, false, false, false}},
+	})
+}
+
+func TestAuthMatrix_Projects(t *testing.T) {
+	ownDept := models.Project{DepartmentID: strPtr("dept-a")}
+	otherDept := models.Project{DepartmentID: strPtr("dept-b")}
+	noDept := models.Project{}
+
+	runAuthMatrix(t, []authCase{
+		{"create in own department", func(p auth.Principal) bool { return auth.CanCreateProject(p, strPtr("dept-a")) }, [4]bool{true, true, false, false}},
+		{"create in other department", func(p auth.Principal) bool { return auth.CanCreateProject(p, strPtr("dept-b")) }, [4]bool{true, false, false, false}},
+		{"access own department", func(p auth.Principal) bool { return auth.CanAccessProject(p, ownDept, "") }, [4]bool{true, true, true, true}},
+		{"access other department", func(p auth.Principal) bool { return auth.CanAccessProject(p, otherDept, "") }, [4]bool{true, false, false, false}},
+		{"access other department as member", func(p auth.Principal) bool { return auth.CanAccessProject(p, otherDept, "Contributor") }, [4]bool{true, true, true, true}},
+		{"access without department", func(p auth.Principal) bool { return auth.CanAccessProject(p, noDept, "") }, [4]bool{true, false, false, false}},
+		{"modify own department", func(p auth.Principal) bool { return auth.CanModifyProject(p, ownDept) }, [4]bool{true, true, false, false}},
+		{"modify other department", func(p auth.Principal) bool { return auth.CanModifyProject(p, otherDept) }, [4]bool{true, false, false, false}},
+		{"modify without department", func(p auth.Principal) bool { return auth.CanModifyProject(p, noDept) }, [4]bool{true, false, false, false}},
+		{"delete own department", func(p auth.Principal) bool { return auth.CanDeleteProject(p, ownDept) }, [4]bool{true, true, false, false}},
+		{"manage members of own department", func(p auth.Principal) bool { return auth.CanManageProjectMembers(p, ownDept, "") }, [4]bool{true, true, false, false}},
+		{"manage members as Lead", func(p auth.Principal) bool { return auth.CanManageProjectMembers(p, otherDept, auth.ProjectRoleLead) }, [4]bool{true, true, true, true}},
+		{"manage members as Contributor", func(p auth.Principal) bool { return auth.CanManageProjectMembers(p, otherDept, "Contributor") }, [4]bool{true, false, false, false}},
+		{"transfer within department", func(p auth.Principal) bool {
+			return auth.CanTransferProject(p, models.User{DepartmentID: strPtr("dept-a")})
+		}, [4]bool{true, true, false, false}},
+		{"transfer to other department", func(p auth.Principal) bool {
+			return auth.CanTransferProject(p, models.User{DepartmentID: strPtr("dept-b")})
+		}, [4]bool{true, false, false, false}},
+	})
+}
+
+func TestAuthMatrix_Users(t *testing.T) {
+	self := models.User{ID: "caller", DepartmentID: strPtr("dept-b")}
+	colleague := models.User{ID: "colleague", DepartmentID: strPtr("dept-a")}
+	stranger := models.User{ID: "stranger", DepartmentID: strPtr("dept-b")}
+
+	runAuthMatrix(t, []authCase{
+		{"access self", func(p auth.Principal) bool { return auth.CanAccessUser(p, self) }, [4]bool{true, true, true, true}},
+		{"access colleague", func(p auth.Principal) bool { return auth.CanAccessUser(p, colleague) }, [4]bool{true, true, true, true}},
+		{"access stranger", func(p auth.Principal) bool { return auth.CanAccessUser(p, stranger) }, [4]bool{true, false, false, false}},
+		{"modify self", func(p auth.Principal) bool { return auth.CanModifyUser(p, self) }, [4]bool{true, true, true, true}},
+		{"modify colleague", func(p auth.Principal) bool { return auth.CanModifyUser(p, colleague) }, [4]bool{true, false, false, false}},
+		{"manage accounts", auth.CanManageUserAccount, [4]bool{true, false, false, false}},
+		{"view own work", func(p auth.Principal) bool { return auth.CanViewUserWork(p, self) }, [4]bool{true, true, true, true}},
+		{"view colleague's work", func(p auth.Principal) bool { return auth.CanViewUserWork(p, colleague) }, [4]bool{true, true, false, false}},
+		{"view stranger's work", func(p auth.Principal) bool { return auth.CanViewUserWork(p, stranger) }, [4]bool{true, false, false, false}},
+	})
+}
+
+func TestAuthMatrix_Templates(t *testing.T) {
+	runAuthMatrix(t, []authCase{
+		{"create global", func(p auth.Principal) bool { return auth.CanCreateTemplate(p, nil) }, [4]bool{true, false, false, false}},
+		{"create for own department", func(p auth.Principal) bool { return auth.CanCreateTemplate(p, strPtr("dept-a")) }, [4]bool{true, true, false, false}},
+		{"create for other department", func(p auth.Principal) bool { return auth.CanCreateTemplate(p, strPtr("dept-b")) }, [4]bool{true, false, false, false}},
+		{"use global", func(p auth.Principal) bool { return auth.CanAccessTemplate(p, models.TaskTemplate{}) }, [4]bool{true, true, true, true}},
+		{"use other department's", func(p auth.Principal) bool {
+			return auth.CanAccessTemplate(p, models.TaskTemplate{DepartmentID: strPtr("dept-b")})
+		}, [4]bool{true, false, false, false}},
+	})
+}
+
+func TestAuthMatrix_Permissions(t *testing.T) {
+	runAuthMatrix(t, []authCase{
+		{"tasks.read", func(p auth.Principal) bool { return auth.HasPermission(p.Role, "tasks.read") }, [4]bool{true, true, true, true}},
+		{"tasks.create", func(p auth.Principal) bool { return auth.HasPermission(p.Role, "tasks.create") }, [4]bool{true, true, true, false}},
+		{"tasks.delete", func(p auth.Principal) bool { return auth.HasPermission(p.Role, "tasks.delete") }, [4]bool{true, true, true, false}},
+		{"projects.create", func(p auth.Principal) bool { return auth.HasPermission(p.Role, "projects.create") }, [4]bool{true, true, false, false}},
+		{"departments.update", func(p auth.Principal) bool { return auth.HasPermission(p.Role, "departments.update") }, [4]bool{true, false, false, false}},
+		{"settings.update", func(p auth.Principal) bool { return auth.HasPermission(p.Role, "settings.update") }, [4]bool{true, false, false, false}},
+	})
+
+	// Unknown roles fall back to Viewer permissions
+	assert.Equal(t, auth.PermissionsForRole(auth.RoleViewer), auth.PermissionsForRole("Intern"))
+}
+
+func TestAuthPrincipal_DepartmentMatching(t *testing.T) {
+	// A caller without a department is never "in" a department, even a missing one
+	p := auth.Principal{ID: "caller", Role: auth.RoleMember}
+	assert.False(t, p.InDepartment(nil))
+	assert.False(t, auth.CanAccessTask(p, models.Task{CreatorID: "someone"}))
+
+	// ...but may still create department-less tasks, which is where their tasks default to
+	assert.True(t, auth.CanCreateTask(p, nil))
+	assert.False(t, auth.CanCreateTask(p, strPtr("dept-a")))
+}
+
+func TestAuthPrincipal_FromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// Nothing set: an empty principal with no department
+	assert.Equal(t, auth.Principal{}, auth.FromContext(c))
+
+	deptID := "dept-a"
+	withTestUser("user-1", auth.RoleManager, &deptID)(c)
+	p := auth.FromContext(c)
+	assert.Equal(t, "user-1", p.ID)
+	assert.True(t, p.IsManager())
+	assert.True(t, p.InDepartment(strPtr("dept-a")))
+}
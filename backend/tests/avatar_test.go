@@ -0,0 +1,85 @@
+// ABOUTME: Tests for default avatar URLs on users without a stored avatar
+// ABOUTME: Covers each avatar style, stored URLs winning, and the Me endpoint
+
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+// marshalledAvatar serializes user and returns its avatar_url, or nil when omitted
+func marshalledAvatar(t *testing.T, user models.User) interface{} {
+	t.Helper()
+	encoded, err := json.Marshal(user)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	return fields["avatar_url"]
+}
+
+// useAvatarStyle serializes users in style for the rest of the test
+func useAvatarStyle(t *testing.T, style string) {
+	t.Helper()
+	models.SetAvatarStyle(style)
+	t.Cleanup(func() { models.SetAvatarStyle(models.AvatarStyleGravatar) })
+}
+
+func TestDefaultAvatar_Styles(t *testing.T) {
+	user := models.User{Email: " Ada@Example.com", FullName: "Ada Lovelace", Username: "ada"}
+
+	gravatar := marshalledAvatar(t, user).(string)
+	assert.True(t, strings.HasPrefix(gravatar, "https://www.gravatar.com/avatar/"), gravatar)
+
+	// Derived from the normalized email, so the same user always gets the same URL
+	user.Email = "ada@example.com"
+	assert.Equal(t, gravatar, marshalledAvatar(t, user))
+
+	useAvatarStyle(t, models.AvatarStyleInitials)
+	initials := marshalledAvatar(t, user).(string)
+	assert.True(t, strings.HasPrefix(initials, "https://ui-avatars.com/api/?"), initials)
+	assert.Contains(t, initials, "name=Ada+Lovelace")
+
+	useAvatarStyle(t, models.AvatarStyleNone)
+	assert.Nil(t, marshalledAvatar(t, user))
+}
+
+func TestDefaultAvatar_StoredURLWins(t *testing.T) {
+	stored := "https://cdn.example.com/ada.png"
+	user := models.User{Email: "ada@example.com", AvatarURL: &stored}
+
+	assert.Equal(t, stored, marshalledAvatar(t, user))
+
+	// Preloaded associations like a task's creator are pointers and serialize the same way
+	encoded, err := json.Marshal(models.Task{Creator: &user})
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"avatar_url":"`+stored+`"`)
+}
+
+func TestMe_DerivesAvatarWithoutStoringIt(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	user := createTestUser(t, db, "Member", nil)
+	require.Nil(t, user.AvatarURL)
+
+	router := gin.New()
+	router.GET("/auth/me", asUser(user), handlers.NewAuthHandler(db).Me)
+
+	w := performJSON(router, "GET", "/auth/me", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.NotEmpty(t, data["avatar_url"])
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", user.ID).Error)
+	assert.Nil(t, stored.AvatarURL)
+}
@@ -0,0 +1,167 @@
+// ABOUTME: Tests for uploading avatar images to the object store
+// ABOUTME: Verifies avatar_url updates, old uploads are replaced and non-images are rejected
+
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/storage"
+)
+
+// uploadAvatar posts content as the multipart avatar field
+func uploadAvatar(t *testing.T, router http.Handler, userID string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("avatar", "avatar.png")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest("POST", "/users/"+userID+"/avatar", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUploadAvatar_StoresImageAndReplacesPrevious(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	user := createTestUser(t, db, "Member", &dept.ID)
+	other := createTestUser(t, db, "Member", &dept.ID)
+
+	dir := t.TempDir()
+	h := handlers.NewAvatarHandler(db, storage.NewLocal(dir, ""))
+	router := gin.New()
+	router.POST("/users/:id/avatar", asUser(user), h.UploadAvatar)
+
+	var image1 bytes.Buffer
+	require.NoError(t, png.Encode(&image1, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+
+	w := uploadAvatar(t, router, user.ID, image1.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	first := decodeResponse(t, w)["data"].(map[string]interface{})["avatar_url"].(string)
+	assert.True(t, strings.HasPrefix(first, "/uploads/avatars/"+user.ID+"-"), first)
+	assert.True(t, strings.HasSuffix(first, ".png"), first)
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", user.ID).Error)
+	require.NotNil(t, stored.AvatarURL)
+	assert.Equal(t, first, *stored.AvatarURL)
+	firstFile := filepath.Join(dir, strings.TrimPrefix(first, storage.URLPrefix))
+	saved, err := os.ReadFile(firstFile)
+	require.NoError(t, err)
+	assert.Equal(t, image1.Bytes(), saved)
+
+	// Re-uploading swaps in a new object and removes the old one
+	w = uploadAvatar(t, router, user.ID, image1.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	second := decodeResponse(t, w)["data"].(map[string]interface{})["avatar_url"].(string)
+	assert.NotEqual(t, first, second)
+	assert.NoFileExists(t, firstFile)
+	assert.FileExists(t, filepath.Join(dir, strings.TrimPrefix(second, storage.URLPrefix)))
+
+	w = uploadAvatar(t, router, user.ID, []byte("not an image, just some text"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = uploadAvatar(t, router, other.ID, image1.Bytes())
+	assert.Equal(t, http.StatusForbidden, w.Code, "members can only change their own avatar")
+}
+
+func TestUploadAvatar_LeavesOtherUsersUploads(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	victim := createTestUser(t, db, "Member", &dept.ID)
+	attacker := createTestUser(t, db, "Member", &dept.ID)
+
+	dir := t.TempDir()
+	h := handlers.NewAvatarHandler(db, storage.NewLocal(dir, ""))
+	routerFor := func(caller *models.User) *gin.Engine {
+		router := gin.New()
+		router.POST("/users/:id/avatar", asUser(caller), h.UploadAvatar)
+		return router
+	}
+
+	var image1 bytes.Buffer
+	require.NoError(t, png.Encode(&image1, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	w := uploadAvatar(t, routerFor(victim), victim.ID, image1.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	victimURL := decodeResponse(t, w)["data"].(map[string]interface{})["avatar_url"].(string)
+
+	// The profile endpoint won't hand out another user's upload
+	w = performJSON(setupUserUpdateRouter(db, attacker), "PUT", "/users/"+attacker.ID, map[string]interface{}{"avatar_url": victimURL})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, []string{"avatar_url"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	// Even if it had, replacing it only deletes the caller's own uploads
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", attacker.ID).Update("avatar_url", victimURL).Error)
+	w = uploadAvatar(t, routerFor(attacker), attacker.ID, image1.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.FileExists(t, filepath.Join(dir, strings.TrimPrefix(victimURL, storage.URLPrefix)))
+}
+
+func TestIsUploadURL(t *testing.T) {
+	base := "https://api.example.com"
+	for url, want := range map[string]bool{
+		"/uploads/avatars/a.png":                        true,
+		"https://api.example.com/uploads/avatars/a.png": true,
+		"https://API.example.com/x/../uploads/a.png":    true,
+		"https://cdn.example.net/uploads/avatars/a.png": false,
+		"https://www.gravatar.com/avatar/abc":           false,
+		"/avatars/a.png":                                false,
+		"":                                              false,
+	} {
+		assert.Equal(t, want, storage.IsUploadURL(url, base), url)
+	}
+}
+
+func TestLocalStorage_PutDeleteAndKeys(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocal(dir, "https://api.example.com/")
+	ctx := t.Context()
+
+	url, err := store.Put(ctx, "avatars/a.png", "image/png", strings.NewReader("first"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/uploads/avatars/a.png", url)
+	_, err = store.Put(ctx, "avatars/a.png", "image/png", strings.NewReader("second"))
+	require.NoError(t, err)
+	saved, err := os.ReadFile(filepath.Join(dir, "avatars", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(saved))
+
+	key, ok := store.KeyFromURL(url)
+	assert.True(t, ok)
+	assert.Equal(t, "avatars/a.png", key)
+	_, ok = store.KeyFromURL("https://www.gravatar.com/avatar/abc")
+	assert.False(t, ok)
+	_, ok = store.KeyFromURL("https://api.example.com/uploads/../secrets")
+	assert.False(t, ok)
+
+	require.NoError(t, store.Delete(ctx, key))
+	assert.NoFileExists(t, filepath.Join(dir, "avatars", "a.png"))
+	assert.NoError(t, store.Delete(ctx, key), "deleting a missing object is fine")
+
+	_, err = store.Put(ctx, "../outside.png", "image/png", strings.NewReader("x"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+// ABOUTME: Tests for the iCalendar task feed and the feed tokens that authenticate it
+// ABOUTME: Checks the ICS structure, that undated tasks are left out, and the access rules
+
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+const calendarTestSecret = "calendar-test-secret-at-least-32-characters"
+
+func setupCalendarRouter(t *testing.T, db *gorm.DB) *gin.Engine {
+	t.Helper()
+	t.Setenv("JWT_SECRET", calendarTestSecret)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:id/calendar.ics", handlers.NewCalendarHandler(db).GetUserCalendar)
+	return router
+}
+
+func feedToken(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := utils.GenerateFeedToken(userID, calendarTestSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestFeedToken_RoundTrip(t *testing.T) {
+	token := feedToken(t, "user-1")
+
+	userID, err := utils.ValidateFeedToken(token, calendarTestSecret)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+
+	// Swapping in another user's id breaks the signature
+	_, signature, _ := strings.Cut(token, ".")
+	_, err = utils.ValidateFeedToken("user-2."+signature, calendarTestSecret)
+	assert.Error(t, err)
+
+	_, err = utils.ValidateFeedToken(token, "a-different-secret-of-at-least-32-chars")
+	assert.Error(t, err)
+	_, err = utils.ValidateFeedToken("no-signature", calendarTestSecret)
+	assert.Error(t, err)
+
+	// Feed URLs keep working while the secret they were signed with is listed as previous
+	rotated := "a-different-secret-of-at-least-32-chars"
+	userID, err = utils.ValidateFeedToken(token, rotated, calendarTestSecret)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+}
+
+func TestUserCalendar_InvalidToken(t *testing.T) {
+	router := setupCalendarRouter(t, nil)
+
+	w := performJSON(router, "GET", "/users/00000000-0000-0000-0000-000000000001/calendar.ics?token=forged.signature", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUserCalendar_Feed(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupCalendarRouter(t, db)
+
+	dept := createTestDepartment(t, db)
+	user := createTestUser(t, db, "Member", &dept.ID)
+	colleague := createTestUser(t, db, "Member", &dept.ID)
+
+	due := time.Date(2030, 3, 14, 17, 30, 0, 0, time.UTC)
+	description := "Numbers for Q1, the board; and notes"
+	created := createTestTask(t, db, models.Task{Title: "File quarterly report", Description: &description, DueDate: &due, CreatorID: user.ID, DepartmentID: &dept.ID})
+	assigned := createTestTask(t, db, models.Task{Title: "Review budget", DueDate: &due, CreatorID: colleague.ID, DepartmentID: &dept.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", assigned.ID, user.ID).Error)
+	createTestTask(t, db, models.Task{Title: "Someday maybe", CreatorID: user.ID, DepartmentID: &dept.ID})
+	createTestTask(t, db, models.Task{Title: "Someone else's deadline", DueDate: &due, CreatorID: colleague.ID, DepartmentID: &dept.ID})
+
+	w := performJSON(router, "GET", "/users/"+user.ID+"/calendar.ics?token="+feedToken(t, user.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT\r\n"))
+	assert.Equal(t, 2, strings.Count(body, "END:VEVENT\r\n"))
+	assert.Contains(t, body, "UID:task-"+created.ID+"@synapse\r\n")
+	assert.Contains(t, body, "UID:task-"+assigned.ID+"@synapse\r\n")
+	assert.Contains(t, body, "DTSTART:20300314T173000Z\r\n")
+	assert.Contains(t, body, "SUMMARY:File quarterly report\r\n")
+	assert.Contains(t, body, `DESCRIPTION:Numbers for Q1\, the board\; and notes`+"\r\n")
+	assert.NotContains(t, body, "Someday maybe")
+	assert.NotContains(t, body, "Someone else's deadline")
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+}
+
+func TestUserCalendar_AccessRules(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupCalendarRouter(t, db)
+
+	dept := createTestDepartment(t, db)
+	user := createTestUser(t, db, "Member", &dept.ID)
+	colleague := createTestUser(t, db, "Member", &dept.ID)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+
+	// Members can't read a colleague's work; their Manager can
+	w := performJSON(router, "GET", "/users/"+user.ID+"/calendar.ics?token="+feedToken(t, colleague.ID), nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = performJSON(router, "GET", "/users/"+user.ID+"/calendar.ics?token="+feedToken(t, manager.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Deactivated accounts lose their feeds
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", manager.ID).Update("is_active", false).Error)
+	w = performJSON(router, "GET", "/users/"+user.ID+"/calendar.ics?token="+feedToken(t, manager.ID), nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
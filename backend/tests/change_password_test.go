@@ -0,0 +1,118 @@
+// ABOUTME: Tests for the authenticated change-password endpoint
+// ABOUTME: Verifies the current password is required, changes take effect and recent passwords aren't reused
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func setupChangePasswordRouter(auth gin.HandlerFunc, db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/auth/change-password", auth, handlers.NewAuthHandler(db).ChangePassword)
+	return router
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "correct horse battery", bcrypt.MinCost)
+	router := setupChangePasswordRouter(asUser(user), db)
+
+	w := performJSON(router, "POST", "/auth/change-password", map[string]string{
+		"current_password": "wrong horse battery",
+		"new_password":     "another horse battery",
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+
+	var stored models.User
+	require.NoError(t, db.Select("*").First(&stored, "id = ?", user.ID).Error)
+	assert.NoError(t, utils.VerifyPassword(*stored.PasswordHash, "correct horse battery"))
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "correct horse battery", bcrypt.MinCost)
+	router := setupChangePasswordRouter(asUser(user), db)
+
+	w := performJSON(router, "POST", "/auth/change-password", map[string]string{
+		"current_password": "correct horse battery",
+		"new_password":     "another horse battery",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored models.User
+	require.NoError(t, db.Select("*").First(&stored, "id = ?", user.ID).Error)
+	assert.NoError(t, utils.VerifyPassword(*stored.PasswordHash, "another horse battery"))
+	assert.Error(t, utils.VerifyPassword(*stored.PasswordHash, "correct horse battery"))
+}
+
+func TestChangePassword_RejectsUnchangedOrWeakPassword(t *testing.T) {
+	router := setupChangePasswordRouter(withTestUser("user-1", "Member", nil), nil)
+
+	w := performJSON(router, "POST", "/auth/change-password", map[string]string{
+		"current_password": "correct horse battery",
+		"new_password":     "correct horse battery",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = performJSON(router, "POST", "/auth/change-password", map[string]string{
+		"current_password": "correct horse battery",
+		"new_password":     "short",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+// changePassword changes the caller's password from current to next
+func changePassword(router *gin.Engine, current, next string) *httptest.ResponseRecorder {
+	return performJSON(router, "POST", "/auth/change-password", map[string]string{
+		"current_password": current,
+		"new_password":     next,
+	})
+}
+
+func TestChangePassword_RejectsRecentPassword(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("PASSWORD_HISTORY_SIZE", "3")
+	t.Setenv("BCRYPT_COST", strconv.Itoa(bcrypt.MinCost))
+
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "first horse battery", bcrypt.MinCost)
+	router := setupChangePasswordRouter(asUser(user), db)
+
+	require.Equal(t, http.StatusOK, changePassword(router, "first horse battery", "second horse battery").Code)
+	require.Equal(t, http.StatusOK, changePassword(router, "second horse battery", "third horse battery").Code)
+
+	// Both earlier passwords are among the last three
+	for _, previous := range []string{"first horse battery", "second horse battery"} {
+		w := changePassword(router, "third horse battery", previous)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Equal(t, "PASSWORD_REUSED", errorCode(t, decodeResponse(t, w)))
+	}
+
+	// A fresh password is accepted and pushes the oldest out of the history
+	require.Equal(t, http.StatusOK, changePassword(router, "third horse battery", "fourth horse battery").Code)
+	var count int64
+	require.NoError(t, db.Model(&models.PasswordHistory{}).Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	w := changePassword(router, "fourth horse battery", "first horse battery")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -0,0 +1,119 @@
+// ABOUTME: Tests for task checklist items
+// ABOUTME: Verifies add, toggle, progress in task responses and atomic reordering
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupChecklistRouter(user *models.User, taskHandler *handlers.TaskHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(asUser(user))
+	router.GET("/tasks/:id", taskHandler.GetTask)
+	router.GET("/tasks/:id/checklist", taskHandler.GetChecklist)
+	router.POST("/tasks/:id/checklist", taskHandler.AddChecklistItem)
+	router.PATCH("/tasks/:id/checklist/:itemId/toggle", taskHandler.ToggleChecklistItem)
+	router.PATCH("/tasks/:id/checklist/:itemId/position", taskHandler.MoveChecklistItem)
+	return router
+}
+
+// addChecklistItems adds items in order via the API and returns their IDs
+func addChecklistItems(t *testing.T, router *gin.Engine, taskID string, texts ...string) []string {
+	t.Helper()
+
+	ids := make([]string, 0, len(texts))
+	for i, text := range texts {
+		w := performJSON(router, "POST", "/tasks/"+taskID+"/checklist", map[string]string{"text": text})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		item := decodeResponse(t, w)["data"].(map[string]interface{})
+		assert.Equal(t, float64(i), item["position"])
+		ids = append(ids, item["id"].(string))
+	}
+	return ids
+}
+
+func checklistTexts(t *testing.T, router *gin.Engine, taskID string) []string {
+	t.Helper()
+
+	w := performJSON(router, "GET", "/tasks/"+taskID+"/checklist", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var texts []string
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		texts = append(texts, raw.(map[string]interface{})["text"].(string))
+	}
+	return texts
+}
+
+func TestChecklist_ToggleUpdatesProgress(t *testing.T) {
+	db := setupTestDB(t)
+
+	creator := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+	router := setupChecklistRouter(creator, handlers.NewTaskHandler(db))
+
+	ids := addChecklistItems(t, router, task.ID, "Write docs", "Add tests", "Update changelog")
+
+	w := performJSON(router, "PATCH", "/tasks/"+task.ID+"/checklist/"+ids[1]+"/toggle", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, true, decodeResponse(t, w)["data"].(map[string]interface{})["done"])
+
+	w = performJSON(router, "GET", "/tasks/"+task.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	progress := data["checklist_progress"].(map[string]interface{})
+	assert.Equal(t, float64(1), progress["done"])
+	assert.Equal(t, float64(3), progress["total"])
+	assert.Len(t, data["checklist_items"], 3)
+
+	// Toggling again un-does the item
+	w = performJSON(router, "PATCH", "/tasks/"+task.ID+"/checklist/"+ids[1]+"/toggle", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, false, decodeResponse(t, w)["data"].(map[string]interface{})["done"])
+}
+
+func TestChecklist_MoveShiftsNeighbors(t *testing.T) {
+	db := setupTestDB(t)
+
+	creator := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+	router := setupChecklistRouter(creator, handlers.NewTaskHandler(db))
+
+	ids := addChecklistItems(t, router, task.ID, "A", "B", "C", "D")
+
+	// Move D to the front
+	w := performJSON(router, "PATCH", "/tasks/"+task.ID+"/checklist/"+ids[3]+"/position", map[string]int{"position": 0})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"D", "A", "B", "C"}, checklistTexts(t, router, task.ID))
+
+	// Move A past the end; the position is clamped to the last slot
+	w = performJSON(router, "PATCH", "/tasks/"+task.ID+"/checklist/"+ids[0]+"/position", map[string]int{"position": 10})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"D", "B", "C", "A"}, checklistTexts(t, router, task.ID))
+
+	var positions []int
+	require.NoError(t, db.Model(&models.ChecklistItem{}).Where("task_id = ?", task.ID).Order("position").Pluck("position", &positions).Error)
+	assert.Equal(t, []int{0, 1, 2, 3}, positions)
+}
+
+func TestAddChecklistItem_RequiresText(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/tasks/:id/checklist", withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil).AddChecklistItem)
+
+	w := performJSON(router, "POST", "/tasks/task-1/checklist", map[string]string{})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
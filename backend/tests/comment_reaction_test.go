@@ -0,0 +1,74 @@
+// ABOUTME: Tests for emoji reactions on comments
+// ABOUTME: Verifies reactions toggle per user and stay hidden with the comment's task
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/models"
+)
+
+func TestCommentReactions_AddAndRemove(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	author := createTestUser(t, db, "Member", &dept.ID)
+	teammate := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: author.ID, DepartmentID: &dept.ID})
+	comment := models.Comment{TaskID: task.ID, UserID: author.ID, Content: "Shipped"}
+	require.NoError(t, db.Create(&comment).Error)
+
+	require.Equal(t, http.StatusOK, performJSON(setupCommentRouter(db, author), "POST", "/comments/"+comment.ID+"/reactions", map[string]string{"emoji": "🎉"}).Code)
+
+	router := setupCommentRouter(db, teammate)
+	for i := 0; i < 2; i++ {
+		w := performJSON(router, "POST", "/comments/"+comment.ID+"/reactions", map[string]string{"emoji": "🎉"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w := performJSON(router, "GET", "/tasks/"+task.ID+"/comments", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	comments := decodeResponse(t, w)["data"].([]interface{})
+	reactions := comments[0].(map[string]interface{})["reactions"].([]interface{})
+	require.Len(t, reactions, 1)
+	reaction := reactions[0].(map[string]interface{})
+	assert.Equal(t, "🎉", reaction["emoji"])
+	assert.Equal(t, float64(2), reaction["count"])
+	assert.Equal(t, true, reaction["reacted"])
+
+	w = performJSON(router, "DELETE", "/comments/"+comment.ID+"/reactions", map[string]string{"emoji": "🎉"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	reactions = decodeResponse(t, w)["data"].(map[string]interface{})["reactions"].([]interface{})
+	require.Len(t, reactions, 1)
+	assert.Equal(t, float64(1), reactions[0].(map[string]interface{})["count"])
+	assert.Equal(t, false, reactions[0].(map[string]interface{})["reacted"])
+}
+
+func TestCommentReactions_HiddenTaskIsNotFound(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	author := createTestUser(t, db, "Member", &dept.ID)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: author.ID, DepartmentID: &dept.ID})
+	comment := models.Comment{TaskID: task.ID, UserID: author.ID, Content: "Internal"}
+	require.NoError(t, db.Create(&comment).Error)
+
+	w := performJSON(setupCommentRouter(db, outsider), "POST", "/comments/"+comment.ID+"/reactions", map[string]string{"emoji": "👍"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "COMMENT_NOT_FOUND", errorCode(t, decodeResponse(t, w)))
+}
+
+func TestCommentReactions_RejectsInvalidEmoji(t *testing.T) {
+	router := setupCommentRouter(nil, &models.User{ID: "user-1", Role: "Member"})
+
+	for _, emoji := range []string{"", "two words", "this-shortcode-is-far-too-long-to-be-an-emoji"} {
+		w := performJSON(router, "POST", "/comments/comment-1/reactions", map[string]string{"emoji": emoji})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, emoji)
+	}
+}
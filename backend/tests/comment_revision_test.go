@@ -0,0 +1,109 @@
+// ABOUTME: Tests for comment edits, their revision history and soft deletion
+// ABOUTME: Verifies each edit keeps the replaced text and deleted comments keep their trail
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// setupCommentRouter serves the comment endpoints to user
+func setupCommentRouter(db *gorm.DB, user *models.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	h := handlers.NewTaskHandler(db)
+	router := gin.New()
+	router.Use(asUser(user))
+	router.GET("/tasks/:id/comments", h.GetTaskComments)
+	router.PUT("/comments/:id", h.UpdateComment)
+	router.DELETE("/comments/:id", h.DeleteComment)
+	router.GET("/comments/:id/revisions", h.GetCommentRevisions)
+	router.POST("/comments/:id/reactions", h.AddCommentReaction)
+	router.DELETE("/comments/:id/reactions", h.RemoveCommentReaction)
+	return router
+}
+
+func TestUpdateComment_KeepsEachRevision(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	author := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: author.ID, DepartmentID: &dept.ID})
+	comment := models.Comment{TaskID: task.ID, UserID: author.ID, Content: "First draft"}
+	require.NoError(t, db.Create(&comment).Error)
+	router := setupCommentRouter(db, author)
+
+	for _, content := range []string{"Second draft", "Final text"} {
+		w := performJSON(router, "PUT", "/comments/"+comment.ID, map[string]string{"content": content})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		data := decodeResponse(t, w)["data"].(map[string]interface{})
+		assert.Equal(t, content, data["content"])
+		assert.Equal(t, true, data["edited"])
+		assert.NotEmpty(t, data["edited_at"])
+	}
+
+	w := performJSON(router, "GET", "/comments/"+comment.ID+"/revisions", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	revisions := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "First draft", revisions[0].(map[string]interface{})["content"])
+	assert.Equal(t, "Second draft", revisions[1].(map[string]interface{})["content"])
+	assert.Equal(t, author.ID, revisions[1].(map[string]interface{})["edited_by"])
+}
+
+func TestUpdateComment_OnlyAuthorCanEdit(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	author := createTestUser(t, db, "Member", &dept.ID)
+	teammate := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: author.ID, DepartmentID: &dept.ID})
+	comment := models.Comment{TaskID: task.ID, UserID: author.ID, Content: "Mine"}
+	require.NoError(t, db.Create(&comment).Error)
+	router := setupCommentRouter(db, teammate)
+
+	w := performJSON(router, "PUT", "/comments/"+comment.ID, map[string]string{"content": "Yours now"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Members other than the author can't read the history either
+	w = performJSON(router, "GET", "/comments/"+comment.ID+"/revisions", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDeleteComment_SoftDeletesAndKeepsRevisions(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	author := createTestUser(t, db, "Member", &dept.ID)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: author.ID, DepartmentID: &dept.ID})
+	comment := models.Comment{TaskID: task.ID, UserID: author.ID, Content: "Original"}
+	require.NoError(t, db.Create(&comment).Error)
+	router := setupCommentRouter(db, author)
+
+	w := performJSON(router, "PUT", "/comments/"+comment.ID, map[string]string{"content": "Edited"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = performJSON(router, "DELETE", "/comments/"+comment.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Gone from the task but still stored
+	w = performJSON(router, "GET", "/tasks/"+task.ID+"/comments", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, decodeResponse(t, w)["data"])
+	var stored models.Comment
+	require.NoError(t, db.Unscoped().First(&stored, "id = ?", comment.ID).Error)
+	assert.True(t, stored.DeletedAt.Valid)
+
+	// Managers can still audit the history
+	w = performJSON(setupCommentRouter(db, manager), "GET", "/comments/"+comment.ID+"/revisions", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, decodeResponse(t, w)["data"], 1)
+}
@@ -0,0 +1,108 @@
+// ABOUTME: Tests for task comments and @username mention notifications
+// ABOUTME: Verifies only known users who can see the task are recorded and notified
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestCreateTaskComment_NotifiesMentionedUsers(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	author := createTestUser(t, db, "Member", &dept.ID)
+	teammate := createTestUser(t, db, "Member", &dept.ID)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: author.ID, DepartmentID: &dept.ID})
+
+	router := gin.New()
+	router.POST("/tasks/:id/comments", asUser(author), handlers.NewTaskHandler(db).CreateTaskComment)
+
+	content := "Thoughts @" + teammate.Username + "? cc @nobody-here and @" + outsider.Username + ", also @" + author.Username
+	w := performJSON(router, "POST", "/tasks/"+task.ID+"/comments", map[string]string{"content": content})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// The outsider can't see the task and the unknown username is ignored
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{teammate.ID, author.ID}, data["mention_ids"])
+
+	// Only the teammate is notified; mentioning yourself doesn't notify you
+	var notifications []models.Notification
+	require.NoError(t, db.Where("task_id = ?", task.ID).Find(&notifications).Error)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, teammate.ID, notifications[0].UserID)
+	assert.Equal(t, "mention", notifications[0].Type)
+	assert.Equal(t, data["id"], *notifications[0].CommentID)
+	assert.Equal(t, author.ID, *notifications[0].ActorID)
+}
+
+func TestCreateTaskComment_HiddenTask(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	creator := createTestUser(t, db, "Member", &dept.ID)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &dept.ID})
+
+	router := gin.New()
+	router.POST("/tasks/:id/comments", asUser(outsider), handlers.NewTaskHandler(db).CreateTaskComment)
+
+	w := performJSON(router, "POST", "/tasks/"+task.ID+"/comments", map[string]string{"content": "hello"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateTaskComment_RequiresContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/tasks/:id/comments", withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil).CreateTaskComment)
+
+	for _, body := range []map[string]string{{}, {"content": "   "}} {
+		w := performJSON(router, "POST", "/tasks/task-1/comments", body)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+	}
+}
+
+func TestNotifications_MarkRead(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	user := createTestUser(t, db, "Member", nil)
+	other := createTestUser(t, db, "Member", nil)
+	mine := models.Notification{UserID: user.ID, Type: "mention", Message: "one"}
+	theirs := models.Notification{UserID: other.ID, Type: "mention", Message: "two"}
+	require.NoError(t, db.Create(&mine).Error)
+	require.NoError(t, db.Create(&theirs).Error)
+
+	notificationHandler := handlers.NewNotificationHandler(db)
+	router := gin.New()
+	router.Use(asUser(user))
+	router.GET("/notifications", notificationHandler.GetNotifications)
+	router.PATCH("/notifications/:id/read", notificationHandler.MarkNotificationRead)
+
+	response := decodeResponse(t, performJSON(router, "GET", "/notifications?unread=true", nil))
+	assert.Len(t, response["data"], 1)
+
+	// Someone else's notification is not found
+	w := performJSON(router, "PATCH", "/notifications/"+theirs.ID+"/read", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performJSON(router, "PATCH", "/notifications/"+mine.ID+"/read", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	response = decodeResponse(t, performJSON(router, "GET", "/notifications?unread=true", nil))
+	assert.Empty(t, response["data"])
+}
@@ -0,0 +1,89 @@
+// ABOUTME: Tests for gzip response compression
+// ABOUTME: Verifies large JSON is compressed only for accepting clients and small or binary bodies aren't
+
+package tests
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/middleware"
+	"github.com/synapse/backend/utils"
+)
+
+func setupCompressRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.Compress(1024))
+	router.GET("/large", func(c *gin.Context) {
+		items := make([]gin.H, 200)
+		for i := range items {
+			items[i] = gin.H{"id": i, "title": "Task title that repeats", "status": "To Do"}
+		}
+		utils.RespondSuccessWithPagination(c, items, 1, 200, 200)
+	})
+	router.GET("/small", func(c *gin.Context) {
+		utils.RespondSuccess(c, http.StatusOK, gin.H{"status": "ok"}, "")
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(strings.Repeat("x", 4096)))
+	})
+	return router
+}
+
+func getWithEncoding(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompress_LargeJSONIsGzipped(t *testing.T) {
+	w := getWithEncoding(setupCompressRouter(), "/large", "gzip, deflate, br")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	assert.Len(t, response["data"].([]interface{}), 200)
+	assert.Less(t, w.Body.Len(), len(body))
+}
+
+func TestCompress_Skipped(t *testing.T) {
+	router := setupCompressRouter()
+
+	tests := []struct {
+		name, path, acceptEncoding string
+	}{
+		{"client without gzip", "/large", ""},
+		{"gzip refused", "/large", "gzip;q=0, *"},
+		{"below threshold", "/small", "gzip"},
+		{"already compressed type", "/image", "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getWithEncoding(router, tt.path, tt.acceptEncoding)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.NotEmpty(t, w.Body.Bytes())
+		})
+	}
+}
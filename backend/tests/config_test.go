@@ -0,0 +1,220 @@
+// ABOUTME: Tests for startup configuration validation
+// ABOUTME: Covers required DATABASE_URL and JWT_SECRET and the minimum secret length
+
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+)
+
+// validConfig returns a config that passes Validate, for tests to break one field at a time
+func validConfig() *config.Config {
+	return &config.Config{
+		DatabaseURL:                "postgres://localhost/synapse",
+		JWTSecret:                  strings.Repeat("s", config.MinJWTSecretLength),
+		JWTAlg:                     config.JWTAlgHS256,
+		DBMaxOpenConns:             config.DefaultDBMaxOpenConns,
+		DBMaxIdleConns:             config.DefaultDBMaxIdleConns,
+		DBConnMaxLifetimeMin:       config.DefaultDBConnMaxLifetimeMin,
+		DBConnMaxIdleTimeMin:       config.DefaultDBConnMaxIdleTimeMin,
+		DBQueryTimeoutSec:          config.DefaultDBQueryTimeoutSec,
+		ServerReadHeaderTimeoutSec: config.DefaultServerReadHeaderTimeoutSec,
+		ServerReadTimeoutSec:       config.DefaultServerReadTimeoutSec,
+		ServerWriteTimeoutSec:      config.DefaultServerWriteTimeoutSec,
+		ServerIdleTimeoutSec:       config.DefaultServerIdleTimeoutSec,
+		HiddenResourceStatus:       config.DefaultHiddenResourceStatus,
+		PaginationDefault:          config.DefaultPaginationDefault,
+		PaginationMax:              config.DefaultPaginationMax,
+		AvatarStyle:                config.AvatarStyleGravatar,
+		AvatarMaxBytes:             config.DefaultAvatarMaxBytes,
+		ProjectAtRiskPercent:       config.DefaultProjectAtRiskPercent,
+		ProjectOverduePercent:      config.DefaultProjectOverduePercent,
+		CacheRefreshSec:            config.DefaultCacheRefreshSec,
+	}
+}
+
+func TestConfigValidate_Valid(t *testing.T) {
+	cfg := validConfig()
+
+	// PORT and GIN_MODE are optional
+	assert.Empty(t, cfg.Port)
+	assert.Empty(t, cfg.GinMode)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfigValidate_MissingDatabaseURL(t *testing.T) {
+	cfg := validConfig()
+	cfg.DatabaseURL = ""
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "DATABASE_URL is required", err.Error())
+}
+
+func TestConfigValidate_MissingSecret(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecret = ""
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "JWT_SECRET is required", err.Error())
+}
+
+func TestConfigValidate_ShortSecret(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecret = strings.Repeat("s", config.MinJWTSecretLength-1)
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET must be at least 32 characters")
+}
+
+func TestConfigValidate_InvalidPool(t *testing.T) {
+	cfg := validConfig()
+	cfg.DBMaxOpenConns = 0
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DB_MAX_OPEN_CONNS")
+}
+
+func TestConfigValidate_HiddenResourceStatus(t *testing.T) {
+	cfg := validConfig()
+	cfg.HiddenResourceStatus = 404
+	assert.NoError(t, cfg.Validate())
+
+	cfg.HiddenResourceStatus = 401
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "HIDDEN_RESOURCE_STATUS must be 403 or 404", err.Error())
+}
+
+func TestConfigValidate_Pagination(t *testing.T) {
+	cfg := validConfig()
+	cfg.PaginationMax = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAGINATION_MAX")
+
+	cfg = validConfig()
+	cfg.PaginationDefault = 200
+	cfg.PaginationMax = 150
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "PAGINATION_DEFAULT must not exceed PAGINATION_MAX", err.Error())
+
+	cfg.PaginationMax = 500
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfigValidate_AvatarStyle(t *testing.T) {
+	cfg := validConfig()
+	cfg.AvatarStyle = "cartoon"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "AVATAR_STYLE must be gravatar, initials or none", err.Error())
+
+	for _, style := range []string{config.AvatarStyleInitials, config.AvatarStyleNone} {
+		cfg.AvatarStyle = style
+		assert.NoError(t, cfg.Validate())
+	}
+}
+
+func TestConfigValidate_TaskDefaultViews(t *testing.T) {
+	cfg := validConfig()
+	cfg.TaskDefaultViews = `{"Member": "everything"}`
+	assert.Error(t, cfg.Validate())
+
+	cfg.TaskDefaultViews = `["mine"]`
+	assert.Error(t, cfg.Validate())
+
+	cfg.TaskDefaultViews = `{"Member": "all", "Auditor": "department_open"}`
+	require.NoError(t, cfg.Validate())
+	views, err := cfg.TaskListViews()
+	require.NoError(t, err)
+	assert.Equal(t, config.TaskViewAll, views["Member"])
+	assert.Equal(t, config.TaskViewDepartmentOpen, views["Auditor"])
+	assert.Equal(t, config.TaskViewDepartmentOpen, views["Manager"])
+}
+
+func TestConfigValidate_DigestTime(t *testing.T) {
+	cfg := validConfig()
+	cfg.DigestTime = "8am"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "DIGEST_TIME must be a time of day in HH:MM (24-hour, UTC)", err.Error())
+
+	cfg.DigestTime = "07:30"
+	require.NoError(t, cfg.Validate())
+	clock, err := cfg.DigestClock()
+	require.NoError(t, err)
+	assert.Equal(t, 7*time.Hour+30*time.Minute, clock)
+}
+
+func TestConfigValidate_PublicBaseURL(t *testing.T) {
+	cfg := validConfig()
+	for _, value := range []string{"api.example.com", "ftp://api.example.com", "https://"} {
+		cfg.PublicBaseURL = value
+		err := cfg.Validate()
+		require.Error(t, err, value)
+		assert.Equal(t, "PUBLIC_BASE_URL must be an absolute http or https URL", err.Error())
+	}
+
+	cfg.PublicBaseURL = "https://api.example.com"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfigValidate_ProjectHealthThresholds(t *testing.T) {
+	cfg := validConfig()
+	cfg.ProjectAtRiskPercent = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg.ProjectAtRiskPercent = 60
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "PROJECT_AT_RISK_PERCENT must not exceed PROJECT_OVERDUE_PERCENT", err.Error())
+
+	cfg.ProjectOverduePercent = 80
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfigValidate_CompressionMinBytes(t *testing.T) {
+	cfg := validConfig()
+	cfg.CompressionMinBytes = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "COMPRESSION_MIN_BYTES must not be negative", err.Error())
+}
+
+func TestConfigValidate_CacheRefreshSec(t *testing.T) {
+	cfg := validConfig()
+	cfg.CacheRefreshSec = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "CACHE_REFRESH_SEC must be a positive integer", err.Error())
+}
+
+func TestConfigValidate_PasswordHistorySize(t *testing.T) {
+	cfg := validConfig()
+	cfg.PasswordHistorySize = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "PASSWORD_HISTORY_SIZE must not be negative", err.Error())
+}
+
+func TestConfigValidate_TaskSources(t *testing.T) {
+	cfg := validConfig()
+	cfg.TaskSources = "Jira, Zapier"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.TaskSources = "Jira, An Extremely Long Integration"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, `TASK_SOURCES: "An Extremely Long Integration" is longer than 20 characters`, err.Error())
+}
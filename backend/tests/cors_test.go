@@ -0,0 +1,109 @@
+// ABOUTME: Tests for CORS origin matching and configurable methods and headers
+// ABOUTME: Verifies origin patterns are honored and preflights reflect the configured allowlists
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/synapse/backend/middleware"
+)
+
+func setupCORSRouter(t *testing.T, origins string) *gin.Engine {
+	t.Setenv("CORS_ORIGINS", origins)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.CORS())
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+	return router
+}
+
+func requestWithOrigin(router http.Handler, origin string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/ping", nil)
+	req.Header.Set("Origin", origin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_WildcardSubdomain(t *testing.T) {
+	router := setupCORSRouter(t, "https://app.example.org, https://*.preview.example.com")
+
+	for _, origin := range []string{"https://pr-42.preview.example.com", "https://a.b.preview.example.com", "https://app.example.org"} {
+		w := requestWithOrigin(router, origin)
+		assert.Equal(t, http.StatusOK, w.Code, origin)
+		assert.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"), origin)
+	}
+}
+
+func TestCORS_RejectsUnrelatedOrigin(t *testing.T) {
+	router := setupCORSRouter(t, "https://*.preview.example.com")
+
+	for _, origin := range []string{"https://evil.com", "https://preview.example.com.evil.com", "http://pr-1.preview.example.com"} {
+		w := requestWithOrigin(router, origin)
+		assert.Equal(t, http.StatusForbidden, w.Code, origin)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+	}
+}
+
+func TestCORS_RegexOrigin(t *testing.T) {
+	router := setupCORSRouter(t, `regex:https://pr-\d+\.example\.com`)
+
+	w := requestWithOrigin(router, "https://pr-7.example.com")
+	assert.Equal(t, "https://pr-7.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = requestWithOrigin(router, "https://pr-x.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCORS_BareWildcardIgnored(t *testing.T) {
+	router := setupCORSRouter(t, "*")
+
+	w := requestWithOrigin(router, "https://evil.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotEqual(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func preflight(router http.Handler, method, headers string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("OPTIONS", "/ping", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", method)
+	req.Header.Set("Access-Control-Request-Headers", headers)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_ConfiguredHeaderAllowlist(t *testing.T) {
+	t.Setenv("CORS_ALLOW_METHODS", "GET, POST")
+	t.Setenv("CORS_ALLOW_HEADERS", "Content-Type, Authorization, X-Tenant")
+	t.Setenv("CORS_EXPOSE_HEADERS", "ETag")
+	router := setupCORSRouter(t, "")
+
+	w := preflight(router, "POST", "X-Tenant")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET,POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type,Authorization,X-Tenant", w.Header().Get("Access-Control-Allow-Headers"))
+
+	// Request ID and idempotency headers stay exposed
+	w = requestWithOrigin(router, "http://localhost:3000")
+	exposed := strings.Split(w.Header().Get("Access-Control-Expose-Headers"), ",")
+	assert.Equal(t, []string{"Etag", "X-Request-Id", "Idempotency-Key"}, exposed)
+}
+
+func TestCORS_DefaultHeaders(t *testing.T) {
+	router := setupCORSRouter(t, "")
+
+	w := preflight(router, "PATCH", "Authorization, If-Match, X-Request-ID, Idempotency-Key")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Idempotency-Key")
+}
@@ -0,0 +1,183 @@
+// ABOUTME: Tests for custom task fields and their Admin-managed definitions
+// ABOUTME: Task values are validated against the definitions and stored under metadata.custom
+
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"gorm.io/gorm"
+)
+
+// setupCustomFieldRouter routes the definition endpoints as an Admin and task writes as member,
+// restoring the process-wide definition cache afterwards
+func setupCustomFieldRouter(t *testing.T, db *gorm.DB, member *models.User) *gin.Engine {
+	t.Helper()
+	base := openTestDB(t)
+	t.Cleanup(func() {
+		assert.NoError(t, repository.LoadCustomFields(base))
+	})
+
+	gin.SetMode(gin.TestMode)
+	fields := handlers.NewCustomFieldHandler(db)
+	tasks := handlers.NewTaskHandler(db)
+	admin := withTestUser("admin-1", "Admin", nil)
+	router := gin.New()
+	router.GET("/custom-fields", admin, fields.GetCustomFields)
+	router.POST("/custom-fields", admin, fields.CreateCustomField)
+	router.PUT("/custom-fields/:id", admin, fields.UpdateCustomField)
+	router.POST("/tasks", asUser(member), tasks.CreateTask)
+	router.PUT("/tasks/:id", asUser(member), tasks.UpdateTask)
+	router.POST("/tasks/import", asUser(member), tasks.ImportTasks)
+	router.POST("/tasks/:id/duplicate", asUser(member), tasks.DuplicateTask)
+	router.POST("/task-templates/:templateId/tasks", asUser(member), tasks.CreateTaskFromTemplate)
+	return router
+}
+
+// storedCustomFields reads a task's custom field values back from the database
+func storedCustomFields(t *testing.T, db *gorm.DB, taskID string) map[string]interface{} {
+	t.Helper()
+	var task models.Task
+	require.NoError(t, db.First(&task, "id = ?", taskID).Error)
+	require.NotNil(t, task.Metadata)
+	var metadata struct {
+		Custom map[string]interface{} `json:"custom"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(*task.Metadata), &metadata))
+	return metadata.Custom
+}
+
+func TestCustomField_RequiredFieldRejected(t *testing.T) {
+	db := setupTestDB(t)
+	department := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &department.ID)
+	router := setupCustomFieldRouter(t, db, member)
+
+	w := performJSON(router, "POST", "/custom-fields", map[string]interface{}{
+		"name": "Client", "type": "text", "required": true, "department_id": department.ID,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = performJSON(router, "POST", "/custom-fields", map[string]interface{}{"name": "Tier", "type": "select"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []string{"options"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "No client"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []string{"custom_fields.Client"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "Blank client", "custom_fields": map[string]interface{}{"Client": "  "}})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []string{"custom_fields.Client"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	// Other departments' tasks don't have the field
+	other := createTestDepartment(t, db)
+	w = performJSON(router, "POST", "/tasks", map[string]interface{}{
+		"title": "Elsewhere", "department_id": other.ID, "custom_fields": map[string]interface{}{"Client": "Acme"},
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "Unknown custom field")
+}
+
+func TestCustomField_ValueRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	department := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &department.ID)
+	router := setupCustomFieldRouter(t, db, member)
+
+	for _, field := range []map[string]interface{}{
+		{"name": "Client", "type": "text", "required": true},
+		{"name": "Cost Center", "type": "select", "options": []string{"CC-100", "CC-200"}},
+		{"name": "Budget", "type": "number"},
+	} {
+		w := performJSON(router, "POST", "/custom-fields", field)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w := performJSON(router, "GET", "/custom-fields?department_id="+department.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, decodeResponse(t, w)["data"], 3)
+
+	w = performJSON(router, "POST", "/tasks", map[string]interface{}{
+		"title": "Invoice run", "custom_fields": map[string]interface{}{"Client": "Acme", "Cost Center": "CC-300", "Budget": "lots"},
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []string{"custom_fields.Budget", "custom_fields.Cost Center"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "POST", "/tasks", map[string]interface{}{
+		"title": "Invoice run", "custom_fields": map[string]interface{}{"Client": "Acme", "Cost Center": "CC-100", "Budget": 1200.5},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	taskID := decodeResponse(t, w)["data"].(map[string]interface{})["id"].(string)
+	assert.Equal(t, map[string]interface{}{"Client": "Acme", "Cost Center": "CC-100", "Budget": 1200.5}, storedCustomFields(t, db, taskID))
+
+	// Updates merge into the stored values; null clears an optional field
+	w = sendWithIfMatch(router, "PUT", "/tasks/"+taskID, `{"custom_fields": {"Cost Center": "CC-200", "Budget": null}}`, "*")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"Client": "Acme", "Cost Center": "CC-200"}, storedCustomFields(t, db, taskID))
+
+	w = sendWithIfMatch(router, "PUT", "/tasks/"+taskID, `{"custom_fields": {"Client": null}}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []string{"custom_fields.Client"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	// Renaming a field carries the stored values over
+	var client models.CustomFieldDefinition
+	require.NoError(t, db.First(&client, "name = ?", "Client").Error)
+	w = performJSON(router, "PUT", "/custom-fields/"+client.ID, map[string]interface{}{"name": "Customer"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"Customer": "Acme", "Cost Center": "CC-200"}, storedCustomFields(t, db, taskID))
+}
+
+func TestCustomField_AppliedByEveryTaskCreator(t *testing.T) {
+	db := setupTestDB(t)
+	department := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &department.ID)
+	router := setupCustomFieldRouter(t, db, member)
+
+	w := performJSON(router, "POST", "/custom-fields", map[string]interface{}{
+		"name": "Client", "type": "text", "required": true, "department_id": department.ID,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// Imported rows are checked one by one
+	w = performJSON(router, "POST", "/tasks/import?dry_run=true", []map[string]interface{}{
+		{"title": "No client"},
+		{"title": "Acme work", "custom_fields": map[string]interface{}{"Client": "Acme"}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	results := decodeResponse(t, w)["data"].(map[string]interface{})["results"].([]interface{})
+	assert.Equal(t, "invalid", results[0].(map[string]interface{})["status"])
+	assert.Equal(t, "valid", results[1].(map[string]interface{})["status"])
+
+	// Templates take values alongside their other overrides
+	template := models.TaskTemplate{Name: "Onboarding", Title: "Onboard client", CreatorID: member.ID, DepartmentID: &department.ID}
+	require.NoError(t, db.Create(&template).Error)
+	w = performJSON(router, "POST", "/task-templates/"+template.ID+"/tasks", map[string]interface{}{})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, []string{"custom_fields.Client"}, errorDetailFields(t, decodeResponse(t, w)))
+	w = performJSON(router, "POST", "/task-templates/"+template.ID+"/tasks", map[string]interface{}{
+		"custom_fields": map[string]interface{}{"Client": "Globex"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	taskID := decodeResponse(t, w)["data"].(map[string]interface{})["id"].(string)
+	assert.Equal(t, map[string]interface{}{"Client": "Globex"}, storedCustomFields(t, db, taskID))
+
+	// Duplicates carry the values over
+	w = performJSON(router, "POST", "/tasks/"+taskID+"/duplicate", nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	copyID := decodeResponse(t, w)["data"].(map[string]interface{})["id"].(string)
+	assert.Equal(t, map[string]interface{}{"Client": "Globex"}, storedCustomFields(t, db, copyID))
+
+	// A task from before the field was required can't be copied until it has a value
+	legacy := createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &department.ID})
+	w = performJSON(router, "POST", "/tasks/"+legacy.ID+"/duplicate", nil)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, []string{"custom_fields.Client"}, errorDetailFields(t, decodeResponse(t, w)))
+}
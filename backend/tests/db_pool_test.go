@@ -0,0 +1,92 @@
+// ABOUTME: Tests for database connection pool configuration
+// ABOUTME: Covers env defaults, validation and the pool limits applied by SetupDatabase
+
+package tests
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+)
+
+func TestGetConfig_PoolDefaults(t *testing.T) {
+	for _, key := range []string{"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME_MIN", "DB_CONN_MAX_IDLE_TIME_MIN"} {
+		// t.Setenv restores the variable afterwards; unset it so the default applies
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	cfg := config.GetConfig()
+	assert.Equal(t, config.DefaultDBMaxOpenConns, cfg.DBMaxOpenConns)
+	assert.Equal(t, config.DefaultDBMaxIdleConns, cfg.DBMaxIdleConns)
+	assert.Equal(t, config.DefaultDBConnMaxLifetimeMin, cfg.DBConnMaxLifetimeMin)
+	assert.Equal(t, config.DefaultDBConnMaxIdleTimeMin, cfg.DBConnMaxIdleTimeMin)
+	assert.NoError(t, cfg.ValidatePool())
+}
+
+func TestGetConfig_PoolFromEnv(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "40")
+	t.Setenv("DB_MAX_IDLE_CONNS", "8")
+	t.Setenv("DB_CONN_MAX_LIFETIME_MIN", "30")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME_MIN", "2")
+
+	cfg := config.GetConfig()
+	assert.Equal(t, 40, cfg.DBMaxOpenConns)
+	assert.Equal(t, 8, cfg.DBMaxIdleConns)
+	assert.Equal(t, 30, cfg.DBConnMaxLifetimeMin)
+	assert.Equal(t, 2, cfg.DBConnMaxIdleTimeMin)
+}
+
+func TestValidatePool_RejectsNonPositive(t *testing.T) {
+	for _, value := range []string{"0", "-5", "lots"} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv("DB_MAX_IDLE_CONNS", value)
+
+			err := config.GetConfig().ValidatePool()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "DB_MAX_IDLE_CONNS")
+		})
+	}
+}
+
+func TestSetupDatabase_RejectsInvalidPool(t *testing.T) {
+	cfg := &config.Config{DatabaseURL: "postgres://localhost/unused", DBMaxOpenConns: 0, DBMaxIdleConns: 5, DBConnMaxLifetimeMin: 60, DBConnMaxIdleTimeMin: 10}
+
+	// Validation fails before any connection is attempted
+	_, err := config.SetupDatabase(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DB_MAX_OPEN_CONNS")
+}
+
+func TestApplyPoolSettings(t *testing.T) {
+	// sql.Open doesn't connect, so the pool can be inspected without a database
+	sqlDB, err := sql.Open("pgx", "postgres://localhost/unused")
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	config.ApplyPoolSettings(sqlDB, &config.Config{DBMaxOpenConns: 13, DBMaxIdleConns: 3, DBConnMaxLifetimeMin: 5, DBConnMaxIdleTimeMin: 1})
+	assert.Equal(t, 13, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestSetupDatabase_AppliesPool(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping database test")
+	}
+
+	cfg := &config.Config{DatabaseURL: dsn, DBMaxOpenConns: 7, DBMaxIdleConns: 2, DBConnMaxLifetimeMin: 15, DBConnMaxIdleTimeMin: 3}
+	db, err := config.SetupDatabase(cfg)
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	require.NoError(t, sqlDB.Ping())
+	assert.Equal(t, 7, sqlDB.Stats().MaxOpenConnections)
+	assert.LessOrEqual(t, sqlDB.Stats().Idle, 2)
+}
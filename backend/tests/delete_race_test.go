@@ -0,0 +1,99 @@
+// ABOUTME: Tests that project and department deletes check for dependents atomically
+// ABOUTME: A delete racing an uncommitted insert waits for it and then refuses, instead of orphaning the row
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// deleteDuringWrite sends a DELETE while writer's insert is still open, checks that it waits
+// for the writer rather than deciding first, then commits writer and returns the response
+func deleteDuringWrite(t *testing.T, router http.Handler, path string, writer *gorm.DB) *httptest.ResponseRecorder {
+	t.Helper()
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		done <- performJSON(router, "DELETE", path, nil)
+	}()
+
+	select {
+	case w := <-done:
+		t.Fatalf("delete answered %d while an insert referencing the row was uncommitted", w.Code)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, writer.Commit().Error)
+	return <-done
+}
+
+// beginWriter opens a transaction on the shared test database that is rolled back at cleanup
+// unless the test commits it
+func beginWriter(t *testing.T, base *gorm.DB) *gorm.DB {
+	t.Helper()
+	writer := base.Begin()
+	require.NoError(t, writer.Error)
+	t.Cleanup(func() {
+		teardownTestDB(t, writer)
+	})
+	return writer
+}
+
+func TestDeleteProject_WaitsForConcurrentTaskInsert(t *testing.T) {
+	// Committed fixtures, since the delete and the insert need separate transactions
+	base := openTestDB(t)
+	admin := createTestUser(t, base, "Admin", nil)
+	project := createTestProject(t, base, nil)
+	t.Cleanup(func() {
+		assert.NoError(t, base.Where("project_id = ?", project.ID).Delete(&models.Task{}).Error)
+		assert.NoError(t, base.Delete(&models.Project{}, "id = ?", project.ID).Error)
+		assert.NoError(t, base.Delete(&models.User{}, "id = ?", admin.ID).Error)
+	})
+
+	writer := beginWriter(t, base)
+	createTestTask(t, writer, models.Task{CreatorID: admin.ID, ProjectID: &project.ID})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.DELETE("/projects/:id", asUser(admin), handlers.NewProjectHandler(base).DeleteProject)
+
+	w := deleteDuringWrite(t, router, "/projects/"+project.ID, writer)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Equal(t, "PROJECT_HAS_TASKS", errorCode(t, decodeResponse(t, w)))
+
+	var count int64
+	require.NoError(t, base.Model(&models.Project{}).Where("id = ?", project.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count, "the project is kept for its new task")
+}
+
+func TestDeleteDepartment_WaitsForConcurrentUserInsert(t *testing.T) {
+	base := openTestDB(t)
+	admin := createTestUser(t, base, "Admin", nil)
+	department := createTestDepartment(t, base)
+	t.Cleanup(func() {
+		assert.NoError(t, base.Where("department_id = ?", department.ID).Delete(&models.User{}).Error)
+		assert.NoError(t, base.Delete(&models.Department{}, "id = ?", department.ID).Error)
+		assert.NoError(t, base.Delete(&models.User{}, "id = ?", admin.ID).Error)
+	})
+
+	writer := beginWriter(t, base)
+	createTestUser(t, writer, "Member", &department.ID)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.DELETE("/departments/:id", asUser(admin), handlers.NewDepartmentHandler(base).DeleteDepartment)
+
+	w := deleteDuringWrite(t, router, "/departments/"+department.ID, writer)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Equal(t, "DEPARTMENT_HAS_USERS", errorCode(t, decodeResponse(t, w)))
+}
@@ -0,0 +1,113 @@
+// ABOUTME: Tests for department head validation on create and update
+// ABOUTME: Verifies heads must be Managers or Admins in the department unless move_head moves them
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+func setupDepartmentHeadRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	departmentHandler := handlers.NewDepartmentHandler(db)
+	router := gin.New()
+	router.Use(withTestUser("admin-1", "Admin", nil))
+	router.POST("/departments", departmentHandler.CreateDepartment)
+	router.PUT("/departments/:id", departmentHandler.UpdateDepartment)
+	return router
+}
+
+func TestUpdateDepartment_HeadFromAnotherDepartmentRejected(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	other := createTestDepartment(t, db)
+	outsider := createTestUser(t, db, "Manager", &other.ID)
+	router := setupDepartmentHeadRouter(db)
+
+	w := performJSON(router, "PUT", "/departments/"+dept.ID, map[string]string{"head_id": outsider.ID})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	response := decodeResponse(t, w)
+	assert.Equal(t, "INVALID_HEAD", errorCode(t, response))
+	assert.Contains(t, response["error"].(map[string]interface{})["message"], "must belong to the department")
+
+	var stored models.Department
+	require.NoError(t, db.First(&stored, "id = ?", dept.ID).Error)
+	assert.Nil(t, stored.HeadID)
+}
+
+func TestUpdateDepartment_InDepartmentManagerAccepted(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	router := setupDepartmentHeadRouter(db)
+
+	w := performJSON(router, "PUT", "/departments/"+dept.ID, map[string]string{"head_id": manager.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored models.Department
+	require.NoError(t, db.First(&stored, "id = ?", dept.ID).Error)
+	require.NotNil(t, stored.HeadID)
+	assert.Equal(t, manager.ID, *stored.HeadID)
+}
+
+func TestUpdateDepartment_MemberHeadRejected(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	router := setupDepartmentHeadRouter(db)
+
+	w := performJSON(router, "PUT", "/departments/"+dept.ID, map[string]interface{}{"head_id": member.ID, "move_head": true})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	response := decodeResponse(t, w)
+	assert.Equal(t, "INVALID_HEAD", errorCode(t, response))
+	assert.Equal(t, "Department head must be a Manager or Admin", response["error"].(map[string]interface{})["message"])
+}
+
+func TestUpdateDepartment_MoveHeadMovesManagerIn(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	other := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &other.ID)
+	router := setupDepartmentHeadRouter(db)
+
+	w := performJSON(router, "PUT", "/departments/"+dept.ID, map[string]interface{}{"head_id": manager.ID, "move_head": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", manager.ID).Error)
+	require.NotNil(t, stored.DepartmentID)
+	assert.Equal(t, dept.ID, *stored.DepartmentID)
+}
+
+func TestCreateDepartment_HeadRequiresMove(t *testing.T) {
+	db := setupTestDB(t)
+
+	manager := createTestUser(t, db, "Manager", nil)
+	router := setupDepartmentHeadRouter(db)
+
+	w := performJSON(router, "POST", "/departments", map[string]string{"name": "Research " + manager.ID, "head_id": manager.ID})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "INVALID_HEAD", errorCode(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "POST", "/departments", map[string]interface{}{"name": "Research " + manager.ID, "head_id": manager.ID, "move_head": true})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	departmentID := decodeResponse(t, w)["data"].(map[string]interface{})["id"].(string)
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", manager.ID).Error)
+	require.NotNil(t, stored.DepartmentID)
+	assert.Equal(t, departmentID, *stored.DepartmentID)
+}
@@ -0,0 +1,123 @@
+// ABOUTME: Tests for the daily manager digest job
+// ABOUTME: Verifies digest contents against seeded tasks and that each digest is sent once a day
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/jobs"
+	"github.com/synapse/backend/models"
+)
+
+func TestBuildManagerDigests_OverdueAndDueToday(t *testing.T) {
+	db := setupTestDB(t)
+
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+
+	dept := createTestDepartment(t, db)
+	quietDept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	quietManager := createTestUser(t, db, "Manager", &quietDept.ID)
+	member := createTestUser(t, db, "Member", &dept.ID)
+
+	newTask := func(title, status string, due *time.Time, deptID *string) {
+		createTestTask(t, db, models.Task{Title: title, Status: status, DueDate: due, CreatorID: member.ID, DepartmentID: deptID})
+	}
+	newTask("Late report", "In Progress", at(-48*time.Hour), &dept.ID)
+	newTask("Standup notes", "To Do", at(3*time.Hour), &dept.ID)
+	newTask("Next week", "To Do", at(7*24*time.Hour), &dept.ID)
+	newTask("Finished", "Done", at(-24*time.Hour), &dept.ID)
+	newTask("Undated", "To Do", nil, &dept.ID)
+	newTask("Quiet next week", "To Do", at(7*24*time.Hour), &quietDept.ID)
+
+	digests, err := jobs.BuildManagerDigests(db, now)
+	require.NoError(t, err)
+
+	var digest *jobs.ManagerDigest
+	for i := range digests {
+		assert.NotEqual(t, quietManager.ID, digests[i].Manager.ID, "managers with nothing due are skipped")
+		if digests[i].Manager.ID == manager.ID {
+			digest = &digests[i]
+		}
+	}
+	require.NotNil(t, digest)
+	require.Len(t, digest.Overdue, 1)
+	require.Len(t, digest.DueToday, 1)
+	assert.Equal(t, "Late report", digest.Overdue[0].Title)
+	assert.Equal(t, "Standup notes", digest.DueToday[0].Title)
+
+	text := digest.SlackPayload()["text"].(string)
+	assert.Contains(t, text, "*Daily digest for Test Manager ("+dept.Name+"), Tuesday 10 Mar 2026*")
+	assert.Contains(t, text, "*Overdue (1)*\n• Late report (Medium, due 2026-03-08)")
+	assert.Contains(t, text, "*Due today (1)*\n• Standup notes (Medium, due 12:00 UTC)")
+	assert.NotContains(t, text, "Next week")
+	assert.NotContains(t, text, "Finished")
+}
+
+func TestDigestJob_SendsOncePerDay(t *testing.T) {
+	db := setupTestDB(t)
+
+	now := time.Now().UTC()
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	overdue := now.Add(-time.Hour)
+	createTestTask(t, db, models.Task{Title: "Overdue for digest", DueDate: &overdue, CreatorID: manager.ID, DepartmentID: &dept.ID})
+
+	var mu sync.Mutex
+	var received []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		mu.Lock()
+		received = append(received, payload["text"])
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	job, err := jobs.NewDigestJob(db, &config.Config{DigestWebhookURL: webhook.URL})
+	require.NoError(t, err)
+
+	// A restart within the day runs the job again; the manager only hears once
+	for i := 0; i < 2; i++ {
+		_, err := job.RunOnce(context.Background(), now)
+		require.NoError(t, err)
+	}
+
+	mentions := 0
+	for _, text := range received {
+		if strings.Contains(text, dept.Name) {
+			mentions++
+			assert.Contains(t, text, "Overdue for digest")
+		}
+	}
+	assert.Equal(t, 1, mentions)
+}
+
+func TestManagerDigest_Text(t *testing.T) {
+	digest := jobs.ManagerDigest{
+		Manager:    models.User{FullName: "Dana Reyes"},
+		Department: "Engineering",
+		Date:       time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		Overdue: []jobs.DigestTask{
+			{Title: "Fix login", Priority: "High", DueDate: time.Date(2026, 10, 14, 17, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	assert.Equal(t, "*Daily digest for Dana Reyes (Engineering), Friday 16 Oct 2026*\n*Overdue (1)*\n• Fix login (High, due 2026-10-14)", digest.Text())
+	assert.Equal(t, map[string]interface{}{"text": digest.Text()}, digest.SlackPayload())
+}
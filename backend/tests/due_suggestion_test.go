@@ -0,0 +1,79 @@
+// ABOUTME: Tests for business-day due date suggestions
+// ABOUTME: Covers the default weekend, department holidays set through the department API, and input validation
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+)
+
+func TestSuggestDueDate_SkipsDefaultWeekend(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/tasks/suggest-due", withTestUser("admin-1", "Admin", nil), handlers.NewTaskHandler(nil).SuggestDueDate)
+
+	// 2024-05-24 is a Friday
+	w := performJSON(router, "GET", "/tasks/suggest-due?days=1&from=2024-05-24", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "2024-05-27", data["due_date"])
+	assert.Equal(t, "2024-05-24", data["from"])
+	assert.Equal(t, float64(1), data["days"])
+}
+
+func TestSuggestDueDate_SkipsDepartmentHolidays(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	department := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &department.ID)
+
+	router := gin.New()
+	router.PUT("/departments/:id", withTestUser("admin-1", "Admin", nil), handlers.NewDepartmentHandler(db).UpdateDepartment)
+	router.GET("/tasks/suggest-due", asUser(member), handlers.NewTaskHandler(db).SuggestDueDate)
+
+	w := performJSON(router, "PUT", "/departments/"+department.ID, map[string]interface{}{
+		"working_calendar": map[string]interface{}{
+			"weekend_days": []string{"saturday", "Sunday"},
+			"holidays":     []string{"2024-05-27"},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Friday + 2 working days skips the weekend and the Monday holiday
+	w = performJSON(router, "GET", "/tasks/suggest-due?days=2&from=2024-05-24", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "2024-05-29", data["due_date"])
+	assert.Equal(t, department.ID, data["department_id"])
+}
+
+func TestSuggestDueDate_InvalidInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/tasks/suggest-due", withTestUser("admin-1", "Admin", nil), handlers.NewTaskHandler(nil).SuggestDueDate)
+
+	for _, query := range []string{"", "?days=0", "?days=366", "?days=abc", "?days=3&from=24/05/2024"} {
+		w := performJSON(router, "GET", "/tasks/suggest-due"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)), query)
+	}
+}
+
+func TestUpdateDepartment_RejectsInvalidWorkingCalendar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.PUT("/departments/:id", withTestUser("admin-1", "Admin", nil), handlers.NewDepartmentHandler(nil).UpdateDepartment)
+
+	w := performJSON(router, "PUT", "/departments/dept-1", `{"working_calendar": {"weekend_days": ["Funday"]}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
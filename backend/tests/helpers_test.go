@@ -0,0 +1,229 @@
+// ABOUTME: Shared helpers for handler tests
+// ABOUTME: Provides authenticated request context, JSON decoding, a migrated rollback-isolated test database and fixtures
+
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/migrations"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+// fixtureSeq keeps fixture names unique across tests sharing a database
+var fixtureSeq int64
+
+// withTestUser sets the same context keys RequireAuth populates from a JWT
+func withTestUser(userID, role string, departmentID *string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("user_email", userID+"@example.com")
+		c.Set("user_name", "Test User")
+		c.Set("user_role", role)
+		c.Set("user_department_id", departmentID)
+		c.Set("user_permissions", []string{})
+		c.Next()
+	}
+}
+
+// asUser is withTestUser for a fixture user
+func asUser(user *models.User) gin.HandlerFunc {
+	return withTestUser(user.ID, user.Role, user.DepartmentID)
+}
+
+// decodeResponse unmarshals a recorded response body into a generic map
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+// performJSON sends a request with an optional JSON body through the router
+func performJSON(router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		encoded, _ := json.Marshal(b)
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// Shared connection for the package; migrations run once on first use
+var (
+	testDBOnce sync.Once
+	testDB     *gorm.DB
+	testDBErr  error
+)
+
+// openTestDB connects to TEST_DATABASE_URL and applies pending migrations, skipping the test
+// when the variable is unset. The database should be dedicated to tests; use setupTestDB for
+// isolated access.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping database test")
+	}
+
+	testDBOnce.Do(func() {
+		cfg := config.GetConfig()
+		cfg.DatabaseURL = dsn
+		testDB, testDBErr = config.SetupDatabase(cfg)
+		if testDBErr == nil {
+			_, testDBErr = migrations.Run(testDB)
+		}
+	})
+	require.NoError(t, testDBErr)
+	return testDB
+}
+
+// setupTestDB returns a transaction on the test database that is rolled back when the test
+// ends, so fixtures and handler writes never leak between tests. Handlers' own transactions
+// nest as savepoints. A failed SQL statement aborts the transaction for the rest of the test.
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	tx := openTestDB(t).Begin()
+	require.NoError(t, tx.Error)
+	t.Cleanup(func() {
+		teardownTestDB(t, tx)
+	})
+	return tx
+}
+
+// teardownTestDB rolls back a transaction from setupTestDB. It runs automatically at cleanup
+// and is safe to call earlier.
+func teardownTestDB(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	if err := db.Rollback().Error; err != nil && !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("rolling back test transaction: %v", err)
+	}
+}
+
+// testSeed is a department with one user per role, for tests that need a populated org
+type testSeed struct {
+	Department *models.Department
+	Admin      *models.User
+	Manager    *models.User
+	Member     *models.User
+	Viewer     *models.User
+}
+
+// seedTestData creates a department and one user of each role inside it (Admin has no department)
+func seedTestData(t *testing.T, db *gorm.DB) testSeed {
+	t.Helper()
+
+	department := createTestDepartment(t, db)
+	return testSeed{
+		Department: department,
+		Admin:      createTestUser(t, db, "Admin", nil),
+		Manager:    createTestUser(t, db, "Manager", &department.ID),
+		Member:     createTestUser(t, db, "Member", &department.ID),
+		Viewer:     createTestUser(t, db, "Viewer", &department.ID),
+	}
+}
+
+// nextFixtureID returns a unique suffix for fixture names
+func nextFixtureID() string {
+	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), atomic.AddInt64(&fixtureSeq, 1))
+}
+
+// createTestDepartment inserts a department
+func createTestDepartment(t *testing.T, db *gorm.DB) *models.Department {
+	t.Helper()
+
+	department := models.Department{Name: "Test Department " + nextFixtureID()}
+	require.NoError(t, db.Create(&department).Error)
+	return &department
+}
+
+// createTestUser inserts a user with the given role
+func createTestUser(t *testing.T, db *gorm.DB, role string, departmentID *string) *models.User {
+	t.Helper()
+
+	id := nextFixtureID()
+	user := models.User{
+		Email:        "user-" + id + "@example.com",
+		Username:     "user-" + id,
+		FullName:     "Test " + role,
+		Role:         role,
+		DepartmentID: departmentID,
+		IsActive:     true,
+	}
+	require.NoError(t, db.Create(&user).Error)
+	return &user
+}
+
+// createTestTask inserts a task
+func createTestTask(t *testing.T, db *gorm.DB, task models.Task) *models.Task {
+	t.Helper()
+
+	if task.Title == "" {
+		task.Title = "Test task " + nextFixtureID()
+	}
+	if task.Status == "" {
+		task.Status = "To Do"
+	}
+	if task.Priority == "" {
+		task.Priority = "Medium"
+	}
+	if task.Source == "" {
+		task.Source = "GUI"
+	}
+	require.NoError(t, db.Create(&task).Error)
+	return &task
+}
+
+// createTestProject inserts a project in the given department
+func createTestProject(t *testing.T, db *gorm.DB, departmentID *string) *models.Project {
+	t.Helper()
+
+	id := nextFixtureID()
+	project := models.Project{
+		ProjectID:    "PRJ-" + id,
+		Name:         "Test project " + id,
+		Status:       "Active",
+		DepartmentID: departmentID,
+	}
+	require.NoError(t, db.Create(&project).Error)
+	return &project
+}
+
+// setTestPassword stores a bcrypt hash of password for the user at the given cost
+func setTestPassword(t *testing.T, db *gorm.DB, user *models.User, password string, cost int) {
+	t.Helper()
+
+	hash, err := utils.HashPasswordWithCost(password, cost)
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("password_hash", hash).Error)
+}
@@ -0,0 +1,133 @@
+// ABOUTME: Tests for the JWKS endpoint other services verify access tokens with
+// ABOUTME: Checks the published key verifies minted tokens and that rotation keeps old keys valid
+
+package tests
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+)
+
+// fetchJWKS serves GET /.well-known/jwks.json with the current environment's keys
+func fetchJWKS(t *testing.T) utils.JWKSet {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", handlers.NewAuthHandler(nil).JWKS)
+
+	req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age=")
+
+	var set utils.JWKSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	return set
+}
+
+// jwkPublicKey rebuilds an RSA public key from its JWK form
+func jwkPublicKey(t *testing.T, key utils.JWK) *rsa.PublicKey {
+	t.Helper()
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	require.NoError(t, err)
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	require.NoError(t, err)
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+}
+
+func setRS256Env(t *testing.T, privatePEM string) {
+	t.Setenv("JWT_SECRET", strings.Repeat("s", config.MinJWTSecretLength))
+	t.Setenv("JWT_ALG", "RS256")
+	t.Setenv("JWT_PRIVATE_KEY", privatePEM)
+}
+
+func TestJWKS_PublishesKeyThatVerifiesTokens(t *testing.T) {
+	privatePEM, _ := testRSAKeyPEM(t)
+	setRS256Env(t, privatePEM)
+
+	keys, err := utils.LoadJWTKeys(config.GetConfig())
+	require.NoError(t, err)
+	token, err := utils.GenerateJWT(&models.User{ID: "user-1", Role: "Member"}, keys, 1)
+	require.NoError(t, err)
+
+	set := fetchJWKS(t)
+	require.Len(t, set.Keys, 1)
+	published := set.Keys[0]
+	assert.Equal(t, "RSA", published.Kty)
+	assert.Equal(t, "sig", published.Use)
+	assert.Equal(t, "RS256", published.Alg)
+
+	// A consumer picks the key by the token's kid and verifies with it alone
+	parsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		assert.Equal(t, published.Kid, token.Header["kid"])
+		return jwkPublicKey(t, published), nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	assert.True(t, parsed.Valid)
+}
+
+func TestJWKS_RotationKeepsPreviousKey(t *testing.T) {
+	oldPrivatePEM, oldPublicPEM := testRSAKeyPEM(t)
+	setRS256Env(t, oldPrivatePEM)
+	oldKeys, err := utils.LoadJWTKeys(config.GetConfig())
+	require.NoError(t, err)
+	oldToken, err := utils.GenerateJWT(&models.User{ID: "user-1", Role: "Member"}, oldKeys, 1)
+	require.NoError(t, err)
+
+	// Rotate: a new signing key, with the old public key kept for the overlap
+	newPrivatePEM, _ := testRSAKeyPEM(t)
+	setRS256Env(t, newPrivatePEM)
+	t.Setenv("JWT_PREVIOUS_PUBLIC_KEYS", oldPublicPEM)
+	cfg := config.GetConfig()
+	cfg.DatabaseURL = "postgres://localhost/synapse"
+	require.NoError(t, cfg.Validate())
+	newKeys, err := utils.LoadJWTKeys(cfg)
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateJWT(oldToken, newKeys)
+	require.NoError(t, err, "tokens signed before the rotation still verify")
+	assert.Equal(t, "user-1", claims.UserID)
+
+	newToken, err := utils.GenerateJWT(&models.User{ID: "user-2", Role: "Member"}, newKeys, 1)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+
+	set := fetchJWKS(t)
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, parsed.Header["kid"], set.Keys[0].Kid, "the signing key is listed first")
+	oldParsed, _, err := jwt.NewParser().ParseUnverified(oldToken, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, oldParsed.Header["kid"], set.Keys[1].Kid)
+
+	// Once the old key is dropped, its tokens stop verifying
+	t.Setenv("JWT_PREVIOUS_PUBLIC_KEYS", "")
+	keys, err := utils.LoadJWTKeys(config.GetConfig())
+	require.NoError(t, err)
+	_, err = utils.ValidateJWT(oldToken, keys)
+	assert.Error(t, err)
+}
+
+func TestJWKS_EmptyUnderHS256(t *testing.T) {
+	t.Setenv("JWT_SECRET", strings.Repeat("s", config.MinJWTSecretLength))
+	t.Setenv("JWT_ALG", "HS256")
+
+	set := fetchJWKS(t)
+	assert.Empty(t, set.Keys)
+}
@@ -0,0 +1,157 @@
+// ABOUTME: Tests for the configurable JWT signing algorithm
+// ABOUTME: Covers RS256 signing and verification, key configuration and algorithm confusion
+
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+)
+
+// testRSAKeyPEM generates a 2048-bit key pair, PEM encoded
+func testRSAKeyPEM(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	public := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	return string(private), string(public)
+}
+
+func rs256Config(privatePEM, publicPEM string) *config.Config {
+	cfg := validConfig()
+	cfg.JWTAlg = config.JWTAlgRS256
+	cfg.JWTPrivateKey = privatePEM
+	cfg.JWTPublicKey = publicPEM
+	return cfg
+}
+
+func TestJWT_RS256SignAndVerify(t *testing.T) {
+	privatePEM, publicPEM := testRSAKeyPEM(t)
+	keys, err := utils.LoadJWTKeys(rs256Config(privatePEM, ""))
+	require.NoError(t, err)
+
+	user := &models.User{ID: "user-1", Email: "rs@example.com", Role: "Member"}
+	token, err := utils.GenerateSessionJWT(user, keys, 1, "session-1")
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Header["alg"])
+
+	claims, err := utils.ValidateJWT(token, keys)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, "session-1", claims.SessionID)
+
+	// A verifier holding only the public key accepts the token but can't mint one
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(publicPEM))
+	require.NoError(t, err)
+	verifier := utils.RSAKeys(nil, publicKey)
+	_, err = utils.ValidateJWT(token, verifier)
+	assert.NoError(t, err)
+	_, err = utils.GenerateJWT(user, verifier, 1)
+	assert.Error(t, err)
+
+	// Tokens from another key pair are rejected
+	otherPrivatePEM, _ := testRSAKeyPEM(t)
+	otherKeys, err := utils.LoadJWTKeys(rs256Config(otherPrivatePEM, ""))
+	require.NoError(t, err)
+	_, err = utils.ValidateJWT(token, otherKeys)
+	assert.Error(t, err)
+}
+
+func TestJWT_RejectsAlgorithmConfusion(t *testing.T) {
+	privatePEM, publicPEM := testRSAKeyPEM(t)
+	rsKeys, err := utils.LoadJWTKeys(rs256Config(privatePEM, publicPEM))
+	require.NoError(t, err)
+
+	// An HS256 token keyed with the public key, which verifiers hand out freely
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, utils.JWTClaims{
+		UserID: "user-1",
+		Role:   "Admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	signed, err := forged.SignedString([]byte(publicPEM))
+	require.NoError(t, err)
+	_, err = utils.ValidateJWT(signed, rsKeys)
+	assert.Error(t, err)
+
+	// And the reverse: HS256 keys don't accept RS256 tokens
+	token, err := utils.GenerateJWT(&models.User{ID: "user-1", Role: "Member"}, rsKeys, 1)
+	require.NoError(t, err)
+	_, err = utils.ValidateJWT(token, utils.HMACKeys(strings.Repeat("s", 32)))
+	assert.Error(t, err)
+}
+
+func TestConfigValidate_JWTAlg(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTAlg = "none"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "JWT_ALG must be HS256 or RS256", err.Error())
+
+	err = rs256Config("", "").Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_PRIVATE_KEY")
+
+	err = rs256Config("not a key", "").Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a PEM encoded RSA private key")
+
+	privatePEM, publicPEM := testRSAKeyPEM(t)
+	_, otherPublicPEM := testRSAKeyPEM(t)
+	err = rs256Config(privatePEM, otherPublicPEM).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match")
+
+	assert.NoError(t, rs256Config(privatePEM, publicPEM).Validate())
+	// Env files often hold PEM keys on one line with \n escapes
+	assert.NoError(t, rs256Config(strings.ReplaceAll(privatePEM, "\n", `\n`), "").Validate())
+}
+
+func TestConfig_JWTKeysFromFiles(t *testing.T) {
+	privatePEM, publicPEM := testRSAKeyPEM(t)
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "jwt.key")
+	publicPath := filepath.Join(dir, "jwt.pub")
+	require.NoError(t, os.WriteFile(privatePath, []byte(privatePEM), 0o600))
+	require.NoError(t, os.WriteFile(publicPath, []byte(publicPEM), 0o644))
+
+	t.Setenv("DATABASE_URL", "postgres://localhost/synapse")
+	t.Setenv("JWT_SECRET", strings.Repeat("s", config.MinJWTSecretLength))
+	t.Setenv("JWT_ALG", "RS256")
+	t.Setenv("JWT_PRIVATE_KEY_PATH", privatePath)
+	t.Setenv("JWT_PUBLIC_KEY_PATH", publicPath)
+	cfg := config.GetConfig()
+	require.NoError(t, cfg.Validate())
+
+	keys, err := utils.LoadJWTKeys(cfg)
+	require.NoError(t, err)
+	token, err := utils.GenerateJWT(&models.User{ID: "user-1", Role: "Member"}, keys, 1)
+	require.NoError(t, err)
+	_, err = utils.ValidateJWT(token, keys)
+	assert.NoError(t, err)
+
+	cfg.JWTPrivateKey = privatePEM
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "set only one of JWT_PRIVATE_KEY and JWT_PRIVATE_KEY_PATH")
+}
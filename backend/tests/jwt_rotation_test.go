@@ -0,0 +1,67 @@
+// ABOUTME: Tests for rotating the HS256 JWT secret
+// ABOUTME: Tokens signed with a previous secret verify during the overlap; unknown secrets never do
+
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+)
+
+func TestJWTSecretRotation_PreviousSecretStillVerifies(t *testing.T) {
+	oldSecret := strings.Repeat("o", config.MinJWTSecretLength)
+	newSecret := strings.Repeat("n", config.MinJWTSecretLength)
+	user := &models.User{ID: "user-1", Role: "Member"}
+
+	oldToken, err := utils.GenerateJWT(user, utils.HMACKeys(oldSecret), 1)
+	require.NoError(t, err)
+	unknownToken, err := utils.GenerateJWT(user, utils.HMACKeys(strings.Repeat("u", config.MinJWTSecretLength)), 1)
+	require.NoError(t, err)
+
+	// Rotate: sign with the new secret, keep verifying with the old one
+	t.Setenv("JWT_SECRET", newSecret)
+	t.Setenv("JWT_ALG", "HS256")
+	t.Setenv("JWT_SECRET_PREVIOUS", " "+oldSecret+" , ")
+	keys, err := utils.LoadJWTKeys(config.GetConfig())
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateJWT(oldToken, keys)
+	require.NoError(t, err, "tokens signed with the previous secret verify during the overlap")
+	assert.Equal(t, "user-1", claims.UserID)
+
+	_, err = utils.ValidateJWT(unknownToken, keys)
+	assert.Error(t, err, "tokens signed with an unknown secret are rejected")
+
+	// New tokens are signed with the primary secret alone
+	newToken, err := utils.GenerateJWT(user, keys, 1)
+	require.NoError(t, err)
+	_, err = utils.ValidateJWT(newToken, utils.HMACKeys(newSecret))
+	assert.NoError(t, err)
+	_, err = utils.ValidateJWT(newToken, utils.HMACKeys(oldSecret))
+	assert.Error(t, err)
+
+	// Ending the overlap retires the old secret's tokens
+	t.Setenv("JWT_SECRET_PREVIOUS", "")
+	keys, err = utils.LoadJWTKeys(config.GetConfig())
+	require.NoError(t, err)
+	_, err = utils.ValidateJWT(oldToken, keys)
+	assert.Error(t, err)
+}
+
+func TestConfigValidate_PreviousJWTSecrets(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecretPrevious = strings.Repeat("a", config.MinJWTSecretLength) + "," + strings.Repeat("b", config.MinJWTSecretLength)
+	assert.NoError(t, cfg.Validate())
+	assert.Len(t, cfg.PreviousJWTSecrets(), 2)
+
+	cfg.JWTSecretPrevious = strings.Repeat("a", config.MinJWTSecretLength) + ",short"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET_PREVIOUS")
+}
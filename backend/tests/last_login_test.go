@@ -0,0 +1,77 @@
+// ABOUTME: Tests for last-login tracking and the inactive_since user filter
+// ABOUTME: Verifies a successful login stamps last_login and dormant users can be listed
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLogin_RecordsLastLogin(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	password := "correct horse battery"
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, password, bcrypt.MinCost)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/login", handlers.NewAuthHandler(db).Login)
+
+	before := time.Now().UTC().Add(-time.Second)
+	w := performJSON(router, "POST", "/auth/login", map[string]string{"email": user.Email, "password": password})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	returned := decodeResponse(t, w)["data"].(map[string]interface{})["user"].(map[string]interface{})
+	assert.NotEmpty(t, returned["last_login"])
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", user.ID).Error)
+	require.NotNil(t, stored.LastLogin)
+	assert.True(t, stored.LastLogin.After(before), "last_login %v should be after %v", stored.LastLogin, before)
+}
+
+func TestGetUsers_InactiveSince(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	dormant := createTestUser(t, db, "Member", &dept.ID)
+	recent := createTestUser(t, db, "Member", &dept.ID)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", dormant.ID).UpdateColumn("last_login", time.Now().AddDate(0, -6, 0)).Error)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", recent.ID).UpdateColumn("last_login", time.Now()).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users", asUser(admin), handlers.NewUserHandler(db).GetUsers)
+
+	since := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	w := performJSON(router, "GET", "/users?per_page=100&department_id="+dept.ID+"&inactive_since="+since, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	ids := []string{}
+	for _, item := range decodeResponse(t, w)["data"].([]interface{}) {
+		ids = append(ids, item.(map[string]interface{})["id"].(string))
+	}
+	assert.Equal(t, []string{dormant.ID}, ids)
+}
+
+func TestGetUsers_InvalidInactiveSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/users", withTestUser("admin-1", "Admin", nil), handlers.NewUserHandler(nil).GetUsers)
+
+	w := performJSON(router, "GET", "/users?inactive_since=last-spring", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,47 @@
+// ABOUTME: Tests for the _links added to serialized resources
+// ABOUTME: Verifies self links point at canonical API paths under PUBLIC_BASE_URL
+
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/models"
+)
+
+func TestTaskResponse_SelfLink(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository(fakeTask()))
+
+	w := performJSON(router, "GET", "/tasks/task-1", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	links := decodeResponse(t, w)["data"].(map[string]interface{})["_links"].(map[string]interface{})
+	assert.Equal(t, "/api/v1/tasks/task-1", links["self"])
+	assert.Equal(t, "/api/v1/tasks/task-1/comments", links["comments"])
+}
+
+func TestLinks_PublicBaseURL(t *testing.T) {
+	models.SetPublicBaseURL("https://api.example.com/")
+	t.Cleanup(func() { models.SetPublicBaseURL("") })
+
+	encoded, err := json.Marshal(models.Department{ID: "dept-a", Name: "Engineering"})
+	require.NoError(t, err)
+
+	var department map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &department))
+	assert.Equal(t, map[string]interface{}{
+		"self":  "https://api.example.com/api/v1/departments/dept-a",
+		"users": "https://api.example.com/api/v1/departments/dept-a/users",
+		"tasks": "https://api.example.com/api/v1/departments/dept-a/tasks",
+	}, department["_links"])
+}
+
+func TestLinks_OmittedForUnsavedResources(t *testing.T) {
+	encoded, err := json.Marshal(models.User{Email: "new@example.com"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "_links")
+}
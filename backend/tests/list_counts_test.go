@@ -0,0 +1,132 @@
+// ABOUTME: Tests for the opt-in child counts on project, department and task listings
+// ABOUTME: Counts must match the seeded rows and only appear with ?with_counts=true
+
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+// onlyListItem fetches path and returns its single listed item
+func onlyListItem(t *testing.T, router *gin.Engine, path string) map[string]interface{} {
+	t.Helper()
+	w := performJSON(router, "GET", path, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	items := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, items, 1)
+	return items[0].(map[string]interface{})
+}
+
+func TestListCounts_MatchSeededData(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	createTestUser(t, db, "Member", &dept.ID)
+	inactive := createTestUser(t, db, "Member", &dept.ID)
+	require.NoError(t, db.Model(inactive).Update("is_active", false).Error)
+
+	project := createTestProject(t, db, &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID})
+
+	for i, text := range []string{"Draft", "Review", "Publish"} {
+		require.NoError(t, db.Create(&models.ChecklistItem{TaskID: task.ID, Text: text, Position: i}).Error)
+	}
+	kept := models.Comment{TaskID: task.ID, UserID: member.ID, Content: "Looks good"}
+	deleted := models.Comment{TaskID: task.ID, UserID: member.ID, Content: "Typo"}
+	require.NoError(t, db.Create(&kept).Error)
+	require.NoError(t, db.Create(&deleted).Error)
+	require.NoError(t, db.Delete(&deleted).Error)
+
+	admin := withTestUser("admin-1", "Admin", nil)
+	router := gin.New()
+	router.GET("/projects", admin, handlers.NewProjectHandler(db).GetProjects)
+	router.GET("/departments", admin, handlers.NewDepartmentHandler(db).GetDepartments)
+	router.GET("/tasks", admin, handlers.NewTaskHandler(db).GetTasks)
+
+	// Counts are opt-in
+	item := onlyListItem(t, router, "/projects?search="+url.QueryEscape(project.Name))
+	assert.NotContains(t, item, "task_count")
+
+	item = onlyListItem(t, router, "/projects?with_counts=true&search="+url.QueryEscape(project.Name))
+	assert.Equal(t, float64(2), item["task_count"])
+
+	item = onlyListItem(t, router, "/departments?with_counts=true&search="+url.QueryEscape(dept.Name))
+	assert.Equal(t, float64(2), item["user_count"], "deactivated users aren't counted")
+	assert.Equal(t, float64(3), item["task_count"])
+
+	w := performJSON(router, "GET", "/tasks?with_counts=true&project_id="+project.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	counts := map[string][2]float64{}
+	for _, listed := range decodeResponse(t, w)["data"].([]interface{}) {
+		row := listed.(map[string]interface{})
+		counts[row["id"].(string)] = [2]float64{row["subtask_count"].(float64), row["comment_count"].(float64)}
+	}
+	require.Len(t, counts, 2)
+	assert.Equal(t, [2]float64{3, 1}, counts[task.ID], "deleted comments aren't counted")
+	for id, pair := range counts {
+		if id != task.ID {
+			assert.Equal(t, [2]float64{0, 0}, pair)
+		}
+	}
+}
+
+func TestListCounts_OnlyCountVisibleTasks(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+
+	assigned := createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID})
+	createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", assigned.ID, outsider.ID).Error)
+
+	router := gin.New()
+	router.GET("/departments", asUser(outsider), handlers.NewDepartmentHandler(db).GetDepartments)
+
+	// The outsider can only list the task they're assigned to, so that's all they see counted
+	item := onlyListItem(t, router, "/departments?with_counts=true&search="+url.QueryEscape(dept.Name))
+	assert.Equal(t, float64(1), item["task_count"])
+}
+
+func TestListCounts_ProjectCountMatchesProjectTasks(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	creator := createTestUser(t, db, "Member", &dept.ID)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+
+	project := createTestProject(t, db, &dept.ID)
+	require.NoError(t, db.Create(&models.ProjectMember{ProjectID: project.ID, UserID: outsider.ID, Role: "Contributor"}).Error)
+	createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+
+	projectHandler := handlers.NewProjectHandler(db)
+	router := gin.New()
+	router.GET("/projects", asUser(outsider), projectHandler.GetProjects)
+	router.GET("/projects/:id/tasks", asUser(outsider), projectHandler.GetProjectTasks)
+
+	// A member from another department sees the same number on the row as in the list
+	item := onlyListItem(t, router, "/projects?with_counts=true&search="+url.QueryEscape(project.Name))
+	w := performJSON(router, "GET", "/projects/"+project.ID+"/tasks", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	total := decodeResponse(t, w)["pagination"].(map[string]interface{})["total"]
+	assert.Equal(t, float64(2), total)
+	assert.Equal(t, total, item["task_count"])
+}
@@ -0,0 +1,76 @@
+// ABOUTME: Tests for weak ETags and conditional GETs on list endpoints
+// ABOUTME: Verifies unchanged lists answer 304 and changed lists send a new body and tag
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+)
+
+func getIfNoneMatch(router *gin.Engine, path, etag string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRespondPaginatedWithETag_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	items := []string{"a", "b"}
+	router := gin.New()
+	router.GET("/items", func(c *gin.Context) {
+		utils.RespondPaginatedWithETag(c, items, 1, 20, int64(len(items)), nil)
+	})
+
+	first := getIfNoneMatch(router, "/items", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, []interface{}{"a", "b"}, decodeResponse(t, first)["data"])
+
+	second := getIfNoneMatch(router, "/items", etag)
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.Bytes())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+
+	// A changed list gets a new body and tag
+	items = append(items, "c")
+	third := getIfNoneMatch(router, "/items", `"stale", `+etag)
+	require.Equal(t, http.StatusOK, third.Code)
+	assert.NotEqual(t, etag, third.Header().Get("ETag"))
+}
+
+func TestGetTasks_ConditionalGet(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID})
+
+	router := gin.New()
+	router.GET("/tasks", asUser(admin), handlers.NewTaskHandler(db).GetTasks)
+	path := "/tasks?department_id=" + dept.ID
+
+	first := getIfNoneMatch(router, path, "")
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	assert.Equal(t, http.StatusNotModified, getIfNoneMatch(router, path, etag).Code)
+
+	require.NoError(t, db.Model(&models.Task{}).Where("id = ?", task.ID).Update("title", "Renamed").Error)
+	assert.Equal(t, http.StatusOK, getIfNoneMatch(router, path, etag).Code)
+}
@@ -0,0 +1,61 @@
+// ABOUTME: Tests for logging in by email or username
+// ABOUTME: Verifies both identifiers work and unknown identifiers get the generic credentials error
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func setupLoginRouter(t *testing.T, db *gorm.DB) *gin.Engine {
+	t.Setenv("JWT_SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/auth/login", handlers.NewAuthHandler(db).Login)
+	return router
+}
+
+func TestLogin_ByUsernameOrEmail(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupLoginRouter(t, db)
+
+	password := "correct horse battery"
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, password, bcrypt.MinCost)
+
+	for _, body := range []map[string]string{
+		{"identifier": user.Username, "password": password},
+		{"identifier": user.Email, "password": password},
+		{"email": user.Email, "password": password},
+	} {
+		w := performJSON(router, "POST", "/auth/login", body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		returned := decodeResponse(t, w)["data"].(map[string]interface{})["user"].(map[string]interface{})
+		assert.Equal(t, user.ID, returned["id"])
+	}
+}
+
+func TestLogin_UnknownIdentifier(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupLoginRouter(t, db)
+
+	w := performJSON(router, "POST", "/auth/login", map[string]string{"identifier": "nobody-" + nextFixtureID(), "password": "correct horse battery"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "INVALID_CREDENTIALS", decodeResponse(t, w)["error"].(map[string]interface{})["code"])
+}
+
+func TestLogin_RequiresIdentifier(t *testing.T) {
+	router := setupLoginRouter(t, nil)
+
+	w := performJSON(router, "POST", "/auth/login", map[string]string{"password": "correct horse battery"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
@@ -0,0 +1,59 @@
+// ABOUTME: Tests for the navigation bar badge counts endpoint
+// ABOUTME: Verifies open, overdue, due-today and unread notification counts for the caller only
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestGetMeCounts_CountsCallersAssignedWork(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	user := createTestUser(t, db, "Member", nil)
+	other := createTestUser(t, db, "Member", nil)
+
+	now := time.Now().UTC()
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	laterToday := now.Add(endOfDay.Sub(now) / 2)
+	yesterday := now.AddDate(0, 0, -1)
+
+	assign := func(task *models.Task, assignee *models.User) {
+		require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assignee.ID).Error)
+	}
+	assign(createTestTask(t, db, models.Task{CreatorID: other.ID}), user)
+	assign(createTestTask(t, db, models.Task{CreatorID: other.ID, DueDate: &yesterday}), user)
+	assign(createTestTask(t, db, models.Task{CreatorID: other.ID, DueDate: &laterToday}), user)
+	// Done, someone else's, and merely created tasks don't count
+	assign(createTestTask(t, db, models.Task{CreatorID: other.ID, DueDate: &yesterday, Status: "Done"}), user)
+	assign(createTestTask(t, db, models.Task{CreatorID: user.ID, DueDate: &yesterday}), other)
+	createTestTask(t, db, models.Task{CreatorID: user.ID, DueDate: &laterToday})
+
+	readAt := now
+	require.NoError(t, db.Create(&[]models.Notification{
+		{UserID: user.ID, Type: "mention", Message: "unread one"},
+		{UserID: user.ID, Type: "mention", Message: "unread two"},
+		{UserID: user.ID, Type: "mention", Message: "read", ReadAt: &readAt},
+		{UserID: other.ID, Type: "mention", Message: "not mine"},
+	}).Error)
+
+	router := gin.New()
+	router.GET("/me/counts", asUser(user), handlers.NewMeHandler(db).GetCounts)
+
+	w := performJSON(router, "GET", "/me/counts", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, float64(3), data["assigned_open"])
+	assert.Equal(t, float64(1), data["overdue"])
+	assert.Equal(t, float64(1), data["due_today"])
+	assert.Equal(t, float64(2), data["unread_notifications"])
+}
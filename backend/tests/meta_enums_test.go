@@ -0,0 +1,54 @@
+// ABOUTME: Tests for the enum metadata endpoint clients build dropdowns from
+// ABOUTME: The endpoint must list exactly the values request validation accepts
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/handlers"
+)
+
+func stringList(t *testing.T, value interface{}) []string {
+	t.Helper()
+	items, ok := value.([]interface{})
+	require.True(t, ok, "expected a list, got %v", value)
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = item.(string)
+	}
+	return result
+}
+
+func TestGetEnums_ListsServerValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/meta/enums", withTestUser("viewer-1", "Viewer", nil), handlers.NewMetaHandler().GetEnums)
+
+	w := performJSON(router, "GET", "/meta/enums", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+
+	assert.Equal(t, []string{"To Do", "In Progress", "In Review", "Blocked", "Done"}, stringList(t, data["task_statuses"]))
+	assert.Equal(t, []string{"Low", "Medium", "High", "Urgent"}, stringList(t, data["task_priorities"]))
+	assert.Equal(t, []string{"GUI", "Email", "API", "Document", "NLP"}, stringList(t, data["task_sources"]))
+	assert.Equal(t, []string{"Active", "On Hold", "Completed", "Archived"}, stringList(t, data["project_statuses"]))
+
+	// The same lists validation is built from
+	assert.Equal(t, handlers.TaskPriorities, stringList(t, data["task_priorities"]))
+	assert.Equal(t, handlers.TaskSources, stringList(t, data["task_sources"]))
+	assert.Equal(t, handlers.ProjectStatuses, stringList(t, data["project_statuses"]))
+	assert.Equal(t, auth.Roles(), stringList(t, data["roles"]))
+
+	transitions := data["status_transitions"].(map[string]interface{})
+	require.Len(t, transitions, len(config.DefaultTaskStatusTransitions))
+	for status, allowed := range config.DefaultTaskStatusTransitions {
+		assert.ElementsMatch(t, allowed, stringList(t, transitions[status]), status)
+	}
+}
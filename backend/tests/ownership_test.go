@@ -0,0 +1,98 @@
+// ABOUTME: Tests for task creator and project owner transfers
+// ABOUTME: Verifies the Admin-only creator change and activity log entries
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// ownershipActivity returns the ownership transfer entries recorded for an entity
+func ownershipActivity(t *testing.T, db *gorm.DB, entityID string) []models.ActivityLog {
+	t.Helper()
+
+	var entries []models.ActivityLog
+	require.NoError(t, db.Where("entity_id = ? AND action = ?", entityID, "ownership_transferred").Find(&entries).Error)
+	return entries
+}
+
+func TestUpdateTask_AdminCanChangeCreator(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	creator := createTestUser(t, db, "Member", &dept.ID)
+	newCreator := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &dept.ID})
+
+	router := gin.New()
+	router.PUT("/tasks/:id", asUser(admin), handlers.NewTaskHandler(db).UpdateTask)
+
+	w := sendWithIfMatch(router, "PUT", "/tasks/"+task.ID, `{"creator_id": "`+newCreator.ID+`"}`, `"1"`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, newCreator.ID, decodeResponse(t, w)["data"].(map[string]interface{})["creator_id"])
+
+	entries := ownershipActivity(t, db, task.ID)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "task", entries[0].EntityType)
+	require.NotNil(t, entries[0].ActorID)
+	assert.Equal(t, admin.ID, *entries[0].ActorID)
+}
+
+func TestUpdateTask_ManagerCannotChangeCreator(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	creator := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &dept.ID})
+
+	router := gin.New()
+	router.PUT("/tasks/:id", asUser(manager), handlers.NewTaskHandler(db).UpdateTask)
+
+	w := sendWithIfMatch(router, "PUT", "/tasks/"+task.ID, `{"creator_id": "`+manager.ID+`"}`, `"1"`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var stored models.Task
+	require.NoError(t, db.First(&stored, "id = ?", task.ID).Error)
+	assert.Equal(t, creator.ID, stored.CreatorID)
+	assert.Empty(t, ownershipActivity(t, db, task.ID))
+}
+
+func TestUpdateProject_OwnerTransferIsRecorded(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	colleague := createTestUser(t, db, "Member", &dept.ID)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+	project := createTestProject(t, db, &dept.ID)
+
+	router := gin.New()
+	router.PUT("/projects/:id", asUser(manager), handlers.NewProjectHandler(db).UpdateProject)
+
+	// Managers can't hand the project to another department
+	w := performJSON(router, "PUT", "/projects/"+project.ID, map[string]string{"owner_id": outsider.ID})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = performJSON(router, "PUT", "/projects/"+project.ID, map[string]string{"owner_id": colleague.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	entries := ownershipActivity(t, db, project.ID)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "project", entries[0].EntityType)
+	require.NotNil(t, entries[0].Details)
+	assert.Contains(t, *entries[0].Details, colleague.ID)
+}
@@ -0,0 +1,136 @@
+// ABOUTME: Tests for shared pagination parsing and paginated responses
+// ABOUTME: Verifies beyond-last-page results, has_next/has_prev and per_page clamping
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/utils"
+)
+
+// setupPaginationRouter pages through total integers the way list handlers page through rows
+func setupPaginationRouter(total int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/items", func(c *gin.Context) {
+		page, perPage := utils.ParsePagination(c)
+
+		var items []int
+		for i := (page - 1) * perPage; i < total && len(items) < perPage; i++ {
+			items = append(items, i)
+		}
+		utils.RespondSuccessWithPagination(c, items, page, perPage, int64(total))
+	})
+	return router
+}
+
+func TestPagination_BeyondLastPage(t *testing.T) {
+	router := setupPaginationRouter(25)
+
+	w := performJSON(router, "GET", "/items?page=5&per_page=10", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	response := decodeResponse(t, w)
+	assert.Equal(t, []interface{}{}, response["data"])
+
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(5), pagination["page"])
+	assert.Equal(t, float64(10), pagination["per_page"])
+	assert.Equal(t, float64(25), pagination["total"])
+	assert.Equal(t, float64(3), pagination["total_pages"])
+	assert.Equal(t, false, pagination["has_next"])
+	assert.Equal(t, true, pagination["has_prev"])
+}
+
+func TestPagination_HasNextAndPrev(t *testing.T) {
+	router := setupPaginationRouter(25)
+
+	response := decodeResponse(t, performJSON(router, "GET", "/items?page=1&per_page=10", nil))
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, true, pagination["has_next"])
+	assert.Equal(t, false, pagination["has_prev"])
+	assert.Len(t, response["data"], 10)
+
+	response = decodeResponse(t, performJSON(router, "GET", "/items?page=3&per_page=10", nil))
+	pagination = response["pagination"].(map[string]interface{})
+	assert.Equal(t, false, pagination["has_next"])
+	assert.Equal(t, true, pagination["has_prev"])
+	assert.Len(t, response["data"], 5)
+}
+
+func TestPagination_PerPageClamped(t *testing.T) {
+	router := setupPaginationRouter(250)
+
+	w := performJSON(router, "GET", "/items?per_page=500", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "100", w.Header().Get("X-Per-Page-Clamped"))
+
+	response := decodeResponse(t, w)
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(100), pagination["per_page"])
+	assert.Equal(t, "500", pagination["requested_per_page"])
+	assert.Len(t, response["data"], 100)
+
+	// Invalid values fall back to the default
+	w = performJSON(router, "GET", "/items?per_page=0", nil)
+	assert.Equal(t, "20", w.Header().Get("X-Per-Page-Clamped"))
+	pagination = decodeResponse(t, w)["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(20), pagination["per_page"])
+	assert.Equal(t, "0", pagination["requested_per_page"])
+}
+
+func TestPagination_InRangePerPageNotFlagged(t *testing.T) {
+	router := setupPaginationRouter(250)
+
+	w := performJSON(router, "GET", "/items?per_page=50", nil)
+	assert.Empty(t, w.Header().Get("X-Per-Page-Clamped"))
+
+	pagination := decodeResponse(t, w)["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(50), pagination["per_page"])
+	assert.NotContains(t, pagination, "requested_per_page")
+}
+
+func TestParsePagination_ConfiguredLimits(t *testing.T) {
+	t.Setenv("PAGINATION_DEFAULT", "50")
+	t.Setenv("PAGINATION_MAX", "500")
+	router := setupPaginationRouter(1000)
+
+	// Default applies when per_page is missing
+	w := performJSON(router, "GET", "/items", nil)
+	pagination := decodeResponse(t, w)["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(50), pagination["per_page"])
+	assert.Empty(t, w.Header().Get("X-Per-Page-Clamped"))
+
+	// Larger pages are allowed up to the configured max
+	pagination = decodeResponse(t, performJSON(router, "GET", "/items?per_page=300", nil))["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(300), pagination["per_page"])
+
+	// Over the max is clamped
+	w = performJSON(router, "GET", "/items?per_page=900", nil)
+	assert.Equal(t, "500", w.Header().Get("X-Per-Page-Clamped"))
+	pagination = decodeResponse(t, w)["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(500), pagination["per_page"])
+
+	// Negative per_page and page fall back to the default and the first page
+	w = performJSON(router, "GET", "/items?per_page=-5&page=-2", nil)
+	assert.Equal(t, "50", w.Header().Get("X-Per-Page-Clamped"))
+	pagination = decodeResponse(t, w)["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(50), pagination["per_page"])
+	assert.Equal(t, float64(1), pagination["page"])
+	assert.Equal(t, "-5", pagination["requested_per_page"])
+}
+
+func TestParsePagination_InvalidConfigFallsBack(t *testing.T) {
+	t.Setenv("PAGINATION_DEFAULT", "200")
+	t.Setenv("PAGINATION_MAX", "100")
+
+	defaultPerPage, maxPerPage := utils.PaginationLimits()
+	assert.Equal(t, 20, defaultPerPage)
+	assert.Equal(t, 100, maxPerPage)
+}
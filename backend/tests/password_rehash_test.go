@@ -0,0 +1,65 @@
+// ABOUTME: Tests for configurable bcrypt cost and upgrading hashes on login
+// ABOUTME: Verifies low-cost hashes are rehashed at the configured cost and still verify
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	hash, err := utils.HashPasswordWithCost("correct horse battery", bcrypt.MinCost)
+	require.NoError(t, err)
+
+	assert.True(t, utils.PasswordNeedsRehash(hash, bcrypt.MinCost+1))
+	assert.False(t, utils.PasswordNeedsRehash(hash, bcrypt.MinCost))
+	assert.NoError(t, utils.VerifyPassword(hash, "correct horse battery"))
+}
+
+func TestHashPasswordWithCost_InvalidCostUsesDefault(t *testing.T) {
+	hash, err := utils.HashPasswordWithCost("correct horse battery", 0)
+	require.NoError(t, err)
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	require.NoError(t, err)
+	assert.Equal(t, utils.DefaultCost, cost)
+}
+
+func TestLogin_UpgradesLowCostHash(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("BCRYPT_COST", "5")
+
+	password := "correct horse battery"
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, password, bcrypt.MinCost)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/login", handlers.NewAuthHandler(db).Login)
+
+	login := map[string]string{"email": user.Email, "password": password}
+	w := performJSON(router, "POST", "/auth/login", login)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored models.User
+	require.NoError(t, db.Select("*").First(&stored, "id = ?", user.ID).Error)
+	require.NotNil(t, stored.PasswordHash)
+	cost, err := bcrypt.Cost([]byte(*stored.PasswordHash))
+	require.NoError(t, err)
+	assert.Equal(t, 5, cost)
+	assert.NoError(t, utils.VerifyPassword(*stored.PasswordHash, password))
+
+	// The upgraded hash still logs in
+	w = performJSON(router, "POST", "/auth/login", login)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -0,0 +1,66 @@
+// ABOUTME: Tests for filtering projects by the dates they are active
+// ABOUTME: Verifies overlap with ?active_from=/?active_to=, open-ended dates and bad input
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestGetProjects_ActiveInRange(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	date := func(value string) *time.Time {
+		parsed, err := time.Parse("2006-01-02", value)
+		require.NoError(t, err)
+		return &parsed
+	}
+	newProject := func(start, end *time.Time) string {
+		project := createTestProject(t, db, &dept.ID)
+		require.NoError(t, db.Model(&models.Project{}).Where("id = ?", project.ID).
+			Updates(map[string]interface{}{"start_date": start, "end_date": end}).Error)
+		return project.ProjectID
+	}
+
+	contained := newProject(date("2026-04-15"), date("2026-05-15"))
+	overlapsStart := newProject(date("2026-03-01"), date("2026-04-10"))
+	startsOnLastDay := newProject(date("2026-06-30"), date("2026-08-01"))
+	openEnded := newProject(date("2026-01-01"), nil)
+	before := newProject(date("2026-01-01"), date("2026-03-31"))
+	after := newProject(date("2026-07-01"), nil)
+
+	router := gin.New()
+	router.GET("/projects", asUser(admin), handlers.NewProjectHandler(db).GetProjects)
+
+	codes := projectCodes(t, router, "/projects?department_id="+dept.ID+"&active_from=2026-04-01&active_to=2026-06-30")
+	assert.ElementsMatch(t, []string{contained, overlapsStart, startsOnLastDay, openEnded}, codes)
+	assert.NotContains(t, codes, before)
+	assert.NotContains(t, codes, after)
+
+	// Either bound alone is open on the other side
+	codes = projectCodes(t, router, "/projects?department_id="+dept.ID+"&active_from=2026-07-01")
+	assert.ElementsMatch(t, []string{startsOnLastDay, openEnded, after}, codes)
+}
+
+func TestGetProjects_ActiveRangeValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/projects", withTestUser("admin-1", "Admin", nil), handlers.NewProjectHandler(nil).GetProjects)
+
+	for _, query := range []string{"active_from=Q2", "active_to=2026-13-01", "active_from=2026-06-30&active_to=2026-04-01"} {
+		w := performJSON(router, "GET", "/projects?"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)), query)
+	}
+}
@@ -0,0 +1,100 @@
+// ABOUTME: Tests for bulk project status updates
+// ABOUTME: Verifies permitted projects change, forbidden and hidden ones are left alone, and bad input
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestBulkUpdateProjectStatus_MixedPermissions(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	own := createTestProject(t, db, &dept.ID)
+	alsoOwn := createTestProject(t, db, &dept.ID)
+	memberOf := createTestProject(t, db, &otherDept.ID)
+	hidden := createTestProject(t, db, &otherDept.ID)
+	require.NoError(t, db.Create(&models.ProjectMember{ProjectID: memberOf.ID, UserID: manager.ID, Role: "Contributor"}).Error)
+
+	projectHandler := handlers.NewProjectHandler(db)
+	router := gin.New()
+	router.Use(asUser(manager))
+	router.PATCH("/projects/bulk/status", projectHandler.BulkUpdateProjectStatus)
+	router.PATCH("/projects/:id", projectHandler.PatchProject)
+
+	w := performJSON(router, "PATCH", "/projects/bulk/status", map[string]interface{}{
+		"ids":    []string{own.ID, memberOf.ID, hidden.ID, alsoOwn.ID, own.ID},
+		"status": "Archived",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{own.ID, alsoOwn.ID}, data["updated"])
+	assert.Equal(t, []interface{}{memberOf.ID}, data["forbidden"])
+	assert.Equal(t, []interface{}{hidden.ID}, data["not_found"])
+	assert.Equal(t, float64(2), data["updated_count"])
+	assert.Equal(t, float64(2), data["skipped_count"])
+
+	statuses := map[string]string{}
+	var projects []models.Project
+	require.NoError(t, db.Where("id IN ?", []string{own.ID, alsoOwn.ID, memberOf.ID, hidden.ID}).Find(&projects).Error)
+	for _, project := range projects {
+		statuses[project.ID] = project.Status
+	}
+	assert.Equal(t, map[string]string{own.ID: "Archived", alsoOwn.ID: "Archived", memberOf.ID: "Active", hidden.ID: "Active"}, statuses)
+}
+
+func TestBulkUpdateProjectStatus_MemberUpdatesNothing(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	project := createTestProject(t, db, &dept.ID)
+
+	router := gin.New()
+	router.PATCH("/projects/bulk/status", asUser(member), handlers.NewProjectHandler(db).BulkUpdateProjectStatus)
+
+	w := performJSON(router, "PATCH", "/projects/bulk/status", map[string]interface{}{"ids": []string{project.ID}, "status": "Completed"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []interface{}{project.ID}, decodeResponse(t, w)["data"].(map[string]interface{})["forbidden"])
+
+	var reloaded models.Project
+	require.NoError(t, db.First(&reloaded, "id = ?", project.ID).Error)
+	assert.Equal(t, "Active", reloaded.Status)
+}
+
+func TestBulkUpdateProjectStatus_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("/projects/bulk/status", withTestUser("admin-1", "Admin", nil), handlers.NewProjectHandler(nil).BulkUpdateProjectStatus)
+
+	id := "00000000-0000-0000-0000-000000000001"
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = id
+	}
+	bodies := []map[string]interface{}{
+		{"ids": []string{}, "status": "Archived"},
+		{"ids": []string{"not-a-uuid"}, "status": "Archived"},
+		{"ids": []string{id}},
+		{"ids": []string{id}, "status": "Closed"},
+		{"ids": tooMany, "status": "Archived"},
+	}
+	for _, body := range bodies {
+		w := performJSON(router, "PATCH", "/projects/bulk/status", body)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)), body)
+	}
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Tests for cloning a project with its task skeleton
+// ABOUTME: Verifies the copied project, fresh To Do tasks and Manager/Admin restrictions
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestCloneProject_WithTasks(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	source := createTestProject(t, db, &dept.ID)
+	require.NoError(t, db.Model(source).Update("status", "Completed").Error)
+
+	due := time.Now().Add(48 * time.Hour)
+	first := createTestTask(t, db, models.Task{
+		Title: "Kickoff", Status: "Done", Priority: "High", CreatorID: member.ID,
+		DepartmentID: &dept.ID, ProjectID: &source.ID, DueDate: &due, Tags: pq.StringArray{"planning"},
+	})
+	createTestTask(t, db, models.Task{Title: "Retro", CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &source.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", first.ID, member.ID).Error)
+
+	router := gin.New()
+	router.POST("/projects/:id/clone", asUser(manager), handlers.NewProjectHandler(db).CloneProject)
+
+	w := performJSON(router, "POST", "/projects/"+source.ID+"/clone?with_tasks=true", nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["tasks_cloned"])
+
+	project := data["project"].(map[string]interface{})
+	assert.NotEqual(t, source.ID, project["id"])
+	assert.Equal(t, "Copy of "+source.Name, project["name"])
+	assert.Equal(t, source.ProjectID+"-COPY", project["project_id"])
+	assert.Equal(t, "Active", project["status"])
+	assert.Equal(t, manager.ID, project["owner_id"])
+
+	var tasks []models.Task
+	require.NoError(t, db.Where("project_id = ?", project["id"]).Order("created_at ASC").Find(&tasks).Error)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Kickoff", tasks[0].Title)
+	assert.Equal(t, "To Do", tasks[0].Status)
+	assert.Equal(t, "High", tasks[0].Priority)
+	assert.Equal(t, pq.StringArray{"planning"}, tasks[0].Tags)
+	assert.Nil(t, tasks[0].DueDate)
+	assert.Equal(t, manager.ID, tasks[0].CreatorID)
+
+	var assigned int64
+	require.NoError(t, db.Table("task_assignees").Where("task_id = ?", tasks[0].ID).Count(&assigned).Error)
+	assert.Zero(t, assigned)
+
+	// A second clone gets the next free code and no tasks without the flag
+	w = performJSON(router, "POST", "/projects/"+source.ID+"/clone", nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	data = decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, source.ProjectID+"-COPY-2", data["project"].(map[string]interface{})["project_id"])
+	assert.Equal(t, float64(0), data["tasks_cloned"])
+}
+
+func TestCloneProject_ManagersLimitedToOwnDepartment(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	outsideManager := createTestUser(t, db, "Manager", &otherDept.ID)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	source := createTestProject(t, db, &dept.ID)
+
+	handler := handlers.NewProjectHandler(db)
+	for _, caller := range []*models.User{outsideManager, member} {
+		router := gin.New()
+		router.POST("/projects/:id/clone", asUser(caller), handler.CloneProject)
+
+		w := performJSON(router, "POST", "/projects/"+source.ID+"/clone", nil)
+		assert.Equal(t, http.StatusForbidden, w.Code, caller.Role)
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.Project{}).Where("department_id = ?", dept.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,63 @@
+// ABOUTME: Tests for generated project codes and finding projects by code
+// ABOUTME: Verifies new projects get a PROJ- code and that search and ?code= match it
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+)
+
+// projectCodes lists projects at path and returns their codes
+func projectCodes(t *testing.T, router *gin.Engine, path string) []string {
+	t.Helper()
+
+	w := performJSON(router, "GET", path, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	codes := []string{}
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		codes = append(codes, raw.(map[string]interface{})["project_id"].(string))
+	}
+	return codes
+}
+
+func TestCreateProject_GeneratesCode(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	router := gin.New()
+	router.POST("/projects", asUser(admin), handlers.NewProjectHandler(db).CreateProject)
+
+	w := performJSON(router, "POST", "/projects", map[string]string{"name": "Launch"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	first := decodeResponse(t, w)["data"].(map[string]interface{})["project_id"].(string)
+	assert.Regexp(t, `^PROJ-\d{4,}$`, first)
+
+	w = performJSON(router, "POST", "/projects", map[string]string{"name": "Follow-up"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.NotEqual(t, first, decodeResponse(t, w)["data"].(map[string]interface{})["project_id"])
+}
+
+func TestGetProjects_FindsByCode(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	target := createTestProject(t, db, nil)
+	other := createTestProject(t, db, nil)
+	router := gin.New()
+	router.GET("/projects", asUser(admin), handlers.NewProjectHandler(db).GetProjects)
+
+	// Search matches codes as well as names and descriptions
+	assert.Equal(t, []string{target.ProjectID}, projectCodes(t, router, "/projects?search="+target.ProjectID))
+
+	assert.Equal(t, []string{other.ProjectID}, projectCodes(t, router, "/projects?code="+other.ProjectID))
+	assert.Empty(t, projectCodes(t, router, "/projects?code="+other.ProjectID[:4]))
+}
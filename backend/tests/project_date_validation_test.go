@@ -0,0 +1,90 @@
+// ABOUTME: Tests for per-field errors from project date validation
+// ABOUTME: Verifies malformed and out-of-order dates name start_date or end_date in the details
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+)
+
+// errorDetailFields returns the field of every detail in an error response
+func errorDetailFields(t *testing.T, response map[string]interface{}) []string {
+	t.Helper()
+	details, _ := response["error"].(map[string]interface{})["details"].([]interface{})
+	fields := []string{}
+	for _, detail := range details {
+		fields = append(fields, detail.(map[string]interface{})["field"].(string))
+	}
+	return fields
+}
+
+func TestProjectDates_FieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	projectHandler := handlers.NewProjectHandler(nil)
+	router.POST("/projects", withTestUser("admin-1", "Admin", nil), projectHandler.CreateProject)
+	router.PUT("/projects/:id", withTestUser("admin-1", "Admin", nil), projectHandler.UpdateProject)
+
+	tests := []struct {
+		name   string
+		body   map[string]interface{}
+		fields []string
+	}{
+		{"bad start", map[string]interface{}{"start_date": "next week"}, []string{"start_date"}},
+		{"bad end", map[string]interface{}{"end_date": "2026-02-30"}, []string{"end_date"}},
+		{"both bad", map[string]interface{}{"start_date": "soon", "end_date": "later"}, []string{"start_date", "end_date"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			createBody := map[string]interface{}{"name": "Launch"}
+			for key, value := range tt.body {
+				createBody[key] = value
+			}
+			for _, w := range []*httptest.ResponseRecorder{
+				performJSON(router, "POST", "/projects", createBody),
+				performJSON(router, "PUT", "/projects/project-1", tt.body),
+			} {
+				require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+				response := decodeResponse(t, w)
+				assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response))
+				assert.Equal(t, tt.fields, errorDetailFields(t, response))
+			}
+		})
+	}
+}
+
+func TestCreateProject_EndBeforeStartNamesEndDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/projects", withTestUser("admin-1", "Admin", nil), handlers.NewProjectHandler(nil).CreateProject)
+
+	w := performJSON(router, "POST", "/projects", map[string]interface{}{
+		"name":       "Launch",
+		"start_date": "2026-06-01T00:00:00Z",
+		"end_date":   "2026-05-01T00:00:00Z",
+	})
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	response := decodeResponse(t, w)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response))
+	assert.Equal(t, []string{"end_date"}, errorDetailFields(t, response))
+}
+
+func TestCreateProject_BindingErrorsNameFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/projects", withTestUser("admin-1", "Admin", nil), handlers.NewProjectHandler(nil).CreateProject)
+
+	w := performJSON(router, "POST", "/projects", map[string]interface{}{"status": "Unknown"})
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.ElementsMatch(t, []string{"name", "status"}, errorDetailFields(t, decodeResponse(t, w)))
+}
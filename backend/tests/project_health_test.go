@@ -0,0 +1,88 @@
+// ABOUTME: Tests for the health derived for projects from their tasks
+// ABOUTME: Verifies On Track, At Risk and Overdue on single project and list responses
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// setProjectEndDate moves a project's end date
+func setProjectEndDate(t *testing.T, db *gorm.DB, project *models.Project, end time.Time) {
+	t.Helper()
+	require.NoError(t, db.Model(&models.Project{}).Where("id = ?", project.ID).Update("end_date", end).Error)
+}
+
+func TestGetProject_OverdueAfterEndDateWithOpenTasks(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	project := createTestProject(t, db, &dept.ID)
+	setProjectEndDate(t, db, project, time.Now().AddDate(0, 0, -7))
+	createTestTask(t, db, models.Task{Status: "In Progress", CreatorID: admin.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	createTestTask(t, db, models.Task{Status: "Done", CreatorID: admin.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+
+	router := gin.New()
+	router.GET("/projects/:id", asUser(admin), handlers.NewProjectHandler(db).GetProject)
+
+	w := performJSON(router, "GET", "/projects/"+project.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Overdue", decodeResponse(t, w)["data"].(map[string]interface{})["health"])
+}
+
+func TestGetProjects_Health(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	nextWeek := time.Now().AddDate(0, 0, 7)
+	addTasks := func(project *models.Project, overdue, onTime int) {
+		for i := 0; i < overdue; i++ {
+			createTestTask(t, db, models.Task{DueDate: &yesterday, CreatorID: admin.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+		}
+		for i := 0; i < onTime; i++ {
+			createTestTask(t, db, models.Task{DueDate: &nextWeek, CreatorID: admin.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+		}
+	}
+
+	// With the default thresholds a fifth of tasks overdue is At Risk and half is Overdue
+	onTrack := createTestProject(t, db, &dept.ID)
+	addTasks(onTrack, 0, 3)
+	atRisk := createTestProject(t, db, &dept.ID)
+	addTasks(atRisk, 1, 3)
+	overdue := createTestProject(t, db, &dept.ID)
+	addTasks(overdue, 2, 2)
+	finishedLate := createTestProject(t, db, &dept.ID)
+	setProjectEndDate(t, db, finishedLate, yesterday)
+	createTestTask(t, db, models.Task{Status: "Done", CreatorID: admin.ID, DepartmentID: &dept.ID, ProjectID: &finishedLate.ID})
+	empty := createTestProject(t, db, &dept.ID)
+
+	router := gin.New()
+	router.GET("/projects", asUser(admin), handlers.NewProjectHandler(db).GetProjects)
+
+	w := performJSON(router, "GET", "/projects?department_id="+dept.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	health := map[string]interface{}{}
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		project := raw.(map[string]interface{})
+		health[project["id"].(string)] = project["health"]
+	}
+	assert.Equal(t, "On Track", health[onTrack.ID])
+	assert.Equal(t, "At Risk", health[atRisk.ID])
+	assert.Equal(t, "Overdue", health[overdue.ID])
+	assert.Equal(t, "On Track", health[finishedLate.ID], "finished projects aren't overdue")
+	assert.Equal(t, "On Track", health[empty.ID])
+}
@@ -0,0 +1,108 @@
+// ABOUTME: Tests for project membership and membership-based project visibility
+// ABOUTME: Verifies a member outside the project's department gains access once added
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupProjectMemberRouter(user *models.User, projectHandler *handlers.ProjectHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(asUser(user))
+	router.GET("/projects", projectHandler.GetProjects)
+	router.GET("/projects/:id", projectHandler.GetProject)
+	router.GET("/projects/:id/members", projectHandler.GetProjectMembers)
+	router.POST("/projects/:id/members", projectHandler.AddProjectMember)
+	router.DELETE("/projects/:id/members/:userId", projectHandler.RemoveProjectMember)
+	return router
+}
+
+func TestProjectMembership_GrantsAccessOutsideDepartment(t *testing.T) {
+	db := setupTestDB(t)
+	projectHandler := handlers.NewProjectHandler(db)
+
+	deptA := createTestDepartment(t, db)
+	deptB := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	outsider := createTestUser(t, db, "Manager", &deptB.ID)
+	project := createTestProject(t, db, &deptA.ID)
+
+	outsiderRouter := setupProjectMemberRouter(outsider, projectHandler)
+	adminRouter := setupProjectMemberRouter(admin, projectHandler)
+
+	// A Manager from another department cannot see the project
+	w := performJSON(outsiderRouter, "GET", "/projects/"+project.ID, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Nor can they add themselves
+	w = performJSON(outsiderRouter, "POST", "/projects/"+project.ID+"/members", map[string]string{"user_id": outsider.ID})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// An Admin adds them as a contributor
+	w = performJSON(adminRouter, "POST", "/projects/"+project.ID+"/members", map[string]string{"user_id": outsider.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Contributor", decodeResponse(t, w)["data"].(map[string]interface{})["role"])
+
+	w = performJSON(outsiderRouter, "GET", "/projects/"+project.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The project shows up in their membership listing
+	w = performJSON(outsiderRouter, "GET", "/projects?member=me", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	projects := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, projects, 1)
+	assert.Equal(t, project.ID, projects[0].(map[string]interface{})["id"])
+
+	// Removing the membership revokes access again
+	w = performJSON(adminRouter, "DELETE", "/projects/"+project.ID+"/members/"+outsider.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = performJSON(outsiderRouter, "GET", "/projects/"+project.ID, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestProjectMembership_LeadCanManageMembers(t *testing.T) {
+	db := setupTestDB(t)
+	projectHandler := handlers.NewProjectHandler(db)
+
+	dept := createTestDepartment(t, db)
+	lead := createTestUser(t, db, "Member", nil)
+	contributor := createTestUser(t, db, "Member", nil)
+	project := createTestProject(t, db, &dept.ID)
+
+	require.NoError(t, db.Create(&models.ProjectMember{ProjectID: project.ID, UserID: lead.ID, Role: "Lead"}).Error)
+
+	router := setupProjectMemberRouter(lead, projectHandler)
+
+	w := performJSON(router, "POST", "/projects/"+project.ID+"/members", map[string]string{"user_id": contributor.ID, "role": "Viewer"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = performJSON(router, "GET", "/projects/"+project.ID+"/members", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, decodeResponse(t, w)["data"].([]interface{}), 2)
+
+	outsider := createTestUser(t, db, "Member", nil)
+	w = performJSON(router, "DELETE", "/projects/"+project.ID+"/members/"+outsider.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAddProjectMember_MalformedUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/projects/:id/members", withTestUser("admin-1", "Admin", nil), handlers.NewProjectHandler(nil).AddProjectMember)
+
+	w := performJSON(router, "POST", "/projects/project-1/members", map[string]string{"user_id": "not-a-uuid"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "INVALID_USER", errorCode(t, decodeResponse(t, w)))
+}
@@ -0,0 +1,74 @@
+// ABOUTME: Tests for moving tasks between projects in bulk
+// ABOUTME: Verifies moved tasks report the new project and the Manager/Admin restriction
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestMoveProjectTasks(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	source := createTestProject(t, db, &dept.ID)
+	target := createTestProject(t, db, &dept.ID)
+	otherDept := createTestDepartment(t, db)
+	foreign := createTestProject(t, db, &otherDept.ID)
+
+	first := createTestTask(t, db, models.Task{Title: "Design", CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &source.ID})
+	second := createTestTask(t, db, models.Task{Title: "Build", CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &source.ID})
+	third := createTestTask(t, db, models.Task{Title: "Ship", CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &source.ID})
+	elsewhere := createTestTask(t, db, models.Task{Title: "Unrelated", CreatorID: member.ID, DepartmentID: &dept.ID})
+
+	h := handlers.NewProjectHandler(db)
+	router := gin.New()
+	router.POST("/as-manager/projects/:id/move-tasks", asUser(manager), h.MoveProjectTasks)
+	router.POST("/as-member/projects/:id/move-tasks", asUser(member), h.MoveProjectTasks)
+	router.GET("/tasks/:id", asUser(manager), handlers.NewTaskHandler(db).GetTask)
+
+	w := performJSON(router, "POST", "/as-member/projects/"+source.ID+"/move-tasks", map[string]interface{}{"to_project_id": target.ID})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = performJSON(router, "POST", "/as-manager/projects/"+source.ID+"/move-tasks", map[string]interface{}{"to_project_id": foreign.ID})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, "a project outside the Manager's department is out of scope")
+	assert.Equal(t, "INVALID_PROJECT", errorCode(t, decodeResponse(t, w)))
+
+	// Selected tasks move; ids from elsewhere are skipped
+	w = performJSON(router, "POST", "/as-manager/projects/"+source.ID+"/move-tasks", map[string]interface{}{
+		"to_project_id": target.ID, "task_ids": []string{first.ID, second.ID, elsewhere.ID},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["moved_count"])
+	assert.Equal(t, float64(1), data["skipped_count"])
+	assert.Equal(t, []string{elsewhere.ID}, stringList(t, data["not_in_project"]))
+
+	w = performJSON(router, "GET", "/tasks/"+first.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, target.ID, decodeResponse(t, w)["data"].(map[string]interface{})["project_id"])
+
+	// Omitting task_ids moves the rest
+	w = performJSON(router, "POST", "/as-manager/projects/"+source.ID+"/move-tasks", map[string]interface{}{"to_project_id": target.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{third.ID}, stringList(t, decodeResponse(t, w)["data"].(map[string]interface{})["moved"]))
+
+	var moved []models.Task
+	require.NoError(t, db.Where("project_id = ?", target.ID).Find(&moved).Error)
+	assert.Len(t, moved, 3)
+	for _, task := range moved {
+		assert.Equal(t, 2, task.Version, "moving a task bumps its version")
+	}
+	var untouched models.Task
+	require.NoError(t, db.First(&untouched, "id = ?", elsewhere.ID).Error)
+	assert.Nil(t, untouched.ProjectID)
+}
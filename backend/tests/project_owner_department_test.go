@@ -0,0 +1,80 @@
+// ABOUTME: Tests for keeping project owners inside the project's department
+// ABOUTME: Verifies mismatches are rejected on create and update unless an Admin overrides
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+func setupProjectOwnerRouter(db *gorm.DB, user *models.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	projectHandler := handlers.NewProjectHandler(db)
+	router := gin.New()
+	router.Use(asUser(user))
+	router.POST("/projects", projectHandler.CreateProject)
+	router.PUT("/projects/:id", projectHandler.UpdateProject)
+	return router
+}
+
+func TestCreateProject_OwnerOutsideDepartmentRejected(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+	router := setupProjectOwnerRouter(db, admin)
+
+	body := map[string]interface{}{"name": "Mismatched", "department_id": dept.ID, "owner_id": outsider.ID}
+	w := performJSON(router, "POST", "/projects", body)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+
+	// Admins can override deliberately
+	body["allow_cross_department_owner"] = true
+	w = performJSON(router, "POST", "/projects", body)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+func TestCreateProject_OwnerInDepartmentAccepted(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	router := setupProjectOwnerRouter(db, admin)
+
+	w := performJSON(router, "POST", "/projects", map[string]interface{}{"name": "Matched", "department_id": dept.ID, "owner_id": member.ID})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, member.ID, decodeResponse(t, w)["data"].(map[string]interface{})["owner_id"])
+}
+
+func TestUpdateProject_MovingDepartmentAwayFromOwnerRejected(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	project := createTestProject(t, db, &dept.ID)
+	require.NoError(t, db.Model(project).Update("owner_id", member.ID).Error)
+	router := setupProjectOwnerRouter(db, admin)
+
+	w := performJSON(router, "PUT", "/projects/"+project.ID, map[string]string{"department_id": otherDept.ID})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+
+	var stored models.Project
+	require.NoError(t, db.First(&stored, "id = ?", project.ID).Error)
+	assert.Equal(t, dept.ID, *stored.DepartmentID)
+}
@@ -0,0 +1,148 @@
+// ABOUTME: Tests for partial project updates via PATCH
+// ABOUTME: Verifies omitted fields stay untouched, nulls clear, and the date range is still enforced
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// seedPatchableProject creates a project with a description, owner and date range set
+func seedPatchableProject(t *testing.T, db *gorm.DB, owner *models.User) *models.Project {
+	t.Helper()
+
+	project := createTestProject(t, db, owner.DepartmentID)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Model(project).Updates(map[string]interface{}{
+		"description": "Original description",
+		"owner_id":    owner.ID,
+		"start_date":  start,
+		"end_date":    end,
+	}).Error)
+	require.NoError(t, db.First(project, "id = ?", project.ID).Error)
+	return project
+}
+
+func setupProjectPatchRouter(db *gorm.DB, user *models.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.PATCH("/projects/:id", asUser(user), handlers.NewProjectHandler(db).PatchProject)
+	return router
+}
+
+func TestPatchProject_SingleFieldLeavesOthersUntouched(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	project := seedPatchableProject(t, db, manager)
+	router := setupProjectPatchRouter(db, manager)
+
+	w := performJSON(router, "PATCH", "/projects/"+project.ID, map[string]string{"status": "On Hold"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"status"}, data["updated_fields"])
+
+	var stored models.Project
+	require.NoError(t, db.First(&stored, "id = ?", project.ID).Error)
+	assert.Equal(t, "On Hold", stored.Status)
+	assert.Equal(t, project.Name, stored.Name)
+	require.NotNil(t, stored.Description)
+	assert.Equal(t, "Original description", *stored.Description)
+	assert.Equal(t, project.OwnerID, stored.OwnerID)
+	assert.Equal(t, project.DepartmentID, stored.DepartmentID)
+	require.NotNil(t, stored.StartDate)
+	require.NotNil(t, stored.EndDate)
+	assert.True(t, project.StartDate.Equal(*stored.StartDate))
+	assert.True(t, project.EndDate.Equal(*stored.EndDate))
+}
+
+func TestPatchProject_NullClearsOnlyThatField(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	project := seedPatchableProject(t, db, manager)
+	router := setupProjectPatchRouter(db, manager)
+
+	w := performJSON(router, "PATCH", "/projects/"+project.ID, `{"end_date": null}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"end_date"}, data["cleared_fields"])
+
+	var stored models.Project
+	require.NoError(t, db.First(&stored, "id = ?", project.ID).Error)
+	assert.Nil(t, stored.EndDate)
+	assert.NotNil(t, stored.StartDate)
+	assert.NotNil(t, stored.Description)
+	assert.Equal(t, "Active", stored.Status)
+}
+
+func TestPatchProject_EndBeforeExistingStartRejected(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	project := seedPatchableProject(t, db, manager)
+	router := setupProjectPatchRouter(db, manager)
+
+	w := performJSON(router, "PATCH", "/projects/"+project.ID, map[string]string{"end_date": "2023-12-31T00:00:00Z"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+
+	var stored models.Project
+	require.NoError(t, db.First(&stored, "id = ?", project.ID).Error)
+	assert.True(t, project.EndDate.Equal(*stored.EndDate))
+}
+
+func TestPatchProject_RejectsInvalidFields(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	project := seedPatchableProject(t, db, manager)
+	router := setupProjectPatchRouter(db, manager)
+
+	for _, body := range []string{`{"name": null}`, `{"status": "Paused"}`, `{"project_id": "NEW"}`, `{"start_date": "tomorrow"}`} {
+		w := performJSON(router, "PATCH", "/projects/"+project.ID, body)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+	}
+}
+
+func TestPatchProject_ReferenceIDs(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	project := seedPatchableProject(t, db, manager)
+	router := setupProjectPatchRouter(db, manager)
+
+	// A malformed id is a validation error, not a failed database lookup
+	for _, body := range []string{`{"department_id": "not-a-uuid"}`, `{"owner_id": "42"}`} {
+		w := performJSON(router, "PATCH", "/projects/"+project.ID, body)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+		response := decodeResponse(t, w)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response), body)
+		assert.Len(t, errorDetailFields(t, response), 1, body)
+	}
+
+	// An empty owner_id clears the owner, the same as null
+	w := performJSON(router, "PATCH", "/projects/"+project.ID, `{"owner_id": ""}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []interface{}{"owner_id"}, decodeResponse(t, w)["data"].(map[string]interface{})["cleared_fields"])
+
+	var stored models.Project
+	require.NoError(t, db.First(&stored, "id = ?", project.ID).Error)
+	assert.Nil(t, stored.OwnerID)
+}
@@ -0,0 +1,45 @@
+// ABOUTME: Tests for which projects Members and Viewers can see
+// ABOUTME: Verifies listings and lookups are limited to the caller's department and memberships
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestGetProjects_MemberSeesOwnDepartmentAndMemberships(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	own := createTestProject(t, db, &dept.ID)
+	joined := createTestProject(t, db, &otherDept.ID)
+	hidden := createTestProject(t, db, &otherDept.ID)
+
+	for _, role := range []string{"Member", "Viewer"} {
+		user := createTestUser(t, db, role, &dept.ID)
+		require.NoError(t, db.Create(&models.ProjectMember{ProjectID: joined.ID, UserID: user.ID, Role: "Contributor"}).Error)
+
+		projectHandler := handlers.NewProjectHandler(db)
+		router := gin.New()
+		router.Use(asUser(user))
+		router.GET("/projects", projectHandler.GetProjects)
+		router.GET("/projects/:id", projectHandler.GetProject)
+
+		assert.Equal(t, []string{own.ProjectID}, projectCodes(t, router, "/projects?department_id="+dept.ID), role)
+		assert.Equal(t, []string{joined.ProjectID}, projectCodes(t, router, "/projects?department_id="+otherDept.ID), role)
+
+		w := performJSON(router, "GET", "/projects/"+joined.ID, nil)
+		assert.Equal(t, http.StatusOK, w.Code, role)
+		w = performJSON(router, "GET", "/projects/"+hidden.ID, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code, role)
+	}
+}
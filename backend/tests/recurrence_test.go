@@ -0,0 +1,47 @@
+// ABOUTME: Tests for recurrence pattern validation
+// ABOUTME: Verifies bad frequencies, intervals and days are reported against their fields
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestValidateRecurrencePattern(t *testing.T) {
+	intPtr := func(value int) *int { return &value }
+
+	tests := []struct {
+		name    string
+		pattern models.RecurrencePattern
+		fields  []string
+	}{
+		{"valid weekly", models.RecurrencePattern{Frequency: "weekly", Interval: 1, DaysOfWeek: []int{1, 3, 5}}, nil},
+		{"valid monthly last day", models.RecurrencePattern{Frequency: "monthly", Interval: 2, DayOfMonth: intPtr(-1)}, nil},
+		{"valid yearly", models.RecurrencePattern{Frequency: "yearly", Interval: 1, MonthOfYear: intPtr(6), Count: intPtr(3)}, nil},
+		{"unknown frequency", models.RecurrencePattern{Frequency: "hourly", Interval: 1}, []string{"recurrence_pattern.frequency"}},
+		{"missing frequency", models.RecurrencePattern{Interval: 1}, []string{"recurrence_pattern.frequency"}},
+		{"zero interval", models.RecurrencePattern{Frequency: "daily"}, []string{"recurrence_pattern.interval"}},
+		{"negative interval", models.RecurrencePattern{Frequency: "daily", Interval: -2}, []string{"recurrence_pattern.interval"}},
+		{"weekday zero", models.RecurrencePattern{Frequency: "weekly", Interval: 1, DaysOfWeek: []int{0}}, []string{"recurrence_pattern.daysOfWeek"}},
+		{"weekday eight", models.RecurrencePattern{Frequency: "weekly", Interval: 1, DaysOfWeek: []int{1, 8, 9}}, []string{"recurrence_pattern.daysOfWeek"}},
+		{"day of month zero", models.RecurrencePattern{Frequency: "monthly", Interval: 1, DayOfMonth: intPtr(0)}, []string{"recurrence_pattern.dayOfMonth"}},
+		{"month thirteen", models.RecurrencePattern{Frequency: "yearly", Interval: 1, MonthOfYear: intPtr(13)}, []string{"recurrence_pattern.monthOfYear"}},
+		{"zero count", models.RecurrencePattern{Frequency: "daily", Interval: 1, Count: intPtr(0)}, []string{"recurrence_pattern.count"}},
+		{"several problems", models.RecurrencePattern{Frequency: "sometimes", DaysOfWeek: []int{7, 0}},
+			[]string{"recurrence_pattern.frequency", "recurrence_pattern.interval", "recurrence_pattern.daysOfWeek"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, detail := range handlers.ValidateRecurrencePattern(tt.pattern) {
+				fields = append(fields, detail.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
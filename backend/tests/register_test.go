@@ -0,0 +1,66 @@
+// ABOUTME: Tests for registration username handling
+// ABOUTME: Verifies colliding email prefixes get distinct usernames and requested usernames are validated
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"gorm.io/gorm"
+)
+
+func setupRegisterRouter(t *testing.T, db *gorm.DB) *gin.Engine {
+	t.Setenv("JWT_SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/auth/register", handlers.NewAuthHandler(db).Register)
+	return router
+}
+
+// register posts a registration with a valid password and name
+func register(router *gin.Engine, body map[string]string) *httptest.ResponseRecorder {
+	body["password"] = "correct horse battery"
+	body["full_name"] = "Registered User"
+	return performJSON(router, "POST", "/auth/register", body)
+}
+
+func registeredUsername(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	return decodeResponse(t, w)["data"].(map[string]interface{})["user"].(map[string]interface{})["username"].(string)
+}
+
+func TestRegister_SameEmailPrefixGetsDistinctUsernames(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupRegisterRouter(t, db)
+
+	local := "john" + nextFixtureID()
+	first := registeredUsername(t, register(router, map[string]string{"email": local + "@a.example.com"}))
+	second := registeredUsername(t, register(router, map[string]string{"email": local + "@b.example.com"}))
+
+	assert.Equal(t, local, first)
+	assert.Equal(t, local+"2", second)
+}
+
+func TestRegister_RequestedUsername(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupRegisterRouter(t, db)
+
+	username := "chosen" + nextFixtureID()
+	w := register(router, map[string]string{"email": "a" + nextFixtureID() + "@example.com", "username": username})
+	assert.Equal(t, username, registeredUsername(t, w))
+
+	// Taken usernames conflict instead of failing with a server error
+	w = register(router, map[string]string{"email": "b" + nextFixtureID() + "@example.com", "username": username})
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	w = register(router, map[string]string{"email": "c" + nextFixtureID() + "@example.com", "username": "not valid!"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
@@ -0,0 +1,201 @@
+// ABOUTME: Tests for roles, custom role management and the permission filter on the users list
+// ABOUTME: Checks permissions resolve to the roles that grant them and reach issued tokens
+
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"gorm.io/gorm"
+)
+
+func TestGetRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/roles", withTestUser("viewer-1", "Viewer", nil), handlers.NewRoleHandler(nil).GetRoles)
+
+	w := performJSON(router, "GET", "/roles", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	data := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, data, 4)
+
+	roles := []string{}
+	for _, item := range data {
+		entry := item.(map[string]interface{})
+		roles = append(roles, entry["role"].(string))
+
+		perms := []string{}
+		for _, perm := range entry["permissions"].([]interface{}) {
+			perms = append(perms, perm.(string))
+		}
+		assert.Equal(t, auth.PermissionsForRole(entry["role"].(string)), perms)
+	}
+	assert.Equal(t, []string{"Admin", "Manager", "Member", "Viewer"}, roles)
+}
+
+func TestRolesWithPermission(t *testing.T) {
+	roles, unknown := auth.RolesWithPermission("projects.create")
+	assert.Equal(t, []string{"Admin", "Manager"}, roles)
+	assert.False(t, unknown)
+
+	// Unknown roles are treated as Viewers, so Viewer permissions cover them too
+	roles, unknown = auth.RolesWithPermission("tasks.read")
+	assert.Equal(t, []string{"Admin", "Manager", "Member", "Viewer"}, roles)
+	assert.True(t, unknown)
+
+	assert.True(t, auth.IsKnownPermission("settings.update"))
+	assert.False(t, auth.IsKnownPermission("projects.archive"))
+}
+
+func TestGetUsers_PermissionFilter(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	createTestUser(t, db, "Member", &dept.ID)
+	createTestUser(t, db, "Viewer", &dept.ID)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users", asUser(admin), handlers.NewUserHandler(db).GetUsers)
+
+	w := performJSON(router, "GET", "/users?per_page=100&department_id="+dept.ID+"&permission=projects.create", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	ids := []string{}
+	for _, item := range decodeResponse(t, w)["data"].([]interface{}) {
+		ids = append(ids, item.(map[string]interface{})["id"].(string))
+	}
+	assert.ElementsMatch(t, []string{admin.ID, manager.ID}, ids)
+}
+
+func TestGetUsers_UnknownPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users", withTestUser("admin-1", "Admin", nil), handlers.NewUserHandler(nil).GetUsers)
+
+	w := performJSON(router, "GET", "/users?permission=projects.archive", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// setupRoleRouter routes the role endpoints for caller. Role changes reload the process-wide
+// role cache from the test transaction, so it is reloaded again once the transaction is gone.
+func setupRoleRouter(t *testing.T, db *gorm.DB, caller gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	base := openTestDB(t)
+	t.Cleanup(func() {
+		assert.NoError(t, auth.LoadRoles(base))
+	})
+
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewRoleHandler(db)
+	router := gin.New()
+	router.Use(caller)
+	router.GET("/roles", h.GetRoles)
+	router.POST("/roles", h.CreateRole)
+	router.PUT("/roles/:name", h.UpdateRole)
+	router.DELETE("/roles/:name", h.DeleteRole)
+	return router
+}
+
+func TestCreateRole_UserTokenCarriesRolePermissions(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupRoleRouter(t, db, withTestUser("admin-1", "Admin", nil))
+
+	w := performJSON(router, "POST", "/roles", map[string]interface{}{
+		"name":        "ProjectAdmin",
+		"permissions": []string{"projects.update", "projects.create", "projects.read", "projects.create"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "ProjectAdmin", data["role"])
+	assert.Equal(t, false, data["is_builtin"])
+	assert.Equal(t, []interface{}{"projects.create", "projects.read", "projects.update"}, data["permissions"])
+
+	user := createTestUser(t, db, "Member", nil)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("role", "ProjectAdmin").Error)
+	user.Role = "ProjectAdmin"
+
+	secret := strings.Repeat("s", config.MinJWTSecretLength)
+	token, err := utils.GenerateJWT(user, utils.HMACKeys(secret), 1)
+	require.NoError(t, err)
+	claims, err := utils.ValidateJWT(token, utils.HMACKeys(secret))
+	require.NoError(t, err)
+	assert.Equal(t, "ProjectAdmin", claims.Role)
+	assert.Equal(t, []string{"projects.create", "projects.read", "projects.update"}, claims.Permissions)
+
+	// Editing the role changes what newly issued tokens carry
+	w = performJSON(router, "PUT", "/roles/ProjectAdmin", map[string]interface{}{"permissions": []string{"projects.read"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	token, err = utils.GenerateJWT(user, utils.HMACKeys(secret), 1)
+	require.NoError(t, err)
+	claims, err = utils.ValidateJWT(token, utils.HMACKeys(secret))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"projects.read"}, claims.Permissions)
+}
+
+func TestCreateRole_DuplicateName(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupRoleRouter(t, db, withTestUser("admin-1", "Admin", nil))
+
+	w := performJSON(router, "POST", "/roles", map[string]interface{}{"name": "manager", "permissions": []string{}})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCreateRole_InvalidInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/roles", withTestUser("admin-1", "Admin", nil), handlers.NewRoleHandler(nil).CreateRole)
+
+	w := performJSON(router, "POST", "/roles", map[string]interface{}{"name": "Project Admin", "permissions": []string{}})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = performJSON(router, "POST", "/roles", map[string]interface{}{"name": "ProjectAdmin", "permissions": []string{"projects.archive"}})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestDeleteRole(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupRoleRouter(t, db, withTestUser("admin-1", "Admin", nil))
+
+	// Built-in roles stay
+	w := performJSON(router, "DELETE", "/roles/Viewer", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "ROLE_BUILTIN", errorCode(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "POST", "/roles", map[string]interface{}{"name": "Auditor", "permissions": []string{"tasks.read"}})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// Not while someone holds it
+	user := createTestUser(t, db, "Viewer", nil)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("role", "Auditor").Error)
+	w = performJSON(router, "DELETE", "/roles/Auditor", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "ROLE_HAS_USERS", errorCode(t, decodeResponse(t, w)))
+
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("role", "Viewer").Error)
+	w = performJSON(router, "DELETE", "/roles/Auditor", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, auth.IsKnownRole("Auditor"))
+}
+
+func TestUpdateRole_AdminIsFixed(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupRoleRouter(t, db, withTestUser("admin-1", "Admin", nil))
+
+	w := performJSON(router, "PUT", "/roles/Admin", map[string]interface{}{"permissions": []string{"tasks.read"}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.True(t, auth.HasPermission("Admin", "roles.update"))
+}
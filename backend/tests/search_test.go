@@ -0,0 +1,130 @@
+// ABOUTME: Tests for global search across tasks, projects and users
+// ABOUTME: Verifies cross-type matches, type filtering and role-based exclusion
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupSearchRouter(auth gin.HandlerFunc, searchHandler *handlers.SearchHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/search", auth, searchHandler.Search)
+	return router
+}
+
+// searchResultsByType groups result IDs under their type discriminator
+func searchResultsByType(t *testing.T, w *httptest.ResponseRecorder) map[string][]string {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	byType := make(map[string][]string)
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		result := raw.(map[string]interface{})
+		byType[result["type"].(string)] = append(byType[result["type"].(string)], result["id"].(string))
+	}
+	return byType
+}
+
+func TestSearch_RequiresQuery(t *testing.T) {
+	router := setupSearchRouter(withTestUser("user-1", "Member", nil), handlers.NewSearchHandler(nil))
+
+	w := performJSON(router, "GET", "/search", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSearch_RejectsUnknownType(t *testing.T) {
+	router := setupSearchRouter(withTestUser("user-1", "Member", nil), handlers.NewSearchHandler(nil))
+
+	w := performJSON(router, "GET", "/search?q=alpha&types=tasks,widgets", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSearch_MatchesAcrossTypes(t *testing.T) {
+	db := setupTestDB(t)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	term := "Zephyr" + nextFixtureID()
+	task := createTestTask(t, db, models.Task{CreatorID: admin.ID, Title: term + " rollout"})
+	project := createTestProject(t, db, nil)
+	require.NoError(t, db.Model(project).Update("name", term+" platform").Error)
+
+	router := setupSearchRouter(asUser(admin), handlers.NewSearchHandler(db))
+
+	byType := searchResultsByType(t, performJSON(router, "GET", "/search?q="+term, nil))
+	assert.Equal(t, []string{task.ID}, byType["tasks"])
+	assert.Equal(t, []string{project.ID}, byType["projects"])
+	assert.Empty(t, byType["users"])
+
+	// Restricting types drops the others
+	byType = searchResultsByType(t, performJSON(router, "GET", "/search?q="+term+"&types=projects", nil))
+	assert.Empty(t, byType["tasks"])
+	assert.Equal(t, []string{project.ID}, byType["projects"])
+}
+
+func TestSearch_ExcludesForbiddenResults(t *testing.T) {
+	db := setupTestDB(t)
+
+	deptA := createTestDepartment(t, db)
+	deptB := createTestDepartment(t, db)
+	owner := createTestUser(t, db, "Member", &deptA.ID)
+	manager := createTestUser(t, db, "Manager", &deptB.ID)
+	term := "Quasar" + nextFixtureID()
+	createTestTask(t, db, models.Task{CreatorID: owner.ID, DepartmentID: &deptA.ID, Title: term + " audit"})
+	require.NoError(t, db.Model(owner).Update("full_name", term+" Owner").Error)
+
+	// A Manager of another department sees neither the task nor the user
+	router := setupSearchRouter(asUser(manager), handlers.NewSearchHandler(db))
+	byType := searchResultsByType(t, performJSON(router, "GET", "/search?q="+term, nil))
+	assert.Empty(t, byType["tasks"])
+	assert.Empty(t, byType["users"])
+
+	// The owner sees their own task, and user results carry no password hash
+	router = setupSearchRouter(asUser(owner), handlers.NewSearchHandler(db))
+	w := performJSON(router, "GET", "/search?q="+term, nil)
+	byType = searchResultsByType(t, w)
+	assert.Len(t, byType["tasks"], 1)
+	assert.NotContains(t, w.Body.String(), "password")
+}
+
+func TestSearch_SkipsTypesTheRoleCannotRead(t *testing.T) {
+	db := setupTestDB(t)
+
+	roles := setupRoleRouter(t, db, withTestUser("admin-1", "Admin", nil))
+	w := performJSON(roles, "POST", "/roles", map[string]interface{}{
+		"name":        "ProjectReader",
+		"permissions": []string{"projects.read"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	dept := createTestDepartment(t, db)
+	reader := createTestUser(t, db, "Member", &dept.ID)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", reader.ID).UpdateColumn("role", "ProjectReader").Error)
+	reader.Role = "ProjectReader"
+
+	term := "Nimbus" + nextFixtureID()
+	createTestTask(t, db, models.Task{CreatorID: reader.ID, DepartmentID: &dept.ID, Title: term + " review"})
+	project := createTestProject(t, db, &dept.ID)
+	require.NoError(t, db.Model(project).Update("name", term+" platform").Error)
+
+	// Without tasks.read the role's own task stays out of the results
+	router := setupSearchRouter(asUser(reader), handlers.NewSearchHandler(db))
+	byType := searchResultsByType(t, performJSON(router, "GET", "/search?q="+term, nil))
+	assert.Empty(t, byType["tasks"])
+	assert.Equal(t, []string{project.ID}, byType["projects"])
+
+	// Asking only for types it can't read is refused
+	w = performJSON(router, "GET", "/search?q="+term+"&types=tasks,users", nil)
+	require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Equal(t, "FORBIDDEN", errorCode(t, decodeResponse(t, w)))
+}
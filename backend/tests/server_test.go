@@ -0,0 +1,42 @@
+// ABOUTME: Tests for the HTTP server constructed at startup
+// ABOUTME: Verifies the configured address and connection timeouts are applied
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+)
+
+func TestNewServer_AppliesTimeouts(t *testing.T) {
+	cfg := validConfig()
+	cfg.Port = "9090"
+	cfg.ServerReadTimeoutSec = 20
+	handler := http.NewServeMux()
+
+	server := config.NewServer(cfg, handler)
+
+	assert.Equal(t, ":9090", server.Addr)
+	assert.Equal(t, handler, server.Handler)
+	assert.Equal(t, 5*time.Second, server.ReadHeaderTimeout)
+	assert.Equal(t, 20*time.Second, server.ReadTimeout)
+	assert.Equal(t, 60*time.Second, server.WriteTimeout)
+	assert.Equal(t, 120*time.Second, server.IdleTimeout)
+}
+
+func TestNewServer_DefaultPort(t *testing.T) {
+	assert.Equal(t, ":"+config.DefaultPort, config.NewServer(validConfig(), http.NewServeMux()).Addr)
+}
+
+func TestConfigValidate_ServerTimeouts(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerWriteTimeoutSec = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "SERVER_WRITE_TIMEOUT_SEC must be a positive integer", err.Error())
+}
@@ -0,0 +1,220 @@
+// ABOUTME: Tests for login sessions: listing the caller's sessions and revoking them
+// ABOUTME: A revoked session's refresh token must stop working while other sessions keep theirs
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/middleware"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// sessionRequest performs a JSON request with an optional bearer token and User-Agent
+func sessionRequest(router http.Handler, method, path, accessToken, userAgent string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func setupSessionRouter(db *gorm.DB, secret string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authHandler := handlers.NewAuthHandler(db)
+	router := gin.New()
+	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/refresh", authHandler.Refresh)
+	router.POST("/auth/logout", authHandler.Logout)
+	authenticated := router.Group("", middleware.RequireAuth(utils.HMACKeys(secret)), middleware.RequireActiveSession(db), middleware.ValidateIDParams())
+	authenticated.POST("/auth/change-password", authHandler.ChangePassword)
+	authenticated.GET("/auth/sessions", authHandler.ListSessions)
+	authenticated.DELETE("/auth/sessions", authHandler.RevokeOtherSessions)
+	authenticated.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
+	return router
+}
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	db := setupTestDB(t)
+	secret := strings.Repeat("s", 32)
+	t.Setenv("JWT_SECRET", secret)
+
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "correct horse battery", bcrypt.MinCost)
+	router := setupSessionRouter(db, secret)
+
+	login := func(userAgent string) (string, string) {
+		w := sessionRequest(router, "POST", "/auth/login", "", userAgent,
+			map[string]string{"email": user.Email, "password": "correct horse battery"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		data := decodeResponse(t, w)["data"].(map[string]interface{})
+		return data["access_token"].(string), data["refresh_token"].(string)
+	}
+	laptopAccess, laptopRefresh := login("Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/605.1.15 Version/17.0 Safari/605.1.15")
+	phoneAccess, phoneRefresh := login("Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36")
+
+	// Both logins are listed, with the laptop's marked as the current session
+	w := sessionRequest(router, "GET", "/auth/sessions", laptopAccess, "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	sessions := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, sessions, 2)
+	devices := map[string]map[string]interface{}{}
+	for _, raw := range sessions {
+		session := raw.(map[string]interface{})
+		devices[session["device"].(string)] = session
+		assert.NotEmpty(t, session["ip_address"])
+		assert.NotEmpty(t, session["created_at"])
+		assert.NotEmpty(t, session["last_used_at"])
+	}
+	require.Contains(t, devices, "Safari on macOS")
+	require.Contains(t, devices, "Chrome on Android")
+	assert.Equal(t, true, devices["Safari on macOS"]["current"])
+	assert.Equal(t, false, devices["Chrome on Android"]["current"])
+
+	// Revoking the phone's session stops its refresh token, but not the laptop's
+	phoneID := devices["Chrome on Android"]["id"].(string)
+	w = sessionRequest(router, "DELETE", "/auth/sessions/"+phoneID, laptopAccess, "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = sessionRequest(router, "POST", "/auth/refresh", "", "", map[string]string{"refresh_token": phoneRefresh})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "SESSION_REVOKED", errorCode(t, decodeResponse(t, w)))
+
+	// Its unexpired access token stops working too
+	w = sessionRequest(router, "GET", "/auth/sessions", phoneAccess, "", nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "SESSION_REVOKED", errorCode(t, decodeResponse(t, w)))
+
+	w = sessionRequest(router, "POST", "/auth/refresh", "", "", map[string]string{"refresh_token": laptopRefresh})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The revoked session is gone from the list, and can't be revoked again
+	w = sessionRequest(router, "GET", "/auth/sessions", laptopAccess, "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, decodeResponse(t, w)["data"].([]interface{}), 1)
+
+	w = sessionRequest(router, "DELETE", "/auth/sessions/"+phoneID, laptopAccess, "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSessions_RevokeOthersKeepsCurrent(t *testing.T) {
+	db := setupTestDB(t)
+	secret := strings.Repeat("s", 32)
+	t.Setenv("JWT_SECRET", secret)
+
+	user := createTestUser(t, db, "Member", nil)
+	other := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "correct horse battery", bcrypt.MinCost)
+	setTestPassword(t, db, other, "correct horse battery", bcrypt.MinCost)
+	router := setupSessionRouter(db, secret)
+
+	login := func(u *models.User) (string, string) {
+		w := sessionRequest(router, "POST", "/auth/login", "", "", map[string]string{"email": u.Email, "password": "correct horse battery"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		data := decodeResponse(t, w)["data"].(map[string]interface{})
+		return data["access_token"].(string), data["refresh_token"].(string)
+	}
+	currentAccess, currentRefresh := login(user)
+	_, staleRefresh := login(user)
+	otherAccess, otherRefresh := login(other)
+
+	// Another user's session can't be revoked, and reads as not found
+	w := sessionRequest(router, "GET", "/auth/sessions", otherAccess, "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	otherID := decodeResponse(t, w)["data"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	w = sessionRequest(router, "DELETE", "/auth/sessions/"+otherID, currentAccess, "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = sessionRequest(router, "DELETE", "/auth/sessions", currentAccess, "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, float64(1), decodeResponse(t, w)["data"].(map[string]interface{})["revoked_count"])
+
+	for refresh, expected := range map[string]int{
+		currentRefresh: http.StatusOK,
+		staleRefresh:   http.StatusUnauthorized,
+		otherRefresh:   http.StatusOK,
+	} {
+		w = sessionRequest(router, "POST", "/auth/refresh", "", "", map[string]string{"refresh_token": refresh})
+		assert.Equal(t, expected, w.Code, w.Body.String())
+	}
+
+	// Logging out ends the session too
+	w = sessionRequest(router, "POST", "/auth/logout", "", "", map[string]string{"refresh_token": currentRefresh})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = sessionRequest(router, "POST", "/auth/refresh", "", "", map[string]string{"refresh_token": currentRefresh})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSessions_ChangePasswordEndsOtherSessions(t *testing.T) {
+	db := setupTestDB(t)
+	secret := strings.Repeat("s", 32)
+	t.Setenv("JWT_SECRET", secret)
+
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "correct horse battery", bcrypt.MinCost)
+	router := setupSessionRouter(db, secret)
+
+	login := func() (string, string) {
+		w := sessionRequest(router, "POST", "/auth/login", "", "", map[string]string{"email": user.Email, "password": "correct horse battery"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		data := decodeResponse(t, w)["data"].(map[string]interface{})
+		return data["access_token"].(string), data["refresh_token"].(string)
+	}
+	currentAccess, currentRefresh := login()
+	stolenAccess, stolenRefresh := login()
+
+	w := sessionRequest(router, "POST", "/auth/change-password", currentAccess, "", map[string]string{
+		"current_password": "correct horse battery",
+		"new_password":     "another horse battery",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The other session's tokens stop working; the caller's keep going
+	w = sessionRequest(router, "POST", "/auth/refresh", "", "", map[string]string{"refresh_token": stolenRefresh})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = sessionRequest(router, "GET", "/auth/sessions", stolenAccess, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = sessionRequest(router, "GET", "/auth/sessions", currentAccess, "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, decodeResponse(t, w)["data"].([]interface{}), 1)
+	w = sessionRequest(router, "POST", "/auth/refresh", "", "", map[string]string{"refresh_token": currentRefresh})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestRefresh_RejectsTokenWithoutSession(t *testing.T) {
+	secret := strings.Repeat("s", 32)
+	t.Setenv("JWT_SECRET", secret)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/refresh", handlers.NewAuthHandler(nil).Refresh)
+
+	// Refresh tokens issued before sessions existed carry no session id
+	token, err := utils.GenerateJWT(&models.User{ID: "user-1", Role: "Member"}, utils.HMACKeys(secret), 1)
+	require.NoError(t, err)
+	w := performJSON(router, "POST", "/auth/refresh", map[string]string{"refresh_token": token})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "INVALID_TOKEN", errorCode(t, decodeResponse(t, w)))
+}
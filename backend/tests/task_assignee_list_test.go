@@ -0,0 +1,62 @@
+// ABOUTME: Tests for the paginated task assignee listing
+// ABOUTME: Verifies pages follow assignment order and the task keeps its full ID list
+
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestGetTaskAssignees_Paginated(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID})
+
+	assignedAt := time.Now().UTC().Add(-time.Hour)
+	var assigneeIDs []string
+	for i := 0; i < 5; i++ {
+		user := createTestUser(t, db, "Member", &dept.ID)
+		require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id, assigned_at) VALUES (?, ?, ?)",
+			task.ID, user.ID, assignedAt.Add(time.Duration(i)*time.Minute)).Error)
+		assigneeIDs = append(assigneeIDs, user.ID)
+	}
+
+	taskHandler := handlers.NewTaskHandler(db)
+	router := gin.New()
+	router.Use(asUser(member))
+	router.GET("/tasks/:id", taskHandler.GetTask)
+	router.GET("/tasks/:id/assignees", taskHandler.GetTaskAssignees)
+
+	var listed []string
+	for page := 1; page <= 3; page++ {
+		w := performJSON(router, "GET", "/tasks/"+task.ID+"/assignees?per_page=2&page="+strconv.Itoa(page), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		response := decodeResponse(t, w)
+		assert.Equal(t, float64(5), response["pagination"].(map[string]interface{})["total"])
+		for _, raw := range response["data"].([]interface{}) {
+			user := raw.(map[string]interface{})
+			assert.NotContains(t, user, "password_hash")
+			listed = append(listed, user["id"].(string))
+		}
+	}
+	assert.Equal(t, assigneeIDs, listed)
+
+	// The task itself still carries every assignee ID
+	w := performJSON(router, "GET", "/tasks/"+task.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	ids := decodeResponse(t, w)["data"].(map[string]interface{})["assignee_ids"].([]interface{})
+	assert.Len(t, ids, 5)
+}
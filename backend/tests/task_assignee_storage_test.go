@@ -0,0 +1,58 @@
+// ABOUTME: Tests that task assignees are written to and read from task_assignees only
+// ABOUTME: Verifies the write path, single task and list reads agree, and no array column remains
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+)
+
+func TestTaskAssignees_WriteAndReadAgree(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	first := createTestUser(t, db, "Member", &dept.ID)
+	second := createTestUser(t, db, "Member", &dept.ID)
+
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTaskHandler(db)
+	router := gin.New()
+	router.Use(asUser(admin))
+	router.POST("/tasks", h.CreateTask)
+	router.GET("/tasks", h.GetTasks)
+	router.GET("/tasks/:id", h.GetTask)
+
+	w := performJSON(router, "POST", "/tasks", map[string]interface{}{
+		"title":         "Shared work",
+		"department_id": dept.ID,
+		"assignee_ids":  []string{first.ID, second.ID},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	taskID := decodeResponse(t, w)["data"].(map[string]interface{})["id"].(string)
+
+	var stored []string
+	require.NoError(t, db.Raw("SELECT user_id FROM task_assignees WHERE task_id = ?", taskID).Scan(&stored).Error)
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, stored)
+
+	w = performJSON(router, "GET", "/tasks/"+taskID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.ElementsMatch(t, stored, decodeResponse(t, w)["data"].(map[string]interface{})["assignee_ids"])
+
+	w = performJSON(router, "GET", "/tasks?department_id="+dept.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	tasks := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, tasks, 1)
+	assert.ElementsMatch(t, stored, tasks[0].(map[string]interface{})["assignee_ids"])
+
+	// The legacy array column is gone, so there's nothing left to drift
+	var columns int64
+	require.NoError(t, db.Raw("SELECT COUNT(*) FROM information_schema.columns WHERE table_name = 'tasks' AND column_name = 'assignees'").Scan(&columns).Error)
+	assert.Zero(t, columns)
+}
@@ -0,0 +1,213 @@
+// ABOUTME: Tests for single-assignee add/remove endpoints
+// ABOUTME: Verifies no-op adds/removes, [] serialization and assignee access
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupAssigneeRouter(user *models.User, taskHandler *handlers.TaskHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(asUser(user))
+	router.POST("/tasks/:id/assignees", taskHandler.AddTaskAssignee)
+	router.DELETE("/tasks/:id/assignees/:userId", taskHandler.RemoveTaskAssignee)
+	return router
+}
+
+func assigneeIDs(t *testing.T, body map[string]interface{}) []interface{} {
+	t.Helper()
+	return body["data"].(map[string]interface{})["assignee_ids"].([]interface{})
+}
+
+func TestAddTaskAssignee_DuplicateIsNoOp(t *testing.T) {
+	db := setupTestDB(t)
+
+	creator := createTestUser(t, db, "Member", nil)
+	assignee := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+	router := setupAssigneeRouter(creator, handlers.NewTaskHandler(db))
+
+	for i := 0; i < 2; i++ {
+		w := performJSON(router, "POST", "/tasks/"+task.ID+"/assignees", map[string]string{"user_id": assignee.ID})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []interface{}{assignee.ID}, assigneeIDs(t, decodeResponse(t, w)))
+	}
+}
+
+func TestRemoveTaskAssignee_NonAssigneeIsNoOp(t *testing.T) {
+	db := setupTestDB(t)
+
+	creator := createTestUser(t, db, "Member", nil)
+	assignee := createTestUser(t, db, "Member", nil)
+	stranger := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assignee.ID).Error)
+	router := setupAssigneeRouter(creator, handlers.NewTaskHandler(db))
+
+	w := performJSON(router, "DELETE", "/tasks/"+task.ID+"/assignees/"+stranger.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []interface{}{assignee.ID}, assigneeIDs(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "DELETE", "/tasks/"+task.ID+"/assignees/"+assignee.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, assigneeIDs(t, decodeResponse(t, w)))
+}
+
+func TestTaskAssignees_ChangesBumpVersion(t *testing.T) {
+	db := setupTestDB(t)
+
+	creator := createTestUser(t, db, "Member", nil)
+	assignee := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+	router := setupAssigneeRouter(creator, handlers.NewTaskHandler(db))
+
+	version := func() int {
+		var stored models.Task
+		require.NoError(t, db.Select("version").First(&stored, "id = ?", task.ID).Error)
+		return stored.Version
+	}
+	start := version()
+
+	// Adds and removes move the version on and return the new ETag; no-ops leave it alone
+	w := performJSON(router, "POST", "/tasks/"+task.ID+"/assignees", map[string]string{"user_id": assignee.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, start+1, version())
+	assert.Equal(t, fmt.Sprintf(`"%d"`, start+1), w.Header().Get("ETag"))
+
+	w = performJSON(router, "POST", "/tasks/"+task.ID+"/assignees", map[string]string{"user_id": assignee.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, start+1, version())
+
+	w = performJSON(router, "DELETE", "/tasks/"+task.ID+"/assignees/"+assignee.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, start+2, version())
+
+	w = performJSON(router, "DELETE", "/tasks/"+task.ID+"/assignees/"+assignee.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, start+2, version())
+}
+
+func TestAddTaskAssignee_RequiresUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/tasks/:id/assignees", withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil).AddTaskAssignee)
+
+	w := performJSON(router, "POST", "/tasks/task-1/assignees", map[string]string{})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestAddTaskAssignee_MalformedUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/tasks/:id/assignees", withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil).AddTaskAssignee)
+
+	w := performJSON(router, "POST", "/tasks/task-1/assignees", map[string]string{"user_id": "not-a-uuid"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "INVALID_ASSIGNEE", errorCode(t, decodeResponse(t, w)))
+}
+
+func TestTaskListings_EmptyAssigneesSerializeAsArray(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	creator := createTestUser(t, db, "Member", &dept.ID)
+	createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &dept.ID})
+
+	router := gin.New()
+	router.Use(asUser(creator))
+	router.GET("/tasks", handlers.NewTaskHandler(db).GetTasks)
+	router.GET("/users/:id/tasks", handlers.NewUserHandler(db).GetUserTasks)
+
+	for _, path := range []string{"/tasks?department_id=" + dept.ID, "/users/" + creator.ID + "/tasks"} {
+		w := performJSON(router, "GET", path, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `"assignee_ids":[]`, path)
+		assert.NotContains(t, w.Body.String(), `"assignee_ids":null`, path)
+	}
+}
+
+func TestGetTask_AssigneeOutsideDepartmentCanView(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	deptA := createTestDepartment(t, db)
+	deptB := createTestDepartment(t, db)
+	creator := createTestUser(t, db, "Member", &deptA.ID)
+	assignee := createTestUser(t, db, "Member", &deptB.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &deptA.ID})
+
+	router := gin.New()
+	router.GET("/tasks/:id", asUser(assignee), handlers.NewTaskHandler(db).GetTask)
+
+	w := performJSON(router, "GET", "/tasks/"+task.ID, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assignee.ID).Error)
+	w = performJSON(router, "GET", "/tasks/"+task.ID, nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestGetTask_NoAssigneesSerializesEmptyArray(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	creator := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+
+	router := gin.New()
+	router.GET("/tasks/:id", asUser(creator), handlers.NewTaskHandler(db).GetTask)
+
+	w := performJSON(router, "GET", "/tasks/"+task.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	require.Contains(t, data, "assignee_ids")
+	assert.NotNil(t, data["assignee_ids"])
+	assert.Equal(t, []interface{}{}, data["assignee_ids"])
+}
+
+func TestGetTask_ExpandAssigneesIncludesUserDetails(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	creator := createTestUser(t, db, "Member", nil)
+	assignee := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assignee.ID).Error)
+
+	router := gin.New()
+	router.Use(asUser(creator))
+	router.GET("/tasks", handlers.NewTaskHandler(db).GetTasks)
+	router.GET("/tasks/:id", handlers.NewTaskHandler(db).GetTask)
+
+	// IDs only by default
+	w := performJSON(router, "GET", "/tasks/"+task.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, decodeResponse(t, w)["data"], "assignees_detail")
+
+	w = performJSON(router, "GET", "/tasks/"+task.ID+"?expand=assignees", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	details := decodeResponse(t, w)["data"].(map[string]interface{})["assignees_detail"].([]interface{})
+	require.Len(t, details, 1)
+	assert.Equal(t, assignee.FullName, details[0].(map[string]interface{})["full_name"])
+
+	w = performJSON(router, "GET", "/tasks?expand=assignees&assignee_id="+assignee.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	listed := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, listed, 1)
+	assert.Len(t, listed[0].(map[string]interface{})["assignees_detail"], 1)
+}
@@ -0,0 +1,80 @@
+// ABOUTME: Tests for the grouped board view of tasks
+// ABOUTME: Verifies column order, per-group caps and totals over the filtered, visible set
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+// boardGroups fetches the board at path and returns its groups keyed by group key, plus the
+// keys in column order
+func boardGroups(t *testing.T, router *gin.Engine, path string) (map[string]map[string]interface{}, []string) {
+	t.Helper()
+	w := performJSON(router, "GET", path, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	groups := map[string]map[string]interface{}{}
+	keys := []string{}
+	for _, item := range decodeResponse(t, w)["data"].(map[string]interface{})["groups"].([]interface{}) {
+		group := item.(map[string]interface{})
+		groups[group["key"].(string)] = group
+		keys = append(keys, group["key"].(string))
+	}
+	return groups, keys
+}
+
+func TestGetTaskBoard_GroupsWithTotals(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	project := createTestProject(t, db, &dept.ID)
+
+	assigned := createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &project.ID, Priority: "High"})
+	createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, ProjectID: &project.ID, Status: "Done"})
+	createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, Status: "Blocked"})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", assigned.ID, member.ID).Error)
+
+	// A task in the project the Manager can't see stays out of every total
+	otherDept := createTestDepartment(t, db)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+	createTestTask(t, db, models.Task{CreatorID: outsider.ID, DepartmentID: &otherDept.ID, ProjectID: &project.ID, Status: "In Progress"})
+
+	router := gin.New()
+	router.GET("/tasks/board", asUser(manager), handlers.NewTaskHandler(db).GetTaskBoard)
+
+	groups, keys := boardGroups(t, router, "/tasks/board?project_id="+project.ID+"&limit=2")
+	assert.Equal(t, []string{"To Do", "In Progress", "In Review", "Blocked", "Done"}, keys)
+	assert.Equal(t, float64(3), groups["To Do"]["total"])
+	assert.Len(t, groups["To Do"]["tasks"], 2, "groups are capped at the limit")
+	assert.Equal(t, float64(0), groups["In Progress"]["total"])
+	assert.Empty(t, groups["In Progress"]["tasks"])
+	assert.Equal(t, float64(0), groups["Blocked"]["total"], "tasks outside the filter are excluded")
+	assert.Equal(t, float64(1), groups["Done"]["total"])
+
+	groups, _ = boardGroups(t, router, "/tasks/board?project_id="+project.ID+"&priority=High")
+	assert.Equal(t, float64(1), groups["To Do"]["total"])
+	assert.Equal(t, float64(0), groups["Done"]["total"])
+
+	groups, keys = boardGroups(t, router, "/tasks/board?project_id="+project.ID+"&group_by=assignee")
+	assert.Equal(t, []string{member.ID, "none"}, keys)
+	assert.Equal(t, float64(1), groups[member.ID]["total"])
+	assert.Equal(t, float64(3), groups["none"]["total"])
+	require.Len(t, groups[member.ID]["tasks"], 1)
+	assert.Equal(t, assigned.ID, groups[member.ID]["tasks"].([]interface{})[0].(map[string]interface{})["id"])
+
+	w := performJSON(router, "GET", "/tasks/board?group_by=color", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []string{"group_by"}, errorDetailFields(t, decodeResponse(t, w)))
+}
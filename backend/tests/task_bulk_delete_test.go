@@ -0,0 +1,86 @@
+// ABOUTME: Tests for deleting many tasks in one request
+// ABOUTME: Verifies deletable, forbidden and hidden tasks are each reported and only the first are deleted
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+const (
+	bulkOwnTaskID       = "11111111-1111-1111-1111-111111111111"
+	bulkColleagueTaskID = "22222222-2222-2222-2222-222222222222"
+	bulkHiddenTaskID    = "33333333-3333-3333-3333-333333333333"
+	bulkMissingTaskID   = "44444444-4444-4444-4444-444444444444"
+)
+
+func setupBulkDeleteRouter(caller gin.HandlerFunc, repo *fakeTaskRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTaskHandlerWithRepositories(nil, repo, &fakeUserRepository{users: map[string]models.User{}})
+
+	router := gin.New()
+	router.DELETE("/tasks/bulk", caller, h.BulkDeleteTasks)
+	return router
+}
+
+func TestBulkDeleteTasks_ReportsPartialPermission(t *testing.T) {
+	otherDept := "dept-b"
+	own := fakeTask()
+	own.ID = bulkOwnTaskID
+	colleagues := fakeTask()
+	colleagues.ID, colleagues.CreatorID = bulkColleagueTaskID, "colleague-1"
+	hidden := fakeTask()
+	hidden.ID, hidden.CreatorID, hidden.DepartmentID = bulkHiddenTaskID, "stranger-1", &otherDept
+
+	repo := newFakeTaskRepository(own, colleagues, hidden)
+	repo.assignees[bulkOwnTaskID] = []string{"colleague-1"}
+	// fakeTask is created by creator-1 in dept-a
+	dept := "dept-a"
+	router := setupBulkDeleteRouter(withTestUser("creator-1", "Member", &dept), repo)
+
+	w := performJSON(router, "DELETE", "/tasks/bulk", map[string][]string{
+		"ids": {bulkOwnTaskID, bulkColleagueTaskID, bulkHiddenTaskID, bulkMissingTaskID, bulkOwnTaskID},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{bulkOwnTaskID}, data["deleted"])
+	assert.Equal(t, []interface{}{bulkColleagueTaskID}, data["forbidden"])
+	assert.Equal(t, []interface{}{bulkHiddenTaskID, bulkMissingTaskID}, data["not_found"])
+	assert.Equal(t, float64(1), data["deleted_count"])
+	assert.Equal(t, float64(3), data["skipped_count"])
+
+	assert.Equal(t, []string{bulkOwnTaskID}, repo.deleted)
+	assert.Contains(t, repo.tasks, bulkColleagueTaskID)
+	assert.NotContains(t, repo.assignees, bulkOwnTaskID)
+}
+
+func TestBulkDeleteTasks_AdminDeletesAll(t *testing.T) {
+	first := fakeTask()
+	first.ID = bulkOwnTaskID
+	second := fakeTask()
+	second.ID, second.CreatorID = bulkColleagueTaskID, "colleague-1"
+
+	repo := newFakeTaskRepository(first, second)
+	router := setupBulkDeleteRouter(withTestUser("admin-1", "Admin", nil), repo)
+
+	w := performJSON(router, "DELETE", "/tasks/bulk", map[string][]string{"ids": {bulkOwnTaskID, bulkColleagueTaskID}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.ElementsMatch(t, []string{bulkOwnTaskID, bulkColleagueTaskID}, repo.deleted)
+}
+
+func TestBulkDeleteTasks_InvalidRequest(t *testing.T) {
+	router := setupBulkDeleteRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository())
+
+	for _, body := range []string{`{}`, `{"ids": []}`, `{"ids": ["not-a-uuid"]}`} {
+		w := performJSON(router, "DELETE", "/tasks/bulk", body)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+	}
+}
@@ -0,0 +1,88 @@
+// ABOUTME: Tests for the per-role default view of unfiltered task lists
+// ABOUTME: Verifies Members land on their own tasks, Managers on open department tasks, and overrides
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// listTaskTitles lists tasks at path as user and returns their titles and the applied view
+func listTaskTitles(t *testing.T, db *gorm.DB, user *models.User, path string) ([]string, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/tasks", asUser(user), handlers.NewTaskHandler(db).GetTasks)
+
+	w := performJSON(router, "GET", path, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	titles := []string{}
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		titles = append(titles, raw.(map[string]interface{})["title"].(string))
+	}
+	return titles, w.Header().Get("X-Task-View")
+}
+
+// seedDefaultViewTasks gives a department a member's own, assigned, colleague's and finished tasks
+func seedDefaultViewTasks(t *testing.T, db *gorm.DB) (member, manager *models.User) {
+	t.Helper()
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	member = createTestUser(t, db, "Member", &dept.ID)
+	manager = createTestUser(t, db, "Manager", &dept.ID)
+	colleague := createTestUser(t, db, "Member", &dept.ID)
+
+	createTestTask(t, db, models.Task{Title: "Created", CreatorID: member.ID, DepartmentID: &dept.ID})
+	assigned := createTestTask(t, db, models.Task{Title: "Assigned", CreatorID: colleague.ID, DepartmentID: &otherDept.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", assigned.ID, member.ID).Error)
+	createTestTask(t, db, models.Task{Title: "Colleague's", CreatorID: colleague.ID, DepartmentID: &dept.ID})
+	createTestTask(t, db, models.Task{Title: "Finished", Status: "Done", CreatorID: colleague.ID, DepartmentID: &dept.ID})
+	return member, manager
+}
+
+func TestGetTasks_MemberDefaultsToOwnTasks(t *testing.T) {
+	db := setupTestDB(t)
+	member, _ := seedDefaultViewTasks(t, db)
+
+	titles, view := listTaskTitles(t, db, member, "/tasks")
+	assert.Equal(t, "mine", view)
+	assert.ElementsMatch(t, []string{"Created", "Assigned"}, titles)
+
+	// Any filter, or an explicit view, skips the default
+	titles, view = listTaskTitles(t, db, member, "/tasks?view=all")
+	assert.Equal(t, "all", view)
+	assert.ElementsMatch(t, []string{"Created", "Assigned", "Colleague's", "Finished"}, titles)
+
+	titles, _ = listTaskTitles(t, db, member, "/tasks?status=Done")
+	assert.Equal(t, []string{"Finished"}, titles)
+}
+
+func TestGetTasks_ManagerDefaultsToOpenDepartmentTasks(t *testing.T) {
+	db := setupTestDB(t)
+	_, manager := seedDefaultViewTasks(t, db)
+
+	titles, view := listTaskTitles(t, db, manager, "/tasks")
+	assert.Equal(t, "department_open", view)
+	assert.ElementsMatch(t, []string{"Created", "Colleague's"}, titles)
+}
+
+func TestGetTasks_DefaultViewsConfigurable(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("TASK_DEFAULT_VIEWS", `{"Member": "all"}`)
+	member, _ := seedDefaultViewTasks(t, db)
+
+	titles, view := listTaskTitles(t, db, member, "/tasks")
+	assert.Equal(t, "all", view)
+	assert.Len(t, titles, 4)
+}
@@ -0,0 +1,61 @@
+// ABOUTME: Tests for the opt-in policy rejecting past due dates on new tasks
+// ABOUTME: Verifies creation is refused when enabled, accepted when disabled, and updates stay lenient
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func pastDueDate() string {
+	return time.Now().UTC().Add(-24 * time.Hour).Format(time.RFC3339)
+}
+
+func TestCreateTask_PastDueDateRejectedWhenEnabled(t *testing.T) {
+	t.Setenv("REJECT_PAST_DUE_DATES", "true")
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/tasks", withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil).CreateTask)
+
+	w := performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "Overdue already", "due_date": pastDueDate()})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	response := decodeResponse(t, w)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response))
+	assert.Equal(t, "due_date cannot be in the past", response["error"].(map[string]interface{})["message"])
+}
+
+func TestCreateTask_PastDueDateAcceptedWhenDisabled(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("REJECT_PAST_DUE_DATES", "false")
+	gin.SetMode(gin.TestMode)
+
+	member := createTestUser(t, db, "Member", nil)
+	router := gin.New()
+	router.POST("/tasks", asUser(member), handlers.NewTaskHandler(db).CreateTask)
+
+	w := performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "Backfilled", "due_date": pastDueDate()})
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+func TestUpdateTask_PastDueDateAllowedWhenEnabled(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("REJECT_PAST_DUE_DATES", "true")
+	gin.SetMode(gin.TestMode)
+
+	member := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: member.ID})
+	router := gin.New()
+	router.PUT("/tasks/:id", asUser(member), handlers.NewTaskHandler(db).UpdateTask)
+
+	w := sendWithIfMatch(router, "PUT", "/tasks/"+task.ID, `{"due_date": "`+pastDueDate()+`"}`, "*")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -0,0 +1,102 @@
+// ABOUTME: Tests for duplicating a task
+// ABOUTME: Verifies copied fields, reset status, optional assignees and checklist, and permissions
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestDuplicateTask_CopiesContentAndResetsStatus(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	creator := createTestUser(t, db, "Member", &dept.ID)
+	caller := createTestUser(t, db, "Member", &dept.ID)
+	description := "Original description"
+	source := createTestTask(t, db, models.Task{
+		Title:        "Quarterly report",
+		Description:  &description,
+		Status:       "In Progress",
+		Priority:     "High",
+		CreatorID:    creator.ID,
+		DepartmentID: &dept.ID,
+		Tags:         pq.StringArray{"finance", "q3"},
+	})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", source.ID, creator.ID).Error)
+	require.NoError(t, db.Create(&models.ChecklistItem{TaskID: source.ID, Text: "Gather numbers", Done: true}).Error)
+
+	router := gin.New()
+	router.POST("/tasks/:id/duplicate", asUser(caller), handlers.NewTaskHandler(db).DuplicateTask)
+
+	w := performJSON(router, "POST", "/tasks/"+source.ID+"/duplicate", nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.NotEqual(t, source.ID, data["id"])
+	assert.Equal(t, "Copy of Quarterly report", data["title"])
+	assert.Equal(t, "To Do", data["status"])
+	assert.Equal(t, "High", data["priority"])
+	assert.Equal(t, description, data["description"])
+	assert.Equal(t, []interface{}{"finance", "q3"}, data["tags"])
+	assert.Equal(t, caller.ID, data["creator_id"])
+	assert.Nil(t, data["completion_date"])
+	assert.Equal(t, []interface{}{}, data["assignee_ids"])
+	assert.Nil(t, data["checklist_items"])
+
+	// Assignees and checklist only come along when asked for, and items start undone
+	w = performJSON(router, "POST", "/tasks/"+source.ID+"/duplicate?assignees=true&checklist=true", nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	data = decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{creator.ID}, data["assignee_ids"])
+	items := data["checklist_items"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "Gather numbers", items[0].(map[string]interface{})["text"])
+	assert.Equal(t, false, items[0].(map[string]interface{})["done"])
+}
+
+func TestDuplicateTask_RequiresCreatePermissionInDepartment(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	creator := createTestUser(t, db, "Member", &dept.ID)
+	assignee := createTestUser(t, db, "Member", &otherDept.ID)
+	outsider := createTestUser(t, db, "Member", &otherDept.ID)
+	source := createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &dept.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", source.ID, assignee.ID).Error)
+
+	handler := handlers.NewTaskHandler(db)
+
+	// An assignee can see the task but can't create tasks in its department
+	router := gin.New()
+	router.POST("/tasks/:id/duplicate", asUser(assignee), handler.DuplicateTask)
+	w := performJSON(router, "POST", "/tasks/"+source.ID+"/duplicate", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Someone who can't see the task can't copy it either
+	router = gin.New()
+	router.POST("/tasks/:id/duplicate", asUser(outsider), handler.DuplicateTask)
+	w = performJSON(router, "POST", "/tasks/"+source.ID+"/duplicate", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDuplicateTask_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTaskHandlerWithRepositories(nil, newFakeTaskRepository(), &fakeUserRepository{users: map[string]models.User{}})
+
+	router := gin.New()
+	router.POST("/tasks/:id/duplicate", withTestUser("admin-1", "Admin", nil), h.DuplicateTask)
+
+	w := performJSON(router, "POST", "/tasks/missing/duplicate", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Tests for facet counts on the task list
+// ABOUTME: Facets cover the whole filtered, scoped set rather than the returned page
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestGetTasks_Facets(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	other := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	colleague := createTestUser(t, db, "Member", &dept.ID)
+
+	seed := []struct{ status, priority string }{
+		{"To Do", "High"}, {"To Do", "High"}, {"To Do", "Low"},
+		{"In Progress", "High"}, {"Done", "Medium"},
+	}
+	tasks := []*models.Task{}
+	for _, s := range seed {
+		tasks = append(tasks, createTestTask(t, db, models.Task{Status: s.status, Priority: s.priority, CreatorID: member.ID, DepartmentID: &dept.ID}))
+	}
+	// Outside the caller's scope, so never counted
+	createTestTask(t, db, models.Task{Status: "To Do", Priority: "High", CreatorID: colleague.ID, DepartmentID: &other.ID})
+
+	for _, assignment := range []struct{ task, user string }{
+		{tasks[0].ID, member.ID}, {tasks[1].ID, member.ID}, {tasks[1].ID, colleague.ID}, {tasks[2].ID, colleague.ID},
+	} {
+		require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", assignment.task, assignment.user).Error)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tasks", asUser(member), handlers.NewTaskHandler(db).GetTasks)
+
+	// One task per page, but counts cover every visible match in the department
+	w := performJSON(router, "GET", "/tasks?per_page=1&facets=status,priority,assignee&department_id="+dept.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	response := decodeResponse(t, w)
+	assert.Len(t, response["data"].([]interface{}), 1)
+
+	facets := response["facets"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"To Do": float64(3), "In Progress": float64(1), "Done": float64(1)}, facets["status"])
+	assert.Equal(t, map[string]interface{}{"High": float64(3), "Low": float64(1), "Medium": float64(1)}, facets["priority"])
+	assert.Equal(t, map[string]interface{}{member.ID: float64(2), colleague.ID: float64(2)}, facets["assignee"])
+
+	// Filters narrow the facets too
+	w = performJSON(router, "GET", "/tasks?facets=status&priority=High", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	facets = decodeResponse(t, w)["facets"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"To Do": float64(2), "In Progress": float64(1)}, facets["status"])
+}
+
+func TestGetTasks_UnknownFacet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tasks", withTestUser("admin-1", "Admin", nil), handlers.NewTaskHandler(nil).GetTasks)
+
+	w := performJSON(router, "GET", "/tasks?facets=status,colour", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetTasks_NoFacetsByDefault(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "Admin", nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tasks", asUser(admin), handlers.NewTaskHandler(db).GetTasks)
+
+	w := performJSON(router, "GET", "/tasks", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, decodeResponse(t, w), "facets")
+}
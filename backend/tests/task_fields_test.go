@@ -0,0 +1,66 @@
+// ABOUTME: Tests for sparse fieldsets on the task list and single task endpoints
+// ABOUTME: Verifies ?fields= returns only the requested keys and rejects unknown names
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func keysOf(object map[string]interface{}) []string {
+	keys := []string{}
+	for key := range object {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestGetTask_Fields(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository(fakeTask()))
+
+	w := performJSON(router, "GET", "/tasks/task-1?fields=title,status,due_date", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"id", "title", "status", "due_date"}, keysOf(data))
+	assert.Equal(t, "Quarterly report", data["title"])
+	assert.Nil(t, data["due_date"], "requested fields the task doesn't have are null")
+}
+
+func TestGetTask_UnknownField(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository(fakeTask()))
+
+	w := performJSON(router, "GET", "/tasks/task-1?fields=title,password_hash", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	response := decodeResponse(t, w)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response))
+	assert.Equal(t, "Unsupported field: password_hash", response["error"].(map[string]interface{})["message"])
+}
+
+func TestGetTasks_Fields(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	createTestTask(t, db, models.Task{Title: "Sparse", CreatorID: admin.ID, DepartmentID: &dept.ID})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tasks", asUser(admin), handlers.NewTaskHandler(db).GetTasks)
+
+	w := performJSON(router, "GET", "/tasks?department_id="+dept.ID+"&fields=id,title,status,priority", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	tasks := decodeResponse(t, w)["data"].([]interface{})
+	require.Len(t, tasks, 1)
+	task := tasks[0].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"id", "title", "status", "priority"}, keysOf(task))
+	assert.Equal(t, "Sparse", task["title"])
+}
@@ -0,0 +1,342 @@
+// ABOUTME: Database-free task handler tests backed by in-memory repositories
+// ABOUTME: Cover not-found, forbidden and error paths of the single-task endpoints
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/middleware"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+)
+
+// fakeTaskRepository serves tasks from memory; err, when set, fails every call
+type fakeTaskRepository struct {
+	tasks     map[string]models.Task
+	assignees map[string][]string
+	deleted   []string
+	err       error
+}
+
+func newFakeTaskRepository(tasks ...models.Task) *fakeTaskRepository {
+	repo := &fakeTaskRepository{tasks: map[string]models.Task{}, assignees: map[string][]string{}}
+	for _, task := range tasks {
+		repo.tasks[task.ID] = task
+	}
+	return repo
+}
+
+func (r *fakeTaskRepository) FindByID(id string) (models.Task, error) {
+	if r.err != nil {
+		return models.Task{}, r.err
+	}
+	task, ok := r.tasks[id]
+	if !ok {
+		return models.Task{}, repository.ErrNotFound
+	}
+	return task, nil
+}
+
+func (r *fakeTaskRepository) FindWithRelations(id string) (models.Task, error) {
+	return r.FindByID(id)
+}
+
+func (r *fakeTaskRepository) FindDetailed(id string) (models.Task, error) {
+	return r.FindByID(id)
+}
+
+func (r *fakeTaskRepository) FindManyWithRelations(ids []string) ([]models.Task, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	tasks := []models.Task{}
+	for _, id := range ids {
+		if task, ok := r.tasks[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeTaskRepository) LoadAssignees(tasks []models.Task) error {
+	for i := range tasks {
+		tasks[i].Assignees = append([]string{}, r.assignees[tasks[i].ID]...)
+	}
+	return r.err
+}
+
+func (r *fakeTaskRepository) LoadAssigneeDetails(tasks []models.Task) error {
+	for i := range tasks {
+		tasks[i].AssigneesDetail = []models.User{}
+	}
+	return r.err
+}
+
+func (r *fakeTaskRepository) Delete(task *models.Task) error {
+	if r.err != nil {
+		return r.err
+	}
+	delete(r.tasks, task.ID)
+	r.deleted = append(r.deleted, task.ID)
+	return nil
+}
+
+func (r *fakeTaskRepository) DeleteMany(ids []string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, id := range ids {
+		delete(r.tasks, id)
+		delete(r.assignees, id)
+		r.deleted = append(r.deleted, id)
+	}
+	return nil
+}
+
+// fakeUserRepository serves users from memory
+type fakeUserRepository struct {
+	users map[string]models.User
+}
+
+func (r *fakeUserRepository) FindByID(id string) (models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return models.User{}, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+// setupFakeTaskRouter routes the single-task endpoints to a handler with no database
+func setupFakeTaskRouter(caller gin.HandlerFunc, repo *fakeTaskRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTaskHandlerWithRepositories(nil, repo, &fakeUserRepository{users: map[string]models.User{}})
+
+	router := gin.New()
+	router.Use(caller)
+	router.GET("/tasks/:id", h.GetTask)
+	router.PUT("/tasks/:id", h.UpdateTask)
+	router.PATCH("/tasks/:id", h.PatchTask)
+	router.PATCH("/tasks/:id/status", h.UpdateTaskStatus)
+	router.DELETE("/tasks/:id", h.DeleteTask)
+	router.POST("/tasks/:id/checklist", h.AddChecklistItem)
+	router.GET("/tasks/:id/assignees", h.GetTaskAssignees)
+	return router
+}
+
+func fakeTask() models.Task {
+	deptID := "dept-a"
+	return models.Task{ID: "task-1", Title: "Quarterly report", Status: "To Do", Priority: "Medium", CreatorID: "creator-1", DepartmentID: &deptID}
+}
+
+func errorCode(t *testing.T, response map[string]interface{}) string {
+	t.Helper()
+	return response["error"].(map[string]interface{})["code"].(string)
+}
+
+func TestTaskHandlerRepo_NotFound(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository())
+
+	requests := []struct {
+		method, path string
+		body         interface{}
+	}{
+		{"GET", "/tasks/missing", nil},
+		{"PUT", "/tasks/missing", map[string]interface{}{"title": "Renamed"}},
+		{"PATCH", "/tasks/missing", map[string]interface{}{"title": "Renamed"}},
+		{"PATCH", "/tasks/missing/status", map[string]interface{}{"status": "Done"}},
+		{"DELETE", "/tasks/missing", nil},
+		{"POST", "/tasks/missing/checklist", map[string]interface{}{"text": "Step"}},
+		{"GET", "/tasks/missing/assignees", nil},
+	}
+	for _, r := range requests {
+		w := performJSON(router, r.method, r.path, r.body)
+		assert.Equal(t, http.StatusNotFound, w.Code, "%s %s", r.method, r.path)
+		assert.Equal(t, "TASK_NOT_FOUND", errorCode(t, decodeResponse(t, w)), "%s %s", r.method, r.path)
+	}
+}
+
+func TestTaskHandlerRepo_Forbidden(t *testing.T) {
+	otherDept := "dept-b"
+	router := setupFakeTaskRouter(withTestUser("outsider-1", "Member", &otherDept), newFakeTaskRepository(fakeTask()))
+
+	requests := []struct {
+		method, path string
+		body         interface{}
+	}{
+		{"GET", "/tasks/task-1", nil},
+		{"PUT", "/tasks/task-1", map[string]interface{}{"title": "Renamed"}},
+		{"PATCH", "/tasks/task-1", map[string]interface{}{"title": "Renamed"}},
+		{"PATCH", "/tasks/task-1/status", map[string]interface{}{"status": "Done"}},
+		{"DELETE", "/tasks/task-1", nil},
+		{"POST", "/tasks/task-1/checklist", map[string]interface{}{"text": "Step"}},
+		{"GET", "/tasks/task-1/assignees", nil},
+	}
+	for _, r := range requests {
+		w := performJSON(router, r.method, r.path, r.body)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", r.method, r.path)
+		assert.Equal(t, "FORBIDDEN", errorCode(t, decodeResponse(t, w)), "%s %s", r.method, r.path)
+	}
+}
+
+func TestTaskHandlerRepo_ViewerCannotModifyDepartmentTask(t *testing.T) {
+	deptID := "dept-a"
+	router := setupFakeTaskRouter(withTestUser("viewer-1", "Viewer", &deptID), newFakeTaskRepository(fakeTask()))
+
+	// Viewers may read their department's tasks but not change them
+	assert.Equal(t, http.StatusOK, performJSON(router, "GET", "/tasks/task-1", nil).Code)
+	assert.Equal(t, http.StatusForbidden, performJSON(router, "PATCH", "/tasks/task-1/status", map[string]interface{}{"status": "Done"}).Code)
+}
+
+func TestTaskHandlerRepo_AssigneeCanView(t *testing.T) {
+	otherDept := "dept-b"
+	repo := newFakeTaskRepository(fakeTask())
+	repo.assignees["task-1"] = []string{"outsider-1"}
+	router := setupFakeTaskRouter(withTestUser("outsider-1", "Member", &otherDept), repo)
+
+	w := performJSON(router, "GET", "/tasks/task-1", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"outsider-1"}, data["assignee_ids"])
+}
+
+func TestTaskHandlerRepo_CreatorCanDelete(t *testing.T) {
+	repo := newFakeTaskRepository(fakeTask())
+	router := setupFakeTaskRouter(withTestUser("creator-1", "Member", nil), repo)
+
+	w := performJSON(router, "DELETE", "/tasks/task-1", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"task-1"}, repo.deleted)
+}
+
+func TestTaskHandlerRepo_StoreError(t *testing.T) {
+	repo := newFakeTaskRepository(fakeTask())
+	repo.err = errors.New("connection reset")
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), repo)
+
+	w := performJSON(router, "GET", "/tasks/task-1", nil)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "SERVER_ERROR", errorCode(t, decodeResponse(t, w)))
+}
+
+func TestTaskHandlerRepo_InvalidIDFormat(t *testing.T) {
+	repo := newFakeTaskRepository()
+	repo.err = errors.New("invalid input syntax for type uuid")
+	router := gin.New()
+	router.Use(withTestUser("admin-1", "Admin", nil), middleware.ValidateIDParams())
+	h := handlers.NewTaskHandlerWithRepositories(nil, repo, &fakeUserRepository{users: map[string]models.User{}})
+	router.GET("/tasks/:id", h.GetTask)
+	router.DELETE("/tasks/:id/checklist/:itemId", h.DeleteChecklistItem)
+
+	w := performJSON(router, "GET", "/tasks/not-a-uuid", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	response := decodeResponse(t, w)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response))
+	assert.Equal(t, "invalid id format", response["error"].(map[string]interface{})["message"])
+
+	w = performJSON(router, "DELETE", "/tasks/3f1c2b8e-4d5a-4b6c-9e7f-0a1b2c3d4e5f/checklist/42", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Well-formed ids reach the handler
+	w = performJSON(router, "GET", "/tasks/3F1C2B8E-4D5A-4B6C-9E7F-0A1B2C3D4E5F", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestTaskHandlerRepo_HiddenResourceStatus(t *testing.T) {
+	otherDept := "dept-b"
+	outsider := withTestUser("outsider-1", "Member", &otherDept)
+
+	t.Run("403 by default", func(t *testing.T) {
+		router := setupFakeTaskRouter(outsider, newFakeTaskRepository(fakeTask()))
+
+		w := performJSON(router, "GET", "/tasks/task-1", nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "FORBIDDEN", errorCode(t, decodeResponse(t, w)))
+	})
+
+	t.Run("404 hides tasks outside the caller's scope", func(t *testing.T) {
+		t.Setenv("HIDDEN_RESOURCE_STATUS", "404")
+		router := setupFakeTaskRouter(outsider, newFakeTaskRepository(fakeTask()))
+
+		for _, r := range []struct {
+			method, path string
+			body         interface{}
+		}{
+			{"GET", "/tasks/task-1", nil},
+			{"PUT", "/tasks/task-1", map[string]interface{}{"title": "Renamed"}},
+			{"PATCH", "/tasks/task-1/status", map[string]interface{}{"status": "Done"}},
+			{"DELETE", "/tasks/task-1", nil},
+		} {
+			w := performJSON(router, r.method, r.path, r.body)
+			assert.Equal(t, http.StatusNotFound, w.Code, "%s %s", r.method, r.path)
+			assert.Equal(t, "TASK_NOT_FOUND", errorCode(t, decodeResponse(t, w)), "%s %s", r.method, r.path)
+		}
+	})
+
+	t.Run("404 mode keeps 403 for tasks the caller can see", func(t *testing.T) {
+		t.Setenv("HIDDEN_RESOURCE_STATUS", "404")
+		deptID := "dept-a"
+		router := setupFakeTaskRouter(withTestUser("viewer-1", "Viewer", &deptID), newFakeTaskRepository(fakeTask()))
+
+		w := performJSON(router, "PATCH", "/tasks/task-1/status", map[string]interface{}{"status": "Done"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "FORBIDDEN", errorCode(t, decodeResponse(t, w)))
+	})
+}
+
+func TestTaskHandlerRepo_BatchGet(t *testing.T) {
+	deptA, deptB := "dept-a", "dept-b"
+	visible := models.Task{ID: "00000000-0000-0000-0000-00000000000a", Title: "Visible", CreatorID: "someone", DepartmentID: &deptA}
+	assigned := models.Task{ID: "00000000-0000-0000-0000-00000000000b", Title: "Assigned", CreatorID: "someone", DepartmentID: &deptB}
+	forbidden := models.Task{ID: "00000000-0000-0000-0000-00000000000c", Title: "Forbidden", CreatorID: "someone", DepartmentID: &deptB}
+	missing := "00000000-0000-0000-0000-00000000000d"
+
+	repo := newFakeTaskRepository(visible, assigned, forbidden)
+	repo.assignees[assigned.ID] = []string{"member-1"}
+
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTaskHandlerWithRepositories(nil, repo, &fakeUserRepository{users: map[string]models.User{}})
+	router := gin.New()
+	router.POST("/tasks/batch-get", withTestUser("member-1", "Member", &deptA), h.BatchGetTasks)
+
+	w := performJSON(router, "POST", "/tasks/batch-get", map[string]interface{}{
+		"ids": []string{assigned.ID, forbidden.ID, missing, visible.ID, assigned.ID},
+	})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	ids := []string{}
+	for _, item := range decodeResponse(t, w)["data"].([]interface{}) {
+		ids = append(ids, item.(map[string]interface{})["id"].(string))
+	}
+	// Request order, each task once, only what the caller may see
+	assert.Equal(t, []string{assigned.ID, visible.ID}, ids)
+}
+
+func TestTaskHandlerRepo_BatchGetValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTaskHandlerWithRepositories(nil, newFakeTaskRepository(), &fakeUserRepository{users: map[string]models.User{}})
+	router := gin.New()
+	router.POST("/tasks/batch-get", withTestUser("admin-1", "Admin", nil), h.BatchGetTasks)
+
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+	}
+	for name, body := range map[string]interface{}{
+		"missing ids": map[string]interface{}{},
+		"not a uuid":  map[string]interface{}{"ids": []string{"task-1"}},
+		"too many":    map[string]interface{}{"ids": tooMany},
+	} {
+		w := performJSON(router, "POST", "/tasks/batch-get", body)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, name)
+	}
+}
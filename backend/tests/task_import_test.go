@@ -0,0 +1,156 @@
+// ABOUTME: Tests for the bulk task import endpoint
+// ABOUTME: Covers per-row validation reporting in dry-run mode for JSON and CSV bodies
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/middleware"
+)
+
+func setupImportRouter(role string, departmentID *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	// Rows without assignees, departments or projects never reach the database
+	router := gin.New()
+	taskHandler := handlers.NewTaskHandler(nil)
+	router.POST("/tasks/import", withTestUser("11111111-1111-1111-1111-111111111111", role, departmentID),
+		middleware.RequirePermission("tasks.create"), taskHandler.ImportTasks)
+	return router
+}
+
+func TestImportTasks_DryRunMixedBatch(t *testing.T) {
+	router := setupImportRouter("Admin", nil)
+
+	body := `[
+		{"title": "Write onboarding guide", "priority": "High", "tags": ["docs"]},
+		{"title": ""},
+		{"title": "Bad status", "status": "Someday"},
+		{"title": "Plan kickoff", "due_date": "2030-01-15T09:00:00Z"},
+		{"title": "Bad date", "due_date": "next tuesday"}
+	]`
+	req, _ := http.NewRequest("POST", "/tasks/import?dry_run=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	response := decodeResponse(t, w)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, true, data["dry_run"])
+	assert.Equal(t, float64(5), data["total"])
+	assert.Equal(t, float64(2), data["valid"])
+	assert.Equal(t, float64(3), data["invalid"])
+	assert.Equal(t, float64(0), data["created"])
+
+	results := data["results"].([]interface{})
+	require.Len(t, results, 5)
+
+	expected := []struct {
+		status string
+		field  string
+	}{
+		{"valid", ""},
+		{"invalid", "title"},
+		{"invalid", "status"},
+		{"valid", ""},
+		{"invalid", "due_date"},
+	}
+	for i, want := range expected {
+		result := results[i].(map[string]interface{})
+		assert.Equal(t, float64(i), result["index"])
+		assert.Equal(t, want.status, result["status"], "row %d", i)
+		assert.Nil(t, result["task_id"], "dry run must not create row %d", i)
+		if want.field != "" {
+			errs := result["errors"].([]interface{})
+			require.NotEmpty(t, errs)
+			assert.Equal(t, want.field, errs[0].(map[string]interface{})["field"], "row %d", i)
+		}
+	}
+}
+
+func TestImportTasks_DryRunCSV(t *testing.T) {
+	router := setupImportRouter("Admin", nil)
+
+	body := "title,priority,tags\n" +
+		"Refresh landing page,Urgent,web;design\n" +
+		"Broken priority,Critical,\n"
+	req, _ := http.NewRequest("POST", "/tasks/import?dry_run=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	results := data["results"].([]interface{})
+	require.Len(t, results, 2)
+	assert.Equal(t, "valid", results[0].(map[string]interface{})["status"])
+	assert.Equal(t, "invalid", results[1].(map[string]interface{})["status"])
+}
+
+func TestImportTasks_DryRunOtherDepartmentRejected(t *testing.T) {
+	ownDept := "22222222-2222-2222-2222-222222222222"
+	router := setupImportRouter("Member", &ownDept)
+
+	body := `[{"title": "Someone else's work", "department_id": "33333333-3333-3333-3333-333333333333"}]`
+	req, _ := http.NewRequest("POST", "/tasks/import?dry_run=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	result := data["results"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "invalid", result["status"])
+	assert.Equal(t, "department_id", result["errors"].([]interface{})[0].(map[string]interface{})["field"])
+}
+
+func TestImportTasks_ViewerForbidden(t *testing.T) {
+	router := setupImportRouter("Viewer", nil)
+
+	req, _ := http.NewRequest("POST", "/tasks/import?dry_run=true", strings.NewReader(`[{"title": "Nope"}]`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestImportTasks_MalformedReferenceIDs(t *testing.T) {
+	router := setupImportRouter("Admin", nil)
+
+	// Malformed ids are row errors, not a failed lookup for the whole import
+	body := `[
+		{"title": "Bad assignee", "assignee_ids": ["bob"]},
+		{"title": "Bad department", "department_id": "engineering"},
+		{"title": "Bad project", "project_id": "42"},
+		{"title": "Fine"}
+	]`
+	req, _ := http.NewRequest("POST", "/tasks/import?dry_run=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["valid"])
+	results := data["results"].([]interface{})
+	for i, field := range []string{"assignee_ids", "department_id", "project_id"} {
+		result := results[i].(map[string]interface{})
+		assert.Equal(t, "invalid", result["status"], "row %d", i)
+		assert.Equal(t, field, result["errors"].([]interface{})[0].(map[string]interface{})["field"], "row %d", i)
+	}
+	assert.Equal(t, "valid", results[3].(map[string]interface{})["status"])
+}
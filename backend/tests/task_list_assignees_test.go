@@ -0,0 +1,86 @@
+// ABOUTME: Tests that every task listing endpoint reports task assignees
+// ABOUTME: Covers project, department and user task lists, with and without ?expand=assignees
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestTaskListings_IncludeAssignees(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	assignee := createTestUser(t, db, "Member", &dept.ID)
+	project := createTestProject(t, db, &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assignee.ID).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(asUser(admin))
+	router.GET("/projects/:id/tasks", handlers.NewProjectHandler(db).GetProjectTasks)
+	router.GET("/departments/:id/tasks", handlers.NewDepartmentHandler(db).GetDepartmentTasks)
+	router.GET("/users/:id/tasks", handlers.NewUserHandler(db).GetUserTasks)
+
+	for _, path := range []string{
+		"/projects/" + project.ID + "/tasks",
+		"/departments/" + dept.ID + "/tasks",
+		"/users/" + assignee.ID + "/tasks",
+	} {
+		w := performJSON(router, "GET", path+"?expand=assignees", nil)
+		require.Equal(t, http.StatusOK, w.Code, path+": "+w.Body.String())
+
+		tasks := decodeResponse(t, w)["data"].([]interface{})
+		require.Len(t, tasks, 1, path)
+		listed := tasks[0].(map[string]interface{})
+		assert.Equal(t, []interface{}{assignee.ID}, listed["assignee_ids"], path)
+		details := listed["assignees_detail"].([]interface{})
+		require.Len(t, details, 1, path)
+		assert.Equal(t, assignee.ID, details[0].(map[string]interface{})["id"], path)
+	}
+}
+
+// Project and department task views once preloaded assignees as if they were an association,
+// which returned none; each task must carry its own assignees and unassigned tasks []
+func TestProjectAndDepartmentTasks_AssigneesPerTask(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	first := createTestUser(t, db, "Member", &dept.ID)
+	second := createTestUser(t, db, "Member", &dept.ID)
+	project := createTestProject(t, db, &dept.ID)
+	shared := createTestTask(t, db, models.Task{Title: "Shared", CreatorID: manager.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	createTestTask(t, db, models.Task{Title: "Unassigned", CreatorID: manager.ID, DepartmentID: &dept.ID, ProjectID: &project.ID})
+	for _, user := range []*models.User{first, second} {
+		require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", shared.ID, user.ID).Error)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(asUser(manager))
+	router.GET("/projects/:id/tasks", handlers.NewProjectHandler(db).GetProjectTasks)
+	router.GET("/departments/:id/tasks", handlers.NewDepartmentHandler(db).GetDepartmentTasks)
+
+	for _, path := range []string{"/projects/" + project.ID + "/tasks", "/departments/" + dept.ID + "/tasks"} {
+		w := performJSON(router, "GET", path, nil)
+		require.Equal(t, http.StatusOK, w.Code, path+": "+w.Body.String())
+
+		assignees := map[string]interface{}{}
+		for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+			task := raw.(map[string]interface{})
+			assignees[task["title"].(string)] = task["assignee_ids"]
+		}
+		assert.ElementsMatch(t, []interface{}{first.ID, second.ID}, assignees["Shared"], path)
+		assert.Equal(t, []interface{}{}, assignees["Unassigned"], path)
+	}
+}
@@ -0,0 +1,63 @@
+// ABOUTME: Tests for the "none" task filters used when triaging unplanned work
+// ABOUTME: Verifies unassigned, no-due-date and no-project filters alongside exact matches
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+// seedTriageTasks creates, in a fresh department, an assigned task with a due date and
+// project, and a bare task with neither
+func seedTriageTasks(t *testing.T, db *gorm.DB) (admin *models.User, departmentID string, assignee *models.User) {
+	t.Helper()
+
+	dept := createTestDepartment(t, db)
+	admin = createTestUser(t, db, "Admin", nil)
+	assignee = createTestUser(t, db, "Member", &dept.ID)
+	project := createTestProject(t, db, &dept.ID)
+	due := time.Now().Add(72 * time.Hour)
+
+	planned := createTestTask(t, db, models.Task{Title: "Planned", CreatorID: admin.ID, DepartmentID: &dept.ID, ProjectID: &project.ID, DueDate: &due})
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", planned.ID, assignee.ID).Error)
+	createTestTask(t, db, models.Task{Title: "Untriaged", CreatorID: admin.ID, DepartmentID: &dept.ID})
+	return admin, dept.ID, assignee
+}
+
+func TestGetTasks_NoneFilters(t *testing.T) {
+	db := setupTestDB(t)
+	admin, departmentID, assignee := seedTriageTasks(t, db)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"&assignee_id=none", []string{"Untriaged"}},
+		{"&due_date=none", []string{"Untriaged"}},
+		{"&project_id=none", []string{"Untriaged"}},
+		{"&assignee_id=" + assignee.ID, []string{"Planned"}},
+	} {
+		titles, _ := listTaskTitles(t, db, admin, "/tasks?department_id="+departmentID+tc.query)
+		assert.Equal(t, tc.want, titles, tc.query)
+	}
+}
+
+func TestGetTasks_DueDateFilterOnlySupportsNone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/tasks", withTestUser("admin-1", "Admin", nil), handlers.NewTaskHandler(nil).GetTasks)
+
+	w := performJSON(router, "GET", "/tasks?due_date=2024-01-01", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+}
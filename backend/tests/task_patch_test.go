@@ -0,0 +1,69 @@
+// ABOUTME: Tests for partial task updates via PATCH
+// ABOUTME: Verifies omitted keys are untouched while explicit nulls clear values
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestPatchTask_OmittedFieldUntouchedNullClears(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+
+	description := "Keep me"
+	dueDate := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	task := createTestTask(t, db, models.Task{
+		CreatorID:    admin.ID,
+		DepartmentID: &dept.ID,
+		Description:  &description,
+		DueDate:      &dueDate,
+	})
+
+	router := gin.New()
+	router.PATCH("/tasks/:id", asUser(admin), handlers.NewTaskHandler(db).PatchTask)
+
+	// Only priority is sent; due_date is explicitly null; description is omitted
+	w := performJSON(router, "PATCH", "/tasks/"+task.ID, `{"priority": "High", "due_date": null}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "High", data["priority"])
+	assert.Equal(t, "Keep me", data["description"])
+	assert.Equal(t, task.Title, data["title"])
+	assert.Nil(t, data["due_date"])
+	assert.ElementsMatch(t, []interface{}{"due_date", "priority"}, data["updated_fields"])
+	assert.ElementsMatch(t, []interface{}{"due_date"}, data["cleared_fields"])
+
+	var stored models.Task
+	require.NoError(t, db.First(&stored, "id = ?", task.ID).Error)
+	assert.Nil(t, stored.DueDate)
+	require.NotNil(t, stored.Description)
+	assert.Equal(t, "Keep me", *stored.Description)
+	assert.Equal(t, "High", stored.Priority)
+}
+
+func TestPatchTask_RejectsNullOnRequiredField(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: admin.ID})
+
+	router := gin.New()
+	router.PATCH("/tasks/:id", asUser(admin), handlers.NewTaskHandler(db).PatchTask)
+
+	w := performJSON(router, "PATCH", "/tasks/"+task.ID, `{"title": null}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
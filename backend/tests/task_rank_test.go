@@ -0,0 +1,127 @@
+// ABOUTME: Tests for manual task ordering on the board
+// ABOUTME: Verifies inserting between neighbors and sorting tasks by rank
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupRankRouter(user *models.User, taskHandler *handlers.TaskHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(asUser(user))
+	router.GET("/tasks", taskHandler.GetTasks)
+	router.PATCH("/tasks/:id/rank", taskHandler.UpdateTaskRank)
+	return router
+}
+
+// rankedTitles lists the department's tasks in manual board order
+func rankedTitles(t *testing.T, router *gin.Engine, departmentID string) []string {
+	t.Helper()
+
+	w := performJSON(router, "GET", "/tasks?sort_by=rank&department_id="+departmentID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var titles []string
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		titles = append(titles, raw.(map[string]interface{})["title"].(string))
+	}
+	return titles
+}
+
+func TestUpdateTaskRank_InsertsBetweenNeighbors(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	a := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "A"})
+	b := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "B"})
+	c := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "C"})
+	router := setupRankRouter(admin, handlers.NewTaskHandler(db))
+
+	// Unranked tasks fall back to creation order
+	assert.Equal(t, []string{"A", "B", "C"}, rankedTitles(t, router, dept.ID))
+
+	w := performJSON(router, "PATCH", "/tasks/"+c.ID+"/rank", map[string]string{"after_id": a.ID, "before_id": b.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored []models.Task
+	require.NoError(t, db.Where("id IN ?", []string{a.ID, b.ID, c.ID}).Find(&stored).Error)
+	ranks := make(map[string]float64)
+	for _, task := range stored {
+		require.NotNil(t, task.Rank, task.Title)
+		ranks[task.Title] = *task.Rank
+	}
+	assert.Less(t, ranks["A"], ranks["C"])
+	assert.Less(t, ranks["C"], ranks["B"])
+}
+
+func TestGetTasks_SortByRankReturnsManualOrder(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	a := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "A"})
+	b := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "B"})
+	c := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "C"})
+	router := setupRankRouter(admin, handlers.NewTaskHandler(db))
+
+	// Move A to the end, then C to the top
+	w := performJSON(router, "PATCH", "/tasks/"+a.ID+"/rank", map[string]string{})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = performJSON(router, "PATCH", "/tasks/"+c.ID+"/rank", map[string]string{"before_id": b.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, []string{"C", "B", "A"}, rankedTitles(t, router, dept.ID))
+
+	// Neighbors in another status column are rejected
+	done := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "D", Status: "Done"})
+	w = performJSON(router, "PATCH", "/tasks/"+a.ID+"/rank", map[string]string{"after_id": done.ID})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestUpdateTaskRank_ColumnIsPerDepartment(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	other := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	a := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID, Title: "A"})
+	elsewhere := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &other.ID, Title: "X"})
+	router := setupRankRouter(admin, handlers.NewTaskHandler(db))
+
+	// Another department's board is a different column, even in the same status
+	w := performJSON(router, "PATCH", "/tasks/"+a.ID+"/rank", map[string]string{"after_id": elsewhere.ID})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	// Moving to the end ranks the task within its own department's column only
+	w = performJSON(router, "PATCH", "/tasks/"+a.ID+"/rank", map[string]string{})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var unranked models.Task
+	require.NoError(t, db.First(&unranked, "id = ?", elsewhere.ID).Error)
+	assert.Nil(t, unranked.Rank, "other departments' tasks aren't rebalanced")
+}
+
+func TestUpdateTaskRank_MalformedNeighborID(t *testing.T) {
+	db := setupTestDB(t)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: admin.ID, Title: "A"})
+	router := setupRankRouter(admin, handlers.NewTaskHandler(db))
+
+	for _, field := range []string{"after_id", "before_id"} {
+		w := performJSON(router, "PATCH", "/tasks/"+task.ID+"/rank", map[string]string{field: "not-a-uuid"})
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, field)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)), field)
+	}
+}
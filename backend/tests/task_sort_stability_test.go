@@ -0,0 +1,60 @@
+// ABOUTME: Tests for deterministic task list ordering when sort values tie
+// ABOUTME: Verifies pages over tasks sharing a created_at are repeatable and don't overlap
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+// taskPageIDs fetches one page of the department's tasks and returns their IDs in order
+func taskPageIDs(t *testing.T, router *gin.Engine, departmentID string, page int) []string {
+	t.Helper()
+
+	w := performJSON(router, "GET", fmt.Sprintf("/tasks?department_id=%s&per_page=2&page=%d", departmentID, page), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	ids := []string{}
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		ids = append(ids, raw.(map[string]interface{})["id"].(string))
+	}
+	return ids
+}
+
+func TestGetTasks_TiedSortValuesPageStably(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", nil)
+	var created []string
+	for i := 0; i < 5; i++ {
+		task := createTestTask(t, db, models.Task{CreatorID: admin.ID, DepartmentID: &dept.ID})
+		created = append(created, task.ID)
+	}
+	sameInstant := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Model(&models.Task{}).Where("id IN ?", created).Update("created_at", sameInstant).Error)
+
+	router := gin.New()
+	router.GET("/tasks", asUser(admin), handlers.NewTaskHandler(db).GetTasks)
+
+	seen := make(map[string]bool)
+	for page := 1; page <= 3; page++ {
+		ids := taskPageIDs(t, router, dept.ID, page)
+		assert.Equal(t, ids, taskPageIDs(t, router, dept.ID, page), "page %d changed between fetches", page)
+		for _, id := range ids {
+			assert.False(t, seen[id], "task %s appeared on more than one page", id)
+			seen[id] = true
+		}
+	}
+	assert.Len(t, seen, len(created))
+}
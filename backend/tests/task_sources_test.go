@@ -0,0 +1,55 @@
+// ABOUTME: Tests for task sources configured with TASK_SOURCES on top of the built-in ones
+// ABOUTME: Configured sources are accepted on create and listed by the enums endpoint; others are rejected
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+)
+
+func TestCreateTask_ConfiguredSourceAccepted(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("TASK_SOURCES", "Jira, Zapier")
+	gin.SetMode(gin.TestMode)
+
+	member := createTestUser(t, db, "Member", nil)
+	router := gin.New()
+	router.POST("/tasks", asUser(member), handlers.NewTaskHandler(db).CreateTask)
+
+	w := performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "Synced from Jira", "source": "Jira"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "Jira", decodeResponse(t, w)["data"].(map[string]interface{})["source"])
+}
+
+func TestCreateTask_UnconfiguredSourceRejected(t *testing.T) {
+	t.Setenv("TASK_SOURCES", "Jira")
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/tasks", withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil).CreateTask)
+
+	w := performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "From Zapier", "source": "Zapier"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	response := decodeResponse(t, w)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response))
+	assert.Equal(t, "Invalid source value", response["error"].(map[string]interface{})["message"])
+}
+
+func TestGetEnums_ListsConfiguredSources(t *testing.T) {
+	t.Setenv("TASK_SOURCES", "Jira,,Email, Zapier")
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/meta/enums", withTestUser("viewer-1", "Viewer", nil), handlers.NewMetaHandler().GetEnums)
+
+	w := performJSON(router, "GET", "/meta/enums", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []string{"GUI", "Email", "API", "Document", "NLP", "Jira", "Zapier"}, stringList(t, data["task_sources"]))
+}
@@ -0,0 +1,118 @@
+// ABOUTME: Tests for the task status workflow
+// ABOUTME: Covers allowed and rejected transitions, Admin override, config and completion dates
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestStatusTransition_DisallowedListsAllowedTargets(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("creator-1", "Member", strPtr("dept-a")), newFakeTaskRepository(fakeTask()))
+
+	// To Do can't skip straight to Done through any of the update endpoints
+	requests := []struct{ method, path, body string }{
+		{"PATCH", "/tasks/task-1/status", `{"status": "Done"}`},
+		{"PUT", "/tasks/task-1", `{"status": "Done"}`},
+		{"PATCH", "/tasks/task-1", `{"status": "Done"}`},
+	}
+	for _, r := range requests {
+		w := sendWithIfMatch(router, r.method, r.path, r.body, "*")
+		require.Equal(t, http.StatusConflict, w.Code, r.method+" "+r.path)
+
+		response := decodeResponse(t, w)
+		assert.Equal(t, "INVALID_TRANSITION", errorCode(t, response))
+		details := response["error"].(map[string]interface{})["details"].([]interface{})
+		assert.Equal(t, "Allowed statuses: In Progress, Blocked", details[0].(map[string]interface{})["message"])
+	}
+}
+
+func TestStatusTransition_AdminOverrideCanBeDisabled(t *testing.T) {
+	t.Setenv("TASK_STATUS_ADMIN_OVERRIDE", "false")
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository(fakeTask()))
+
+	w := sendWithIfMatch(router, "PATCH", "/tasks/task-1/status", `{"status": "Done"}`, "*")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestStatusTransition_ConfiguredWorkflow(t *testing.T) {
+	t.Setenv("TASK_STATUS_TRANSITIONS", `{"To Do": ["Blocked"]}`)
+	router := setupFakeTaskRouter(withTestUser("creator-1", "Member", strPtr("dept-a")), newFakeTaskRepository(fakeTask()))
+
+	w := sendWithIfMatch(router, "PATCH", "/tasks/task-1/status", `{"status": "In Progress"}`, "*")
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "INVALID_TRANSITION", errorCode(t, decodeResponse(t, w)))
+}
+
+func TestStatusTransition_InvalidConfigFailsValidation(t *testing.T) {
+	cfg := validConfig()
+	cfg.TaskStatusTransitions = `["To Do"]`
+	assert.Error(t, cfg.Validate())
+
+	cfg.TaskStatusTransitions = `{"To Do": ["In Progress"]}`
+	assert.NoError(t, cfg.Validate())
+
+	transitions, err := (&config.Config{}).StatusTransitions()
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultTaskStatusTransitions, transitions)
+}
+
+func TestStatusTransition_AllowedMoveMaintainsCompletionDate(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &dept.ID)
+	task := createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &dept.ID, Status: "In Review"})
+
+	router := gin.New()
+	router.PATCH("/tasks/:id/status", asUser(member), handlers.NewTaskHandler(db).UpdateTaskStatus)
+
+	w := sendWithIfMatch(router, "PATCH", "/tasks/"+task.ID+"/status", `{"status": "Done"}`, "*")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "Done", data["status"])
+	assert.NotNil(t, data["completion_date"])
+
+	// Reopening clears the completion date
+	w = sendWithIfMatch(router, "PATCH", "/tasks/"+task.ID+"/status", `{"status": "In Progress"}`, "*")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	data = decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "In Progress", data["status"])
+	assert.Nil(t, data["completion_date"])
+
+	var stored models.Task
+	require.NoError(t, db.First(&stored, "id = ?", task.ID).Error)
+	assert.Nil(t, stored.CompletionDate)
+}
+
+func TestUpdateTask_UncompletingClearsCompletionDate(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: admin.ID, Status: "In Review"})
+
+	router := gin.New()
+	router.PUT("/tasks/:id", asUser(admin), handlers.NewTaskHandler(db).UpdateTask)
+
+	w := sendWithIfMatch(router, "PUT", "/tasks/"+task.ID, `{"status": "Done"}`, "*")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotNil(t, decodeResponse(t, w)["data"].(map[string]interface{})["completion_date"])
+
+	w = sendWithIfMatch(router, "PUT", "/tasks/"+task.ID, `{"status": "In Progress"}`, "*")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Nil(t, decodeResponse(t, w)["data"].(map[string]interface{})["completion_date"])
+
+	var stored models.Task
+	require.NoError(t, db.First(&stored, "id = ?", task.ID).Error)
+	assert.Nil(t, stored.CompletionDate)
+}
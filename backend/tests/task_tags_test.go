@@ -0,0 +1,67 @@
+// ABOUTME: Tests for task tag normalization on write and in the tag filter
+// ABOUTME: Verifies duplicates collapse to one lowercase tag and oversized tag lists are rejected
+
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupTagRouter(auth gin.HandlerFunc, taskHandler *handlers.TaskHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(auth)
+	router.GET("/tasks", taskHandler.GetTasks)
+	router.POST("/tasks", taskHandler.CreateTask)
+	return router
+}
+
+func TestCreateTask_NormalizesTags(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := createTestUser(t, db, "Admin", nil)
+	router := setupTagRouter(asUser(user), handlers.NewTaskHandler(db))
+
+	w := performJSON(router, "POST", "/tasks", map[string]interface{}{
+		"title": "Tagged task " + nextFixtureID(),
+		"tags":  []string{"Urgent", " urgent ", "URGENT", "Needs   Review", "needs review"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	taskID := decodeResponse(t, w)["data"].(map[string]interface{})["id"].(string)
+
+	var stored models.Task
+	require.NoError(t, db.First(&stored, "id = ?", taskID).Error)
+	assert.Equal(t, []string{"urgent", "needs review"}, []string(stored.Tags))
+
+	// The filter normalizes too, so any casing finds the task
+	w = performJSON(router, "GET", "/tasks?tag=URGENT&per_page=100", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	ids := []string{}
+	for _, item := range decodeResponse(t, w)["data"].([]interface{}) {
+		ids = append(ids, item.(map[string]interface{})["id"].(string))
+	}
+	assert.Contains(t, ids, taskID)
+}
+
+func TestCreateTask_RejectsTagLimits(t *testing.T) {
+	router := setupTagRouter(withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil))
+
+	tooMany := []string{}
+	for i := 0; i < 21; i++ {
+		tooMany = append(tooMany, "tag-"+strings.Repeat("x", i))
+	}
+	w := performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "Task", "tags": tooMany})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = performJSON(router, "POST", "/tasks", map[string]interface{}{"title": "Task", "tags": []string{strings.Repeat("a", 51)}})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
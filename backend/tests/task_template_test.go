@@ -0,0 +1,124 @@
+// ABOUTME: Tests for task templates and instantiating tasks from them
+// ABOUTME: Verifies template defaults, caller overrides and department scoping
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupTemplateRouter(user *models.User, taskHandler *handlers.TaskHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(asUser(user))
+	router.GET("/task-templates", taskHandler.GetTaskTemplates)
+	router.POST("/task-templates", taskHandler.CreateTaskTemplate)
+	router.POST("/tasks/from-template/:templateId", taskHandler.CreateTaskFromTemplate)
+	return router
+}
+
+// createTemplateViaAPI creates a release checklist template for the manager's department
+func createTemplateViaAPI(t *testing.T, router *gin.Engine) string {
+	t.Helper()
+
+	w := performJSON(router, "POST", "/task-templates", map[string]interface{}{
+		"name":      "Weekly release",
+		"title":     "Release {date}",
+		"priority":  "High",
+		"tags":      []string{"release"},
+		"checklist": []string{"Cut branch", "Run smoke tests"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	return decodeResponse(t, w)["data"].(map[string]interface{})["id"].(string)
+}
+
+func TestCreateTaskTemplate_MembersForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/task-templates", withTestUser("user-1", "Member", nil), handlers.NewTaskHandler(nil).CreateTaskTemplate)
+
+	w := performJSON(router, "POST", "/task-templates", map[string]string{"name": "Mine", "title": "Task"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateTaskFromTemplate_UsesTemplateDefaults(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	router := setupTemplateRouter(manager, handlers.NewTaskHandler(db))
+
+	templateID := createTemplateViaAPI(t, router)
+
+	w := performJSON(router, "POST", "/tasks/from-template/"+templateID, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	task := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "Release "+time.Now().Format("2006-01-02"), task["title"])
+	assert.Equal(t, "High", task["priority"])
+	assert.Equal(t, "To Do", task["status"])
+	assert.Equal(t, dept.ID, task["department_id"])
+	assert.Equal(t, []interface{}{"release"}, task["tags"])
+	assert.Empty(t, task["assignee_ids"])
+
+	items := task["checklist_items"].([]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "Cut branch", items[0].(map[string]interface{})["text"])
+	assert.Equal(t, float64(2), task["checklist_progress"].(map[string]interface{})["total"])
+}
+
+func TestCreateTaskFromTemplate_AppliesOverrides(t *testing.T) {
+	db := setupTestDB(t)
+
+	dept := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &dept.ID)
+	assignee := createTestUser(t, db, "Member", &dept.ID)
+	router := setupTemplateRouter(manager, handlers.NewTaskHandler(db))
+
+	templateID := createTemplateViaAPI(t, router)
+
+	w := performJSON(router, "POST", "/tasks/from-template/"+templateID, map[string]interface{}{
+		"title":        "Hotfix release",
+		"due_date":     "2026-12-01T09:00:00Z",
+		"assignee_ids": []string{assignee.ID},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	task := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, "Hotfix release", task["title"])
+	assert.Equal(t, "2026-12-01T09:00:00Z", task["due_date"])
+	assert.Equal(t, []interface{}{assignee.ID}, task["assignee_ids"])
+	assert.Equal(t, "High", task["priority"])
+}
+
+func TestCreateTaskFromTemplate_RespectsDepartmentScope(t *testing.T) {
+	db := setupTestDB(t)
+
+	deptA := createTestDepartment(t, db)
+	deptB := createTestDepartment(t, db)
+	manager := createTestUser(t, db, "Manager", &deptA.ID)
+	outsider := createTestUser(t, db, "Member", &deptB.ID)
+	taskHandler := handlers.NewTaskHandler(db)
+
+	templateID := createTemplateViaAPI(t, setupTemplateRouter(manager, taskHandler))
+
+	outsiderRouter := setupTemplateRouter(outsider, taskHandler)
+	w := performJSON(outsiderRouter, "POST", "/tasks/from-template/"+templateID, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = performJSON(outsiderRouter, "GET", "/task-templates", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+		assert.NotEqual(t, templateID, raw.(map[string]interface{})["id"])
+	}
+}
@@ -0,0 +1,112 @@
+// ABOUTME: Tests for task time tracking endpoints
+// ABOUTME: Verifies logging, per-task totals, timesheets and the edit-own-only rule
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func setupTimeLogRouter(user *models.User, timeLogHandler *handlers.TimeLogHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(asUser(user))
+	router.GET("/tasks/:id/time", timeLogHandler.GetTaskTime)
+	router.POST("/tasks/:id/time", timeLogHandler.LogTaskTime)
+	router.PUT("/tasks/:id/time/:logId", timeLogHandler.UpdateTimeLog)
+	router.DELETE("/tasks/:id/time/:logId", timeLogHandler.DeleteTimeLog)
+	router.GET("/users/:id/time", timeLogHandler.GetUserTime)
+	return router
+}
+
+func TestLogTaskTime_RejectsNonPositiveMinutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/tasks/:id/time", withTestUser("user-1", "Member", nil), handlers.NewTimeLogHandler(nil).LogTaskTime)
+
+	w := performJSON(router, "POST", "/tasks/task-1/time", `{"minutes": 0}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestLogTaskTime_TotalsAcrossEntries(t *testing.T) {
+	db := setupTestDB(t)
+	timeLogHandler := handlers.NewTimeLogHandler(db)
+
+	creator := createTestUser(t, db, "Member", nil)
+	estimate := 120
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID, EstimatedMinutes: &estimate})
+	router := setupTimeLogRouter(creator, timeLogHandler)
+
+	w := performJSON(router, "POST", "/tasks/"+task.ID+"/time", map[string]interface{}{"minutes": 45, "note": "Investigation"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = performJSON(router, "POST", "/tasks/"+task.ID+"/time", map[string]interface{}{"minutes": 30, "logged_at": "2026-01-15T10:00:00Z"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = performJSON(router, "GET", "/tasks/"+task.ID+"/time?per_page=1", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	response := decodeResponse(t, w)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(75), data["total_minutes"])
+	assert.Equal(t, float64(120), data["estimated_minutes"])
+	assert.Len(t, data["entries"], 1)
+	assert.Equal(t, float64(2), response["pagination"].(map[string]interface{})["total"])
+
+	// The timesheet only includes entries within the range
+	w = performJSON(router, "GET", "/users/"+creator.ID+"/time?from=2026-01-15&to=2026-01-15", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	timesheet := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, float64(30), timesheet["total_minutes"])
+	assert.Len(t, timesheet["entries"], 1)
+}
+
+func TestLogTaskTime_OnlyParticipantsMayLog(t *testing.T) {
+	db := setupTestDB(t)
+	timeLogHandler := handlers.NewTimeLogHandler(db)
+
+	creator := createTestUser(t, db, "Member", nil)
+	stranger := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID})
+
+	w := performJSON(setupTimeLogRouter(stranger, timeLogHandler), "POST", "/tasks/"+task.ID+"/time", map[string]interface{}{"minutes": 15})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Assignment grants the right to log time
+	require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, stranger.ID).Error)
+	w = performJSON(setupTimeLogRouter(stranger, timeLogHandler), "POST", "/tasks/"+task.ID+"/time", map[string]interface{}{"minutes": 15})
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+func TestUpdateTimeLog_OnlyOwnEntries(t *testing.T) {
+	db := setupTestDB(t)
+	timeLogHandler := handlers.NewTimeLogHandler(db)
+
+	owner := createTestUser(t, db, "Member", nil)
+	admin := createTestUser(t, db, "Admin", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: owner.ID})
+
+	entry := models.TimeLog{TaskID: task.ID, UserID: owner.ID, Minutes: 60}
+	require.NoError(t, db.Create(&entry).Error)
+
+	path := "/tasks/" + task.ID + "/time/" + entry.ID
+
+	// Even Admins can't edit someone else's entry
+	w := performJSON(setupTimeLogRouter(admin, timeLogHandler), "PUT", path, map[string]interface{}{"minutes": 90})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = performJSON(setupTimeLogRouter(owner, timeLogHandler), "PUT", path, map[string]interface{}{"minutes": 90})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored models.TimeLog
+	require.NoError(t, db.First(&stored, "id = ?", entry.ID).Error)
+	assert.Equal(t, 90, stored.Minutes)
+}
@@ -0,0 +1,106 @@
+// ABOUTME: Tests for filtering task and project lists by created and updated dates
+// ABOUTME: Verifies ?created_after=/?created_before= ranges, updated ranges and bad input
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+func TestGetTasks_CreatedInRange(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	newTask := func(title string, created time.Time) {
+		task := createTestTask(t, db, models.Task{Title: title, CreatorID: admin.ID, DepartmentID: &dept.ID})
+		require.NoError(t, db.Model(&models.Task{}).Where("id = ?", task.ID).
+			UpdateColumns(map[string]interface{}{"created_at": created, "updated_at": created}).Error)
+	}
+	newTask("Before", time.Date(2026, 10, 4, 23, 59, 0, 0, time.UTC))
+	newTask("Monday", time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC))
+	newTask("Sunday", time.Date(2026, 10, 11, 18, 0, 0, 0, time.UTC))
+	newTask("After", time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC))
+
+	router := gin.New()
+	router.GET("/tasks", asUser(admin), handlers.NewTaskHandler(db).GetTasks)
+
+	titles := func(path string) []string {
+		w := performJSON(router, "GET", path, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var titles []string
+		for _, raw := range decodeResponse(t, w)["data"].([]interface{}) {
+			titles = append(titles, raw.(map[string]interface{})["title"].(string))
+		}
+		return titles
+	}
+
+	// After bounds are inclusive and before bounds exclusive
+	assert.ElementsMatch(t, []string{"Monday", "Sunday"},
+		titles("/tasks?department_id="+dept.ID+"&created_after=2026-10-05&created_before=2026-10-12"))
+	assert.ElementsMatch(t, []string{"Sunday", "After"},
+		titles("/tasks?department_id="+dept.ID+"&updated_after=2026-10-11T12:00:00Z"))
+	// Offsets are converted to UTC
+	assert.ElementsMatch(t, []string{"Before"},
+		titles("/tasks?department_id="+dept.ID+"&created_before=2026-10-05T02:00:00%2B02:00"))
+}
+
+func TestGetProjects_CreatedInRange(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	admin := createTestUser(t, db, "Admin", &dept.ID)
+	newProject := func(created time.Time) string {
+		project := createTestProject(t, db, &dept.ID)
+		require.NoError(t, db.Model(&models.Project{}).Where("id = ?", project.ID).
+			UpdateColumn("created_at", created).Error)
+		return project.ProjectID
+	}
+	inRange := newProject(time.Date(2026, 10, 6, 9, 0, 0, 0, time.UTC))
+	newProject(time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC))
+
+	router := gin.New()
+	router.GET("/projects", asUser(admin), handlers.NewProjectHandler(db).GetProjects)
+
+	codes := projectCodes(t, router, "/projects?department_id="+dept.ID+"&created_after=2026-10-05&created_before=2026-10-12")
+	assert.Equal(t, []string{inRange}, codes)
+}
+
+func TestTimestampFilters_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(withTestUser("admin-1", "Admin", nil))
+	router.GET("/tasks", handlers.NewTaskHandler(nil).GetTasks)
+	router.GET("/projects", handlers.NewProjectHandler(nil).GetProjects)
+
+	tests := []struct {
+		query string
+		field string
+	}{
+		{"created_after=last-week", "created_after"},
+		{"created_before=2026-13-01", "created_before"},
+		{"updated_after=yesterday", "updated_after"},
+		{"created_after=2026-10-12&created_before=2026-10-05", "created_before"},
+		{"updated_after=2026-10-05&updated_before=2026-10-05", "updated_before"},
+	}
+
+	for _, path := range []string{"/tasks", "/projects"} {
+		for _, tt := range tests {
+			w := performJSON(router, "GET", path+"?"+tt.query, nil)
+			require.Equal(t, http.StatusBadRequest, w.Code, path+"?"+tt.query)
+			response := decodeResponse(t, w)
+			assert.Equal(t, "VALIDATION_ERROR", errorCode(t, response), tt.query)
+			assert.Equal(t, []string{tt.field}, errorDetailFields(t, response), tt.query)
+		}
+	}
+}
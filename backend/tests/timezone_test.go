@@ -0,0 +1,107 @@
+// ABOUTME: Tests for per-user timezones in date-boundary features
+// ABOUTME: "Due today" follows the user's calendar day while due dates stay stored in UTC
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+)
+
+func TestDayBounds_DueTodayDependsOnTimezone(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 10, 22, 0, 0, 0, time.UTC) // 07:00 on the 11th in Tokyo
+
+	dueToday := func(user models.User) bool {
+		start, end := utils.DayBounds(now, user.Location())
+		return !due.Before(start) && due.Before(end)
+	}
+	assert.True(t, dueToday(models.User{}), "due today on the UTC day")
+	assert.False(t, dueToday(models.User{Preferences: `{"timezone":"Asia/Tokyo"}`}), "due tomorrow in Tokyo")
+	assert.True(t, dueToday(models.User{Preferences: `{"timezone":"America/Los_Angeles"}`}), "due this afternoon in Los Angeles")
+}
+
+func TestDayBounds_DSTDays(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	start, end := utils.DayBounds(time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC), newYork)
+	assert.Equal(t, time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC), start.UTC())
+	assert.Equal(t, 23*time.Hour, end.Sub(start), "clocks spring forward")
+}
+
+func TestUserPreferences_Timezone(t *testing.T) {
+	user := models.User{Preferences: `{"theme":"dark"}`}
+	require.NoError(t, user.SetTimezone("Europe/Berlin"))
+	assert.JSONEq(t, `{"theme":"dark","timezone":"Europe/Berlin"}`, user.Preferences)
+	assert.Equal(t, "Europe/Berlin", user.Location().String())
+
+	require.NoError(t, user.SetTimezone(""))
+	assert.JSONEq(t, `{"theme":"dark"}`, user.Preferences)
+	assert.Equal(t, time.UTC, user.Location())
+
+	// Unknown or stale names fall back to UTC rather than failing
+	assert.Equal(t, time.UTC, models.User{Preferences: `{"timezone":"Mars/Olympus"}`}.Location())
+	assert.Equal(t, time.UTC, models.User{Preferences: `not json`}.Location())
+
+	_, err := models.LoadTimezone("Mars/Olympus")
+	assert.Error(t, err)
+	_, err = models.LoadTimezone("Local")
+	assert.Error(t, err)
+}
+
+func TestGetMeCounts_DueTodayInCallersTimezone(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	// Pick a due time on today's UTC date that falls on another date in Tokyo (UTC+9)
+	now := time.Now().UTC()
+	due := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 0, 0, time.UTC)
+	if now.Hour() >= 15 {
+		due = time.Date(now.Year(), now.Month(), now.Day(), 0, 1, 0, 0, time.UTC)
+	}
+
+	creator := createTestUser(t, db, "Member", nil)
+	utcUser := createTestUser(t, db, "Member", nil)
+	tokyoUser := createTestUser(t, db, "Member", nil)
+	require.NoError(t, db.Model(tokyoUser).Update("preferences", `{"timezone":"Asia/Tokyo"}`).Error)
+	for _, assignee := range []*models.User{utcUser, tokyoUser} {
+		task := createTestTask(t, db, models.Task{CreatorID: creator.ID, DueDate: &due})
+		require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", task.ID, assignee.ID).Error)
+	}
+
+	dueToday := func(user *models.User) float64 {
+		router := gin.New()
+		router.GET("/me/counts", asUser(user), handlers.NewMeHandler(db).GetCounts)
+		w := performJSON(router, "GET", "/me/counts", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return decodeResponse(t, w)["data"].(map[string]interface{})["due_today"].(float64)
+	}
+	assert.Equal(t, float64(1), dueToday(utcUser))
+	assert.Equal(t, float64(0), dueToday(tokyoUser))
+}
+
+func TestUpdateUser_Timezone(t *testing.T) {
+	db := setupTestDB(t)
+	user := createTestUser(t, db, "Member", nil)
+	router := setupUserUpdateRouter(db, user)
+
+	w := performJSON(router, "PUT", "/users/"+user.ID, map[string]interface{}{"timezone": "Mars/Olympus"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, []string{"timezone"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "PUT", "/users/"+user.ID, map[string]interface{}{"timezone": "America/Sao_Paulo"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", user.ID).Error)
+	assert.Equal(t, "America/Sao_Paulo", stored.ParsedPreferences().Timezone)
+}
@@ -0,0 +1,298 @@
+// ABOUTME: Tests for TOTP two-factor authentication
+// ABOUTME: Covers code generation, secret storage, enrollment and the two-step login
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/config"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// rfc6238Secret is the RFC 6238 SHA-1 test key "12345678901234567890", base32 encoded
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; authenticator apps use their last 6 digits
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range vectors {
+		code, err := utils.TOTPCode(rfc6238Secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, unix)
+	}
+}
+
+func TestVerifyTOTP_AllowsOnePeriodOfDrift(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := utils.TOTPCode(rfc6238Secret, now)
+	require.NoError(t, err)
+
+	step, ok := utils.VerifyTOTP(rfc6238Secret, code, now.Add(25*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234567890/30), step)
+
+	_, ok = utils.VerifyTOTP(rfc6238Secret, code, now.Add(2*time.Minute))
+	assert.False(t, ok)
+	_, ok = utils.VerifyTOTP(rfc6238Secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestTOTPSecret_EncryptedAtRest(t *testing.T) {
+	key := strings.Repeat("k", 32)
+	encrypted, err := utils.EncryptTOTPSecret(rfc6238Secret, key)
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, rfc6238Secret)
+
+	decrypted, err := utils.DecryptTOTPSecret(encrypted, key)
+	require.NoError(t, err)
+	assert.Equal(t, rfc6238Secret, decrypted)
+
+	_, err = utils.DecryptTOTPSecret(encrypted, strings.Repeat("x", 32))
+	assert.Error(t, err)
+}
+
+func TestTwoFactorChallenge_NotAnAccessToken(t *testing.T) {
+	secret := strings.Repeat("s", 32)
+	challenge, err := utils.GenerateTwoFactorChallenge("user-1", 2, secret, time.Minute)
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateTwoFactorChallenge(challenge, secret)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, 2, claims.Failures)
+
+	_, err = utils.ValidateJWT(challenge, utils.HMACKeys(secret))
+	assert.Error(t, err, "a challenge must not authenticate API requests")
+
+	accessToken, err := utils.GenerateJWT(&models.User{ID: "user-1", Role: "Admin"}, utils.HMACKeys(secret), 1)
+	require.NoError(t, err)
+	_, err = utils.ValidateTwoFactorChallenge(accessToken, secret)
+	assert.Error(t, err, "an access token must not skip the code step")
+
+	// Challenges issued just before a rotation are still accepted under the new secret
+	rotated := strings.Repeat("r", 32)
+	_, err = utils.ValidateTwoFactorChallenge(challenge, rotated)
+	assert.Error(t, err)
+	claims, err = utils.ValidateTwoFactorChallenge(challenge, rotated, secret)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+
+	expired, err := utils.GenerateTwoFactorChallenge("user-1", 0, secret, -time.Minute)
+	require.NoError(t, err)
+	_, err = utils.ValidateTwoFactorChallenge(expired, secret)
+	assert.Error(t, err)
+}
+
+func TestTwoFactor_EnrollAndLogin(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	gin.SetMode(gin.TestMode)
+
+	admin := createTestUser(t, db, "Admin", nil)
+	setTestPassword(t, db, admin, "correct horse battery", bcrypt.MinCost)
+
+	authHandler := handlers.NewAuthHandler(db)
+	router := gin.New()
+	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/2fa/login", authHandler.LoginTwoFactor)
+	router.POST("/auth/2fa/enroll", asUser(admin), authHandler.EnrollTwoFactor)
+	router.POST("/auth/2fa/verify", asUser(admin), authHandler.VerifyTwoFactor)
+
+	// Enrolling returns a secret but leaves login unchanged until a code is verified
+	w := performJSON(router, "POST", "/auth/2fa/enroll", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	enrollment := decodeResponse(t, w)["data"].(map[string]interface{})
+	secret := enrollment["secret"].(string)
+	assert.True(t, strings.HasPrefix(enrollment["otpauth_uri"].(string), "otpauth://totp/Synapse:"))
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", admin.ID).Error)
+	require.NotNil(t, stored.TOTPSecret)
+	assert.NotEqual(t, secret, *stored.TOTPSecret)
+	assert.False(t, stored.TOTPEnabled)
+
+	w = performJSON(router, "POST", "/auth/2fa/verify", map[string]string{"code": "000000"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	code, err := utils.TOTPCode(secret, time.Now().Add(-30*time.Second))
+	require.NoError(t, err)
+	w = performJSON(router, "POST", "/auth/2fa/verify", map[string]string{"code": code})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	recoveryCodes := decodeResponse(t, w)["data"].(map[string]interface{})["recovery_codes"].([]interface{})
+	assert.Len(t, recoveryCodes, 10)
+
+	login := func() string {
+		w := performJSON(router, "POST", "/auth/login", map[string]string{"email": admin.Email, "password": "correct horse battery"})
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		data := decodeResponse(t, w)["data"].(map[string]interface{})
+		assert.Equal(t, "2FA_REQUIRED", data["code"])
+		assert.NotContains(t, data, "access_token")
+		return data["challenge_token"].(string)
+	}
+
+	// A wrong code is rejected
+	challenge := login()
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": challenge, "code": "000000"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "INVALID_2FA_CODE", errorCode(t, decodeResponse(t, w)))
+
+	// The current code completes the login, once
+	code, err = utils.TOTPCode(secret, time.Now())
+	require.NoError(t, err)
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": challenge, "code": code})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotEmpty(t, decodeResponse(t, w)["data"].(map[string]interface{})["access_token"])
+
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": login(), "code": code})
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "a code can't be replayed")
+
+	// Recovery codes work in place of the authenticator, once each
+	recovery := recoveryCodes[0].(string)
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": login(), "recovery_code": recovery})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": login(), "recovery_code": recovery})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Enabled accounts can't re-enroll over their secret
+	w = performJSON(router, "POST", "/auth/2fa/enroll", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestLoginTwoFactor_Validation(t *testing.T) {
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/2fa/login", handlers.NewAuthHandler(nil).LoginTwoFactor)
+
+	w := performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": "x"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": "x", "code": "123456", "recovery_code": "abcde-12345"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": "not-a-token", "code": "123456"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "INVALID_TOKEN", errorCode(t, decodeResponse(t, w)))
+}
+
+func TestLoginTwoFactor_LimitsWrongCodes(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	gin.SetMode(gin.TestMode)
+
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "correct horse battery", bcrypt.MinCost)
+	encrypted, err := utils.EncryptTOTPSecret(rfc6238Secret, config.GetConfig().TOTPKey())
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).
+		UpdateColumns(map[string]interface{}{"totp_secret": encrypted, "totp_enabled": true}).Error)
+
+	authHandler := handlers.NewAuthHandler(db)
+	router := gin.New()
+	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/2fa/login", authHandler.LoginTwoFactor)
+
+	login := func() *httptest.ResponseRecorder {
+		return performJSON(router, "POST", "/auth/login", map[string]string{"email": user.Email, "password": "correct horse battery"})
+	}
+	challengeFrom := func(w *httptest.ResponseRecorder) string {
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		return decodeResponse(t, w)["data"].(map[string]interface{})["challenge_token"].(string)
+	}
+	tryCode := func(challenge string) *httptest.ResponseRecorder {
+		return performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": challenge, "code": "000000"})
+	}
+
+	// A challenge takes three codes before the password has to be entered again
+	challenge := challengeFrom(login())
+	for i := 0; i < 3; i++ {
+		w := tryCode(challenge)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "INVALID_2FA_CODE", errorCode(t, decodeResponse(t, w)))
+	}
+	w := tryCode(challenge)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "INVALID_TOKEN", errorCode(t, decodeResponse(t, w)))
+
+	// Ten wrong codes in a row lock both login steps, and retire the challenges already issued
+	stale := challengeFrom(login())
+	for failures := 3; failures < 10; failures += 3 {
+		challenge = challengeFrom(login())
+		for i := failures; i < failures+3 && i < 10; i++ {
+			require.Equal(t, http.StatusUnauthorized, tryCode(challenge).Code)
+		}
+	}
+	w = login()
+	require.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+	assert.Equal(t, "TOO_MANY_ATTEMPTS", errorCode(t, decodeResponse(t, w)))
+	w = tryCode(stale)
+	require.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+
+	// The lock lifts after it expires, without restoring the old challenges
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).
+		UpdateColumn("totp_locked_until", time.Now().Add(-time.Minute)).Error)
+	w = tryCode(stale)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "INVALID_TOKEN", errorCode(t, decodeResponse(t, w)))
+	code, err := utils.TOTPCode(rfc6238Secret, time.Now())
+	require.NoError(t, err)
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": challengeFrom(login()), "code": code})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestLoginTwoFactor_SurvivesSecretRotation(t *testing.T) {
+	db := setupTestDB(t)
+	oldSecret := strings.Repeat("o", 32)
+	newSecret := strings.Repeat("n", 32)
+	t.Setenv("JWT_SECRET", oldSecret)
+	t.Setenv("TWO_FACTOR_ENCRYPTION_KEY", "")
+	gin.SetMode(gin.TestMode)
+
+	// Without TWO_FACTOR_ENCRYPTION_KEY the stored secret is sealed under JWT_SECRET
+	user := createTestUser(t, db, "Member", nil)
+	setTestPassword(t, db, user, "correct horse battery", bcrypt.MinCost)
+	encrypted, err := utils.EncryptTOTPSecret(rfc6238Secret, config.GetConfig().TOTPKey())
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).
+		UpdateColumns(map[string]interface{}{"totp_secret": encrypted, "totp_enabled": true}).Error)
+
+	authHandler := handlers.NewAuthHandler(db)
+	router := gin.New()
+	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/2fa/login", authHandler.LoginTwoFactor)
+
+	w := performJSON(router, "POST", "/auth/login", map[string]string{"email": user.Email, "password": "correct horse battery"})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	challenge := decodeResponse(t, w)["data"].(map[string]interface{})["challenge_token"].(string)
+
+	// Rotate between the password and code steps
+	t.Setenv("JWT_SECRET", newSecret)
+	t.Setenv("JWT_SECRET_PREVIOUS", oldSecret)
+
+	code, err := utils.TOTPCode(rfc6238Secret, time.Now())
+	require.NoError(t, err)
+	w = performJSON(router, "POST", "/auth/2fa/login", map[string]string{"challenge_token": challenge, "code": code})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Reading the secret re-seals it under the new key, so the old one can be retired
+	var stored models.User
+	require.NoError(t, db.Select("*").First(&stored, "id = ?", user.ID).Error)
+	require.NotNil(t, stored.TOTPSecret)
+	decrypted, err := utils.DecryptTOTPSecret(*stored.TOTPSecret, newSecret)
+	require.NoError(t, err)
+	assert.Equal(t, rfc6238Secret, decrypted)
+}
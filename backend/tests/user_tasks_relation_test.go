@@ -0,0 +1,74 @@
+// ABOUTME: Tests for the relation filter on a user's task list
+// ABOUTME: Verifies created, assigned and both subsets and the derived relation field
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+)
+
+// userTaskRelations maps each returned task id to its relation field
+func userTaskRelations(t *testing.T, response map[string]interface{}) map[string]string {
+	t.Helper()
+	relations := make(map[string]string)
+	for _, item := range response["data"].([]interface{}) {
+		task := item.(map[string]interface{})
+		relations[task["id"].(string)] = task["relation"].(string)
+	}
+	return relations
+}
+
+func TestGetUserTasks_RelationFilter(t *testing.T) {
+	db := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+
+	dept := createTestDepartment(t, db)
+	user := createTestUser(t, db, "Member", &dept.ID)
+	other := createTestUser(t, db, "Member", &dept.ID)
+
+	created := createTestTask(t, db, models.Task{CreatorID: user.ID, DepartmentID: &dept.ID})
+	assigned := createTestTask(t, db, models.Task{CreatorID: other.ID, DepartmentID: &dept.ID})
+	both := createTestTask(t, db, models.Task{CreatorID: user.ID, DepartmentID: &dept.ID})
+	createTestTask(t, db, models.Task{CreatorID: other.ID, DepartmentID: &dept.ID})
+	for _, taskID := range []string{assigned.ID, both.ID} {
+		require.NoError(t, db.Exec("INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)", taskID, user.ID).Error)
+	}
+
+	router := gin.New()
+	router.GET("/users/:id/tasks", asUser(user), handlers.NewUserHandler(db).GetUserTasks)
+
+	cases := []struct {
+		query    string
+		expected map[string]string
+	}{
+		{"", map[string]string{created.ID: "created", assigned.ID: "assigned", both.ID: "both"}},
+		{"?relation=both", map[string]string{created.ID: "created", assigned.ID: "assigned", both.ID: "both"}},
+		{"?relation=created", map[string]string{created.ID: "created", both.ID: "both"}},
+		{"?relation=assigned", map[string]string{assigned.ID: "assigned", both.ID: "both"}},
+	}
+	for _, tc := range cases {
+		w := performJSON(router, "GET", "/users/"+user.ID+"/tasks"+tc.query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		response := decodeResponse(t, w)
+		assert.Equal(t, tc.expected, userTaskRelations(t, response), tc.query)
+		assert.Equal(t, float64(len(tc.expected)), response["pagination"].(map[string]interface{})["total"], tc.query)
+	}
+}
+
+func TestGetUserTasks_InvalidRelation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/users/:id/tasks", withTestUser("user-1", "Member", nil), handlers.NewUserHandler(nil).GetUserTasks)
+
+	w := performJSON(router, "GET", "/users/user-1/tasks?relation=watching", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+}
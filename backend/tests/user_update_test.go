@@ -0,0 +1,114 @@
+// ABOUTME: Tests for Admin changes to users through UpdateUser
+// ABOUTME: Covers departments, headship on a move, and keeping at least one active Admin
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"gorm.io/gorm"
+)
+
+func setupUserUpdateRouter(db *gorm.DB, caller *models.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/users/:id", asUser(caller), handlers.NewUserHandler(db).UpdateUser)
+	return router
+}
+
+func TestUpdateUser_InvalidDepartment(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "Admin", nil)
+	dept := createTestDepartment(t, db)
+	user := createTestUser(t, db, "Member", &dept.ID)
+	router := setupUserUpdateRouter(db, admin)
+
+	// Unknown and malformed ids are both rejected, not a server error
+	for _, departmentID := range []string{"00000000-0000-0000-0000-000000000000", "engineering"} {
+		w := performJSON(router, "PUT", "/users/"+user.ID, map[string]interface{}{"department_id": departmentID})
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Equal(t, "INVALID_DEPARTMENT", errorCode(t, decodeResponse(t, w)), departmentID)
+	}
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", user.ID).Error)
+	require.NotNil(t, stored.DepartmentID)
+	assert.Equal(t, dept.ID, *stored.DepartmentID)
+}
+
+func TestUpdateUser_MoveClearsDepartmentHead(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "Admin", nil)
+	from := createTestDepartment(t, db)
+	to := createTestDepartment(t, db)
+	head := createTestUser(t, db, "Manager", &from.ID)
+	require.NoError(t, db.Model(from).Update("head_id", head.ID).Error)
+	router := setupUserUpdateRouter(db, admin)
+
+	w := performJSON(router, "PUT", "/users/"+head.ID, map[string]interface{}{"department_id": to.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored models.Department
+	require.NoError(t, db.First(&stored, "id = ?", from.ID).Error)
+	assert.Nil(t, stored.HeadID)
+}
+
+func TestUpdateUser_ClearDepartment(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "Admin", nil)
+	dept := createTestDepartment(t, db)
+	head := createTestUser(t, db, "Manager", &dept.ID)
+	require.NoError(t, db.Model(dept).Update("head_id", head.ID).Error)
+	router := setupUserUpdateRouter(db, admin)
+
+	// Saving the same department keeps the headship
+	w := performJSON(router, "PUT", "/users/"+head.ID, map[string]interface{}{"department_id": dept.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stored models.Department
+	require.NoError(t, db.First(&stored, "id = ?", dept.ID).Error)
+	require.NotNil(t, stored.HeadID)
+
+	w = performJSON(router, "PUT", "/users/"+head.ID, map[string]interface{}{"department_id": ""})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var user models.User
+	require.NoError(t, db.First(&user, "id = ?", head.ID).Error)
+	assert.Nil(t, user.DepartmentID)
+	require.NoError(t, db.First(&stored, "id = ?", dept.ID).Error)
+	assert.Nil(t, stored.HeadID)
+}
+
+func TestUpdateUser_LastAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	// Only this test's Admins count
+	require.NoError(t, db.Model(&models.User{}).Where("role = ?", "Admin").Update("is_active", false).Error)
+	first := createTestUser(t, db, "Admin", nil)
+	second := createTestUser(t, db, "Admin", nil)
+	router := setupUserUpdateRouter(db, first)
+
+	w := performJSON(router, "PUT", "/users/"+second.ID, map[string]interface{}{"is_active": false})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// first is now the only active Admin: neither deactivating nor demoting them is allowed
+	w = performJSON(router, "PUT", "/users/"+first.ID, map[string]interface{}{"is_active": false})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "LAST_ADMIN", errorCode(t, decodeResponse(t, w)))
+
+	w = performJSON(router, "PUT", "/users/"+first.ID, map[string]interface{}{"role": "Manager"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", first.ID).Error)
+	assert.True(t, stored.IsActive)
+	assert.Equal(t, "Admin", stored.Role)
+
+	// Profile edits that keep them an active Admin still go through
+	w = performJSON(router, "PUT", "/users/"+first.ID, map[string]interface{}{"full_name": "Still In Charge"})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,39 @@
+// ABOUTME: Tests for the split between 400 and 422 validation responses
+// ABOUTME: Bodies that can't be decoded get 400; well-formed bodies that break a rule get 422
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationStatus_MalformedBodyIs400(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository(fakeTask()))
+
+	for _, body := range []string{`{"status":`, `not json`, `{"status": 5}`} {
+		w := sendWithIfMatch(router, "PATCH", "/tasks/task-1/status", body, "*")
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)), body)
+	}
+}
+
+func TestValidationStatus_InvalidValueIs422(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("admin-1", "Admin", nil), newFakeTaskRepository(fakeTask()))
+
+	w := sendWithIfMatch(router, "PATCH", "/tasks/task-1/status", `{"status": "Someday"}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+
+	// A missing required field decodes fine, so it's a rule violation too
+	w = sendWithIfMatch(router, "PATCH", "/tasks/task-1/status", `{}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, []string{"status"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	w = sendWithIfMatch(router, "PUT", "/tasks/task-1", `{"priority": "Critical"}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+}
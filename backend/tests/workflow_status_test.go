@@ -0,0 +1,169 @@
+// ABOUTME: Tests for configurable task workflows and their Admin management endpoints
+// ABOUTME: Task statuses must belong to the workflow of the task's department
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/synapse/backend/handlers"
+	"github.com/synapse/backend/models"
+	"github.com/synapse/backend/repository"
+	"gorm.io/gorm"
+)
+
+// reloadWorkflowsOnCleanup reloads the process-wide workflow cache once the test transaction
+// it was loaded from is gone
+func reloadWorkflowsOnCleanup(t *testing.T) {
+	t.Helper()
+	base := openTestDB(t)
+	t.Cleanup(func() {
+		assert.NoError(t, repository.LoadWorkflows(base))
+	})
+}
+
+// setupWorkflowRouter routes the workflow status endpoints and task status updates for caller
+func setupWorkflowRouter(t *testing.T, db *gorm.DB, caller gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	reloadWorkflowsOnCleanup(t)
+
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewWorkflowStatusHandler(db)
+	router := gin.New()
+	router.Use(caller)
+	router.GET("/workflow-statuses", h.GetWorkflowStatuses)
+	router.POST("/workflow-statuses", h.CreateWorkflowStatus)
+	router.PUT("/workflow-statuses/:id", h.UpdateWorkflowStatus)
+	router.DELETE("/workflow-statuses/:id", h.DeleteWorkflowStatus)
+	router.PATCH("/tasks/:id/status", handlers.NewTaskHandler(db).UpdateTaskStatus)
+	return router
+}
+
+func TestWorkflowStatus_RejectsStatusOutsideWorkflow(t *testing.T) {
+	router := setupFakeTaskRouter(withTestUser("creator-1", "Member", strPtr("dept-a")), newFakeTaskRepository(fakeTask()))
+
+	w := sendWithIfMatch(router, "PATCH", "/tasks/task-1/status", `{"status": "Someday"}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "this workflow allows To Do, In Progress, In Review, Blocked, Done")
+
+	w = sendWithIfMatch(router, "PATCH", "/tasks/task-1", `{"status": "Someday"}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []string{"status"}, errorDetailFields(t, decodeResponse(t, w)))
+}
+
+func TestWorkflowStatus_CustomDepartmentStatus(t *testing.T) {
+	db := setupTestDB(t)
+	qaDept := createTestDepartment(t, db)
+	otherDept := createTestDepartment(t, db)
+	admin := setupWorkflowRouter(t, db, withTestUser("admin-1", "Admin", nil))
+
+	names := func(departmentID string) []string {
+		w := performJSON(admin, "GET", "/workflow-statuses?department_id="+departmentID, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		result := []string{}
+		for _, item := range decodeResponse(t, w)["data"].([]interface{}) {
+			result = append(result, item.(map[string]interface{})["name"].(string))
+		}
+		return result
+	}
+
+	// A department's first status forks the default workflow and lands at its end
+	w := performJSON(admin, "POST", "/workflow-statuses", map[string]interface{}{
+		"name": "QA", "department_id": qaDept.ID, "color": "#F59E0B",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	created := decodeResponse(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, float64(5), created["position"])
+	qaID := created["id"].(string)
+
+	assert.Equal(t, []string{"To Do", "In Progress", "In Review", "Blocked", "Done", "QA"}, names(qaDept.ID))
+	assert.Equal(t, []string{"To Do", "In Progress", "In Review", "Blocked", "Done"}, names(otherDept.ID))
+
+	w = performJSON(admin, "POST", "/workflow-statuses", map[string]interface{}{"name": "qa", "department_id": qaDept.ID})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = performJSON(admin, "POST", "/workflow-statuses", map[string]interface{}{"name": "Parked", "color": "orange"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []string{"color"}, errorDetailFields(t, decodeResponse(t, w)))
+
+	// Reordering moves the column
+	w = performJSON(admin, "PUT", "/workflow-statuses/"+qaID, map[string]interface{}{"position": 0})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "QA", names(qaDept.ID)[0])
+
+	// Only the department's tasks may use the status
+	creator := createTestUser(t, db, "Member", &qaDept.ID)
+	qaTask := createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &qaDept.ID})
+	otherTask := createTestTask(t, db, models.Task{CreatorID: creator.ID, DepartmentID: &otherDept.ID})
+
+	w = sendWithIfMatch(admin, "PATCH", "/tasks/"+qaTask.ID+"/status", `{"status": "QA"}`, "*")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = sendWithIfMatch(admin, "PATCH", "/tasks/"+otherTask.ID+"/status", `{"status": "QA"}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "VALIDATION_ERROR", errorCode(t, decodeResponse(t, w)))
+
+	// A status tasks are in can't be removed or renamed
+	w = performJSON(admin, "DELETE", "/workflow-statuses/"+qaID, nil)
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "STATUS_IN_USE", errorCode(t, decodeResponse(t, w)))
+	w = performJSON(admin, "PUT", "/workflow-statuses/"+qaID, map[string]interface{}{"name": "Testing"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestWorkflowStatus_CustomStatusesSkipTransitionRules(t *testing.T) {
+	db := setupTestDB(t)
+	department := createTestDepartment(t, db)
+	member := createTestUser(t, db, "Member", &department.ID)
+	reloadWorkflowsOnCleanup(t)
+
+	status := models.WorkflowStatus{Name: "Triage", DepartmentID: &department.ID}
+	require.NoError(t, db.Create(&status).Error)
+	require.NoError(t, repository.LoadWorkflows(db))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("/tasks/:id/status", asUser(member), handlers.NewTaskHandler(db).UpdateTaskStatus)
+
+	// Triage is the department's only status, so even To Do is outside its workflow
+	task := createTestTask(t, db, models.Task{CreatorID: member.ID, DepartmentID: &department.ID, Status: "Triage"})
+	w := sendWithIfMatch(router, "PATCH", "/tasks/"+task.ID+"/status", `{"status": "To Do"}`, "*")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, "the department's workflow replaces the default one")
+
+	require.NoError(t, db.Exec(`INSERT INTO workflow_statuses (name, department_id, position) VALUES ('Done', ?, 1)`, department.ID).Error)
+	require.NoError(t, repository.LoadWorkflows(db))
+
+	// Triage isn't in the transitions setting, so a Member may move straight to Done
+	w = sendWithIfMatch(router, "PATCH", "/tasks/"+task.ID+"/status", `{"status": "Done"}`, "*")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestWorkflowStatus_MovingDepartmentChecksCurrentStatus(t *testing.T) {
+	db := setupTestDB(t)
+	triageDept := createTestDepartment(t, db)
+	reloadWorkflowsOnCleanup(t)
+
+	require.NoError(t, db.Create(&models.WorkflowStatus{Name: "Triage", DepartmentID: &triageDept.ID}).Error)
+	require.NoError(t, repository.LoadWorkflows(db))
+
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTaskHandler(db)
+	router := gin.New()
+	router.Use(withTestUser("admin-1", "Admin", nil))
+	router.PUT("/tasks/:id", h.UpdateTask)
+	router.PATCH("/tasks/:id", h.PatchTask)
+
+	// To Do isn't in the target department's workflow, so the task can't move there as it is
+	creator := createTestUser(t, db, "Member", nil)
+	task := createTestTask(t, db, models.Task{CreatorID: creator.ID, Status: "To Do"})
+	for _, method := range []string{"PUT", "PATCH"} {
+		w := sendWithIfMatch(router, method, "/tasks/"+task.ID, `{"department_id": "`+triageDept.ID+`"}`, "*")
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, method)
+		assert.Contains(t, w.Body.String(), "this workflow allows Triage", method)
+	}
+
+	w := sendWithIfMatch(router, "PATCH", "/tasks/"+task.ID, `{"department_id": "`+triageDept.ID+`", "status": "Triage"}`, "*")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -0,0 +1,48 @@
+// ABOUTME: Weak ETags and conditional GETs for list responses
+// ABOUTME: Pollers that send back a matching If-None-Match get 304 Not Modified without a body
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RespondPaginatedWithETag is RespondSuccessWithFacets with a weak ETag hashed from the
+// response body. Requests whose If-None-Match already names that tag get 304 Not Modified.
+func RespondPaginatedWithETag(c *gin.Context, data interface{}, page, perPage int, total int64, facets Facets) {
+	body, err := json.Marshal(paginatedResponse(c, data, page, perPage, total, facets))
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "SERVER_ERROR", "Failed to encode response", nil)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches reports whether an If-None-Match header names etag, comparing weakly so W/
+// prefixes are ignored
+func etagMatches(ifNoneMatch, etag string) bool {
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+// ABOUTME: Signed, non-expiring tokens for feeds fetched by clients that can't send headers
+// ABOUTME: A token names its user and is checked with an HMAC keyed by the JWT secret
+
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// feedTokenPurpose keeps feed signatures from being valid for any other HMAC use of the secret
+const feedTokenPurpose = "calendar-feed:"
+
+// GenerateFeedToken returns userID's feed token: the user id and its signature, dot separated
+func GenerateFeedToken(userID, secret string) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("JWT secret not configured")
+	}
+	return userID + "." + feedSignature(userID, secret), nil
+}
+
+// ValidateFeedToken checks a feed token's signature and returns the user id it was issued for.
+// Tokens signed with any of the previous secrets still validate, so feed URLs survive a
+// JWT_SECRET rotation.
+func ValidateFeedToken(token, secret string, previous ...string) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("JWT secret not configured")
+	}
+	userID, signature, ok := strings.Cut(token, ".")
+	if !ok || userID == "" {
+		return "", fmt.Errorf("malformed feed token")
+	}
+	for _, key := range append([]string{secret}, previous...) {
+		if key != "" && hmac.Equal([]byte(signature), []byte(feedSignature(userID, key))) {
+			return userID, nil
+		}
+	}
+	return "", fmt.Errorf("invalid feed token signature")
+}
+
+func feedSignature(userID, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(feedTokenPurpose + userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
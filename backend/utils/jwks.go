@@ -0,0 +1,59 @@
+// ABOUTME: JSON Web Key Set publishing of the RS256 public keys tokens are verified with
+// ABOUTME: Key ids are RFC 7638 thumbprints, so the same key always gets the same kid
+
+package utils
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK is an RSA public key in JSON Web Key form
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeyID returns the RFC 7638 thumbprint of key, base64url encoded
+func KeyID(key *rsa.PublicKey) string {
+	n, e := jwkModulus(key), jwkExponent(key)
+	// The thumbprint hashes the required members in lexicographic order, without whitespace
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWKS returns the public keys tokens may be verified with, current key first. HS256 keys
+// are secret, so they publish an empty set.
+func (k *JWTKeys) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range k.publicKeys {
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: k.method.Alg(),
+			Kid: KeyID(key),
+			N:   jwkModulus(key),
+			E:   jwkExponent(key),
+		})
+	}
+	return set
+}
+
+func jwkModulus(key *rsa.PublicKey) string {
+	return base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+}
+
+func jwkExponent(key *rsa.PublicKey) string {
+	return base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+}
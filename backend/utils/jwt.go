@@ -4,10 +4,15 @@
 package utils
 
 import (
+	"crypto/rsa"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/synapse/backend/auth"
+	"github.com/synapse/backend/config"
 	"github.com/synapse/backend/models"
 )
 
@@ -19,13 +24,112 @@ type JWTClaims struct {
 	Role         string   `json:"role"`
 	DepartmentID *string  `json:"department_id,omitempty"`
 	Permissions  []string `json:"permissions"`
+	SessionID    string   `json:"sid,omitempty"` // the login session the token belongs to
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT generates a new JWT token for the given user
-func GenerateJWT(user *models.User, secret string, expiryHours int) (string, error) {
+// JWTKeys are the signing method and keys access and refresh tokens are signed and
+// verified with. RS256 keys are identified by a kid, so retired public keys can keep
+// verifying tokens during a rotation.
+type JWTKeys struct {
+	method     jwt.SigningMethod
+	kid        string // of the signing key; empty for HS256
+	signKey    interface{}
+	verifyKeys map[string]interface{} // by kid
+	publicKeys []*rsa.PublicKey       // published in the JWKS, current key first
+}
+
+// HMACKeys returns HS256 keys that sign with secret. Tokens signed with any of the previous
+// secrets still verify, so JWT_SECRET can be rotated without logging everyone out.
+func HMACKeys(secret string, previous ...string) *JWTKeys {
+	keys := &JWTKeys{method: jwt.SigningMethodHS256, verifyKeys: map[string]interface{}{}}
 	if secret == "" {
-		return "", fmt.Errorf("JWT secret not configured")
+		return keys
+	}
+	keys.signKey = []byte(secret)
+	if len(previous) == 0 {
+		keys.verifyKeys[""] = []byte(secret)
+		return keys
+	}
+	// HS256 tokens carry no kid, so the parser tries each secret in turn
+	set := jwt.VerificationKeySet{Keys: []jwt.VerificationKey{[]byte(secret)}}
+	for _, old := range previous {
+		if old != "" && old != secret {
+			set.Keys = append(set.Keys, []byte(old))
+		}
+	}
+	keys.verifyKeys[""] = set
+	return keys
+}
+
+// RSAKeys returns RS256 keys for a key pair, also accepting tokens signed by any of the
+// previous public keys. privateKey may be nil for keys that only verify.
+func RSAKeys(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, previous ...*rsa.PublicKey) *JWTKeys {
+	keys := &JWTKeys{method: jwt.SigningMethodRS256, verifyKeys: map[string]interface{}{}}
+	if publicKey != nil {
+		keys.kid = KeyID(publicKey)
+		keys.verifyKeys[keys.kid] = publicKey
+		keys.publicKeys = append(keys.publicKeys, publicKey)
+	}
+	for _, key := range previous {
+		if kid := KeyID(key); keys.verifyKeys[kid] == nil {
+			keys.verifyKeys[kid] = key
+			keys.publicKeys = append(keys.publicKeys, key)
+		}
+	}
+	if privateKey != nil {
+		keys.signKey = privateKey
+	}
+	return keys
+}
+
+// rsaKeysCache keeps the parsed key pair, since handlers load keys on every token they issue
+var rsaKeysCache struct {
+	sync.Mutex
+	source string
+	keys   *JWTKeys
+}
+
+// LoadJWTKeys returns the keys for cfg's JWT_ALG. RS256 keys are parsed once and reused
+// while their settings stay the same.
+func LoadJWTKeys(cfg *config.Config) (*JWTKeys, error) {
+	switch cfg.JWTAlg {
+	case config.JWTAlgHS256:
+		return HMACKeys(cfg.JWTSecret, cfg.PreviousJWTSecrets()...), nil
+	case config.JWTAlgRS256:
+	default:
+		return nil, fmt.Errorf("unsupported JWT_ALG %q", cfg.JWTAlg)
+	}
+
+	source := strings.Join([]string{cfg.JWTPrivateKey, cfg.JWTPrivateKeyPath, cfg.JWTPublicKey, cfg.JWTPublicKeyPath,
+		cfg.JWTPreviousPublicKeys, cfg.JWTPreviousPublicKeysPath}, "\x00")
+	rsaKeysCache.Lock()
+	defer rsaKeysCache.Unlock()
+	if rsaKeysCache.keys != nil && rsaKeysCache.source == source {
+		return rsaKeysCache.keys, nil
+	}
+	privateKey, publicKey, err := cfg.RSAKeys()
+	if err != nil {
+		return nil, err
+	}
+	previous, err := cfg.PreviousRSAPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	rsaKeysCache.source = source
+	rsaKeysCache.keys = RSAKeys(privateKey, publicKey, previous...)
+	return rsaKeysCache.keys, nil
+}
+
+// GenerateJWT generates a new JWT token for the given user
+func GenerateJWT(user *models.User, keys *JWTKeys, expiryHours int) (string, error) {
+	return GenerateSessionJWT(user, keys, expiryHours, "")
+}
+
+// GenerateSessionJWT generates a JWT token for the given user tied to a login session
+func GenerateSessionJWT(user *models.User, keys *JWTKeys, expiryHours int, sessionID string) (string, error) {
+	if keys == nil || keys.signKey == nil {
+		return "", fmt.Errorf("JWT signing key not configured")
 	}
 
 	// Calculate expiration time
@@ -38,7 +142,8 @@ func GenerateJWT(user *models.User, secret string, expiryHours int) (string, err
 		FullName:     user.FullName,
 		Role:         user.Role,
 		DepartmentID: user.DepartmentID,
-		Permissions:  getPermissionsForRole(user.Role),
+		Permissions:  auth.PermissionsForRole(user.Role),
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiryTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -47,11 +152,14 @@ func GenerateJWT(user *models.User, secret string, expiryHours int) (string, err
 		},
 	}
 
-	// Create token with claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// Create token with claims, naming the key so verifiers can pick it from the JWKS
+	token := jwt.NewWithClaims(keys.method, claims)
+	if keys.kid != "" {
+		token.Header["kid"] = keys.kid
+	}
 
-	// Sign token with secret
-	signedToken, err := token.SignedString([]byte(secret))
+	// Sign token with the configured key
+	signedToken, err := token.SignedString(keys.signKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -59,20 +167,30 @@ func GenerateJWT(user *models.User, secret string, expiryHours int) (string, err
 	return signedToken, nil
 }
 
-// ValidateJWT validates a JWT token and returns the claims
-func ValidateJWT(tokenString string, secret string) (*JWTClaims, error) {
-	if secret == "" {
-		return nil, fmt.Errorf("JWT secret not configured")
+// ValidateJWT validates a JWT token and returns the claims. Only tokens signed with the
+// keys' own algorithm are accepted, so an RS256 public key can't be passed off as an HMAC secret.
+func ValidateJWT(tokenString string, keys *JWTKeys) (*JWTClaims, error) {
+	if keys == nil || len(keys.verifyKeys) == 0 {
+		return nil, fmt.Errorf("JWT verification key not configured")
 	}
 
 	// Parse token
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != keys.method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secret), nil
-	})
+		// Pick the key the token names; tokens without a kid use the current key
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			kid = keys.kid
+		}
+		key, ok := keys.verifyKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{keys.method.Alg()}))
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
@@ -87,45 +205,10 @@ func ValidateJWT(tokenString string, secret string) (*JWTClaims, error) {
 	return claims, nil
 }
 
-// GenerateRefreshToken generates a long-lived refresh token
-func GenerateRefreshToken(user *models.User, secret string) (string, error) {
-	// Refresh tokens are valid for 7 days (168 hours)
-	return GenerateJWT(user, secret, 168)
-}
-
-// getPermissionsForRole returns permissions based on user role
-func getPermissionsForRole(role string) []string {
-	permissions := map[string][]string{
-		"Admin": {
-			"tasks.create", "tasks.read", "tasks.update", "tasks.delete",
-			"users.create", "users.read", "users.update", "users.delete",
-			"projects.create", "projects.read", "projects.update", "projects.delete",
-			"departments.create", "departments.read", "departments.update", "departments.delete",
-		},
-		"Manager": {
-			"tasks.create", "tasks.read", "tasks.update", "tasks.delete",
-			"users.read",
-			"projects.create", "projects.read", "projects.update",
-			"departments.read",
-		},
-		"Member": {
-			"tasks.create", "tasks.read", "tasks.update",
-			"users.read",
-			"projects.read",
-			"departments.read",
-		},
-		"Viewer": {
-			"tasks.read",
-			"users.read",
-			"projects.read",
-			"departments.read",
-		},
-	}
-
-	if perms, ok := permissions[role]; ok {
-		return perms
-	}
+// RefreshTokenHours is how long a refresh token, and the session it renews, stays valid
+const RefreshTokenHours = 168 // 7 days
 
-	// Default to viewer permissions
-	return permissions["Viewer"]
+// GenerateRefreshToken generates a long-lived refresh token for a login session
+func GenerateRefreshToken(user *models.User, keys *JWTKeys, sessionID string) (string, error) {
+	return GenerateSessionJWT(user, keys, RefreshTokenHours, sessionID)
 }
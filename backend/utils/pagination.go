@@ -0,0 +1,57 @@
+// ABOUTME: Pagination query parsing shared by list endpoints
+// ABOUTME: Clamps out-of-range values and records when per_page was adjusted
+
+package utils
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/synapse/backend/config"
+)
+
+// requestedPerPageKey holds the caller's per_page when it was clamped
+const requestedPerPageKey = "pagination_requested_per_page"
+
+// ParsePagination reads page and per_page from the query string and returns the clamped
+// values. Invalid pages become 1; per_page above PAGINATION_MAX is clamped to it and invalid
+// values fall back to PAGINATION_DEFAULT. When per_page is adjusted, the response carries
+// requested_per_page and an X-Per-Page-Clamped header.
+func ParsePagination(c *gin.Context) (page, perPage int) {
+	defaultPerPage, maxPerPage := PaginationLimits()
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	raw, present := c.GetQuery("per_page")
+	if !present {
+		return page, defaultPerPage
+	}
+
+	requested, err := strconv.Atoi(raw)
+	switch {
+	case err != nil || requested < 1:
+		perPage = defaultPerPage
+	case requested > maxPerPage:
+		perPage = maxPerPage
+	default:
+		return page, requested
+	}
+
+	c.Set(requestedPerPageKey, raw)
+	c.Header("X-Per-Page-Clamped", strconv.Itoa(perPage))
+	return page, perPage
+}
+
+// PaginationLimits returns the configured default and maximum per_page. Settings that
+// Validate would reject fall back to the built-in defaults rather than breaking list endpoints.
+func PaginationLimits() (defaultPerPage, maxPerPage int) {
+	cfg := config.GetConfig()
+	defaultPerPage, maxPerPage = cfg.PaginationDefault, cfg.PaginationMax
+	if defaultPerPage <= 0 || maxPerPage <= 0 || defaultPerPage > maxPerPage {
+		return config.DefaultPaginationDefault, config.DefaultPaginationMax
+	}
+	return defaultPerPage, maxPerPage
+}
@@ -10,18 +10,23 @@ import (
 )
 
 const (
-	// DefaultCost is the default bcrypt cost factor (12 provides good security/performance balance)
+	// DefaultCost is the bcrypt cost factor used when BCRYPT_COST is unset (12 provides good security/performance balance)
 	DefaultCost = 12
 )
 
-// HashPassword generates a bcrypt hash from a plain text password
+// HashPassword generates a bcrypt hash from a plain text password using DefaultCost
 func HashPassword(password string) (string, error) {
+	return HashPasswordWithCost(password, DefaultCost)
+}
+
+// HashPasswordWithCost generates a bcrypt hash at the given cost; costs outside bcrypt's
+// supported range fall back to DefaultCost
+func HashPasswordWithCost(password string, cost int) (string, error) {
 	if len(password) == 0 {
 		return "", fmt.Errorf("password cannot be empty")
 	}
 
-	// Generate hash with default cost
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), DefaultCost)
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordCost(cost))
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -29,6 +34,24 @@ func HashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
+// PasswordCost returns cost if bcrypt supports it, otherwise DefaultCost
+func PasswordCost(cost int) int {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return DefaultCost
+	}
+	return cost
+}
+
+// PasswordNeedsRehash reports whether a hash was made with a lower cost than the target,
+// so it should be replaced the next time the plain text password is available
+func PasswordNeedsRehash(hashedPassword string, cost int) bool {
+	current, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return false
+	}
+	return current < PasswordCost(cost)
+}
+
 // VerifyPassword compares a plain text password with a bcrypt hash
 func VerifyPassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
@@ -4,8 +4,10 @@
 package utils
 
 import (
-	"github.com/gin-gonic/gin"
 	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
 )
 
 type SuccessResponse struct {
@@ -15,8 +17,9 @@ type SuccessResponse struct {
 }
 
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Error   Error  `json:"error"`
+	Success bool        `json:"success"`
+	Error   Error       `json:"error"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 type Error struct {
@@ -34,13 +37,20 @@ type PaginatedResponse struct {
 	Success    bool        `json:"success"`
 	Data       interface{} `json:"data"`
 	Pagination Pagination  `json:"pagination"`
+	Facets     Facets      `json:"facets,omitempty"`
 }
 
+// Facets counts the full filtered result set by dimension, then by value
+type Facets map[string]map[string]int64
+
 type Pagination struct {
-	Page       int   `json:"page"`
-	PerPage    int   `json:"per_page"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"total_pages"`
+	Page             int     `json:"page"`
+	PerPage          int     `json:"per_page"`
+	Total            int64   `json:"total"`
+	TotalPages       int     `json:"total_pages"`
+	HasNext          bool    `json:"has_next"`
+	HasPrev          bool    `json:"has_prev"`
+	RequestedPerPage *string `json:"requested_per_page,omitempty"` // Set when per_page was clamped
 }
 
 func RespondSuccess(c *gin.Context, statusCode int, data interface{}, message string) {
@@ -52,17 +62,43 @@ func RespondSuccess(c *gin.Context, statusCode int, data interface{}, message st
 }
 
 func RespondSuccessWithPagination(c *gin.Context, data interface{}, page, perPage int, total int64) {
+	RespondSuccessWithFacets(c, data, page, perPage, total, nil)
+}
+
+// RespondSuccessWithFacets is RespondSuccessWithPagination plus facet counts; nil facets are omitted
+func RespondSuccessWithFacets(c *gin.Context, data interface{}, page, perPage int, total int64, facets Facets) {
+	c.JSON(http.StatusOK, paginatedResponse(c, data, page, perPage, total, facets))
+}
+
+// paginatedResponse builds the body of a paginated list response
+func paginatedResponse(c *gin.Context, data interface{}, page, perPage int, total int64, facets Facets) PaginatedResponse {
 	totalPages := int((total + int64(perPage) - 1) / int64(perPage))
-	c.JSON(http.StatusOK, PaginatedResponse{
-		Success: true,
-		Data:    data,
-		Pagination: Pagination{
-			Page:       page,
-			PerPage:    perPage,
-			Total:      total,
-			TotalPages: totalPages,
-		},
-	})
+
+	// Pages past the end are empty lists, never null
+	if value := reflect.ValueOf(data); data == nil || (value.Kind() == reflect.Slice && value.IsNil()) {
+		data = []interface{}{}
+	}
+
+	pagination := Pagination{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+	if requested, ok := c.Get(requestedPerPageKey); ok {
+		if value, ok := requested.(string); ok {
+			pagination.RequestedPerPage = &value
+		}
+	}
+
+	return PaginatedResponse{
+		Success:    true,
+		Data:       data,
+		Pagination: pagination,
+		Facets:     facets,
+	}
 }
 
 func RespondError(c *gin.Context, statusCode int, code string, message string, details []ErrorDetail) {
@@ -76,8 +112,23 @@ func RespondError(c *gin.Context, statusCode int, code string, message string, d
 	})
 }
 
+// RespondErrorWithData returns an error alongside the current resource state (e.g. on conflicts)
+func RespondErrorWithData(c *gin.Context, statusCode int, code string, message string, data interface{}) {
+	c.JSON(statusCode, ErrorResponse{
+		Success: false,
+		Error: Error{
+			Code:    code,
+			Message: message,
+		},
+		Data: data,
+	})
+}
+
+// RespondValidationError rejects a well-formed request body whose content is invalid, such
+// as an unknown enum value, an inverted date range or a broken business rule, with 422.
+// Bodies that can't be decoded at all and invalid query parameters or headers stay 400.
 func RespondValidationError(c *gin.Context, details []ErrorDetail) {
-	RespondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", details)
+	RespondError(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Validation failed", details)
 }
 
 func RespondUnauthorized(c *gin.Context) {
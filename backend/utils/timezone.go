@@ -0,0 +1,14 @@
+// ABOUTME: Calendar day boundaries in a user's timezone
+// ABOUTME: Timestamps stay UTC in storage; only "today" depends on where the user is
+
+package utils
+
+import "time"
+
+// DayBounds returns the start of the day containing now in loc and the start of the next
+// day. Days around DST changes are 23 or 25 hours long.
+func DayBounds(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,238 @@
+// ABOUTME: Two-factor authentication helpers: RFC 6238 TOTP codes, secret encryption and recovery codes
+// ABOUTME: Also issues the short-lived challenge tokens that link a login's password and code steps
+
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TOTP parameters, the defaults authenticator apps assume
+const (
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// totpSkew accepts codes from one period either side to allow for clock drift
+	totpSkew = 1
+)
+
+// Key derivation purposes keep the JWT secret's other uses from being interchangeable
+const (
+	twoFactorSecretPurpose    = "totp-secret:"
+	twoFactorChallengePurpose = "2fa-challenge:"
+)
+
+// twoFactorChallengeAudience marks challenge tokens so they can't pass for anything else
+const twoFactorChallengeAudience = "2fa-challenge"
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random 160-bit secret, base32 encoded for authenticator apps
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPURI returns the otpauth:// URI authenticator apps enroll from, usually shown as a QR code
+func TOTPURI(issuer, account, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("digits", fmt.Sprint(totpDigits))
+	values.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + values.Encode()
+}
+
+// TOTPCode returns the code for secret at t
+func TOTPCode(secret string, t time.Time) (string, error) {
+	return totpCodeAt(secret, totpStep(t))
+}
+
+// VerifyTOTP reports whether code is valid for secret at now, allowing one period of drift,
+// and returns the time step it matched so callers can refuse to accept a step twice
+func VerifyTOTP(secret, code string, now time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	current := totpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := totpCodeAt(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpStep is the number of whole periods since the Unix epoch at t
+func totpStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod.Seconds())
+}
+
+func totpCodeAt(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	return hotp(key, uint64(step)), nil
+}
+
+// hotp is RFC 4226's HMAC-SHA1 one-time password for counter
+func hotp(key []byte, counter uint64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulus := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%modulus)
+}
+
+// EncryptTOTPSecret seals secret with AES-256-GCM under a key derived from key, for storage
+func EncryptTOTPSecret(secret, key string) (string, error) {
+	gcm, err := totpCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptTOTPSecret opens a secret sealed by EncryptTOTPSecret with the same key
+func DecryptTOTPSecret(encrypted, key string) (string, error) {
+	gcm, err := totpCipher(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted TOTP secret")
+	}
+	secret, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+func totpCipher(key string) (cipher.AEAD, error) {
+	if key == "" {
+		return nil, fmt.Errorf("two-factor encryption key not configured")
+	}
+	derived := sha256.Sum256([]byte(twoFactorSecretPurpose + key))
+	block, err := aes.NewCipher(derived[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// GenerateRecoveryCodes returns count random single-use codes formatted like abcde-12345
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, count)
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		encoded := hex.EncodeToString(raw)
+		codes[i] = encoded[:5] + "-" + encoded[5:]
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode returns the stored form of a recovery code. Codes are random enough that
+// a fast hash is safe, and it lets a code be looked up directly.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// TwoFactorChallengeClaims identify the user a challenge was issued to and how many failed
+// codes they had at the time, so the challenge can be retired after a few more
+type TwoFactorChallengeClaims struct {
+	Failures int `json:"failures"`
+	jwt.RegisteredClaims
+}
+
+// GenerateTwoFactorChallenge returns a token proving userID passed the password step of a
+// login, valid for ttl; failures is the user's current failed code count. It is signed with
+// a key derived from secret, so it is never accepted as an access token.
+func GenerateTwoFactorChallenge(userID string, failures int, secret string, ttl time.Duration) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("JWT secret not configured")
+	}
+	now := time.Now()
+	claims := TwoFactorChallengeClaims{
+		Failures: failures,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{twoFactorChallengeAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			Issuer:    "synapse-api",
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(twoFactorChallengePurpose + secret))
+}
+
+// ValidateTwoFactorChallenge checks a challenge token and returns its claims. Challenges
+// signed with any of the previous secrets still validate, so a JWT_SECRET rotation doesn't
+// strand logins halfway through.
+func ValidateTwoFactorChallenge(tokenString, secret string, previous ...string) (*TwoFactorChallengeClaims, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("JWT secret not configured")
+	}
+	keys := jwt.VerificationKeySet{Keys: []jwt.VerificationKey{[]byte(twoFactorChallengePurpose + secret)}}
+	for _, old := range previous {
+		if old != "" && old != secret {
+			keys.Keys = append(keys.Keys, []byte(twoFactorChallengePurpose+old))
+		}
+	}
+	claims := &TwoFactorChallengeClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return keys, nil
+	}, jwt.WithAudience(twoFactorChallengeAudience))
+	if err != nil || !token.Valid || claims.Subject == "" {
+		return nil, fmt.Errorf("invalid two-factor challenge")
+	}
+	return claims, nil
+}
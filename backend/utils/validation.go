@@ -0,0 +1,95 @@
+// ABOUTME: Request validation helpers built on Gin's binding validator
+// ABOUTME: Converts validator errors into field-level ErrorDetail entries
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report JSON field names instead of Go struct field names in validation errors
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" || name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// uuidPattern matches the canonical hyphenated form Postgres returns for UUID columns
+var uuidPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// IsUUID reports whether s is a UUID Postgres will accept for a uuid column
+func IsUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// ValidateStruct runs the binding tag rules against an already-decoded value
+func ValidateStruct(obj interface{}) []ErrorDetail {
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return BindingErrorDetails(err)
+	}
+	return nil
+}
+
+// BindingErrorDetails converts a binding error into per-field details.
+// Errors that are not validator errors (e.g. malformed JSON) yield a single detail without a field.
+func BindingErrorDetails(err error) []ErrorDetail {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []ErrorDetail{{Message: err.Error()}}
+	}
+
+	details := make([]ErrorDetail, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		details = append(details, ErrorDetail{
+			Field:   fe.Field(),
+			Message: validationMessage(fe),
+		})
+	}
+	return details
+}
+
+// RespondBindingError answers a failed ShouldBindJSON: 400 when the body isn't decodable JSON
+// of the expected shape, 422 when it decoded but broke a binding rule such as required or max
+func RespondBindingError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		status = http.StatusUnprocessableEntity
+	}
+	RespondError(c, status, "VALIDATION_ERROR", "Invalid input data", BindingErrorDetails(err))
+}
+
+// validationMessage renders a human readable message for a single failed rule
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fe.Field())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), fe.Param())
+	case "uuid":
+		return fmt.Sprintf("%s must be a UUID", fe.Field())
+	default:
+		return fmt.Sprintf("%s failed the %s rule", fe.Field(), fe.Tag())
+	}
+}
@@ -0,0 +1,23 @@
+// ABOUTME: Build information stamped into the binary at link time
+// ABOUTME: Set with -ldflags "-X github.com/synapse/backend/version.Version=..." and friends
+
+package version
+
+// Build details; unstamped builds (go run, tests) report "dev"
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildTime = "dev"
+)
+
+// Info is the build information served by the version endpoint
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Get returns the running binary's build information
+func Get() Info {
+	return Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+}